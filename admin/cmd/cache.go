@@ -0,0 +1,24 @@
+package cmd
+
+import "encoding/json"
+
+//CachePurgeInfo 清除接口响应缓存，Key为空时清除接口下的全部缓存
+type CachePurgeInfo struct {
+	APIID int    `json:"apiID"`
+	Key   string `json:"key,omitempty"`
+}
+
+//EncodeCachePurge 编码缓存清除指令
+func EncodeCachePurge(apiID int, key string) ([]byte, error) {
+	return json.Marshal(&CachePurgeInfo{APIID: apiID, Key: key})
+}
+
+//DecodeCachePurge 解码缓存清除指令
+func DecodeCachePurge(data []byte) (*CachePurgeInfo, error) {
+	info := new(CachePurgeInfo)
+	err := json.Unmarshal(data, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
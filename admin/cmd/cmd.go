@@ -23,6 +23,9 @@ const (
 	Restart            Code = "restart"
 	Stop               Code = "stop"
 	Monitor            Code = "monitor"
+	PluginError        Code = "plugin-error"
+	CachePurge         Code = "cache-purge"
+	ConfigAck          Code = "config-ack"
 	EventClientLeave   Code = "leave"
 	Error              Code = "error"
 )
@@ -0,0 +1,23 @@
+package cmd
+
+import "encoding/json"
+
+//ConfigAckInfo 节点应用配置后的回报
+type ConfigAckInfo struct {
+	VersionID int `json:"versionID"`
+}
+
+//EncodeConfigAck 编码配置回报
+func EncodeConfigAck(versionID int) ([]byte, error) {
+	return json.Marshal(&ConfigAckInfo{VersionID: versionID})
+}
+
+//DecodeConfigAck 解码配置回报
+func DecodeConfigAck(data []byte) (*ConfigAckInfo, error) {
+	ack := new(ConfigAckInfo)
+	err := json.Unmarshal(data, ack)
+	if err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import "encoding/json"
+
+//EncodePluginErrors 编码节点插件加载错误，key为插件名
+func EncodePluginErrors(errs map[string]string) ([]byte, error) {
+	if errs == nil {
+		errs = make(map[string]string)
+	}
+	return json.Marshal(errs)
+}
+
+//DecodePluginErrors 解码节点插件加载错误
+func DecodePluginErrors(data []byte) (map[string]string, error) {
+	errs := make(map[string]string)
+	if len(data) == 0 {
+		return errs, nil
+	}
+	err := json.Unmarshal(data, &errs)
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
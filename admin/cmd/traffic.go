@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//EncodeTraffic 编码节点上报的流量统计
+func EncodeTraffic(report *config.TrafficReport) ([]byte, error) {
+	return json.Marshal(report)
+}
+
+//DecodeTraffic 解码节点上报的流量统计
+func DecodeTraffic(data []byte) (*config.TrafficReport, error) {
+	report := new(config.TrafficReport)
+	err := json.Unmarshal(data, report)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,24 @@
+package console
+
+import (
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+//PurgeCache 通知所有在线节点清除接口响应缓存，返回通知成功的节点数
+func PurgeCache(apiID int, key string) int {
+	data, err := cmd.EncodeCachePurge(apiID, key)
+	if err != nil {
+		log.Warn("encode cache purge error:", err)
+		return 0
+	}
+	count := 0
+	for _, client := range clientManager.All() {
+		if err := client.Send(cmd.CachePurge, data); err != nil {
+			log.Warn("send cache purge to node ", client.instance, " error:", err)
+			continue
+		}
+		count++
+	}
+	return count
+}
@@ -65,3 +65,14 @@ func IsLive(key string) bool {
 	_, b := clientManager.Get(key)
 	return b
 }
+
+//All 获取所有在线节点
+func (m *ClientManager) All() []*Client {
+	m.locker.RLock()
+	clients := make([]*Client, 0, len(m.clients))
+	for _, c := range m.clients {
+		clients = append(clients, c)
+	}
+	m.locker.RUnlock()
+	return clients
+}
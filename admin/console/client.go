@@ -11,6 +11,7 @@ import (
 type Client struct {
 	*cmd.Connect
 	instance string
+	cluster  string
 }
 
 func NewClient(conn net.Conn, instance string) *Client {
@@ -7,7 +7,9 @@ import (
 	"sync"
 
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
 	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
 	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 )
 
@@ -27,6 +29,11 @@ func Start(addr string) error {
 	once.Do(func() {
 		versionConfig.InitVersionConfig()
 		register = doRegister()
+		node.KeepSessions()
+		replica.Start()
+		alert.Start()
+		versionConfig.StartPublishScheduler()
+		versionConfig.StartCanaryWatcher()
 	})
 
 	var lc net.ListenConfig
@@ -1,11 +1,14 @@
 package console
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
 	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	"github.com/eolinker/goku-api-gateway/console/module/traffic"
 	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
@@ -28,7 +31,8 @@ func NodeRegister(client *Client) error {
 	if err != nil {
 		return ErrorDuplicateInstance
 	}
-	result, err := versionConfig.GetConfig(nodeInfo.Cluster)
+	client.cluster = nodeInfo.Cluster
+	result, err := versionConfig.GetNodeConfig(nodeInfo.Cluster, nodeInfo.GroupID)
 	if err != nil {
 		return err
 	}
@@ -40,9 +44,47 @@ func NodeRegister(client *Client) error {
 
 func NodeLeave(client *Client) {
 	clientManager.Remove(client.instance)
+	node.SetPluginErrors(client.instance, nil)
 
 }
 
+//OnPluginErrors 节点上报插件加载错误
+func OnPluginErrors(code cmd.Code, data []byte, client *Client) error {
+	errs, err := cmd.DecodePluginErrors(data)
+	if err != nil {
+		log.Warn("decode plugin errors of node ", client.instance, " error:", err)
+		return nil
+	}
+	node.SetPluginErrors(client.instance, errs)
+	return nil
+}
+
+//OnTraffic 节点上报流量统计
+func OnTraffic(code cmd.Code, data []byte, client *Client) error {
+	report, err := cmd.DecodeTraffic(data)
+	if err != nil {
+		log.Warn("decode traffic of node ", client.instance, " error:", err)
+		return nil
+	}
+	if err := traffic.AddReport(client.cluster, client.instance, report); err != nil {
+		log.Warn("save traffic of node ", client.instance, " error:", err)
+	}
+	return nil
+}
+
+//OnConfigAck 节点回报已应用的配置版本
+func OnConfigAck(code cmd.Code, data []byte, client *Client) error {
+	ack, err := cmd.DecodeConfigAck(data)
+	if err != nil {
+		log.Warn("decode config ack of node ", client.instance, " error:", err)
+		return nil
+	}
+	if err := versionConfig.ConfirmNodeConfig(client.instance, ack.VersionID); err != nil {
+		log.Warn("save config version of node ", client.instance, " error:", err)
+	}
+	return nil
+}
+
 func getNodeMapByCluster() (map[string][]*entity.Node, error) {
 	nodes, e := node.GetAllNode()
 	if e != nil {
@@ -67,8 +109,11 @@ func OnConfigChange(conf map[string]*config.GokuConfig) {
 
 			client, has := clientManager.Get(nodeInfo.NodeKey)
 			if has {
-
-				_ = client.SendConfig(c, nodeInfo)
+				nodeConf := c
+				if canary := versionConfig.CanaryConfig(cluster, nodeInfo.GroupID); canary != nil {
+					nodeConf = canary
+				}
+				_ = client.SendConfig(nodeConf, nodeInfo)
 
 			}
 
@@ -76,16 +121,19 @@ func OnConfigChange(conf map[string]*config.GokuConfig) {
 	}
 }
 
+//StopNode 停止节点，节点连接在其他控制台实例时广播指令
 func StopNode(nodeKey string) {
 
 	client, has := clientManager.Get(nodeKey)
 	if has {
 		_ = client.SendRunCMD("stop")
 		NodeLeave(client)
-
+		return
 	}
+	publishNodeCommand(nodeKey, "stop")
 }
 
+//RestartNode 重启节点，节点连接在其他控制台实例时广播指令
 func RestartNode(nodeKey string) {
 
 	client, has := clientManager.Get(nodeKey)
@@ -93,5 +141,36 @@ func RestartNode(nodeKey string) {
 		NodeLeave(client)
 		node.UnLock(nodeKey)
 		_ = client.SendRunCMD("restart")
+		return
+	}
+	publishNodeCommand(nodeKey, "restart")
+}
+
+// nodeCommand 广播到其他控制台实例的节点指令
+type nodeCommand struct {
+	NodeKey string `json:"nodeKey"`
+	Operate string `json:"operate"`
+}
+
+func publishNodeCommand(nodeKey, operate string) {
+	data, _ := json.Marshal(&nodeCommand{NodeKey: nodeKey, Operate: operate})
+	if err := replica.Publish(replica.EventNodeCommand, string(data)); err != nil {
+		log.Warn("publish node command error:", err)
+	}
+}
+
+func onNodeCommand(data string) {
+	c := new(nodeCommand)
+	if err := json.Unmarshal([]byte(data), c); err != nil {
+		return
+	}
+	if _, has := clientManager.Get(c.NodeKey); !has {
+		return
+	}
+	switch c.Operate {
+	case "stop":
+		StopNode(c.NodeKey)
+	case "restart":
+		RestartNode(c.NodeKey)
 	}
 }
@@ -2,6 +2,8 @@ package console
 
 import (
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	response_cache "github.com/eolinker/goku-api-gateway/console/module/response-cache"
 	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 )
@@ -11,11 +13,19 @@ var(
 	callbacksInit = NewRegister()
 )
 
+func init() {
+	AddRegisterFunc(cmd.PluginError, OnPluginErrors)
+	AddRegisterFunc(cmd.Monitor, OnTraffic)
+	AddRegisterFunc(cmd.ConfigAck, OnConfigAck)
+}
+
 func doRegister()*Register{
 
 	r:=callbacksInit
 	callbacksInit = nil
 	versionConfig.AddCallback(OnConfigChange)
+	response_cache.AddPurgeCallback(PurgeCache)
+	replica.Subscribe(replica.EventNodeCommand, onNodeCommand)
 	return r
 }
 func AddRegisterHandler(code cmd.Code,handler CodeHandler)  {
@@ -0,0 +1,21 @@
+package node
+
+import (
+	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	goku_log "github.com/eolinker/goku-api-gateway/goku-log"
+	response_cache "github.com/eolinker/goku-api-gateway/node/response-cache"
+)
+
+//PurgeCache 清除接口响应缓存，清除失败不影响与控制台的连接
+func PurgeCache(code cmd.Code, data []byte) error {
+	info, err := cmd.DecodeCachePurge(data)
+	if err != nil {
+		goku_log.Warn("decode cache purge error:", err)
+		return nil
+	}
+	err = response_cache.Purge(info.APIID, info.Key)
+	if err != nil {
+		goku_log.Warn("purge response cache of api ", info.APIID, " error:", err)
+	}
+	return nil
+}
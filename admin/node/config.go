@@ -2,6 +2,8 @@ package node
 
 import (
 	"github.com/eolinker/goku-api-gateway/admin/cmd"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 )
 
 
@@ -13,5 +15,20 @@ func (c *TcpConsole)OnConfigChange(code cmd.Code,data []byte) error {
 	}
 	c.lastConfig.Set(conf)
 	c.listener.Call(conf)
+	c.sendConfigAck(conf)
 	return nil
+}
+
+// sendConfigAck 向控制台回报已应用的配置版本
+func (c *TcpConsole) sendConfigAck(conf *config.GokuConfig) {
+	if conf == nil || conf.VersionID == 0 {
+		return
+	}
+	data, err := cmd.EncodeConfigAck(conf.VersionID)
+	if err != nil {
+		return
+	}
+	if err = c.conn.Send(cmd.ConfigAck, data); err != nil {
+		log.Warn("send config ack error:", err)
+	}
 }
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,12 +12,17 @@ import (
 	"github.com/eolinker/goku-api-gateway/common/listener"
 	"github.com/eolinker/goku-api-gateway/common/manager"
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/node/console"
 )
 
+// reRegisterInterval 重新注册被拒绝后的等待时间
+const reRegisterInterval = time.Second * 3
+
 type TcpConsole struct {
 	conn       *cmd.Connect
-	addr       string
+	addrs      []string
+	current    int
 	lock       sync.Mutex
 	instance   string
 	register   *Register
@@ -35,6 +41,24 @@ func (c *TcpConsole) SendMonitor(data []byte) error {
 
 }
 
+//SendTraffic 上报流量统计
+func (c *TcpConsole) SendTraffic(report *config.TrafficReport) error {
+	data, err := cmd.EncodeTraffic(report)
+	if err != nil {
+		return err
+	}
+	return c.SendMonitor(data)
+}
+
+//SendPluginErrors 上报插件加载错误
+func (c *TcpConsole) SendPluginErrors(errs map[string]string) error {
+	data, err := cmd.EncodePluginErrors(errs)
+	if err != nil {
+		return err
+	}
+	return c.conn.Send(cmd.PluginError, data)
+}
+
 func (c *TcpConsole) GetConfig() (*config.GokuConfig, error) {
 	conf, b := c.lastConfig.Get()
 	if b {
@@ -55,9 +79,10 @@ func (c *TcpConsole) AddListen(callback console.ConfigCallbackFunc) {
 	})
 }
 
+//NewConsole 创建控制台连接，addr可为逗号分隔的多个控制台地址，连接断开时依次切换
 func NewConsole(addr string, instance string) *TcpConsole {
 	c := &TcpConsole{
-		addr:     addr,
+		addrs:    splitAddrs(addr),
 		instance: instance,
 		conn:     nil,
 		register: NewRegister(),
@@ -68,42 +93,63 @@ func NewConsole(addr string, instance string) *TcpConsole {
 	c.register.RegisterFunc(cmd.Config, c.OnConfigChange)
 	c.register.RegisterFunc(cmd.Restart, Restart)
 	c.register.RegisterFunc(cmd.Stop, Stop)
+	c.register.RegisterFunc(cmd.CachePurge, PurgeCache)
 
 	return c
 }
 
-func connect(addr string) net.Conn {
+func splitAddrs(addr string) []string {
+	addrs := make([]string, 0)
+	for _, a := range strings.Split(addr, ",") {
+		a = strings.TrimSpace(a)
+		if a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+// connect 从第next个地址开始依次连接控制台，所有地址都连接失败后按退避时间重试
+func connect(addrs []string, next int) (net.Conn, int) {
 	sleeps := []time.Duration{time.Second * 0, time.Second * 1, time.Second * 5, time.Second * 10}
 	maxSleep := sleeps[len(sleeps)-1]
 	retry := 0
 
 	for {
-		if retry > 0 {
-			if retry > len(sleeps)-1 {
+		if retry > 0 && retry%len(addrs) == 0 {
+			round := retry / len(addrs)
+			if round > len(sleeps)-1 {
 				time.Sleep(maxSleep)
 			} else {
-				time.Sleep(sleeps[retry])
+				time.Sleep(sleeps[round])
 			}
 		}
-		conn, err := net.Dial("tcp", addr)
+		index := (next + retry) % len(addrs)
+		retry++
+		conn, err := net.Dial("tcp", addrs[index])
 		if err != nil {
 			continue
 		}
-		return conn
+		return conn, index
 	}
 }
 
 func (c *TcpConsole) RegisterToConsole() (*config.GokuConfig, error) {
+	if len(c.addrs) == 0 {
+		return nil, ErrorNoConsoleAddress
+	}
 	data, err := cmd.EncodeRegister(c.instance)
 	if err != nil {
 		return nil, err
 	}
 	for {
 
-		conn := connect(c.addr)
+		conn, index := connect(c.addrs, c.current)
+		c.current = index
 		e := cmd.SendFrame(conn, cmd.NodeRegister, data)
 		if e != nil {
 			conn.Close()
+			c.current++
 			continue
 		}
 
@@ -111,6 +157,7 @@ func (c *TcpConsole) RegisterToConsole() (*config.GokuConfig, error) {
 
 		if err != nil {
 			conn.Close()
+			c.current++
 			continue
 		}
 
@@ -136,10 +183,13 @@ func (c *TcpConsole) RegisterToConsole() (*config.GokuConfig, error) {
 		}
 
 		c.conn = cmd.NewConnect(conn)
+		c.sendConfigAck(result.Config)
 
 		return result.Config, nil
 	}
 }
+
+//Listen 监听控制台指令，连接断开后重新注册到控制台（多个地址时先尝试下一个），并应用最新配置
 func (c *TcpConsole) Listen() {
 
 	c.listenOnce.Do(
@@ -147,13 +197,29 @@ func (c *TcpConsole) Listen() {
 			go func() {
 				for {
 					c.listenRead()
-					c.RegisterToConsole()
+					c.current++
+					c.reRegister()
 				}
 			}()
 		})
 
 }
 
+// reRegister 重新注册直到成功，原控制台实例的会话尚未释放时会被拒绝，需等待后重试
+func (c *TcpConsole) reRegister() {
+	for {
+		conf, err := c.RegisterToConsole()
+		if err == nil {
+			c.lastConfig.Set(conf)
+			c.listener.Call(conf)
+			return
+		}
+		log.Warn("register to console error:", err)
+		c.current++
+		time.Sleep(reRegisterInterval)
+	}
+}
+
 func (c *TcpConsole) listenRead() {
 	defer c.conn.Close()
 	for {
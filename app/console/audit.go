@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/module/api"
+	"github.com/eolinker/goku-api-gateway/console/module/audit"
+	"github.com/eolinker/goku-api-gateway/console/module/auth"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/console/module/plugin"
+	"github.com/eolinker/goku-api-gateway/console/module/strategy"
+)
+
+var errAuditEntityNotFound = errors.New("entity not found")
+
+// result 将模块中(bool, 结果, error)形式的返回值转换为审计快照
+func result(ok bool, v interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errAuditEntityNotFound
+	}
+	return v, nil
+}
+
+func loadAPI(id string) (interface{}, error) {
+	apiID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	return result(api.GetAPIInfo(apiID))
+}
+
+func loadStrategy(id string) (interface{}, error) {
+	return result(strategy.GetStrategyInfo(id))
+}
+
+func loadAuth(id string) (interface{}, error) {
+	return result(auth.GetAuthInfo(id))
+}
+
+func loadBalance(name string) (interface{}, error) {
+	return balance.Get(name)
+}
+
+func loadPlugin(name string) (interface{}, error) {
+	return result(plugin.GetPluginInfo(name))
+}
+
+func loadStrategyPlugins(id string) (interface{}, error) {
+	return result(strategy.GetStrategyPluginList(id, "", 0))
+}
+
+func loadAPIPlugins(id string) (interface{}, error) {
+	return result(api.GetAllAPIPluginInStrategy(id))
+}
+
+// auditWatch 关联修改类接口与审计实体，未关联的修改类接口只记录请求参数
+func auditWatch() {
+	for path, key := range map[string]string{
+		"/apis/add":              "apiID",
+		"/apis/edit":             "apiID",
+		"/apis/copy":             "apiID",
+		"/apis/batchEditGroup":   "apiIDList",
+		"/apis/batchEditBalance": "apiIDList",
+		"/apis/batchDelete":      "apiIDList",
+	} {
+		audit.Watch(path, "api", key, loadAPI)
+	}
+	for path, key := range map[string]string{
+		"/strategy/add":            "strategyID",
+		"/strategy/edit":           "strategyID",
+		"/strategy/copy":           "strategyID",
+		"/strategy/delete":         "strategyID",
+		"/strategy/batchEditGroup": "strategyIDList",
+		"/strategy/batchDelete":    "strategyIDList",
+		"/strategy/batchStart":     "strategyIDList",
+		"/strategy/batchStop":      "strategyIDList",
+	} {
+		audit.Watch(path, "strategy", key, loadStrategy)
+	}
+	audit.Watch("/auth/editInfo", "auth", "strategyID", loadAuth)
+	for path, key := range map[string]string{
+		"/balance/add":         "balanceName",
+		"/balance/edit":        "balanceName",
+		"/balance/delete":      "balanceName",
+		"/balance/batchDelete": "balanceNames",
+	} {
+		audit.Watch(path, "balance", key, loadBalance)
+	}
+	for path, key := range map[string]string{
+		"/plugin/add":        "pluginName",
+		"/plugin/edit":       "pluginName",
+		"/plugin/delete":     "pluginName",
+		"/plugin/start":      "pluginName",
+		"/plugin/stop":       "pluginName",
+		"/plugin/batchStart": "pluginNameList",
+		"/plugin/batchStop":  "pluginNameList",
+	} {
+		audit.Watch(path, "plugin", key, loadPlugin)
+	}
+	for _, path := range []string{"/plugin/strategy/addPluginToStrategy", "/plugin/strategy/edit", "/plugin/strategy/batchStart", "/plugin/strategy/batchStop", "/plugin/strategy/batchDelete"} {
+		audit.Watch(path, "strategyPlugin", "strategyID", loadStrategyPlugins)
+	}
+	for _, path := range []string{"/plugin/api/addPluginToApi", "/plugin/api/edit", "/plugin/api/batchStart", "/plugin/api/batchStop", "/plugin/api/batchDelete"} {
+		audit.Watch(path, "apiPlugin", "strategyID", loadAPIPlugins)
+	}
+	for _, path := range []string{"/version/config/add", "/version/config/basic/edit", "/version/config/delete", "/version/config/publish", "/version/config/rollback", "/version/config/canary/start"} {
+		audit.Watch(path, audit.EntityVersion, "versionID", nil)
+	}
+	for _, path := range []string{"/version/config/publish/approve", "/version/config/publish/reject", "/version/config/publish/cancel"} {
+		audit.Watch(path, audit.EntityVersion, "requestID", nil)
+	}
+	for _, path := range []string{"/version/config/canary/promote", "/version/config/canary/rollback"} {
+		audit.Watch(path, audit.EntityVersion, "canaryID", nil)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/eolinker/goku-api-gateway/console/module/bundle"
+)
+
+const bundleUsage = `Usage:
+  goku-console [-c config] bundle export [-format yaml|json] [-o file] [-include-secrets]
+  goku-console [-c config] bundle apply -f file [-dry-run]`
+
+//runBundle 执行bundle子命令，导出或应用控制台配置包
+func runBundle(args []string) error {
+	if len(args) == 0 {
+		return errors.New(bundleUsage)
+	}
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("export", flag.ContinueOnError)
+		format := fs.String("format", bundle.FormatYAML, "bundle format, yaml or json")
+		output := fs.String("o", "", "output file, default stdout")
+		includeSecrets := fs.Bool("include-secrets", false, "export decrypted auth credentials instead of placeholders")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		b, err := bundle.Export(*includeSecrets)
+		if err != nil {
+			return err
+		}
+		data, err := bundle.Encode(b, *format)
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		// 配置包中可能包含解密后的鉴权凭证
+		return ioutil.WriteFile(*output, data, 0600)
+	case "apply":
+		fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+		file := fs.String("f", "", "bundle file in yaml or json")
+		dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *file == "" {
+			return errors.New(bundleUsage)
+		}
+		data, err := ioutil.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		b, err := bundle.Decode(data)
+		if err != nil {
+			return err
+		}
+		plan, err := bundle.Apply(b, 0, *dryRun)
+		if err != nil {
+			return err
+		}
+		fmt.Print(plan.String())
+		if !*dryRun && plan.HasChanges() {
+			fmt.Println("Bundle applied. Publish a new version to deliver it to the nodes.")
+		}
+		return nil
+	}
+	return errors.New(bundleUsage)
+}
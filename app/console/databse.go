@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/eolinker/goku-api-gateway/common/database"
 	"github.com/eolinker/goku-api-gateway/common/pdao"
+	console_mysql "github.com/eolinker/goku-api-gateway/server/dao/console-mysql"
 	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
 	"github.com/eolinker/goku-api-gateway/server/entity"
 )
@@ -24,7 +25,8 @@ func (c *ClusterDatabaseConfig) GetSource() string {
 	switch c.Driver {
 	case database.MysqlDriver:
 		{
-			return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8", c.UserName, c.Password, c.Host, c.Port, c.Database)
+			// clientFoundRows使更新语句返回匹配的行数，与sqlite3保持一致
+			return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&clientFoundRows=true", c.UserName, c.Password, c.Host, c.Port, c.Database)
 		}
 	case database.Sqlite3Driver:
 		{
@@ -41,6 +43,7 @@ func (c *ClusterDatabaseConfig) GetSource() string {
 //InitDatabase 初始化数据库
 func InitDatabase() {
 	console_sqlite3.DoRegister()
+	console_mysql.DoRegister()
 
 	def, err := getDefaultDatabase()
 	if err != nil {
@@ -51,6 +54,11 @@ func InitDatabase() {
 	if e != nil {
 		panic(e)
 	}
+	// 升级数据库时会加密已有凭证，需要先确认主密钥与其他控制台一致
+	err = checkMasterKey(db)
+	if err != nil {
+		panic(err)
+	}
 	err =pdao.Build(c.GetDriver(),db)
 	if err!=nil{
 		panic(err)
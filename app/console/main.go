@@ -5,7 +5,9 @@ import (
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 
 	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/common/database"
 	"github.com/eolinker/goku-api-gateway/common/general"
+	"github.com/eolinker/goku-api-gateway/common/secret"
 	"github.com/eolinker/goku-api-gateway/console/module/account"
 	"github.com/eolinker/goku-api-gateway/utils"
 )
@@ -31,7 +33,13 @@ func main() {
 		log.Panic(err)
 		return
 	}
-
+	// 初始化主密钥，用于加密保存鉴权凭证，需要在数据库升级前加载
+	// 使用mysql时可能部署多个控制台，不自动生成主密钥，各控制台需配置相同的主密钥
+	generate := conf.MastValue("db_type", "sqlite3") != database.MysqlDriver
+	if err := secret.LoadMasterKey(conf.MastValue("master_key_file", "./config/master.key"), generate); err != nil {
+		log.Panic(err)
+		return
+	}
 
 	// 初始化db
 	InitDatabase()
@@ -39,6 +47,13 @@ func main() {
 
 	// 其他需要初始化的模块
 	_ = general.General()
+	// 配置包子命令，执行后退出
+	if flag.NArg() > 0 && flag.Arg(0) == "bundle" {
+		if err := runBundle(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	// 检测是否安装
 	s, err := account.CheckSuperAdminCount()
 	if err != nil {
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+)
+
+//checkMasterKey 校验本地主密钥与数据库中记录的校验值是否一致，避免多个控制台使用不同的主密钥
+func checkMasterKey(db *sql.DB) error {
+	check, err := secret.KeyCheck()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS goku_master_key_check (id INT NOT NULL PRIMARY KEY, keyCheck VARCHAR(128) NOT NULL, createTime VARCHAR(32) NOT NULL)")
+	if err != nil {
+		return err
+	}
+	stored, err := getKeyCheck(db)
+	if err == sql.ErrNoRows {
+		now := time.Now().Format("2006-01-02 15:04:05")
+		if _, err = db.Exec("INSERT INTO goku_master_key_check (id,keyCheck,createTime) VALUES (1,?,?)", check, now); err == nil {
+			return nil
+		}
+		// 其他控制台可能同时写入了校验值
+		stored, err = getKeyCheck(db)
+	}
+	if err != nil {
+		return err
+	}
+	if stored != check {
+		return errors.New("master key does not match the key used by other consoles, please set the same GOKU_MASTER_KEY or master_key_file")
+	}
+	return nil
+}
+
+func getKeyCheck(db *sql.DB) (string, error) {
+	check := ""
+	err := db.QueryRow("SELECT keyCheck FROM goku_master_key_check WHERE id = 1").Scan(&check)
+	return check, err
+}
@@ -5,18 +5,30 @@ import (
 
 	account_default "github.com/eolinker/goku-api-gateway/console/account"
 	"github.com/eolinker/goku-api-gateway/console/controller/account"
+	"github.com/eolinker/goku-api-gateway/console/controller/alert"
+	"github.com/eolinker/goku-api-gateway/console/controller/audit"
 	"github.com/eolinker/goku-api-gateway/console/controller/api"
 	"github.com/eolinker/goku-api-gateway/console/controller/auth"
 	"github.com/eolinker/goku-api-gateway/console/controller/balance"
+	"github.com/eolinker/goku-api-gateway/console/controller/bundle"
 	"github.com/eolinker/goku-api-gateway/console/controller/cluster"
 	config_log "github.com/eolinker/goku-api-gateway/console/controller/config-log"
 	"github.com/eolinker/goku-api-gateway/console/controller/discovery"
 	"github.com/eolinker/goku-api-gateway/console/controller/gateway"
 	"github.com/eolinker/goku-api-gateway/console/controller/monitor"
 	"github.com/eolinker/goku-api-gateway/console/controller/node"
+	open_api "github.com/eolinker/goku-api-gateway/console/controller/open-api"
 	"github.com/eolinker/goku-api-gateway/console/controller/plugin"
 	"github.com/eolinker/goku-api-gateway/console/controller/project"
+	proto_descriptor "github.com/eolinker/goku-api-gateway/console/controller/proto-descriptor"
+	rate_limit "github.com/eolinker/goku-api-gateway/console/controller/rate-limit"
+	response_cache "github.com/eolinker/goku-api-gateway/console/controller/response-cache"
+	"github.com/eolinker/goku-api-gateway/console/controller/script"
+	size_limit "github.com/eolinker/goku-api-gateway/console/controller/size-limit"
 	"github.com/eolinker/goku-api-gateway/console/controller/strategy"
+	"github.com/eolinker/goku-api-gateway/console/controller/traffic"
+	"github.com/eolinker/goku-api-gateway/console/controller/validation"
+	audit_module "github.com/eolinker/goku-api-gateway/console/module/audit"
 	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
 )
 
@@ -25,6 +37,9 @@ var (
 )
 
 func router() http.Handler {
+	// 记录所有修改类请求的审计日志
+	accountFactory.SetRecorder(audit_module.NewRecorder())
+	auditWatch()
 	s := goku_handler.NewGokuServer(accountFactory)
 
 	// 账号管理模块
@@ -59,6 +74,15 @@ func router() http.Handler {
 	// 监控模块
 	s.Add("/monitor/module/config", monitor.NewHandlers())
 
+	// 流量统计模块
+	s.Add("/monitor/traffic", traffic.NewHandlers())
+
+	// 告警模块
+	s.Add("/alert", alert.NewHandlers())
+
+	// 审计日志模块
+	s.Add("/audit", audit.NewHandlers())
+
 	// 节点模块
 	s.Add("/node", node.NewNodeHandlers())
 	s.Add("/node/group", node.NewGroupHandlers())
@@ -66,9 +90,33 @@ func router() http.Handler {
 	// 插件模块
 	s.Add("/plugin", plugin.NewHandlers())
 
+	// protobuf描述文件模块
+	s.Add("/proto/descriptor", proto_descriptor.NewHandlers())
+
+	// OpenAPI文档模块
+	s.Add("/openapi", open_api.NewHandlers())
+
+	// 配置包导入导出模块
+	s.Add("/bundle", bundle.NewHandlers())
+
 	// 项目模块
 	s.Add("/project", project.NewHandlers())
 
+	// 限流模块
+	s.Add("/rateLimit", rate_limit.NewHandlers())
+
+	// 接口响应缓存模块
+	s.Add("/apis/cache", response_cache.NewHandlers())
+
+	// 大小限制模块
+	s.Add("/sizeLimit", size_limit.NewHandlers())
+
+	// 接口请求校验模块
+	s.Add("/apis/validation", validation.NewHandlers())
+
+	// 脚本模块
+	s.Add("/script", script.NewHandlers())
+
 	// 策略模块
 	s.Add("/strategy", strategy.NewStrategyHandlers())
 	s.Add("/strategy/group", strategy.NewGroupHandlers())
@@ -3,15 +3,17 @@ package main
 import "flag"
 
 //ParseFlag 获取命令行参数
-func ParseFlag() (instance string, admin string, staticConfigFile string, isDebug bool) {
-	adminP := flag.String("admin", "", "Please provide a valid host!")
+func ParseFlag() (instance string, admin string, staticConfigFile string, checkPlugins string, trustedProxy string, isDebug bool) {
+	adminP := flag.String("admin", "", "Please provide a valid host! Multiple console addresses are separated by comma for failover")
 	instanceP := flag.String("instance", "", "Please provide a valid instance!")
-	staticConfigFileP := flag.String("config", "", "Please provide a config file")
+	staticConfigFileP := flag.String("config", "", "Please provide a config file, or a directory of json/yaml config fragments. Reloaded when changed or on SIGHUP")
+	trustedProxyP := flag.String("trusted-proxy", "", "Trusted proxy IPs or CIDRs separated by comma, X-Forwarded-For and X-Real-Ip are only used for requests from them")
+	checkPluginsP := flag.String("check-plugin", "", "Check whether the plugins can be loaded by this node, separated by comma, \"all\" for every plugin in ./plugin")
 
 	isDebugP := flag.Bool("debug", false, "")
 
 	flag.Parse()
 
-	return *instanceP, *adminP, *staticConfigFileP, *isDebugP
+	return *instanceP, *adminP, *staticConfigFileP, *checkPluginsP, *trustedProxyP, *isDebugP
 
 }
@@ -3,21 +3,37 @@ package main
 import (
 	"flag"
 	"github.com/eolinker/goku-api-gateway/admin/node"
-	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
+	file_console "github.com/eolinker/goku-api-gateway/node/file-console"
+	rate_limit "github.com/eolinker/goku-api-gateway/node/rate-limit"
 	"github.com/eolinker/goku-api-gateway/node/server"
+	"os"
 	"runtime"
+	"strings"
 )
 
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
-	instance, admin, staticConfigFile, isDebug := ParseFlag()
+	instance, admin, staticConfigFile, plugins, trustedProxy, isDebug := ParseFlag()
 
 	if isDebug {
 		log.StartDebug()
 	}
 
+	if trustedProxy != "" {
+		if err := rate_limit.SetTrustedProxies(strings.Split(trustedProxy, ",")); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if plugins != "" {
+		if checkPlugins(plugins) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if admin != "" && instance != ""{
 
 		console := node.NewConsole(admin,instance)
@@ -29,13 +45,10 @@ func main() {
 
  	if staticConfigFile != "" {
 
-		// 从静态文件启动
-		c, err := config.ReadConfig(staticConfigFile)
-		if err != nil {
-			log.Panic("read config from :", staticConfigFile, "\t", err)
-		}
+		// 从静态文件启动，文件变化或收到SIGHUP时重新加载
 		ser := server.NewServer()
-		log.Fatal(ser.ServerWidthConfig(c))
+		log.Fatal(ser.ServerWidthConsole(file_console.NewConsole(staticConfigFile)))
+		return
 	}
 
 	flag.Usage()
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+)
+
+// checkPlugins 检查插件能否被当前节点加载，返回失败的数量
+func checkPlugins(names string) int {
+	list := make([]string, 0)
+	if names == "all" {
+		list = append(list, plugin_loader.Builtins()...)
+		files, err := ioutil.ReadDir("plugin")
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Println("read plugin dir error:", err)
+			return 1
+		}
+		for _, f := range files {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), ".so") {
+				list = append(list, strings.TrimSuffix(f.Name(), ".so"))
+			}
+		}
+	} else {
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				list = append(list, name)
+			}
+		}
+	}
+
+	failed := 0
+	for _, name := range list {
+		if err := plugin_loader.Check(name); err != nil {
+			failed++
+			fmt.Printf("[FAIL] %s: %s\n", name, err.Error())
+			continue
+		}
+		if plugin_loader.IsBuiltin(name) {
+			fmt.Printf("[OK] %s (builtin)\n", name)
+			continue
+		}
+		fmt.Printf("[OK] %s\n", name)
+	}
+	return failed
+}
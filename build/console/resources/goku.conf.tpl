@@ -1,2 +1,15 @@
 listen_port: 7000
 admin_bind: 127.0.0.1:7005
+master_key_file: ./config/master.key
+# 多个控制台实例共享同一mysql数据库时使用以下配置，默认为sqlite3(db_path)
+# 使用mysql时不会自动生成主密钥，各实例需通过GOKU_MASTER_KEY或master_key_file配置相同的主密钥
+# db_type: mysql
+# db_host: 127.0.0.1
+# db_port: 3306
+# db_user: goku
+# db_password: goku
+# db_name: goku
+# 节点上报的流量统计保留天数
+# traffic_retention_days: 7
+# 发布版本前需由其他用户审批
+# version_publish_approval: true
@@ -0,0 +1,389 @@
+-- ----------------------------
+-- 控制台MySQL数据库结构，与goku_ce.sql对应，后续版本的表结构变更由升级程序执行
+-- ----------------------------
+-- ----------------------------
+-- Table structure for goku_admin
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_admin` (
+  `userID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `loginCall` VARCHAR(255) NOT NULL,
+  `loginPassword` VARCHAR(255) NOT NULL,
+  `userType` INT NOT NULL DEFAULT 0,
+  `groupID` INT NOT NULL DEFAULT 0,
+  `remark` VARCHAR(255),
+  `permissions` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_balance
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_balance` (
+  `balanceID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `balanceName` VARCHAR(255) NOT NULL,
+  `serviceName` VARCHAR(255) NOT NULL,
+  `balanceConfig` TEXT,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `balanceDesc` VARCHAR(255),
+  `defaultConfig` TEXT NOT NULL,
+  `clusterConfig` TEXT NOT NULL,
+  `appName` VARCHAR(255) NOT NULL DEFAULT '',
+  `static` TEXT,
+  `staticCluster` TEXT,
+  INDEX `balanceName` (`balanceName`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_cluster
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_cluster` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `name` VARCHAR(20) NOT NULL,
+  `title` VARCHAR(50) NOT NULL,
+  `note` VARCHAR(255),
+  `db` TEXT,
+  `redis` TEXT,
+  INDEX `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_config_log
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_config_log` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `name` VARCHAR(20) NOT NULL,
+  `enable` INT NOT NULL DEFAULT 1,
+  `dir` VARCHAR(255) NOT NULL,
+  `file` VARCHAR(255) NOT NULL,
+  `period` VARCHAR(10) NOT NULL,
+  `level` VARCHAR(10) NOT NULL,
+  `fields` TEXT NOT NULL,
+  `expire` INT NOT NULL DEFAULT 3,
+  UNIQUE INDEX `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_conn_plugin_api
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_conn_plugin_api` (
+  `connID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `apiID` INT NOT NULL,
+  `pluginName` VARCHAR(255) NOT NULL,
+  `pluginConfig` TEXT,
+  `strategyID` VARCHAR(255) NOT NULL,
+  `pluginInfo` TEXT,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `pluginStatus` INT,
+  `updateTag` VARCHAR(32),
+  `updaterID` INT NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_conn_plugin_strategy
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_conn_plugin_strategy` (
+  `connID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `strategyID` VARCHAR(255) NOT NULL,
+  `pluginName` VARCHAR(255) NOT NULL,
+  `pluginConfig` TEXT,
+  `pluginInfo` TEXT,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `pluginStatus` INT,
+  `updateTag` VARCHAR(32),
+  `updaterID` INT NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_conn_strategy_api
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_conn_strategy_api` (
+  `connID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `strategyID` VARCHAR(255) NOT NULL,
+  `apiID` INT NOT NULL,
+  `apiMonitorStatus` INT NOT NULL DEFAULT 0,
+  `strategyMonitorStatus` INT NOT NULL DEFAULT 0,
+  `target` VARCHAR(255),
+  `updateTime` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway` (
+  `id` INT NOT NULL,
+  `successCode` VARCHAR(255) NOT NULL,
+  `nodeUpdatePeriod` INT NOT NULL,
+  `monitorUpdatePeriod` INT NOT NULL,
+  `alertStatus` INT NOT NULL,
+  `alertPeriodType` INT NOT NULL,
+  `alertAddress` VARCHAR(255),
+  `alertLogPath` VARCHAR(255),
+  `sender` VARCHAR(255),
+  `senderPassword` VARCHAR(255),
+  `smtpAddress` VARCHAR(255),
+  `smtpPort` INT NOT NULL,
+  `smtpProtocol` INT NOT NULL,
+  `receiverList` VARCHAR(255),
+  `monitorTimeout` INT NOT NULL,
+  `apiAlertInfo` TEXT,
+  `nodeAlertInfo` TEXT,
+  `redisAlertInfo` TEXT,
+  `versionID` INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_alert
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_alert` (
+  `alertID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `requestURL` VARCHAR(255) NOT NULL,
+  `targetServer` VARCHAR(255) NOT NULL,
+  `alertPeriodType` INT NOT NULL,
+  `alertCount` INT NOT NULL,
+  `updateTime` TEXT,
+  `targetURL` VARCHAR(255) NOT NULL,
+  `clusterName` VARCHAR(255),
+  `nodeIP` VARCHAR(255)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_api
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_api` (
+  `apiID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `groupID` INT NOT NULL,
+  `projectID` INT NOT NULL,
+  `requestURL` VARCHAR(255) NOT NULL,
+  `apiName` VARCHAR(255) NOT NULL,
+  `requestMethod` VARCHAR(255) NOT NULL,
+  `targetServer` VARCHAR(255),
+  `targetURL` VARCHAR(255),
+  `targetMethod` VARCHAR(255),
+  `isFollow` VARCHAR(32) NOT NULL,
+  `stripPrefix` VARCHAR(32),
+  `timeout` INT,
+  `retryCount` INT,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `alertValve` INT NOT NULL DEFAULT 0,
+  `monitorStatus` INT NOT NULL DEFAULT 1,
+  `managerID` INT NOT NULL,
+  `lastUpdateUserID` INT NOT NULL,
+  `createUserID` INT NOT NULL,
+  `balanceName` VARCHAR(255),
+  `protocol` VARCHAR(20),
+  `stripSlash` VARCHAR(32),
+  `apiType` INT NOT NULL DEFAULT 0,
+  `responseDataType` VARCHAR(32) NOT NULL DEFAULT 'origin',
+  `linkApis` TEXT,
+  `staticResponse` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_api_group
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_api_group` (
+  `groupID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `projectID` INT NOT NULL,
+  `groupName` VARCHAR(255) NOT NULL,
+  `groupPath` VARCHAR(255),
+  `groupDepth` VARCHAR(255),
+  `parentGroupID` INT NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_permission_group
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_permission_group` (
+  `groupID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `groupName` VARCHAR(255) NOT NULL,
+  `permissions` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_project
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_project` (
+  `projectID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `projectName` VARCHAR(255) NOT NULL,
+  `createTime` TEXT,
+  `updateTime` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_strategy
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_strategy` (
+  `strategyID` VARCHAR(32) NOT NULL,
+  `strategyName` VARCHAR(255) NOT NULL,
+  `updateTime` TEXT,
+  `createTime` TEXT,
+  `auth` VARCHAR(255),
+  `groupID` INT NOT NULL DEFAULT 0,
+  `monitorStatus` INT NOT NULL DEFAULT 0,
+  `enableStatus` INT NOT NULL DEFAULT 0,
+  `strategyType` INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (`strategyID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_strategy_group
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_strategy_group` (
+  `groupID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `groupName` VARCHAR(255) NOT NULL,
+  `groupType` INT NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_gateway_version_config
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_gateway_version_config` (
+  `versionID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `name` TEXT NOT NULL,
+  `version` TEXT,
+  `remark` TEXT,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `publishTime` TEXT,
+  `config` LONGTEXT,
+  `balanceConfig` LONGTEXT,
+  `discoverConfig` LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_monitor_cluster
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_monitor_cluster` (
+  `recordID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `strategyID` VARCHAR(20) NOT NULL,
+  `apiID` INT NOT NULL,
+  `clusterID` INT NOT NULL,
+  `hour` INT NOT NULL,
+  `gatewayRequestCount` INT NOT NULL,
+  `gatewaySuccessCount` INT NOT NULL,
+  `gatewayStatus2xxCount` INT NOT NULL,
+  `gatewayStatus4xxCount` INT NOT NULL,
+  `gatewayStatus5xxCount` INT NOT NULL,
+  `proxyRequestCount` INT NOT NULL,
+  `proxySuccessCount` INT NOT NULL,
+  `proxyStatus2xxCount` INT NOT NULL,
+  `proxyStatus4xxCount` INT NOT NULL,
+  `proxyStatus5xxCount` INT NOT NULL,
+  `proxyTimeoutCount` INT NOT NULL,
+  `updateTime` TEXT NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_node_group
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_node_group` (
+  `groupID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `groupName` VARCHAR(255) NOT NULL,
+  `groupType` INT NOT NULL,
+  `clusterID` INT NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_node_info
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_node_info` (
+  `nodeID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `nodeIP` VARCHAR(255) NOT NULL,
+  `updateStatus` INT NOT NULL DEFAULT 0,
+  `createTime` TEXT,
+  `updateTime` TEXT,
+  `groupID` INT NOT NULL DEFAULT 0,
+  `nodeName` VARCHAR(255) NOT NULL,
+  `nodePort` VARCHAR(255),
+  `nodeStatus` INT NOT NULL,
+  `version` VARCHAR(255),
+  `sshPort` VARCHAR(255) DEFAULT '22',
+  `userName` VARCHAR(255),
+  `password` VARCHAR(255),
+  `gatewayPath` VARCHAR(255),
+  `key` TEXT,
+  `authMethod` INT NOT NULL DEFAULT 0,
+  `clusterID` INT NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_plugin
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_plugin` (
+  `pluginID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `pluginName` VARCHAR(255) NOT NULL,
+  `chineseName` VARCHAR(255),
+  `pluginStatus` INT NOT NULL,
+  `pluginPriority` INT NOT NULL,
+  `pluginConfig` TEXT,
+  `pluginInfo` TEXT,
+  `isStop` INT NOT NULL,
+  `pluginType` INT NOT NULL,
+  `official` VARCHAR(255) NOT NULL,
+  `pluginDesc` VARCHAR(255),
+  `version` VARCHAR(255) NOT NULL,
+  `isCheck` INT NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_service_config
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_service_config` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `name` VARCHAR(255) NOT NULL,
+  `default` INT,
+  `driver` VARCHAR(20) NOT NULL,
+  `desc` TEXT NOT NULL,
+  `config` TEXT NOT NULL,
+  `clusterConfig` TEXT NOT NULL,
+  `healthCheck` INT NOT NULL,
+  `healthCheckPath` VARCHAR(255) NOT NULL,
+  `healthCheckPeriod` INT NOT NULL,
+  `healthCheckCode` VARCHAR(255) NOT NULL,
+  `healthCheckTimeOut` INT NOT NULL,
+  `createTime` TEXT NOT NULL,
+  `updateTime` TEXT NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_service_discovery
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_service_discovery` (
+  `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  `name` VARCHAR(30),
+  `type` VARCHAR(20),
+  `remark` VARCHAR(500),
+  `config` TEXT,
+  `default` VARCHAR(255),
+  `createTime` TEXT,
+  `updateTime` TEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_table_update_record
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_table_update_record` (
+  `name` VARCHAR(64) NOT NULL,
+  `updateTime` TEXT NOT NULL,
+  `tableID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Table structure for goku_version
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS `goku_version` (
+  `sol` INT NOT NULL,
+  `version` VARCHAR(32) NOT NULL,
+  PRIMARY KEY (`sol`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ----------------------------
+-- Records
+-- ----------------------------
+INSERT INTO `goku_gateway` VALUES (1, 200, 1, 30, 0, 0, NULL, NULL, NULL, NULL, NULL, 25, 0, NULL, 0, NULL, NULL, NULL, 0);
+INSERT INTO `goku_gateway_strategy` VALUES ('RGAtKBd', '开放策略', '2019-10-17 00:00:00', '2019-10-17 00:00:00', NULL, 0, 0, 0, 1);
+INSERT INTO `goku_gateway_strategy_group` VALUES (1, '开放分组', 1);
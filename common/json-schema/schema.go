@@ -0,0 +1,342 @@
+package json_schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//Schema 编译后的JSON Schema，支持draft-07常用关键字及OpenAPI 3的nullable
+type Schema struct {
+	always *bool
+	ref    string
+	c      *compiler
+
+	types    []string
+	nullable bool
+
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	minProperties        *int
+	maxProperties        *int
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+type compiler struct {
+	root interface{}
+	refs map[string]*Schema
+}
+
+//Compile 编译JSON格式的Schema
+func Compile(data []byte) (*Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return CompileValue(root, root)
+}
+
+//CompileValue 编译已解析的Schema，$ref按root解析，用于编译OpenAPI等文档中的Schema
+func CompileValue(root, value interface{}) (*Schema, error) {
+	c := &compiler{root: root, refs: make(map[string]*Schema)}
+	s, err := c.compile(value)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkRefs(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkRefs 检查只由$ref组成的循环引用，这类引用无法解析到实际的Schema
+func (c *compiler) checkRefs() error {
+	for ref := range c.refs {
+		seen := map[string]bool{ref: true}
+		for s := c.refs[ref]; s.ref != ""; s = c.refs[s.ref] {
+			if seen[s.ref] {
+				return fmt.Errorf("circular $ref %s", ref)
+			}
+			seen[s.ref] = true
+		}
+	}
+	return nil
+}
+
+func (c *compiler) resolve(ref string) (*Schema, error) {
+	if s, has := c.refs[ref]; has {
+		return s, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %s", ref)
+	}
+	node, err := Pointer(c.root, strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("unresolvable $ref %s", ref)
+	}
+	// 先占位以支持递归引用
+	s := &Schema{c: c}
+	c.refs[ref] = s
+	compiled, err := c.compile(node)
+	if err != nil {
+		return nil, err
+	}
+	*s = *compiled
+	return s, nil
+}
+
+//Pointer 按JSON Pointer获取文档中的节点
+func Pointer(root interface{}, pointer string) (interface{}, error) {
+	node := root
+	if pointer == "" || pointer == "/" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, has := n[token]
+			if !has {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+	return node, nil
+}
+
+func (c *compiler) compile(value interface{}) (*Schema, error) {
+	s := &Schema{c: c}
+	if b, ok := value.(bool); ok {
+		s.always = &b
+		return s, nil
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema must be an object or boolean")
+	}
+	if ref, ok := m["$ref"].(string); ok {
+		s.ref = ref
+		if _, err := c.resolve(ref); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	switch t := m["type"].(type) {
+	case string:
+		s.types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if name, ok := v.(string); ok {
+				s.types = append(s.types, name)
+			}
+		}
+	}
+	s.nullable, _ = m["nullable"].(bool)
+
+	var err error
+	if props, ok := m["properties"].(map[string]interface{}); ok {
+		s.properties = make(map[string]*Schema, len(props))
+		for name, p := range props {
+			if s.properties[name], err = c.compile(p); err != nil {
+				return nil, fmt.Errorf("properties.%s: %s", name, err)
+			}
+		}
+	}
+	if required, ok := m["required"].([]interface{}); ok {
+		for _, v := range required {
+			if name, ok := v.(string); ok {
+				s.required = append(s.required, name)
+			}
+		}
+	}
+	if v, has := m["additionalProperties"]; has {
+		if s.additionalProperties, err = c.compile(v); err != nil {
+			return nil, fmt.Errorf("additionalProperties: %s", err)
+		}
+	}
+	if v, has := m["items"]; has {
+		if s.items, err = c.compile(v); err != nil {
+			return nil, fmt.Errorf("items: %s", err)
+		}
+	}
+	if v, has := m["not"]; has {
+		if s.not, err = c.compile(v); err != nil {
+			return nil, fmt.Errorf("not: %s", err)
+		}
+	}
+	for name, target := range map[string]*[]*Schema{"allOf": &s.allOf, "anyOf": &s.anyOf, "oneOf": &s.oneOf} {
+		list, ok := m[name].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, v := range list {
+			sub, err := c.compile(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %s", name, i, err)
+			}
+			*target = append(*target, sub)
+		}
+	}
+
+	if enum, ok := m["enum"].([]interface{}); ok {
+		s.enum = enum
+	}
+	if v, has := m["const"]; has {
+		s.constant, s.hasConst = v, true
+	}
+
+	s.minimum = number(m["minimum"])
+	s.maximum = number(m["maximum"])
+	s.multipleOf = number(m["multipleOf"])
+	// OpenAPI 3.0 使用布尔值表示minimum/maximum是否排除边界
+	switch v := m["exclusiveMinimum"].(type) {
+	case bool:
+		if v {
+			s.exclusiveMinimum, s.minimum = s.minimum, nil
+		}
+	default:
+		s.exclusiveMinimum = number(v)
+	}
+	switch v := m["exclusiveMaximum"].(type) {
+	case bool:
+		if v {
+			s.exclusiveMaximum, s.maximum = s.maximum, nil
+		}
+	default:
+		s.exclusiveMaximum = number(v)
+	}
+
+	s.minLength = integer(m["minLength"])
+	s.maxLength = integer(m["maxLength"])
+	s.minItems = integer(m["minItems"])
+	s.maxItems = integer(m["maxItems"])
+	s.minProperties = integer(m["minProperties"])
+	s.maxProperties = integer(m["maxProperties"])
+	s.uniqueItems, _ = m["uniqueItems"].(bool)
+	s.format, _ = m["format"].(string)
+	if pattern, ok := m["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("pattern: %s", err)
+		}
+	}
+	return s, nil
+}
+
+func number(v interface{}) *float64 {
+	switch n := v.(type) {
+	case float64:
+		return &n
+	case int:
+		f := float64(n)
+		return &f
+	}
+	return nil
+}
+
+func integer(v interface{}) *int {
+	f := number(v)
+	if f == nil {
+		return nil
+	}
+	i := int(math.Floor(*f))
+	return &i
+}
+
+func (s *Schema) target() *Schema {
+	for s.ref != "" {
+		s = s.c.refs[s.ref]
+	}
+	return s
+}
+
+//Property 获取对象属性的Schema，不存在时返回nil
+func (s *Schema) Property(name string) *Schema {
+	s = s.target()
+	if s.properties == nil {
+		return nil
+	}
+	return s.properties[name]
+}
+
+//Properties 获取对象属性名
+func (s *Schema) Properties() []string {
+	s = s.target()
+	names := make([]string, 0, len(s.properties))
+	for name := range s.properties {
+		names = append(names, name)
+	}
+	return names
+}
+
+//Required 对象必需的属性名
+func (s *Schema) Required() []string {
+	return s.target().required
+}
+
+//Coerce 将query参数、请求头等字符串按Schema声明的类型转换，转换失败时返回原字符串
+func (s *Schema) Coerce(raw string) interface{} {
+	s = s.target()
+	for _, t := range s.types {
+		switch t {
+		case "integer", "number":
+			if f, err := strconv.ParseFloat(raw, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(raw); err == nil {
+				return b
+			}
+		case "array":
+			list := make([]interface{}, 0)
+			for _, v := range strings.Split(raw, ",") {
+				if s.items != nil {
+					list = append(list, s.items.Coerce(v))
+				} else {
+					list = append(list, v)
+				}
+			}
+			return list
+		case "string":
+			return raw
+		}
+	}
+	return raw
+}
@@ -0,0 +1,103 @@
+package json_schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const userSchema = `{
+  "type": "object",
+  "required": ["name", "age"],
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 2},
+    "age": {"type": "integer", "minimum": 0},
+    "email": {"type": "string", "format": "email"},
+    "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+    "parent": {"$ref": "#"}
+  }
+}`
+
+func validate(t *testing.T, schema *Schema, data string) []*Error {
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	return schema.Validate(v)
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(userSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := validate(t, schema, `{"name":"tom","age":3,"tags":["a","b"],"parent":{"name":"jim","age":30}}`); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	cases := map[string]string{
+		`{"name":"tom"}`:                               "",
+		`{"name":"t","age":3}`:                         "/name",
+		`{"name":"tom","age":1.5}`:                     "/age",
+		`{"name":"tom","age":3,"email":"x"}`:           "/email",
+		`{"name":"tom","age":3,"tags":["a","a"]}`:      "/tags",
+		`{"name":"tom","age":3,"other":1}`:             "/other",
+		`{"name":"tom","age":3,"parent":{"age":-1}}`:   "/parent",
+		`{"name":"tom","age":3,"parent":{"name":"x"}}`: "/parent",
+	}
+	for data, path := range cases {
+		errs := validate(t, schema, data)
+		if len(errs) == 0 {
+			t.Fatalf("%s: expected errors", data)
+		}
+		if errs[0].Path != path && !(path == "/parent" && len(errs[0].Path) > len(path)) {
+			t.Fatalf("%s: path %s, want %s (%v)", data, errs[0].Path, path, errs)
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	schema, err := Compile([]byte(`{"oneOf":[{"type":"string"},{"type":"integer","exclusiveMinimum":0}],"not":{"enum":["x"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for data, ok := range map[string]bool{`"a"`: true, `1`: true, `0`: false, `"x"`: false, `true`: false} {
+		if errs := validate(t, schema, data); (len(errs) == 0) != ok {
+			t.Fatalf("%s: %v", data, errs)
+		}
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	schema, err := Compile([]byte(`{"type":"object","properties":{"page":{"type":"integer"},"ids":{"type":"array","items":{"type":"integer"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := schema.Property("page").Coerce("2"); v != float64(2) {
+		t.Fatalf("page: %#v", v)
+	}
+	if v := schema.Property("ids").Coerce("1,2").([]interface{}); len(v) != 2 || v[1] != float64(2) {
+		t.Fatalf("ids: %#v", v)
+	}
+	if _, err := Compile([]byte(`{"$ref":"#/definitions/none"}`)); err == nil {
+		t.Fatal("expected unresolvable $ref error")
+	}
+}
+
+func TestCircularRef(t *testing.T) {
+	for _, data := range []string{
+		`{"definitions":{"a":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`,
+		`{"definitions":{"a":{"$ref":"#/definitions/b"},"b":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`,
+		`{"$ref":"#"}`,
+	} {
+		if _, err := Compile([]byte(data)); err == nil {
+			t.Errorf("expected circular $ref error: %s", data)
+		}
+	}
+	tree, err := Compile([]byte(`{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := validate(t, tree, `{"children":[{"children":[]}]}`); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+}
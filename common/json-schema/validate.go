@@ -0,0 +1,286 @@
+package json_schema
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uuidRegexp  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+//Error 校验错误，Path为出错位置的JSON Pointer
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+//Validate 校验json.Unmarshal得到的数据，返回全部校验错误
+func (s *Schema) Validate(v interface{}) []*Error {
+	errs := make([]*Error, 0)
+	s.validate(v, "", &errs)
+	return errs
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *[]*Error) {
+	s = s.target()
+	add := func(format string, args ...interface{}) {
+		*errs = append(*errs, &Error{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.always != nil {
+		if !*s.always {
+			add("value is not allowed")
+		}
+		return
+	}
+	if v == nil && s.nullable {
+		return
+	}
+	if len(s.types) > 0 && !s.matchType(v) {
+		add("expected %s but got %s", strings.Join(s.types, " or "), typeOf(v))
+		return
+	}
+	if len(s.enum) > 0 {
+		found := false
+		for _, e := range s.enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("value must be one of %s", enumString(s.enum))
+		}
+	}
+	if s.hasConst && !equal(s.constant, v) {
+		add("value must be %v", s.constant)
+	}
+
+	switch value := v.(type) {
+	case float64:
+		s.validateNumber(value, add)
+	case string:
+		s.validateString(value, add)
+	case []interface{}:
+		s.validateArray(value, path, errs, add)
+	case map[string]interface{}:
+		s.validateObject(value, path, errs, add)
+	}
+
+	for _, sub := range s.allOf {
+		sub.validate(v, path, errs)
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if len(sub.Validate(v)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("value does not match any schema of anyOf")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		count := 0
+		for _, sub := range s.oneOf {
+			if len(sub.Validate(v)) == 0 {
+				count++
+			}
+		}
+		if count != 1 {
+			add("value must match exactly one schema of oneOf, matched %d", count)
+		}
+	}
+	if s.not != nil && len(s.not.Validate(v)) == 0 {
+		add("value must not match the schema of not")
+	}
+}
+
+func (s *Schema) validateNumber(value float64, add func(string, ...interface{})) {
+	if s.minimum != nil && value < *s.minimum {
+		add("value must be >= %v", *s.minimum)
+	}
+	if s.maximum != nil && value > *s.maximum {
+		add("value must be <= %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && value <= *s.exclusiveMinimum {
+		add("value must be > %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && value >= *s.exclusiveMaximum {
+		add("value must be < %v", *s.exclusiveMaximum)
+	}
+	if s.multipleOf != nil && *s.multipleOf > 0 {
+		if q := value / *s.multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			add("value must be a multiple of %v", *s.multipleOf)
+		}
+	}
+}
+
+func (s *Schema) validateString(value string, add func(string, ...interface{})) {
+	length := utf8.RuneCountInString(value)
+	if s.minLength != nil && length < *s.minLength {
+		add("length must be >= %d", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		add("length must be <= %d", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		add("value does not match pattern %s", s.pattern.String())
+	}
+	if s.format != "" && !validFormat(s.format, value) {
+		add("value is not a valid %s", s.format)
+	}
+}
+
+func (s *Schema) validateArray(value []interface{}, path string, errs *[]*Error, add func(string, ...interface{})) {
+	if s.minItems != nil && len(value) < *s.minItems {
+		add("array must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(value) > *s.maxItems {
+		add("array must have at most %d items", *s.maxItems)
+	}
+	if s.uniqueItems {
+	unique:
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if equal(value[i], value[j]) {
+					add("array items must be unique")
+					break unique
+				}
+			}
+		}
+	}
+	if s.items != nil {
+		for i, item := range value {
+			s.items.validate(item, path+"/"+strconv.Itoa(i), errs)
+		}
+	}
+}
+
+func (s *Schema) validateObject(value map[string]interface{}, path string, errs *[]*Error, add func(string, ...interface{})) {
+	if s.minProperties != nil && len(value) < *s.minProperties {
+		add("object must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(value) > *s.maxProperties {
+		add("object must have at most %d properties", *s.maxProperties)
+	}
+	for _, name := range s.required {
+		if _, has := value[name]; !has {
+			add("missing required property %q", name)
+		}
+	}
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub := path + "/" + escape(name)
+		if p, has := s.properties[name]; has {
+			p.validate(value[name], sub, errs)
+			continue
+		}
+		if s.additionalProperties != nil {
+			if a := s.additionalProperties.target(); a.always != nil && !*a.always {
+				*errs = append(*errs, &Error{Path: sub, Message: "additional property is not allowed"})
+				continue
+			}
+			s.additionalProperties.validate(value[name], sub, errs)
+		}
+	}
+}
+
+func (s *Schema) matchType(v interface{}) bool {
+	actual := typeOf(v)
+	for _, t := range s.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+		if t == "null" && v == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func enumString(enum []interface{}) string {
+	list := make([]string, 0, len(enum))
+	for _, e := range enum {
+		list = append(list, fmt.Sprintf("%v", e))
+	}
+	return "[" + strings.Join(list, ", ") + "]"
+}
+
+func escape(name string) string {
+	return strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1)
+}
+
+func validFormat(format, value string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "email":
+		return emailRegexp.MatchString(value)
+	case "uuid":
+		return uuidRegexp.MatchString(value)
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && strings.Contains(value, ".")
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && strings.Contains(value, ":")
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != ""
+	}
+	// 未知的format不做校验
+	return true
+}
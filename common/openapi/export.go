@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+//ExportInfo 导出文档的基本信息
+type ExportInfo struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []string
+}
+
+//ExportOperation 导出的接口
+type ExportOperation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	// Body 请求体JSON Schema，为空时不导出请求体
+	Body string
+}
+
+//Export 生成OpenAPI 3文档，Path使用网关路由格式（:name及*name）
+func Export(info *ExportInfo, operations []*ExportOperation) ([]byte, error) {
+	paths := make(map[string]map[string]interface{})
+	tags := make(map[string]bool)
+	for _, op := range operations {
+		path, params := TemplatePath(op.Path)
+		item, has := paths[path]
+		if !has {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		operation := map[string]interface{}{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"responses": map[string]interface{}{
+				"default": map[string]interface{}{"description": "response of backend"},
+			},
+		}
+		if op.Description != "" {
+			operation["description"] = op.Description
+		}
+		if len(op.Tags) > 0 {
+			operation["tags"] = op.Tags
+			for _, tag := range op.Tags {
+				tags[tag] = true
+			}
+		}
+		if len(params) > 0 {
+			parameters := make([]interface{}, 0, len(params))
+			for _, name := range params {
+				parameters = append(parameters, map[string]interface{}{
+					"name":     name,
+					"in":       "path",
+					"required": true,
+					"schema":   map[string]interface{}{"type": "string"},
+				})
+			}
+			operation["parameters"] = parameters
+		}
+		if op.Body != "" {
+			var schema interface{}
+			if err := json.Unmarshal([]byte(op.Body), &schema); err == nil {
+				operation["requestBody"] = map[string]interface{}{
+					"required": true,
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": schema},
+					},
+				}
+			}
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"paths": paths,
+	}
+	if len(info.Servers) > 0 {
+		servers := make([]interface{}, 0, len(info.Servers))
+		for _, server := range info.Servers {
+			servers = append(servers, map[string]interface{}{"url": server})
+		}
+		doc["servers"] = servers
+	}
+	if len(tags) > 0 {
+		names := make([]string, 0, len(tags))
+		for name := range tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		list := make([]interface{}, 0, len(names))
+		for _, name := range names {
+			list = append(list, map[string]interface{}{"name": name})
+		}
+		doc["tags"] = list
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+//GatewayPath 将OpenAPI路径模板转换为网关路由格式，{id}转换为:id
+func GatewayPath(path string) string {
+	return pathParamRegexp.ReplaceAllStringFunc(path, func(param string) string {
+		return ":" + strings.Trim(param, "{}")
+	})
+}
+
+//TemplatePath 将网关路由格式转换为OpenAPI路径模板，返回路径参数名
+func TemplatePath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	params := make([]string, 0)
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(segments, "/"), params
+}
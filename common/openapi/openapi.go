@@ -0,0 +1,414 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	json_schema "github.com/eolinker/goku-api-gateway/common/json-schema"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	//ErrorInvalidDocument 不是OpenAPI 3或Swagger 2文档
+	ErrorInvalidDocument = errors.New("openapi: document must contain openapi or swagger version")
+
+	methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+	pathParamRegexp = regexp.MustCompile(`\{[^/{}]+\}`)
+)
+
+//Document OpenAPI 3 或 Swagger 2 文档
+type Document struct {
+	root map[string]interface{}
+
+	Version    string       `json:"version"`
+	Title      string       `json:"title"`
+	APIVersion string       `json:"apiVersion"`
+	BasePath   string       `json:"basePath"`
+	Servers    []string     `json:"servers"`
+	Operations []*Operation `json:"operations"`
+}
+
+//Operation 接口操作
+type Operation struct {
+	ID          string       `json:"operationId"`
+	Method      string       `json:"method"`
+	Path        string       `json:"path"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Tags        []string     `json:"tags,omitempty"`
+	Parameters  []*Parameter `json:"parameters"`
+
+	Body         *json_schema.Schema `json:"-"`
+	BodyRequired bool                `json:"bodyRequired"`
+
+	pathRegexp *regexp.Regexp
+	pathParams []string
+}
+
+//Parameter 参数，In为path、query、header或cookie
+type Parameter struct {
+	Name     string              `json:"name"`
+	In       string              `json:"in"`
+	Required bool                `json:"required"`
+	Schema   *json_schema.Schema `json:"-"`
+}
+
+//Parse 解析JSON或YAML格式的文档
+func Parse(data []byte) (*Document, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		var y interface{}
+		if e := yaml.Unmarshal(data, &y); e != nil {
+			return nil, fmt.Errorf("openapi: %s", e)
+		}
+		v = normalize(y)
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, ErrorInvalidDocument
+	}
+	d := &Document{root: root}
+	if version, ok := root["openapi"].(string); ok {
+		d.Version = version
+	} else if version, ok := root["swagger"].(string); ok {
+		d.Version = version
+		d.BasePath, _ = root["basePath"].(string)
+	} else {
+		return nil, ErrorInvalidDocument
+	}
+	if info, ok := root["info"].(map[string]interface{}); ok {
+		d.Title, _ = info["title"].(string)
+		d.APIVersion, _ = info["version"].(string)
+	}
+	if d.BasePath == "" {
+		d.BasePath = serverBasePath(root)
+	}
+	d.Servers = servers(root)
+	if err := d.parseOperations(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+//IsSwagger 是否为Swagger 2文档
+func (d *Document) IsSwagger() bool {
+	_, has := d.root["swagger"]
+	return has
+}
+
+//Raw 文档原始内容
+func (d *Document) Raw() map[string]interface{} {
+	return d.root
+}
+
+//Operation 按operationId获取操作
+func (d *Document) Operation(id string) (*Operation, bool) {
+	for _, op := range d.Operations {
+		if op.ID == id {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+//Find 按请求方法及路径匹配操作，路径可以包含文档的basePath，返回匹配的路径参数
+func (d *Document) Find(method, path string) (*Operation, map[string]string, bool) {
+	method = strings.ToUpper(method)
+	for _, op := range d.Operations {
+		if op.Method != method {
+			continue
+		}
+		if params, ok := d.Match(op, path); ok {
+			return op, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+//Match 请求路径是否匹配操作的路径模板，匹配时返回路径参数
+func (d *Document) Match(op *Operation, path string) (map[string]string, bool) {
+	candidates := []string{path}
+	if base := strings.TrimSuffix(d.BasePath, "/"); base != "" && strings.HasPrefix(path, base) {
+		candidates = append(candidates, strings.TrimPrefix(path, base))
+	}
+	for _, candidate := range candidates {
+		match := op.pathRegexp.FindStringSubmatch(candidate)
+		if match == nil {
+			continue
+		}
+		params := make(map[string]string, len(op.pathParams))
+		for i, name := range op.pathParams {
+			params[name] = match[i+1]
+		}
+		return params, true
+	}
+	return nil, false
+}
+
+func (d *Document) parseOperations() error {
+	paths, _ := d.root["paths"].(map[string]interface{})
+	names := make([]string, 0, len(paths))
+	for p := range paths {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	for _, p := range names {
+		item, ok := d.deref(paths[p]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		common, err := d.parseParameters(item["parameters"])
+		if err != nil {
+			return fmt.Errorf("openapi: %s: %s", p, err)
+		}
+		for _, method := range methods {
+			raw, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			op, err := d.parseOperation(p, method, raw, common)
+			if err != nil {
+				return fmt.Errorf("openapi: %s %s: %s", strings.ToUpper(method), p, err)
+			}
+			d.Operations = append(d.Operations, op)
+		}
+	}
+	return nil
+}
+
+func (d *Document) parseOperation(path, method string, raw map[string]interface{}, common []*Parameter) (*Operation, error) {
+	op := &Operation{
+		Method: strings.ToUpper(method),
+		Path:   path,
+	}
+	op.pathRegexp, op.pathParams = pathRegexp(path)
+	op.ID, _ = raw["operationId"].(string)
+	if op.ID == "" {
+		op.ID = op.Method + " " + path
+	}
+	op.Summary, _ = raw["summary"].(string)
+	op.Description, _ = raw["description"].(string)
+	if tags, ok := raw["tags"].([]interface{}); ok {
+		for _, t := range tags {
+			if tag, ok := t.(string); ok {
+				op.Tags = append(op.Tags, tag)
+			}
+		}
+	}
+
+	params, err := d.parseParameters(raw["parameters"])
+	if err != nil {
+		return nil, err
+	}
+	// 操作的参数覆盖路径上的同名参数
+	merged := make([]*Parameter, 0, len(common)+len(params))
+	for _, c := range common {
+		overridden := false
+		for _, p := range params {
+			if p.Name == c.Name && p.In == c.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, c)
+		}
+	}
+	for _, p := range params {
+		if p.In == "body" {
+			op.Body, op.BodyRequired = p.Schema, p.Required
+			continue
+		}
+		merged = append(merged, p)
+	}
+	op.Parameters = merged
+
+	if body, ok := d.deref(raw["requestBody"]).(map[string]interface{}); ok {
+		op.BodyRequired, _ = body["required"].(bool)
+		if schema := jsonContentSchema(body["content"]); schema != nil {
+			if op.Body, err = json_schema.CompileValue(d.root, schema); err != nil {
+				return nil, fmt.Errorf("requestBody: %s", err)
+			}
+		}
+	}
+	return op, nil
+}
+
+func (d *Document) parseParameters(v interface{}) ([]*Parameter, error) {
+	list, _ := v.([]interface{})
+	params := make([]*Parameter, 0, len(list))
+	for _, item := range list {
+		raw, ok := d.deref(item).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := &Parameter{}
+		p.Name, _ = raw["name"].(string)
+		p.In, _ = raw["in"].(string)
+		p.Required, _ = raw["required"].(bool)
+		if p.In == "path" {
+			p.Required = true
+		}
+		schema, has := raw["schema"]
+		if !has {
+			if content := jsonContentSchema(raw["content"]); content != nil {
+				schema = content
+			} else {
+				// Swagger 2 的非body参数直接在参数上声明类型
+				schema = swaggerParamSchema(raw)
+			}
+		}
+		var err error
+		if p.Schema, err = json_schema.CompileValue(d.root, schema); err != nil {
+			return nil, fmt.Errorf("parameter %s: %s", p.Name, err)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// deref 解析文档内的$ref引用
+func (d *Document) deref(v interface{}) interface{} {
+	for i := 0; i < 16; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#") {
+			return v
+		}
+		target, err := json_schema.Pointer(d.root, strings.TrimPrefix(ref, "#"))
+		if err != nil {
+			return nil
+		}
+		v = target
+	}
+	return v
+}
+
+func jsonContentSchema(v interface{}) interface{} {
+	content, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if !strings.Contains(t, "json") && t != "*/*" {
+			continue
+		}
+		if media, ok := content[t].(map[string]interface{}); ok {
+			if schema, has := media["schema"]; has {
+				return schema
+			}
+		}
+	}
+	return nil
+}
+
+func swaggerParamSchema(raw map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	for k, v := range raw {
+		switch k {
+		case "name", "in", "required", "description", "collectionFormat", "allowEmptyValue":
+			continue
+		}
+		schema[k] = v
+	}
+	return schema
+}
+
+func serverBasePath(root map[string]interface{}) string {
+	servers, _ := root["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	raw, _ := server["url"].(string)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// servers 获取文档中的服务地址，只保留包含host的地址，OpenAPI 3的服务变量使用默认值替换
+func servers(root map[string]interface{}) []string {
+	list := make([]string, 0)
+	if host, ok := root["host"].(string); ok && host != "" {
+		basePath, _ := root["basePath"].(string)
+		schemes, _ := root["schemes"].([]interface{})
+		if len(schemes) == 0 {
+			schemes = []interface{}{"http"}
+		}
+		for _, scheme := range schemes {
+			if s, ok := scheme.(string); ok {
+				list = append(list, s+"://"+host+basePath)
+			}
+		}
+		return list
+	}
+	items, _ := root["servers"].([]interface{})
+	for _, item := range items {
+		server, _ := item.(map[string]interface{})
+		raw, _ := server["url"].(string)
+		variables, _ := server["variables"].(map[string]interface{})
+		for name, v := range variables {
+			variable, _ := v.(map[string]interface{})
+			if def, ok := variable["default"].(string); ok {
+				raw = strings.Replace(raw, "{"+name+"}", def, -1)
+			}
+		}
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			list = append(list, raw)
+		}
+	}
+	return list
+}
+
+// pathRegexp 将路径模板转换为正则，返回路径参数名
+func pathRegexp(path string) (*regexp.Regexp, []string) {
+	names := pathParamRegexp.FindAllString(path, -1)
+	for i, name := range names {
+		names[i] = strings.Trim(name, "{}")
+	}
+	parts := pathParamRegexp.Split(path, -1)
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "([^/]+)") + "/?$"), names
+}
+
+// normalize 将yaml解析得到的map[interface{}]interface{}转换为json结构，数字统一为float64
+func normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalize(item)
+		}
+		return value
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case uint64:
+		return float64(value)
+	}
+	return v
+}
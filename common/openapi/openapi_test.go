@@ -0,0 +1,140 @@
+package openapi
+
+import "testing"
+
+const petstore = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://example.com/v1
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        schema:
+          type: integer
+    put:
+      operationId: updatePet
+      parameters:
+        - $ref: '#/components/parameters/Trace'
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+components:
+  parameters:
+    Trace:
+      name: X-Trace
+      in: header
+      required: true
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        age:
+          type: integer
+          minimum: 0
+`
+
+const swagger = `{
+  "swagger": "2.0",
+  "info": {"title": "Users", "version": "1"},
+  "basePath": "/api",
+  "paths": {
+    "/users": {
+      "get": {
+        "parameters": [{"name": "page", "in": "query", "type": "integer", "minimum": 1}]
+      },
+      "post": {
+        "operationId": "createUser",
+        "parameters": [{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}]
+      }
+    }
+  },
+  "definitions": {"User": {"type": "object", "required": ["id"]}}
+}`
+
+func TestParseOpenAPI(t *testing.T) {
+	d, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Title != "Petstore" || d.BasePath != "/v1" || len(d.Operations) != 1 || len(d.Servers) != 1 || d.Servers[0] != "https://example.com/v1" {
+		t.Fatalf("document: %+v", d)
+	}
+	op, params, ok := d.Find("PUT", "/v1/pets/12")
+	if !ok || op.ID != "updatePet" || params["petId"] != "12" {
+		t.Fatalf("find: %v %v %v", op, params, ok)
+	}
+	if len(op.Parameters) != 2 || !op.BodyRequired || op.Body == nil {
+		t.Fatalf("operation: %+v", op)
+	}
+	if errs := op.Body.Validate(map[string]interface{}{"age": float64(-1)}); len(errs) != 2 {
+		t.Fatalf("body errors: %v", errs)
+	}
+	if _, _, ok := d.Find("GET", "/v1/pets/12"); ok {
+		t.Fatal("GET should not match")
+	}
+}
+
+func TestParseSwagger(t *testing.T) {
+	d, err := Parse([]byte(swagger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsSwagger() || len(d.Operations) != 2 {
+		t.Fatalf("document: %+v", d)
+	}
+	op, ok := d.Operation("GET /users")
+	if !ok || len(op.Parameters) != 1 {
+		t.Fatalf("get: %+v", op)
+	}
+	if errs := op.Parameters[0].Schema.Validate(float64(0)); len(errs) != 1 {
+		t.Fatalf("page errors: %v", errs)
+	}
+	op, ok = d.Operation("createUser")
+	if !ok || op.Body == nil || !op.BodyRequired {
+		t.Fatalf("post: %+v", op)
+	}
+	if _, err := Parse([]byte(`{"info":{}}`)); err != ErrorInvalidDocument {
+		t.Fatalf("expected invalid document, got %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	if p := GatewayPath("/pets/{petId}/toys/{toyId}"); p != "/pets/:petId/toys/:toyId" {
+		t.Fatalf("gateway path: %s", p)
+	}
+	data, err := Export(&ExportInfo{Title: "Pets", Version: "1", Servers: []string{"http://gw.example.com"}}, []*ExportOperation{
+		{ID: "api-1", Method: "GET", Path: "/pets/:petId", Summary: "get pet", Tags: []string{"pet"}},
+		{ID: "api-2", Method: "POST", Path: "/pets", Body: `{"type":"object","required":["name"]}`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Operations) != 2 || len(d.Servers) != 1 {
+		t.Fatalf("document: %+v", d)
+	}
+	op, params, ok := d.Find("GET", "/pets/3")
+	if !ok || op.ID != "api-1" || params["petId"] != "3" || len(op.Tags) != 1 {
+		t.Fatalf("find: %+v %v %v", op, params, ok)
+	}
+	op, _ = d.Operation("api-2")
+	if op.Body == nil || len(op.Body.Validate(map[string]interface{}{})) != 1 {
+		t.Fatalf("body: %+v", op)
+	}
+}
@@ -0,0 +1,514 @@
+package proto_codec
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireStart   = 3
+	wireEnd     = 4
+	wireFixed32 = 5
+)
+
+var (
+	//ErrorTruncated 数据不完整
+	ErrorTruncated = errors.New("proto: truncated data")
+)
+
+//ErrorUnknownMessage 未知的消息类型
+type ErrorUnknownMessage string
+
+func (e ErrorUnknownMessage) Error() string {
+	return fmt.Sprintf("proto: unknown message type %s", string(e))
+}
+
+//Decode 按消息类型将protobuf二进制解码为通用结构，字段名使用json_name
+func (r *Registry) Decode(message string, data []byte) (map[string]interface{}, error) {
+	m, has := r.Message(message)
+	if !has {
+		return nil, ErrorUnknownMessage(message)
+	}
+	return r.decodeMessage(m, data)
+}
+
+//Encode 将通用结构按消息类型编码为protobuf二进制，字段名可以是json_name或原始名称
+func (r *Registry) Encode(message string, v interface{}) ([]byte, error) {
+	m, has := r.Message(message)
+	if !has {
+		return nil, ErrorUnknownMessage(message)
+	}
+	values, ok := v.(map[string]interface{})
+	if !ok {
+		if v != nil {
+			return nil, fmt.Errorf("proto: %s must be encoded from object", message)
+		}
+		values = map[string]interface{}{}
+	}
+	return r.encodeMessage(m, values, nil)
+}
+
+func (r *Registry) decodeMessage(m *Message, data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(m.Fields))
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, ErrorTruncated
+		}
+		data = data[n:]
+		num := int32(key >> 3)
+		wire := int(key & 7)
+
+		f, has := m.byNum[num]
+		if !has {
+			skip, err := skipField(wire, data)
+			if err != nil {
+				return nil, err
+			}
+			data = data[skip:]
+			continue
+		}
+
+		if f.Repeated && wire == wireBytes && isPackable(f.Type) {
+			l, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < l {
+				return nil, ErrorTruncated
+			}
+			packed := data[n : n+int(l)]
+			data = data[n+int(l):]
+			list, _ := result[f.JSONName].([]interface{})
+			for len(packed) > 0 {
+				v, used, err := r.decodeValue(f, packedWire(f.Type), packed)
+				if err != nil {
+					return nil, err
+				}
+				packed = packed[used:]
+				list = append(list, v)
+			}
+			result[f.JSONName] = list
+			continue
+		}
+
+		v, used, err := r.decodeValue(f, wire, data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[used:]
+
+		if f.Type == descriptor.FieldDescriptorProto_TYPE_MESSAGE && f.Repeated {
+			if item, ok := r.messages[f.TypeName]; ok && item.MapItem {
+				mp, _ := result[f.JSONName].(map[string]interface{})
+				if mp == nil {
+					mp = make(map[string]interface{})
+				}
+				entry := v.(map[string]interface{})
+				mp[fmt.Sprint(entry["key"])] = entry["value"]
+				result[f.JSONName] = mp
+				continue
+			}
+		}
+		if f.Repeated {
+			list, _ := result[f.JSONName].([]interface{})
+			result[f.JSONName] = append(list, v)
+			continue
+		}
+		result[f.JSONName] = v
+	}
+	return result, nil
+}
+
+func (r *Registry) decodeValue(f *Field, wire int, data []byte) (interface{}, int, error) {
+	switch wire {
+	case wireVarint:
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, 0, ErrorTruncated
+		}
+		switch f.Type {
+		case descriptor.FieldDescriptorProto_TYPE_INT32:
+			return int32(v), n, nil
+		case descriptor.FieldDescriptorProto_TYPE_INT64:
+			return int64(v), n, nil
+		case descriptor.FieldDescriptorProto_TYPE_UINT32:
+			return uint32(v), n, nil
+		case descriptor.FieldDescriptorProto_TYPE_UINT64:
+			return v, n, nil
+		case descriptor.FieldDescriptorProto_TYPE_SINT32:
+			return int32(uint32(v>>1) ^ -uint32(v&1)), n, nil
+		case descriptor.FieldDescriptorProto_TYPE_SINT64:
+			return int64(v>>1) ^ -int64(v&1), n, nil
+		case descriptor.FieldDescriptorProto_TYPE_BOOL:
+			return v != 0, n, nil
+		case descriptor.FieldDescriptorProto_TYPE_ENUM:
+			if name, has := r.enums[f.TypeName][int32(v)]; has {
+				return name, n, nil
+			}
+			return int32(v), n, nil
+		}
+	case wireFixed64:
+		if len(data) < 8 {
+			return nil, 0, ErrorTruncated
+		}
+		v := binary.LittleEndian.Uint64(data)
+		switch f.Type {
+		case descriptor.FieldDescriptorProto_TYPE_FIXED64:
+			return v, 8, nil
+		case descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+			return int64(v), 8, nil
+		case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+			return math.Float64frombits(v), 8, nil
+		}
+	case wireFixed32:
+		if len(data) < 4 {
+			return nil, 0, ErrorTruncated
+		}
+		v := binary.LittleEndian.Uint32(data)
+		switch f.Type {
+		case descriptor.FieldDescriptorProto_TYPE_FIXED32:
+			return v, 4, nil
+		case descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+			return int32(v), 4, nil
+		case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+			return float64(math.Float32frombits(v)), 4, nil
+		}
+	case wireBytes:
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l {
+			return nil, 0, ErrorTruncated
+		}
+		b := data[n : n+int(l)]
+		used := n + int(l)
+		switch f.Type {
+		case descriptor.FieldDescriptorProto_TYPE_STRING:
+			return string(b), used, nil
+		case descriptor.FieldDescriptorProto_TYPE_BYTES:
+			return base64.StdEncoding.EncodeToString(b), used, nil
+		case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+			m, has := r.messages[f.TypeName]
+			if !has {
+				return nil, 0, ErrorUnknownMessage(f.TypeName)
+			}
+			v, err := r.decodeMessage(m, b)
+			if err != nil {
+				return nil, 0, err
+			}
+			return v, used, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("proto: field %s has invalid wire type %d", f.Name, wire)
+}
+
+func packedWire(t descriptor.FieldDescriptorProto_Type) int {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_FIXED64, descriptor.FieldDescriptorProto_TYPE_SFIXED64, descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return wireFixed64
+	case descriptor.FieldDescriptorProto_TYPE_FIXED32, descriptor.FieldDescriptorProto_TYPE_SFIXED32, descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return wireFixed32
+	}
+	return wireVarint
+}
+
+func skipField(wire int, data []byte) (int, error) {
+	switch wire {
+	case wireVarint:
+		_, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, ErrorTruncated
+		}
+		return n, nil
+	case wireFixed64:
+		if len(data) < 8 {
+			return 0, ErrorTruncated
+		}
+		return 8, nil
+	case wireFixed32:
+		if len(data) < 4 {
+			return 0, ErrorTruncated
+		}
+		return 4, nil
+	case wireBytes:
+		l, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < l {
+			return 0, ErrorTruncated
+		}
+		return n + int(l), nil
+	case wireStart:
+		offset := 0
+		for offset < len(data) {
+			key, n := binary.Uvarint(data[offset:])
+			if n <= 0 {
+				return 0, ErrorTruncated
+			}
+			offset += n
+			if int(key&7) == wireEnd {
+				return offset, nil
+			}
+			skip, err := skipField(int(key&7), data[offset:])
+			if err != nil {
+				return 0, err
+			}
+			offset += skip
+		}
+		return 0, ErrorTruncated
+	}
+	return 0, fmt.Errorf("proto: invalid wire type %d", wire)
+}
+
+func (r *Registry) encodeMessage(m *Message, values map[string]interface{}, buf []byte) ([]byte, error) {
+	for _, f := range m.Fields {
+		v, has := values[f.JSONName]
+		if !has {
+			v, has = values[f.Name]
+		}
+		if !has || v == nil {
+			continue
+		}
+		var err error
+		buf, err = r.encodeField(f, v, buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (r *Registry) encodeField(f *Field, v interface{}, buf []byte) ([]byte, error) {
+	if !f.Repeated {
+		return r.encodeSingle(f, v, buf)
+	}
+	if item, ok := r.messages[f.TypeName]; ok && item.MapItem {
+		mp, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("proto: field %s must be object", f.Name)
+		}
+		keys := make([]string, 0, len(mp))
+		for k := range mp {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			entry := map[string]interface{}{"key": k, "value": mp[k]}
+			var err error
+			buf, err = r.encodeSingle(f, entry, buf)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		list = []interface{}{v}
+	}
+	if f.Packed {
+		packed := make([]byte, 0, len(list)*4)
+		for _, item := range list {
+			var err error
+			packed, err = r.encodeValue(f, item, packed)
+			if err != nil {
+				return nil, err
+			}
+		}
+		buf = appendTag(buf, f.Number, wireBytes)
+		buf = appendVarint(buf, uint64(len(packed)))
+		return append(buf, packed...), nil
+	}
+	for _, item := range list {
+		var err error
+		buf, err = r.encodeSingle(f, item, buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (r *Registry) encodeSingle(f *Field, v interface{}, buf []byte) ([]byte, error) {
+	wire := packedWire(f.Type)
+	if !isPackable(f.Type) {
+		wire = wireBytes
+	}
+	buf = appendTag(buf, f.Number, wire)
+	return r.encodeValue(f, v, buf)
+}
+
+func (r *Registry) encodeValue(f *Field, v interface{}, buf []byte) ([]byte, error) {
+	switch f.Type {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		s := toString(v)
+		buf = appendVarint(buf, uint64(len(s)))
+		return append(buf, s...), nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		s := toString(v)
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			b = []byte(s)
+		}
+		buf = appendVarint(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		m, has := r.messages[f.TypeName]
+		if !has {
+			return nil, ErrorUnknownMessage(f.TypeName)
+		}
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("proto: field %s must be object", f.Name)
+		}
+		sub, err := r.encodeMessage(m, values, nil)
+		if err != nil {
+			return nil, err
+		}
+		buf = appendVarint(buf, uint64(len(sub)))
+		return append(buf, sub...), nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		b, err := toBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("proto: field %s:%s", f.Name, err.Error())
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		if s, ok := v.(string); ok {
+			if n, has := r.enumVals[f.TypeName][s]; has {
+				return appendVarint(buf, uint64(int64(n))), nil
+			}
+		}
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		n, err := toFloat(v)
+		if err != nil {
+			return nil, fmt.Errorf("proto: field %s:%s", f.Name, err.Error())
+		}
+		return appendFixed32(buf, math.Float32bits(float32(n))), nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		n, err := toFloat(v)
+		if err != nil {
+			return nil, fmt.Errorf("proto: field %s:%s", f.Name, err.Error())
+		}
+		return appendFixed64(buf, math.Float64bits(n)), nil
+	}
+
+	n, err := toInt(v)
+	if err != nil {
+		return nil, fmt.Errorf("proto: field %s:%s", f.Name, err.Error())
+	}
+	switch f.Type {
+	case descriptor.FieldDescriptorProto_TYPE_SINT32, descriptor.FieldDescriptorProto_TYPE_SINT64:
+		return appendVarint(buf, uint64(n<<1)^uint64(n>>63)), nil
+	case descriptor.FieldDescriptorProto_TYPE_FIXED32, descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return appendFixed32(buf, uint32(n)), nil
+	case descriptor.FieldDescriptorProto_TYPE_FIXED64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return appendFixed64(buf, uint64(n)), nil
+	}
+	return appendVarint(buf, uint64(n)), nil
+}
+
+func appendTag(buf []byte, num int32, wire int) []byte {
+	return appendVarint(buf, uint64(num)<<3|uint64(wire))
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int32:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint32:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	}
+	return 0, fmt.Errorf("can not convert %v to number", v)
+}
+
+func toInt(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case int32:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case uint32:
+		return int64(t), nil
+	case uint64:
+		return int64(t), nil
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		if n, err := strconv.ParseInt(t, 10, 64); err == nil {
+			return n, nil
+		}
+	}
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f), nil
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		return strconv.ParseBool(t)
+	}
+	n, err := toFloat(v)
+	if err != nil {
+		return false, err
+	}
+	return n != 0, nil
+}
+
+func appendVarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func appendFixed32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendFixed64(buf []byte, v uint64) []byte {
+	return appendFixed32(appendFixed32(buf, uint32(v)), uint32(v>>32))
+}
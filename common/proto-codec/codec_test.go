@@ -0,0 +1,135 @@
+package proto_codec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+func field(name string, number int32, typ descriptor.FieldDescriptorProto_Type, label descriptor.FieldDescriptorProto_Label, typeName string) *descriptor.FieldDescriptorProto {
+	f := &descriptor.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Type:     typ.Enum(),
+		Label:    label.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func testDescriptorSet(t *testing.T) []byte {
+	optional := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+	file := &descriptor.FileDescriptorProto{
+		Name:    proto.String("user.proto"),
+		Package: proto.String("demo"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptor.EnumDescriptorProto{{
+			Name: proto.String("Level"),
+			Value: []*descriptor.EnumValueDescriptorProto{
+				{Name: proto.String("NORMAL"), Number: proto.Int32(0)},
+				{Name: proto.String("VIP"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("User"),
+				Field: []*descriptor.FieldDescriptorProto{
+					field("id", 1, descriptor.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("name", 2, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					field("scores", 3, descriptor.FieldDescriptorProto_TYPE_SINT32, repeated, ""),
+					field("level", 4, descriptor.FieldDescriptorProto_TYPE_ENUM, optional, ".demo.Level"),
+					field("address", 5, descriptor.FieldDescriptorProto_TYPE_MESSAGE, optional, ".demo.Address"),
+					field("tags", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".demo.User.TagsEntry"),
+					field("rate", 7, descriptor.FieldDescriptorProto_TYPE_DOUBLE, optional, ""),
+					field("active", 8, descriptor.FieldDescriptorProto_TYPE_BOOL, optional, ""),
+				},
+				NestedType: []*descriptor.DescriptorProto{{
+					Name: proto.String("TagsEntry"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+					},
+					Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptor.FieldDescriptorProto{
+					field("city", 1, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+			},
+		},
+		Service: []*descriptor.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptor.MethodDescriptorProto{{
+				Name:       proto.String("Get"),
+				InputType:  proto.String(".demo.Address"),
+				OutputType: proto.String(".demo.User"),
+			}},
+		}},
+	}
+	data, err := proto.Marshal(&descriptor.FileDescriptorSet{File: []*descriptor.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRoundTrip(t *testing.T) {
+	r, err := ParseDescriptorSet(testDescriptorSet(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := `{"id":"9007199254740993","name":"goku","scores":[1,-2,3],"level":"VIP","address":{"city":"GZ"},"tags":{"a":"1","b":"2"},"rate":0.5,"active":true}`
+	var v interface{}
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.Encode("demo.User", v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Decode(".demo.User", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(out)
+	want := `{"active":true,"address":{"city":"GZ"},"id":9007199254740993,"level":"VIP","name":"goku","rate":0.5,"scores":[1,-2,3],"tags":{"a":"1","b":"2"}}`
+	if string(got) != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	m, has := r.Method("/demo.UserService/Get")
+	if !has || m.Input != "demo.Address" || m.Output != "demo.User" {
+		t.Errorf("method not found:%+v", m)
+	}
+	if _, err := r.Decode("demo.Unknown", data); err == nil {
+		t.Error("expected unknown message error")
+	}
+	if _, err := r.Decode("demo.User", data[:len(data)-1]); err == nil {
+		t.Error("expected truncated error")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	data := append(EncodeFrame([]byte("a")), EncodeFrame(nil)...)
+	frames, err := DecodeFrames(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || string(frames[0]) != "a" || len(frames[1]) != 0 {
+		t.Errorf("unexpected frames:%q", frames)
+	}
+	if _, err := DecodeFrames(data[:3]); err != ErrorTruncated {
+		t.Errorf("expected truncated error, got %v", err)
+	}
+	if HTTPStatus(5) != 404 || HTTPStatus(13) != 500 {
+		t.Error("unexpected http status mapping")
+	}
+}
@@ -0,0 +1,68 @@
+package proto_codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+)
+
+var (
+	//ErrorCompressedFrame 不支持压缩的grpc消息
+	ErrorCompressedFrame = errors.New("grpc: compressed message is not supported")
+)
+
+//EncodeFrame 按grpc协议为消息添加5字节的长度前缀
+func EncodeFrame(data []byte) []byte {
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	return append(frame, data...)
+}
+
+//DecodeFrames 拆分grpc消息体中的所有消息
+func DecodeFrames(data []byte) ([][]byte, error) {
+	frames := make([][]byte, 0, 1)
+	for len(data) > 0 {
+		if len(data) < 5 {
+			return nil, ErrorTruncated
+		}
+		if data[0] != 0 {
+			return nil, ErrorCompressedFrame
+		}
+		size := int(binary.BigEndian.Uint32(data[1:5]))
+		if len(data)-5 < size {
+			return nil, ErrorTruncated
+		}
+		frames = append(frames, data[5:5+size])
+		data = data[5+size:]
+	}
+	return frames, nil
+}
+
+//HTTPStatus 将grpc状态码转换为http状态码
+func HTTPStatus(code int) int {
+	switch code {
+	case 0:
+		return http.StatusOK
+	case 1:
+		return 499
+	case 3, 9, 11:
+		return http.StatusBadRequest
+	case 4:
+		return http.StatusGatewayTimeout
+	case 5:
+		return http.StatusNotFound
+	case 6, 10:
+		return http.StatusConflict
+	case 7:
+		return http.StatusForbidden
+	case 8:
+		return http.StatusTooManyRequests
+	case 12:
+		return http.StatusNotImplemented
+	case 14:
+		return http.StatusServiceUnavailable
+	case 16:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,269 @@
+package proto_codec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+//Message 消息描述
+type Message struct {
+	Name    string
+	Proto3  bool
+	Fields  []*Field
+	byNum   map[int32]*Field
+	byName  map[string]*Field
+	MapItem bool
+}
+
+//Field 字段描述
+type Field struct {
+	Name     string
+	JSONName string
+	Number   int32
+	Type     descriptor.FieldDescriptorProto_Type
+	Repeated bool
+	Packed   bool
+	TypeName string // message、enum类型的全名，不带前缀 .
+}
+
+//Method grpc方法描述
+type Method struct {
+	Service         string
+	Name            string
+	Input           string
+	Output          string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+//FullPath grpc请求路径
+func (m *Method) FullPath() string {
+	return fmt.Sprintf("/%s/%s", m.Service, m.Name)
+}
+
+//Registry 描述集合
+type Registry struct {
+	messages map[string]*Message
+	enums    map[string]map[int32]string
+	enumVals map[string]map[string]int32
+	methods  map[string]*Method
+}
+
+//NewRegistry 创建空的描述集合
+func NewRegistry() *Registry {
+	return &Registry{
+		messages: make(map[string]*Message),
+		enums:    make(map[string]map[int32]string),
+		enumVals: make(map[string]map[string]int32),
+		methods:  make(map[string]*Method),
+	}
+}
+
+//ParseDescriptorSet 解析 protoc --descriptor_set_out 生成的 FileDescriptorSet
+func ParseDescriptorSet(data []byte) (*Registry, error) {
+	r := NewRegistry()
+	if err := r.Add(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+//Add 加入FileDescriptorSet
+func (r *Registry) Add(data []byte) error {
+	set := new(descriptor.FileDescriptorSet)
+	if err := proto.Unmarshal(data, set); err != nil {
+		return fmt.Errorf("invalid descriptor set:%s", err.Error())
+	}
+	if len(set.File) == 0 {
+		return fmt.Errorf("invalid descriptor set: no file")
+	}
+	for _, f := range set.File {
+		proto3 := f.GetSyntax() == "proto3"
+		prefix := f.GetPackage()
+		for _, m := range f.MessageType {
+			r.addMessage(prefix, m, proto3)
+		}
+		for _, e := range f.EnumType {
+			r.addEnum(prefix, e)
+		}
+		for _, s := range f.Service {
+			service := join(prefix, s.GetName())
+			for _, m := range s.Method {
+				method := &Method{
+					Service:         service,
+					Name:            m.GetName(),
+					Input:           strings.TrimPrefix(m.GetInputType(), "."),
+					Output:          strings.TrimPrefix(m.GetOutputType(), "."),
+					ClientStreaming: m.GetClientStreaming(),
+					ServerStreaming: m.GetServerStreaming(),
+				}
+				r.methods[method.FullPath()] = method
+			}
+		}
+	}
+	return nil
+}
+
+//Merge 合并其他描述集合
+func (r *Registry) Merge(o *Registry) {
+	for k, v := range o.messages {
+		r.messages[k] = v
+	}
+	for k, v := range o.enums {
+		r.enums[k] = v
+	}
+	for k, v := range o.enumVals {
+		r.enumVals[k] = v
+	}
+	for k, v := range o.methods {
+		r.methods[k] = v
+	}
+}
+
+func (r *Registry) addMessage(prefix string, m *descriptor.DescriptorProto, proto3 bool) {
+	name := join(prefix, m.GetName())
+	msg := &Message{
+		Name:    name,
+		Proto3:  proto3,
+		Fields:  make([]*Field, 0, len(m.Field)),
+		byNum:   make(map[int32]*Field, len(m.Field)),
+		byName:  make(map[string]*Field, len(m.Field)*2),
+		MapItem: m.GetOptions().GetMapEntry(),
+	}
+	for _, fd := range m.Field {
+		f := &Field{
+			Name:     fd.GetName(),
+			JSONName: fd.GetJsonName(),
+			Number:   fd.GetNumber(),
+			Type:     fd.GetType(),
+			Repeated: fd.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED,
+			TypeName: strings.TrimPrefix(fd.GetTypeName(), "."),
+		}
+		if f.JSONName == "" {
+			f.JSONName = f.Name
+		}
+		if f.Repeated && isPackable(f.Type) {
+			if fd.GetOptions() != nil && fd.GetOptions().Packed != nil {
+				f.Packed = fd.GetOptions().GetPacked()
+			} else {
+				f.Packed = proto3
+			}
+		}
+		msg.Fields = append(msg.Fields, f)
+		msg.byNum[f.Number] = f
+		msg.byName[f.Name] = f
+		msg.byName[f.JSONName] = f
+	}
+	sort.Slice(msg.Fields, func(i, j int) bool {
+		return msg.Fields[i].Number < msg.Fields[j].Number
+	})
+	r.messages[name] = msg
+
+	for _, nested := range m.NestedType {
+		r.addMessage(name, nested, proto3)
+	}
+	for _, e := range m.EnumType {
+		r.addEnum(name, e)
+	}
+}
+
+func (r *Registry) addEnum(prefix string, e *descriptor.EnumDescriptorProto) {
+	name := join(prefix, e.GetName())
+	names := make(map[int32]string, len(e.Value))
+	values := make(map[string]int32, len(e.Value))
+	for _, v := range e.Value {
+		names[v.GetNumber()] = v.GetName()
+		values[v.GetName()] = v.GetNumber()
+	}
+	r.enums[name] = names
+	r.enumVals[name] = values
+}
+
+//Message 获取消息描述
+func (r *Registry) Message(name string) (*Message, bool) {
+	m, has := r.messages[strings.TrimPrefix(name, ".")]
+	return m, has
+}
+
+//Messages 返回所有消息名称
+func (r *Registry) Messages() []string {
+	names := make([]string, 0, len(r.messages))
+	for name, m := range r.messages {
+		if m.MapItem {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Method 通过 /package.Service/Method 获取方法描述
+func (r *Registry) Method(path string) (*Method, bool) {
+	m, has := r.methods[path]
+	return m, has
+}
+
+//Methods 返回所有grpc方法
+func (r *Registry) Methods() []*Method {
+	methods := make([]*Method, 0, len(r.methods))
+	for _, m := range r.methods {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].FullPath() < methods[j].FullPath()
+	})
+	return methods
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func isPackable(t descriptor.FieldDescriptorProto_Type) bool {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_STRING,
+		descriptor.FieldDescriptorProto_TYPE_BYTES,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+		descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+	return true
+}
+
+var (
+	defaultRegistry = NewRegistry()
+	locker          sync.RWMutex
+)
+
+//Reset 使用新的描述集合替换全局描述，key为描述名称，value为FileDescriptorSet内容
+//无法解析的描述会被跳过，并返回最后一个错误
+func Reset(sets map[string][]byte) error {
+	r := NewRegistry()
+	var err error
+	for name, data := range sets {
+		if e := r.Add(data); e != nil {
+			err = fmt.Errorf("%s:%s", name, e.Error())
+		}
+	}
+	locker.Lock()
+	defaultRegistry = r
+	locker.Unlock()
+	return err
+}
+
+//Default 获取全局描述集合
+func Default() *Registry {
+	locker.RLock()
+	r := defaultRegistry
+	locker.RUnlock()
+	return r
+}
@@ -31,3 +31,8 @@ func GetConnection() Redis {
 	def = Create(defaultConfig)
 	return def
 }
+
+//Default 获取已设置的默认redis，未设置时返回false
+func Default() (Redis, bool) {
+	return def, def != nil
+}
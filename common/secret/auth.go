@@ -0,0 +1,156 @@
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+//Redacted 导出时替代加密凭证的占位值
+const Redacted = "******"
+
+//ErrorRedacted 占位值对应的凭证不存在，无法还原
+var ErrorRedacted = errors.New("redacted credential does not exist, export the bundle with secrets")
+
+const (
+	modeHash = iota
+	modeKeyHash
+	modeEncrypt
+)
+
+type authField struct {
+	list  string // 凭证列表所在字段，为空时配置本身为列表
+	key   string // 凭证的标识字段，用于还原占位值
+	field string
+	mode  int
+}
+
+// authFields 鉴权插件中需要保护的字段，同时支持插件名及网关中的鉴权类型名
+var authFields = map[string]authField{
+	"goku-basic_auth":  {key: "userName", field: "password", mode: modeHash},
+	"Basic":            {key: "userName", field: "password", mode: modeHash},
+	"goku-apikey_auth": {key: "Apikey", field: "Apikey", mode: modeKeyHash},
+	"Apikey":           {key: "Apikey", field: "Apikey", mode: modeKeyHash},
+	"goku-jwt_auth":    {list: "jwtCredentials", key: "iss", field: "secret", mode: modeEncrypt},
+	"Jwt":              {list: "jwtCredentials", key: "iss", field: "secret", mode: modeEncrypt},
+	"goku-hmac_auth":   {key: "userName", field: "secret", mode: modeEncrypt},
+	"Hmac":             {key: "userName", field: "secret", mode: modeEncrypt},
+	"goku-oauth2_auth": {list: "oauth2CredentialList", key: "clientID", field: "clientSecret", mode: modeEncrypt},
+	"Oauth2":           {list: "oauth2CredentialList", key: "clientID", field: "clientSecret", mode: modeEncrypt},
+}
+
+//IsAuthPlugin 是否为需要保护凭证的鉴权插件
+func IsAuthPlugin(name string) bool {
+	_, has := authFields[name]
+	return has
+}
+
+//SealAuthConfig 保存鉴权配置前对凭证进行哈希或加密，已处理过的凭证保持不变
+func SealAuthConfig(name, config string) (string, error) {
+	return walkAuthConfig(name, config, func(mode int, key, v string) (string, error) {
+		switch mode {
+		case modeHash:
+			return HashPassword(v)
+		case modeKeyHash:
+			if IsKeyHash(v) {
+				return v, nil
+			}
+			return HashKey(v), nil
+		}
+		return Encrypt(v)
+	})
+}
+
+//OpenAuthConfig 解密鉴权配置中加密保存的凭证，哈希后的凭证保持不变
+func OpenAuthConfig(name, config string) (string, error) {
+	return walkAuthConfig(name, config, func(mode int, key, v string) (string, error) {
+		if mode != modeEncrypt {
+			return v, nil
+		}
+		return Decrypt(v)
+	})
+}
+
+//RedactAuthConfig 将鉴权配置中加密保存的凭证替换为占位值，哈希后的凭证保持不变
+func RedactAuthConfig(name, config string) (string, error) {
+	return walkAuthConfig(name, config, func(mode int, key, v string) (string, error) {
+		if mode != modeEncrypt {
+			return v, nil
+		}
+		return Redacted, nil
+	})
+}
+
+//RestoreAuthConfig 将占位值还原为stored中相同标识的凭证
+func RestoreAuthConfig(name, config string, stored ...string) (string, error) {
+	values := make(map[string]string)
+	for _, s := range stored {
+		_, err := walkAuthConfig(name, s, func(mode int, key, v string) (string, error) {
+			values[key] = v
+			return v, nil
+		})
+		if err != nil {
+			return config, err
+		}
+	}
+	return walkAuthConfig(name, config, func(mode int, key, v string) (string, error) {
+		if v != Redacted {
+			return v, nil
+		}
+		if old, has := values[key]; has && old != Redacted {
+			return old, nil
+		}
+		return v, ErrorRedacted
+	})
+}
+
+func walkAuthConfig(name, config string, handler func(mode int, key, v string) (string, error)) (string, error) {
+	f, has := authFields[name]
+	if !has || strings.TrimSpace(config) == "" {
+		return config, nil
+	}
+	var root interface{}
+	if err := json.Unmarshal([]byte(config), &root); err != nil {
+		return config, err
+	}
+	items := root
+	if f.list != "" {
+		m, ok := root.(map[string]interface{})
+		if !ok {
+			return config, nil
+		}
+		items = m[f.list]
+	}
+	list, ok := items.([]interface{})
+	if !ok {
+		return config, nil
+	}
+	changed := false
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v, ok := m[f.field].(string)
+		if !ok || v == "" {
+			continue
+		}
+		key, _ := m[f.key].(string)
+		nv, err := handler(f.mode, key, v)
+		if err != nil {
+			return config, err
+		}
+		if nv != v {
+			m[f.field] = nv
+			changed = true
+		}
+	}
+	if !changed {
+		return config, nil
+	}
+	data, err := json.Marshal(root)
+	if err != nil {
+		return config, err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,194 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	//EnvMasterKey 主密钥环境变量，优先于密钥文件
+	EnvMasterKey = "GOKU_MASTER_KEY"
+
+	keyHashPrefix   = "sha256:"
+	encryptedPrefix = "enc:"
+	keyCheckPlain   = "goku-master-key-check"
+)
+
+var (
+	//ErrorNoMasterKey 未设置主密钥
+	ErrorNoMasterKey = errors.New("master key is not set")
+	//ErrorDecrypt 解密失败，可能是主密钥不正确
+	ErrorDecrypt = errors.New("fail to decrypt secret, please check the master key")
+
+	masterKey []byte
+	locker    sync.RWMutex
+)
+
+//SetMasterKey 设置主密钥，实际使用的是其SHA-256摘要
+func SetMasterKey(key string) {
+	sum := sha256.Sum256([]byte(key))
+	locker.Lock()
+	masterKey = sum[:]
+	locker.Unlock()
+}
+
+//LoadMasterKey 从环境变量或密钥文件加载主密钥，密钥文件不存在且generate为true时生成新的主密钥
+func LoadMasterKey(file string, generate bool) error {
+	if key := os.Getenv(EnvMasterKey); key != "" {
+		SetMasterKey(key)
+		return nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		SetMasterKey(strings.TrimSpace(string(data)))
+		return nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if !generate {
+		return fmt.Errorf("master key is not found, every console must set the same key by env %s or file %s", EnvMasterKey, file)
+	}
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return err
+	}
+	key := base64.StdEncoding.EncodeToString(buf)
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, []byte(key+"\n"), 0600); err != nil {
+		return err
+	}
+	SetMasterKey(key)
+	return nil
+}
+
+//KeyCheck 主密钥校验值，用于确认多个控制台使用了相同的主密钥
+func KeyCheck() (string, error) {
+	key, err := getMasterKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(keyCheckPlain))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func getMasterKey() ([]byte, error) {
+	locker.RLock()
+	defer locker.RUnlock()
+	if masterKey == nil {
+		return nil, ErrorNoMasterKey
+	}
+	return masterKey, nil
+}
+
+//HashPassword 使用bcrypt对密码进行哈希，已哈希的值原样返回
+func HashPassword(password string) (string, error) {
+	if password == "" || IsPasswordHash(password) {
+		return password, nil
+	}
+	data, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//IsPasswordHash 是否为bcrypt哈希
+func IsPasswordHash(v string) bool {
+	return len(v) == 60 && (strings.HasPrefix(v, "$2a$") || strings.HasPrefix(v, "$2b$") || strings.HasPrefix(v, "$2y$"))
+}
+
+//VerifyPassword 校验密码，兼容未哈希的旧数据
+func VerifyPassword(stored, password string) bool {
+	if IsPasswordHash(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
+
+//HashKey 对apikey等高熵凭证进行SHA-256哈希
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return keyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+//IsKeyHash 是否为HashKey生成的哈希
+func IsKeyHash(v string) bool {
+	return strings.HasPrefix(v, keyHashPrefix) && len(v) == len(keyHashPrefix)+sha256.Size*2
+}
+
+//Encrypt 使用主密钥加密（AES-256-GCM），已加密的值原样返回
+func Encrypt(plain string) (string, error) {
+	if plain == "" || IsEncrypted(plain) {
+		return plain, nil
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	data := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+//Decrypt 解密Encrypt生成的密文，未加密的值原样返回
+func Decrypt(v string) (string, error) {
+	if !IsEncrypted(v) {
+		return v, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedPrefix))
+	if err != nil {
+		return "", ErrorDecrypt
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", ErrorDecrypt
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrorDecrypt
+	}
+	return string(plain), nil
+}
+
+//IsEncrypted 是否为Encrypt生成的密文
+func IsEncrypted(v string) bool {
+	return strings.HasPrefix(v, encryptedPrefix)
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key, err := getMasterKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
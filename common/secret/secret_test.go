@@ -0,0 +1,126 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	SetMasterKey("test")
+	enc, err := Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(enc) || strings.Contains(enc, "secret") {
+		t.Fatalf("unexpected ciphertext:%s", enc)
+	}
+	if again, _ := Encrypt(enc); again != enc {
+		t.Error("encrypted value should not be encrypted again")
+	}
+	if plain, err := Decrypt(enc); err != nil || plain != "secret" {
+		t.Errorf("decrypt:%s %v", plain, err)
+	}
+	if plain, _ := Decrypt("plain"); plain != "plain" {
+		t.Error("plain value should pass through")
+	}
+	SetMasterKey("other")
+	if _, err := Decrypt(enc); err != ErrorDecrypt {
+		t.Errorf("expected decrypt error, got %v", err)
+	}
+}
+
+func TestLoadMasterKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Unsetenv(EnvMasterKey)
+
+	file := filepath.Join(dir, "config", "master.key")
+	if err := LoadMasterKey(file, false); err == nil {
+		t.Fatal("master key should not be generated")
+	}
+	if err := LoadMasterKey(file, true); err != nil {
+		t.Fatal(err)
+	}
+	enc, _ := Encrypt("secret")
+	check, _ := KeyCheck()
+	SetMasterKey("other")
+	if other, _ := KeyCheck(); other == check {
+		t.Error("key check should differ between master keys")
+	}
+	if err := LoadMasterKey(file, false); err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := Decrypt(enc); err != nil || plain != "secret" {
+		t.Errorf("master key should be reloaded from file:%s %v", plain, err)
+	}
+	if again, _ := KeyCheck(); again != check {
+		t.Error("key check should be stable for the same master key")
+	}
+}
+
+func TestSealAuthConfig(t *testing.T) {
+	SetMasterKey("test")
+	basic, err := SealAuthConfig("goku-basic_auth", `[{"userName":"a","password":"p","remark":""}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(basic, `"p"`) || !strings.Contains(basic, `$2a$`) {
+		t.Errorf("password not hashed:%s", basic)
+	}
+	if again, _ := SealAuthConfig("goku-basic_auth", basic); again != basic {
+		t.Error("hashed password should not be hashed again")
+	}
+
+	apikey, _ := SealAuthConfig("Apikey", `[{"Apikey":"k"}]`)
+	if apikey != `[{"Apikey":"`+HashKey("k")+`"}]` {
+		t.Errorf("apikey not hashed:%s", apikey)
+	}
+
+	jwt, _ := SealAuthConfig("goku-jwt_auth", `{"jwtCredentials":[{"iss":"i","secret":"s"}]}`)
+	if strings.Contains(jwt, `"s"`) {
+		t.Errorf("jwt secret not encrypted:%s", jwt)
+	}
+	opened, err := OpenAuthConfig("Jwt", jwt)
+	if err != nil || opened != `{"jwtCredentials":[{"iss":"i","secret":"s"}]}` {
+		t.Errorf("open jwt:%s %v", opened, err)
+	}
+	if v, _ := SealAuthConfig("goku-extra", `{"secret":"s"}`); v != `{"secret":"s"}` {
+		t.Error("unknown plugin should not be changed")
+	}
+}
+
+func TestRedactAuthConfig(t *testing.T) {
+	SetMasterKey("test")
+	stored, _ := SealAuthConfig("goku-hmac_auth", `[{"userName":"a","secret":"s1"},{"userName":"b","secret":"s2"}]`)
+	redacted, err := RedactAuthConfig("goku-hmac_auth", stored)
+	if err != nil || redacted != `[{"secret":"******","userName":"a"},{"secret":"******","userName":"b"}]` {
+		t.Fatalf("redact hmac:%s %v", redacted, err)
+	}
+	config := `[{"userName":"b","secret":"******"},{"userName":"c","secret":"s3"}]`
+	if _, err := RestoreAuthConfig("goku-hmac_auth", config, stored); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := RestoreAuthConfig("goku-hmac_auth", `[{"userName":"b","secret":"******"}]`, stored)
+	if opened, _ := OpenAuthConfig("Hmac", restored); opened != `[{"secret":"s2","userName":"b"}]` {
+		t.Errorf("restore hmac:%s", opened)
+	}
+	if _, err := RestoreAuthConfig("goku-hmac_auth", `[{"userName":"d","secret":"******"}]`, stored); err != ErrorRedacted {
+		t.Errorf("expected redacted error, got %v", err)
+	}
+}
+
+func TestVerifyPassword(t *testing.T) {
+	hash, _ := HashPassword("p")
+	if !VerifyPassword(hash, "p") || VerifyPassword(hash, "x") {
+		t.Error("bcrypt verify failed")
+	}
+	if !VerifyPassword("p", "p") || VerifyPassword("p", "x") {
+		t.Error("plain verify failed")
+	}
+}
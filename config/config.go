@@ -24,6 +24,20 @@ type GokuConfig struct {
 	MonitorModules map[string]string      `json:"monitor_modules"`
 	RedisConfig    map[string]interface{} `json:"redisConfig"`
 	ExtendsConfig  map[string]interface{} `json:"extends_config"`
+	//ProtoDescriptors protobuf描述文件，key为名称，value为base64编码的FileDescriptorSet
+	ProtoDescriptors map[string]string `json:"protoDescriptors,omitempty"`
+	//RateLimits 限流配置
+	RateLimits []*RateLimitConfig `json:"rateLimits,omitempty"`
+	//Scripts 脚本插件使用的脚本，key为脚本名称
+	Scripts map[string]*ScriptConfig `json:"scripts,omitempty"`
+	//SizeLimits 请求及响应大小限制
+	SizeLimits []*SizeLimitConfig `json:"sizeLimits,omitempty"`
+	//OpenAPIs 用于请求校验的OpenAPI文档，key为名称
+	OpenAPIs map[string]string `json:"openAPIs,omitempty"`
+	//VersionID 配置对应的已发布版本ID，节点应用配置后回报给控制台
+	VersionID int `json:"versionID,omitempty"`
+	//AccessLogOutputs 各集群的access日志输出，key为集群名称，仅保存在版本配置中，下发时写入AccessLog.Outputs
+	AccessLogOutputs map[string][]*AccessLogOutput `json:"accessLogOutputs,omitempty"`
 }
 
 //Router 路由
@@ -48,6 +62,32 @@ type AccessLogConfig struct {
 	Period string   `json:"period"`
 	Expire int      `json:"expire"`
 	Fields []string `json:"fields"`
+	//Outputs 日志输出，为空时输出到Dir下的文件
+	Outputs []*AccessLogOutput `json:"outputs,omitempty"`
+}
+
+//AccessLogOutput access日志输出
+type AccessLogOutput struct {
+	//Type 输出类型：file、stdout、syslog、kafka、http
+	Type string `json:"type"`
+	//Format 日志格式：text或json，file默认为text，其余默认为json
+	Format string `json:"format,omitempty"`
+	//Address syslog为network://host:port，kafka为逗号分隔的broker地址，http为接收日志的URL
+	Address string `json:"address,omitempty"`
+	//Topic kafka主题
+	Topic string `json:"topic,omitempty"`
+	//Tag syslog的APP-NAME
+	Tag string `json:"tag,omitempty"`
+	//Facility syslog的facility，默认为16（local0）
+	Facility int `json:"facility,omitempty"`
+	//Headers http请求头
+	Headers map[string]string `json:"headers,omitempty"`
+	//BufferSize 缓冲的日志条数，缓冲已满时丢弃新日志
+	BufferSize int `json:"bufferSize,omitempty"`
+	//BatchSize 每批写入的最大条数
+	BatchSize int `json:"batchSize,omitempty"`
+	//FlushInterval 凑批的最长等待时间（毫秒）
+	FlushInterval int `json:"flushInterval,omitempty"`
 }
 
 //LogConfig log日志配置
@@ -98,6 +138,30 @@ type PluginConfig struct {
 	Config    string `json:"config"`
 	UpdateTag string `json:"updateTag"`
 	IsAuth    bool   `json:"isAuth"`
+
+	// Priority 同一阶段内按优先级从高到低执行
+	Priority    int    `json:"priority"`
+	ErrorPolicy string `json:"errorPolicy,omitempty"`
+	ErrorStatus int    `json:"errorStatus,omitempty"`
+	ErrorBody   string `json:"errorBody,omitempty"`
+}
+
+const (
+	//PluginErrorContinue 插件出错时继续执行后续插件
+	PluginErrorContinue = "continue"
+	//PluginErrorStop 插件出错时中断请求，插件未设置状态码时返回errorStatus及错误信息
+	PluginErrorStop = "stop"
+	//PluginErrorResponse 插件出错时中断请求，并返回errorStatus及errorBody
+	PluginErrorResponse = "response"
+)
+
+//IsPluginErrorPolicy 是否为合法的插件错误处理策略，空值表示按isStop处理
+func IsPluginErrorPolicy(policy string) bool {
+	switch policy {
+	case "", PluginErrorContinue, PluginErrorStop, PluginErrorResponse:
+		return true
+	}
+	return false
 }
 
 //APIContent api详情
@@ -115,11 +179,14 @@ type APIContent struct {
 
 	StaticResponseStrategy string `json:"static_respone_strategy"`
 	StaticResponse         string `json:"staticResponse"`
+
+	Cache      *ResponseCacheConfig `json:"cache,omitempty"`
+	Validation *ValidationConfig    `json:"validation,omitempty"`
 }
 
 //APIStepConfig 链路配置
 type APIStepConfig struct {
-	Proto   string   `json:"proto"`
+	Proto   string   `json:"proto"` // http | https | grpc | grpcs
 	Balance string   `json:"balance"`
 	Method  string   `json:"method"` // follow | get | post | put ...
 	Path    string   `json:"path"`
@@ -136,11 +203,27 @@ type APIStepConfig struct {
 	Group   string `json:"group"`
 	Retry   int    `json:"retry"`
 	TimeOut int    `json:"timeout"`
+
+	Rewrite *RewriteConfig `json:"rewrite,omitempty"`
+	//GRPCMethod grpc转码的目标方法，格式为 /包名.服务名/方法名，仅在proto为grpc、grpcs时有效；为空时直连转发grpc请求，只支持一元调用
+	GRPCMethod string `json:"grpcMethod,omitempty"`
+}
+
+//RewriteConfig 转发路径及query重写配置
+type RewriteConfig struct {
+	StripPrefix string `json:"stripPrefix"`
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement"` // 支持 $1、${name} 引用正则捕获组
+	AddPrefix   string `json:"addPrefix"`
+
+	QueryAdd    map[string]string `json:"queryAdd,omitempty"`
+	QueryRemove []string          `json:"queryRemove,omitempty"`
+	QueryRename map[string]string `json:"queryRename,omitempty"`
 }
 
 //APIStepUIConfig 链路UI配置
 type APIStepUIConfig struct {
-	Proto   string   `json:"proto"`
+	Proto   string   `json:"proto"` // http | https | grpc | grpcs
 	Balance string   `json:"balance"`
 	Method  string   `json:"method"` // follow | get | post | put ...
 	Path    string   `json:"path"`
@@ -152,13 +235,18 @@ type APIStepUIConfig struct {
 	BlackList []string `json:"blackList"`
 	WhiteList []string `json:"whiteList"`
 
-	Move    []MoveConfig   `json:"move"`
-	Delete  []DeleteConfig `json:"delete"`
-	Rename  []RenameConfig `json:"rename"`
-	Target  string         `json:"target"`
-	Group   string         `json:"group"`
-	Retry   int            `json:"retry"`
-	TimeOut int            `json:"timeout"`
+	Move      []MoveConfig    `json:"move"`
+	Delete    []DeleteConfig  `json:"delete"`
+	Rename    []RenameConfig  `json:"rename"`
+	Transform []*ActionConfig `json:"transform,omitempty"`
+	Target    string          `json:"target"`
+	Group     string          `json:"group"`
+	Retry     int             `json:"retry"`
+	TimeOut   int             `json:"timeout"`
+
+	Rewrite *RewriteConfig `json:"rewrite,omitempty"`
+	//GRPCMethod grpc转码的目标方法，格式为 /包名.服务名/方法名，仅在proto为grpc、grpcs时有效；为空时直连转发grpc请求，只支持一元调用
+	GRPCMethod string `json:"grpcMethod,omitempty"`
 }
 
 //MoveConfig move配置
@@ -183,6 +271,10 @@ type ActionConfig struct {
 	ActionType string `json:"type"`
 	Original   string `json:"original"`
 	Target     string `json:"target"`
+
+	Expression string `json:"expression,omitempty"` // set、default、map 使用的表达式
+	Condition  string `json:"condition,omitempty"`  // 表达式结果为真时才执行该action，filter中针对数组的每一项求值
+	ValueType  string `json:"valueType,omitempty"`  // convert 的目标类型
 }
 
 //StrategyConfig 策略配置
@@ -193,6 +285,8 @@ type StrategyConfig struct {
 	APIS    []*APIOfStrategy  `json:"apis"`
 	AUTH    map[string]string `json:"auth"`
 	Plugins []*PluginConfig   `json:"plugins"`
+	//AuthPolicy 多个鉴权方式的校验策略：any（任一通过）| all（全部通过）
+	AuthPolicy string `json:"authPolicy,omitempty"`
 }
 
 //Gateway 网关配置
@@ -0,0 +1,31 @@
+package config
+
+const (
+	//RateLimitTokenBucket 令牌桶算法
+	RateLimitTokenBucket = "token-bucket"
+	//RateLimitSlidingWindow 滑动窗口算法
+	RateLimitSlidingWindow = "sliding-window"
+
+	//RateLimitModeLocal 单节点计数
+	RateLimitModeLocal = "local"
+	//RateLimitModeRedis 使用redis进行集群计数
+	RateLimitModeRedis = "redis"
+)
+
+//RateLimitConfig 限流配置，只设置StrategyID时作用于整个策略，只设置APIID时作用于接口，两者都设置时作用于策略下的接口
+type RateLimitConfig struct {
+	ID         int    `json:"id"`
+	StrategyID string `json:"strategyID"`
+	APIID      int    `json:"apiID"`
+
+	Algorithm string `json:"algorithm"` // token-bucket | sliding-window
+	Mode      string `json:"mode"`      // local | redis
+	Key       string `json:"key"`       // ip | credential | strategy | header:名称
+
+	Limit  int64 `json:"limit"`  // 每个周期允许的请求数，为0时不限制速率
+	Period int   `json:"period"` // 周期，单位秒
+	Burst  int64 `json:"burst"`  // 令牌桶容量，为0时与limit相同
+
+	DailyQuota   int64 `json:"dailyQuota"`   // 每日配额，为0时不限制
+	MonthlyQuota int64 `json:"monthlyQuota"` // 每月配额，为0时不限制
+}
@@ -0,0 +1,22 @@
+package config
+
+import "strconv"
+
+//ResponseCacheConfig 接口响应缓存配置，只缓存GET/HEAD请求
+type ResponseCacheConfig struct {
+	Enable   bool `json:"enable"`
+	TTL      int  `json:"ttl"`      // 缓存有效期，单位秒，后端Cache-Control指定的有效期不会超过该值
+	StaleTTL int  `json:"staleTTL"` // 过期后允许返回旧响应并刷新的时长，单位秒
+
+	QueryParams []string `json:"queryParams,omitempty"` // 参与缓存key的query参数，为空时使用全部参数
+	Headers     []string `json:"headers,omitempty"`     // 参与缓存key的请求头
+	Consumer    bool     `json:"consumer"`              // 是否按调用方区分缓存
+
+	StatusCodes []int `json:"statusCodes,omitempty"` // 允许缓存的状态码，为空时只缓存200
+	Shared      bool  `json:"shared"`                // 是否使用redis在节点间共享缓存
+}
+
+//ResponseCacheKeyPrefix 接口缓存key前缀
+func ResponseCacheKeyPrefix(apiID int) string {
+	return "goku:cache:" + strconv.Itoa(apiID) + ":"
+}
@@ -0,0 +1,34 @@
+package config
+
+const (
+	//ScriptLanguageJavaScript javascript脚本
+	ScriptLanguageJavaScript = "javascript"
+
+	//ScriptPluginGlobal 全局脚本插件
+	ScriptPluginGlobal = "goku-global_script"
+	//ScriptPluginStrategy 策略脚本插件
+	ScriptPluginStrategy = "goku-script"
+	//ScriptPluginAPI 接口脚本插件
+	ScriptPluginAPI = "goku-api_script"
+)
+
+//ScriptConfig 脚本配置，脚本通过脚本插件的配置 {"scripts":["脚本名称"]} 绑定到全局、策略或接口
+type ScriptConfig struct {
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+	Language string `json:"language"`
+	Content  string `json:"content"`
+
+	Timeout     int `json:"timeout"`     // 单次执行超时时间，单位毫秒
+	MaxDataSize int `json:"maxDataSize"` // 单次执行可读写的请求/响应数据大小上限，单位字节
+}
+
+//ScriptPluginConfig 脚本插件配置
+type ScriptPluginConfig struct {
+	Scripts []string `json:"scripts"`
+}
+
+//IsScriptPlugin 是否为脚本插件
+func IsScriptPlugin(name string) bool {
+	return name == ScriptPluginGlobal || name == ScriptPluginStrategy || name == ScriptPluginAPI
+}
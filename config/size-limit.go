@@ -0,0 +1,26 @@
+package config
+
+//SizeLimitConfig 请求及响应大小限制，单位字节，为0时不限制。
+//StrategyID及APIID都为空时作用于整个网关，同一请求匹配多条规则时取最小值
+type SizeLimitConfig struct {
+	ID         int    `json:"id"`
+	StrategyID string `json:"strategyID"`
+	APIID      int    `json:"apiID"`
+
+	MaxRequestBody  int64 `json:"maxRequestBody"`
+	MaxResponseBody int64 `json:"maxResponseBody"`
+	MaxHeader       int   `json:"maxHeader"`
+}
+
+//ValidationConfig 接口请求校验配置，Body、Query、Headers为JSON Schema，
+//设置OpenAPI时使用文档中的操作校验，Operation为空时按请求方法及路径匹配操作
+type ValidationConfig struct {
+	Enable bool `json:"enable"`
+
+	Body    string `json:"body,omitempty"`
+	Query   string `json:"query,omitempty"`
+	Headers string `json:"headers,omitempty"`
+
+	OpenAPI   string `json:"openAPI,omitempty"`
+	Operation string `json:"operation,omitempty"`
+}
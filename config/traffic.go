@@ -0,0 +1,124 @@
+package config
+
+//TrafficBuckets 流量统计的耗时分桶上界，单位毫秒
+var TrafficBuckets = []float64{5, 25, 50, 100, 200, 400, 600, 800, 1000, 2500, 5000}
+
+//TrafficReport 节点定期通过管理通道上报的流量统计
+type TrafficReport struct {
+	// Time 统计周期的结束时间，unix秒
+	Time int64 `json:"time"`
+	// Period 统计周期，单位秒
+	Period int            `json:"period"`
+	Stats  []*TrafficStat `json:"stats"`
+}
+
+//TrafficStat 按策略及接口聚合的流量统计。
+//LatencyBuckets为各耗时分桶内的请求数，与TrafficBuckets对应，最后一项为超出最大分桶的请求数
+type TrafficStat struct {
+	StrategyID string `json:"strategyID"`
+	APIID      int    `json:"apiID"`
+
+	Requests  uint64 `json:"requests"`
+	Status2xx uint64 `json:"status2xx"`
+	Status3xx uint64 `json:"status3xx"`
+	Status4xx uint64 `json:"status4xx"`
+	Status5xx uint64 `json:"status5xx"`
+
+	LatencySum     float64  `json:"latencySum"`
+	LatencyMax     float64  `json:"latencyMax"`
+	LatencyBuckets []uint64 `json:"latencyBuckets"`
+
+	UpstreamRequests uint64 `json:"upstreamRequests"`
+	UpstreamErrors   uint64 `json:"upstreamErrors"`
+}
+
+//NewTrafficStat 创建流量统计
+func NewTrafficStat(strategyID string, apiID int) *TrafficStat {
+	return &TrafficStat{
+		StrategyID:     strategyID,
+		APIID:          apiID,
+		LatencyBuckets: make([]uint64, len(TrafficBuckets)+1),
+	}
+}
+
+//ObserveRequest 记录一次网关请求
+func (s *TrafficStat) ObserveRequest(status int, latency float64) {
+	s.Requests++
+	switch {
+	case status >= 500:
+		s.Status5xx++
+	case status >= 400:
+		s.Status4xx++
+	case status >= 300:
+		s.Status3xx++
+	case status >= 200:
+		s.Status2xx++
+	}
+	s.LatencySum += latency
+	if latency > s.LatencyMax {
+		s.LatencyMax = latency
+	}
+	i := 0
+	for i < len(TrafficBuckets) && latency > TrafficBuckets[i] {
+		i++
+	}
+	s.LatencyBuckets[i]++
+}
+
+//ObserveUpstream 记录一次转发，status为0（连接失败或超时）或5xx时计为上游错误
+func (s *TrafficStat) ObserveUpstream(status int) {
+	s.UpstreamRequests++
+	if status == 0 || status >= 500 {
+		s.UpstreamErrors++
+	}
+}
+
+//Merge 合并其他统计
+func (s *TrafficStat) Merge(o *TrafficStat) {
+	s.Requests += o.Requests
+	s.Status2xx += o.Status2xx
+	s.Status3xx += o.Status3xx
+	s.Status4xx += o.Status4xx
+	s.Status5xx += o.Status5xx
+	s.LatencySum += o.LatencySum
+	if o.LatencyMax > s.LatencyMax {
+		s.LatencyMax = o.LatencyMax
+	}
+	for i := 0; i < len(o.LatencyBuckets) && i < len(s.LatencyBuckets); i++ {
+		s.LatencyBuckets[i] += o.LatencyBuckets[i]
+	}
+	s.UpstreamRequests += o.UpstreamRequests
+	s.UpstreamErrors += o.UpstreamErrors
+}
+
+//Percentile 按分桶线性插值估算耗时分位数，q取值0~1
+func (s *TrafficStat) Percentile(q float64) float64 {
+	var total uint64
+	for _, c := range s.LatencyBuckets {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	target := q * float64(total)
+	var count uint64
+	for i, c := range s.LatencyBuckets {
+		if c == 0 || float64(count+c) < target {
+			count += c
+			continue
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = TrafficBuckets[i-1]
+		}
+		upper := s.LatencyMax
+		if i < len(TrafficBuckets) && TrafficBuckets[i] < upper {
+			upper = TrafficBuckets[i]
+		}
+		if upper < lower {
+			return upper
+		}
+		return lower + (upper-lower)*(target-float64(count))/float64(c)
+	}
+	return s.LatencyMax
+}
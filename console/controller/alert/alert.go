@@ -0,0 +1,92 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationAlert = "gateway"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/rule/add":            factory.NewAccountHandleFunction(operationAlert, true, AddRule),
+		"/rule/edit":           factory.NewAccountHandleFunction(operationAlert, true, EditRule),
+		"/rule/getInfo":        factory.NewAccountHandleFunction(operationAlert, false, GetRule),
+		"/rule/getList":        factory.NewAccountHandleFunction(operationAlert, false, GetRuleList),
+		"/rule/batchDelete":    factory.NewAccountHandleFunction(operationAlert, true, BatchDeleteRule),
+		"/channel/add":         factory.NewAccountHandleFunction(operationAlert, true, AddChannel),
+		"/channel/edit":        factory.NewAccountHandleFunction(operationAlert, true, EditChannel),
+		"/channel/getInfo":     factory.NewAccountHandleFunction(operationAlert, false, GetChannel),
+		"/channel/getList":     factory.NewAccountHandleFunction(operationAlert, false, GetChannelList),
+		"/channel/batchDelete": factory.NewAccountHandleFunction(operationAlert, true, BatchDeleteChannel),
+		"/channel/test":        factory.NewAccountHandleFunction(operationAlert, true, TestChannel),
+		"/silence/add":         factory.NewAccountHandleFunction(operationAlert, true, AddSilence),
+		"/silence/getList":     factory.NewAccountHandleFunction(operationAlert, false, GetSilenceList),
+		"/silence/batchDelete": factory.NewAccountHandleFunction(operationAlert, true, BatchDeleteSilence),
+		"/history/getList":     factory.NewAccountHandleFunction(operationAlert, false, GetHistoryList),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseIDList 读取以逗号分隔的ID列表，列表为空时写入错误并返回false
+func parseIDList(httpResponse http.ResponseWriter, httpRequest *http.Request) ([]int, bool) {
+	ids := make([]int, 0)
+	for _, v := range strings.Split(httpRequest.PostFormValue("idList"), ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		errInfo := "[ERROR]Illegal idList!"
+		controller.WriteError(httpResponse,
+			"510003",
+			"alert",
+			errInfo,
+			errors.New(errInfo))
+		return nil, false
+	}
+	return ids, true
+}
+
+// parseID 读取整数ID参数，不合法时写入错误并返回false
+func parseID(httpResponse http.ResponseWriter, value string) (int, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510002",
+			"alert",
+			"[ERROR]Illegal id!",
+			err)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeResult 操作失败时写入错误，否则写入结果
+func writeResult(httpResponse http.ResponseWriter, key string, value interface{}, err error) {
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510000",
+			"alert",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", key, value)
+}
@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+// parseChannel 读取表单中的通知渠道
+func parseChannel(httpRequest *http.Request) *entity.AlertChannel {
+	return &entity.AlertChannel{
+		Name:      httpRequest.PostFormValue("name"),
+		Type:      httpRequest.PostFormValue("type"),
+		Config:    httpRequest.PostFormValue("config"),
+		IsDefault: httpRequest.PostFormValue("isDefault") == "1",
+		Enable:    httpRequest.PostFormValue("enable") != "0",
+	}
+}
+
+//AddChannel 新增通知渠道，type为webhook、email或chat，config为对应类型的JSON配置
+func AddChannel(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	id, err := alert.AddChannel(parseChannel(httpRequest))
+	writeResult(httpResponse, "channelID", id, err)
+}
+
+//EditChannel 编辑通知渠道
+func EditChannel(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	channelID, ok := parseID(httpResponse, httpRequest.PostFormValue("channelID"))
+	if !ok {
+		return
+	}
+	c := parseChannel(httpRequest)
+	c.ChannelID = channelID
+	writeResult(httpResponse, "", nil, alert.EditChannel(c))
+}
+
+//GetChannel 获取通知渠道
+func GetChannel(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	channelID, ok := parseID(httpResponse, httpRequest.Form.Get("channelID"))
+	if !ok {
+		return
+	}
+	c, err := alert.GetChannel(channelID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510005",
+			"alert",
+			"[ERROR]The alert channel does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "channel", c)
+}
+
+//GetChannelList 获取通知渠道列表
+func GetChannelList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := alert.GetChannelList()
+	writeResult(httpResponse, "channelList", list, err)
+}
+
+//BatchDeleteChannel 批量删除通知渠道，idList以逗号分隔
+func BatchDeleteChannel(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ids, ok := parseIDList(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	writeResult(httpResponse, "", nil, alert.BatchDeleteChannel(ids))
+}
+
+//TestChannel 通过通知渠道发送测试告警
+func TestChannel(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	channelID, ok := parseID(httpResponse, httpRequest.PostFormValue("channelID"))
+	if !ok {
+		return
+	}
+	writeResult(httpResponse, "", nil, alert.TestChannel(channelID))
+}
@@ -0,0 +1,38 @@
+package alert
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+)
+
+//GetHistoryList 分页获取告警记录，可按ruleID及status（firing、resolved）筛选
+func GetHistoryList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	ruleID, _ := strconv.Atoi(httpRequest.Form.Get("ruleID"))
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	list, count, err := alert.GetHistoryList(ruleID, httpRequest.Form.Get("status"), page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510000",
+			"alert",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "alert", "historyList", list, &controller.PageInfo{
+		ItemNum:  len(list),
+		TotalNum: count,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+// parseRule 读取表单中的告警规则，返回出错的参数名
+func parseRule(httpRequest *http.Request) (*entity.AlertRule, string, error) {
+	r := &entity.AlertRule{
+		Name:       httpRequest.PostFormValue("name"),
+		Metric:     httpRequest.PostFormValue("metric"),
+		Cluster:    httpRequest.PostFormValue("cluster"),
+		StrategyID: httpRequest.PostFormValue("strategyID"),
+		Channels:   make([]int, 0),
+		Enable:     httpRequest.PostFormValue("enable") != "0",
+	}
+	ints := map[string]*int{
+		"apiID":       &r.APIID,
+		"duration":    &r.Duration,
+		"minRequests": &r.MinRequests,
+	}
+	for name, target := range ints {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	if v := httpRequest.PostFormValue("threshold"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, "threshold", err
+		}
+		r.Threshold = f
+	}
+	if v := httpRequest.PostFormValue("channels"); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return nil, "channels", err
+			}
+			r.Channels = append(r.Channels, id)
+		}
+	}
+	return r, "", nil
+}
+
+//AddRule 新增告警规则
+func AddRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	r, name, err := parseRule(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510001",
+			"alert",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	id, err := alert.AddRule(r)
+	writeResult(httpResponse, "ruleID", id, err)
+}
+
+//EditRule 编辑告警规则
+func EditRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ruleID, ok := parseID(httpResponse, httpRequest.PostFormValue("ruleID"))
+	if !ok {
+		return
+	}
+	r, name, err := parseRule(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510001",
+			"alert",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	r.RuleID = ruleID
+	writeResult(httpResponse, "", nil, alert.EditRule(r))
+}
+
+//GetRule 获取告警规则
+func GetRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	ruleID, ok := parseID(httpResponse, httpRequest.Form.Get("ruleID"))
+	if !ok {
+		return
+	}
+	r, err := alert.GetRule(ruleID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"510004",
+			"alert",
+			"[ERROR]The alert rule does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "alert", "rule", r)
+}
+
+//GetRuleList 获取告警规则列表
+func GetRuleList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := alert.GetRuleList()
+	writeResult(httpResponse, "ruleList", list, err)
+}
+
+//BatchDeleteRule 批量删除告警规则，idList以逗号分隔
+func BatchDeleteRule(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ids, ok := parseIDList(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	writeResult(httpResponse, "", nil, alert.BatchDeleteRule(ids))
+}
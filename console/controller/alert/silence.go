@@ -0,0 +1,66 @@
+package alert
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/alert"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//AddSilence 新增静默，startTime、endTime为unix秒，ruleID为0时匹配所有规则，target为空时匹配所有告警对象
+func AddSilence(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	s := &entity.AlertSilence{
+		Target:       httpRequest.PostFormValue("target"),
+		Comment:      httpRequest.PostFormValue("comment"),
+		CreateUserID: goku_handler.UserIDFromRequest(httpRequest),
+	}
+	var err error
+	if v := httpRequest.PostFormValue("ruleID"); v != "" {
+		if s.RuleID, err = strconv.Atoi(v); err != nil {
+			controller.WriteError(httpResponse,
+				"510001",
+				"alert",
+				"[ERROR]Illegal ruleID!",
+				err)
+			return
+		}
+	}
+	if v := httpRequest.PostFormValue("startTime"); v != "" {
+		if s.StartTime, err = strconv.ParseInt(v, 10, 64); err != nil {
+			controller.WriteError(httpResponse,
+				"510001",
+				"alert",
+				"[ERROR]Illegal startTime!",
+				err)
+			return
+		}
+	}
+	if s.EndTime, err = strconv.ParseInt(httpRequest.PostFormValue("endTime"), 10, 64); err != nil {
+		controller.WriteError(httpResponse,
+			"510001",
+			"alert",
+			"[ERROR]Illegal endTime!",
+			err)
+		return
+	}
+	id, err := alert.AddSilence(s)
+	writeResult(httpResponse, "silenceID", id, err)
+}
+
+//GetSilenceList 获取未过期的静默列表
+func GetSilenceList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := alert.GetSilenceList()
+	writeResult(httpResponse, "silenceList", list, err)
+}
+
+//BatchDeleteSilence 批量删除静默，idList以逗号分隔
+func BatchDeleteSilence(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	ids, ok := parseIDList(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
+	writeResult(httpResponse, "", nil, alert.BatchDeleteSilence(ids))
+}
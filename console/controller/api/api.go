@@ -12,8 +12,10 @@ import (
 
 	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/console/controller"
 	"github.com/eolinker/goku-api-gateway/console/module/api"
+	node_rewrite "github.com/eolinker/goku-api-gateway/node/gateway/application/rewrite"
 )
 
 const operationAPI = "apiManagement"
@@ -63,6 +65,7 @@ func AddAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	apiType := httpRequest.PostFormValue("apiType")
 	linkApis := httpRequest.PostFormValue("linkApis")
 	staticResponse := httpRequest.PostFormValue("staticResponse")
+	rewrite := httpRequest.PostFormValue("rewrite")
 	responseDataType := httpRequest.PostFormValue("responseDataType")
 	userID := goku_handler.UserIDFromRequest(httpRequest)
 	if apiName == "" {
@@ -139,13 +142,17 @@ func AddAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	if managerID == "" {
 		mgID = userID
 	}
+	if !checkRewrite(rewrite) {
+		controller.WriteError(httpResponse, "190023", "api", "[ERROR]Illegal rewrite!", nil)
+		return
+	}
 	if api.CheckAliasIsExist(0, alias) {
 		errInfo := "[ERROR]duplicate alias!"
 		controller.WriteError(httpResponse, "190020", "api", errInfo, errors.New(errInfo))
 		return
 	}
 
-	flag, id, err := api.AddAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkApis, staticResponse, responseDataType, balanceName, protocol, pjID, gID, t, count, apiValve, mgID, userID, aType)
+	flag, id, err := api.AddAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkApis, staticResponse, rewrite, responseDataType, balanceName, protocol, pjID, gID, t, count, apiValve, mgID, userID, aType)
 	if !flag {
 
 		controller.WriteError(httpResponse,
@@ -178,6 +185,7 @@ func EditAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	managerID := httpRequest.PostFormValue("managerID")
 	linkApis := httpRequest.PostFormValue("linkApis")
 	staticResponse := httpRequest.PostFormValue("staticResponse")
+	rewrite := httpRequest.PostFormValue("rewrite")
 	responseDataType := httpRequest.PostFormValue("responseDataType")
 	userID := goku_handler.UserIDFromRequest(httpRequest)
 
@@ -248,13 +256,17 @@ func EditAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	if managerID == "" {
 		mgID = userID
 	}
+	if !checkRewrite(rewrite) {
+		controller.WriteError(httpResponse, "190023", "api", "[ERROR]Illegal rewrite!", nil)
+		return
+	}
 	if api.CheckAliasIsExist(aID, alias) {
 		errInfo := "[ERROR]duplicate alias!"
 		controller.WriteError(httpResponse, "190020", "api", errInfo, errors.New(errInfo))
 		return
 	}
 
-	flag, err := api.EditAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkApis, staticResponse, responseDataType, balanceName, protocol, pjID, gID, t, count, apiValve, aID, mgID, userID)
+	flag, err := api.EditAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkApis, staticResponse, rewrite, responseDataType, balanceName, protocol, pjID, gID, t, count, apiValve, aID, mgID, userID)
 	if !flag {
 
 		controller.WriteError(httpResponse, "190000", "api", "[ERROR]apiID does not exist!", err)
@@ -493,7 +505,12 @@ func CopyAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		return
 	}
 	linkApis, _ := json.Marshal(apiInfo.LinkAPIs)
-	flag, id, err := api.AddAPI(apiName, alisa, requestURL, targetURL, requestMethod, targetMethod, isFollow, string(linkApis), apiInfo.StaticResponse, apiInfo.ResponseDataType, balanceName, protocol, pjID, gID, apiInfo.Timeout, apiInfo.RetryConut, apiInfo.Valve, apiInfo.ManagerID, userID, apiInfo.APIType)
+	rewrite := ""
+	if apiInfo.Rewrite != nil {
+		r, _ := json.Marshal(apiInfo.Rewrite)
+		rewrite = string(r)
+	}
+	flag, id, err := api.AddAPI(apiName, alisa, requestURL, targetURL, requestMethod, targetMethod, isFollow, string(linkApis), apiInfo.StaticResponse, rewrite, apiInfo.ResponseDataType, balanceName, protocol, pjID, gID, apiInfo.Timeout, apiInfo.RetryConut, apiInfo.Valve, apiInfo.ManagerID, userID, apiInfo.APIType)
 	if !flag {
 		controller.WriteError(httpResponse, "190000", "api", "[ERROR]Fail to add api!", err)
 		return
@@ -503,3 +520,16 @@ func CopyAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	controller.WriteResultInfo(httpResponse, "api", "apiID", id)
 	return
 }
+
+//checkRewrite 校验单步接口的转发重写配置，为空表示不重写
+func checkRewrite(rewrite string) bool {
+	if rewrite == "" {
+		return true
+	}
+	cfg := new(config.RewriteConfig)
+	if err := json.Unmarshal([]byte(rewrite), cfg); err != nil {
+		return false
+	}
+	_, err := node_rewrite.New(cfg)
+	return err == nil
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/audit"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationAudit = "logManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/getList": factory.NewAccountHandleFunction(operationAudit, false, GetAuditLogList),
+		"/export":  factory.NewAccountHandleFunction(operationAudit, false, ExportAuditLog),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseFilter 读取查询条件，返回出错的参数名
+func parseFilter(httpRequest *http.Request) (*entity.AuditFilter, string, error) {
+	f := &entity.AuditFilter{
+		EntityType: httpRequest.Form.Get("entityType"),
+		EntityID:   httpRequest.Form.Get("entityID"),
+		Start:      httpRequest.Form.Get("startTime"),
+		End:        httpRequest.Form.Get("endTime"),
+	}
+	ints := map[string]*int{
+		"userID":    &f.UserID,
+		"versionID": &f.VersionID,
+	}
+	for name, target := range ints {
+		v := httpRequest.Form.Get(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	return f, "", nil
+}
+
+//GetAuditLogList 分页获取审计日志，可按userID、entityType、entityID、versionID及时间范围（startTime、endTime）筛选
+func GetAuditLogList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	filter, name, err := parseFilter(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"520001",
+			"audit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	list, count, err := audit.GetLogList(filter, page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"520000",
+			"audit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfoWithPage(httpResponse, "audit", "logList", list, &controller.PageInfo{
+		ItemNum:  len(list),
+		TotalNum: count,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+//ExportAuditLog 导出符合条件的审计日志，format为json（默认）或csv
+func ExportAuditLog(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	filter, name, err := parseFilter(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"520001",
+			"audit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	format := httpRequest.Form.Get("format")
+	if format == "" {
+		format = audit.FormatJSON
+	}
+	data, err := audit.Export(filter, format)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"520000",
+			"audit",
+			err.Error(),
+			err)
+		return
+	}
+	contentType := "application/json; charset=utf-8"
+	if format == audit.FormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	httpResponse.Header().Set("Content-Type", contentType)
+	httpResponse.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"goku-audit.%s\"", format))
+	httpResponse.WriteHeader(http.StatusOK)
+	httpResponse.Write(data)
+}
@@ -80,14 +80,16 @@ func EditAuthInfo(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 
 	strategyID := httpRequest.PostFormValue("strategyID")
 	strategyName := httpRequest.PostFormValue("strategyName")
+	authPolicy := httpRequest.PostFormValue("authPolicy")
 	basicAuthList := httpRequest.PostFormValue("basicAuthList")
 	apikeyList := httpRequest.PostFormValue("apiKeyList")
 	jwtCredentialList := httpRequest.PostFormValue("jwtCredentialList")
+	hmacCredentialList := httpRequest.PostFormValue("hmacCredentialList")
 	oauth2CredentialList := httpRequest.PostFormValue("oauth2CredentialList")
 	delClientIDList := httpRequest.PostFormValue("deleteClientIDList")
 
 	idList := strings.Split(delClientIDList, ",")
-	flag, err := auth.EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyList, jwtCredentialList, oauth2CredentialList, idList)
+	flag, err := auth.EditAuthInfo(strategyID, strategyName, authPolicy, basicAuthList, apikeyList, jwtCredentialList, hmacCredentialList, oauth2CredentialList, idList)
 	if !flag {
 		controller.WriteError(httpResponse, "250000", "auth", "[ERROR]Fail to edit auth!", err)
 
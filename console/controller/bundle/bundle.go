@@ -0,0 +1,120 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/bundle"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationBundle = "versionManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/export": factory.NewAccountHandleFunction(operationBundle, false, ExportBundle),
+		"/apply":  factory.NewAccountHandleFunction(operationBundle, true, ApplyBundle),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+//ExportBundle 导出控制台配置包，format为yaml（默认）或json，鉴权凭证以占位值导出
+func ExportBundle(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	format := httpRequest.Form.Get("format")
+	if format == "" {
+		format = bundle.FormatYAML
+	}
+	b, err := bundle.Export(false)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"490003",
+			"bundle",
+			"[ERROR]Fail to export bundle!",
+			err)
+		return
+	}
+	data, err := bundle.Encode(b, format)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"490004",
+			"bundle",
+			err.Error(),
+			err)
+		return
+	}
+	contentType := "application/x-yaml; charset=utf-8"
+	if format == bundle.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	httpResponse.Header().Set("Content-Type", contentType)
+	httpResponse.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"goku-bundle.%s\"", format))
+	httpResponse.WriteHeader(http.StatusOK)
+	httpResponse.Write(data)
+}
+
+//ApplyBundle 应用控制台配置包，dryRun为true时只返回变更计划
+func ApplyBundle(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	data, err := readContent(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"490001",
+			"bundle",
+			err.Error(),
+			err)
+		return
+	}
+	b, err := bundle.Decode(data)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"490002",
+			"bundle",
+			"[ERROR]Illegal bundle!",
+			err)
+		return
+	}
+	plan, err := bundle.Apply(b, goku_handler.UserIDFromRequest(httpRequest), httpRequest.PostFormValue("dryRun") == "true")
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"490000",
+			"bundle",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "bundle", "plan", plan)
+}
+
+// readContent 读取上传的配置包，multipart请求读取file参数，否则读取content参数
+func readContent(httpRequest *http.Request) ([]byte, error) {
+	if !strings.Contains(httpRequest.Header.Get("Content-Type"), "multipart/form-data") {
+		content := httpRequest.PostFormValue("content")
+		if content == "" {
+			return nil, errors.New("[ERROR]Param content does not exist!")
+		}
+		return []byte(content), nil
+	}
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		return nil, errors.New("[ERROR]Param file does not exist!")
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, errors.New("[ERROR]Fail to read file!")
+	}
+	return data, nil
+}
@@ -22,11 +22,19 @@ type VersionHandlers struct {
 //Handlers handlers
 func (h *VersionHandlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
 	return map[string]http.Handler{
-		"/add":        factory.NewAccountHandleFunction(operationVersion, true, AddVersionConfig),
-		"/basic/edit": factory.NewAccountHandleFunction(operationVersion, true, EditVersionBasicConfig),
-		"/delete":     factory.NewAccountHandleFunction(operationVersion, true, BatchDeleteVersionConfig),
-		"/getList":    factory.NewAccountHandleFunction(operationVersion, false, GetVersionList),
-		"/publish":    factory.NewAccountHandleFunction(operationVersion, true, PublishVersion),
+		"/add":             factory.NewAccountHandleFunction(operationVersion, true, AddVersionConfig),
+		"/basic/edit":      factory.NewAccountHandleFunction(operationVersion, true, EditVersionBasicConfig),
+		"/delete":          factory.NewAccountHandleFunction(operationVersion, true, BatchDeleteVersionConfig),
+		"/getList":         factory.NewAccountHandleFunction(operationVersion, false, GetVersionList),
+		"/publish":         factory.NewAccountHandleFunction(operationVersion, true, PublishVersion),
+		"/diff":            factory.NewAccountHandleFunction(operationVersion, false, DiffVersion),
+		"/validate":        factory.NewAccountHandleFunction(operationVersion, false, ValidateVersion),
+		"/publish/approve": factory.NewAccountHandleFunction(operationVersion, true, ApprovePublish),
+		"/publish/reject":  factory.NewAccountHandleFunction(operationVersion, true, RejectPublish),
+		"/publish/cancel":  factory.NewAccountHandleFunction(operationVersion, true, CancelPublish),
+		"/publish/getList": factory.NewAccountHandleFunction(operationVersion, false, GetPublishList),
+		"/rollback":        factory.NewAccountHandleFunction(operationVersion, true, RollbackVersion),
+		"/nodes":           factory.NewAccountHandleFunction(operationVersion, false, GetAffectedNodes),
 	}
 }
 
@@ -70,7 +78,19 @@ func AddVersionConfig(httpResponse http.ResponseWriter, httpRequest *http.Reques
 	}
 
 	if p == 1 {
-		versionConfig.PublishVersion(id, userID, now)
+		if !checkVersion(httpResponse, httpRequest, id) {
+			return
+		}
+		request, err := versionConfig.RequestPublish(id, userID, 0, remark)
+		if err != nil {
+			controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+			return
+		}
+		controller.WriteResultInfo(httpResponse,
+			"versionConfig",
+			"publish",
+			request)
+		return
 	}
 	controller.WriteResultInfo(httpResponse,
 		"versionConfig",
@@ -133,7 +153,8 @@ func BatchDeleteVersionConfig(httpResponse http.ResponseWriter, httpRequest *htt
 	return
 }
 
-//PublishVersion 发布版本
+//PublishVersion 发布版本，开启审批或指定计划发布时间（publishTime，unix秒）时生成发布申请；
+//版本配置校验不通过时拒绝发布，force为1时忽略校验结果
 func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 	httpRequest.ParseForm()
 	versionID := httpRequest.Form.Get("versionID")
@@ -143,8 +164,18 @@ func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request)
 		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
 		return
 	}
-	now := time.Now().Format("2006-01-02 15:04:05")
-	err = versionConfig.PublishVersion(id, userID, now)
+	var publishTime int64
+	if t := httpRequest.Form.Get("publishTime"); t != "" {
+		publishTime, err = strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			controller.WriteError(httpResponse, "380005", "versionConfig", "[ERROR]Illegal publishTime", err)
+			return
+		}
+	}
+	if !checkVersion(httpResponse, httpRequest, id) {
+		return
+	}
+	request, err := versionConfig.RequestPublish(id, userID, publishTime, httpRequest.Form.Get("remark"))
 	if err != nil {
 		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
 		return
@@ -152,7 +183,7 @@ func PublishVersion(httpResponse http.ResponseWriter, httpRequest *http.Request)
 
 	controller.WriteResultInfo(httpResponse,
 		"versionConfig",
-		"",
-		nil)
+		"publish",
+		request)
 	return
 }
@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//StartCanary 将版本灰度发布到节点分组，observeTime为观察时长（秒），errorThreshold为允许升高的5xx比例（百分比），
+//autoPromote为1时观察期结束后自动全量发布，force为1时忽略版本配置校验结果
+func StartCanary(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	c := &entity.VersionCanary{
+		AutoPromote: httpRequest.Form.Get("autoPromote") == "1",
+		UserID:      goku_handler.UserIDFromRequest(httpRequest),
+	}
+	var err error
+	if c.VersionID, err = strconv.Atoi(httpRequest.Form.Get("versionID")); err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	if c.GroupID, err = strconv.Atoi(httpRequest.Form.Get("groupID")); err != nil || c.GroupID < 1 {
+		controller.WriteError(httpResponse, "380007", "versionConfig", "[ERROR]Illegal groupID", err)
+		return
+	}
+	if c.ObserveTime, err = formInt(httpRequest, "observeTime"); err != nil || c.ObserveTime < 0 {
+		controller.WriteError(httpResponse, "380009", "versionConfig", "[ERROR]Illegal observeTime", err)
+		return
+	}
+	if c.ErrorThreshold, err = strconv.ParseFloat(httpRequest.Form.Get("errorThreshold"), 64); err != nil {
+		controller.WriteError(httpResponse, "380010", "versionConfig", "[ERROR]Illegal errorThreshold", err)
+		return
+	}
+	if c.MinRequests, err = formInt(httpRequest, "minRequests"); err != nil || c.MinRequests < 0 {
+		controller.WriteError(httpResponse, "380011", "versionConfig", "[ERROR]Illegal minRequests", err)
+		return
+	}
+	if !checkVersion(httpResponse, httpRequest, c.VersionID) {
+		return
+	}
+	c, err = versionConfig.StartCanary(c)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"canary",
+		c)
+}
+
+//PromoteCanary 将灰度版本发布到全部节点，开启审批时生成待审批的发布申请
+func PromoteCanary(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	canaryID, err := strconv.Atoi(httpRequest.Form.Get("canaryID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380008", "versionConfig", "[ERROR]Illegal canaryID", err)
+		return
+	}
+	userID := goku_handler.UserIDFromRequest(httpRequest)
+	request, err := versionConfig.PromoteCanary(canaryID, userID, httpRequest.Form.Get("reason"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"publish",
+		request)
+}
+
+//RollbackCanary 结束灰度发布，灰度节点恢复为当前发布版本
+func RollbackCanary(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	canaryID, err := strconv.Atoi(httpRequest.Form.Get("canaryID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380008", "versionConfig", "[ERROR]Illegal canaryID", err)
+		return
+	}
+	err = versionConfig.RollbackCanary(canaryID, httpRequest.Form.Get("reason"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"",
+		nil)
+}
+
+//GetRunningCanary 获取进行中的灰度发布及灰度节点与其余节点的错误率
+func GetRunningCanary(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	s, err := versionConfig.GetRunningCanary()
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"canary",
+		s)
+}
+
+//GetCanaryList 分页获取灰度发布记录
+func GetCanaryList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	list, count, err := versionConfig.GetCanaryList(page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfoWithPage(httpResponse,
+		"versionConfig",
+		"canaryList",
+		list,
+		&controller.PageInfo{
+			ItemNum:  len(list),
+			TotalNum: count,
+			Page:     page,
+			PageSize: pageSize,
+		})
+}
@@ -0,0 +1,158 @@
+package cluster
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+// formInt 读取整数参数，参数为空时返回0
+func formInt(httpRequest *http.Request, name string) (int, error) {
+	v := httpRequest.Form.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+//DiffVersion 比较两个版本的配置，fromVersionID为空时与当前发布版本比较
+func DiffVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	fromID, err := formInt(httpRequest, "fromVersionID")
+	if err != nil {
+		controller.WriteError(httpResponse, "380006", "versionConfig", "[ERROR]Illegal fromVersionID", err)
+		return
+	}
+	toID, err := strconv.Atoi(httpRequest.Form.Get("versionID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	d, err := versionConfig.DiffVersions(fromID, toID)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"diff",
+		d)
+}
+
+func handlePublishRequest(httpResponse http.ResponseWriter, httpRequest *http.Request, handle func(requestID, userID int) error) {
+	httpRequest.ParseForm()
+	requestID, err := strconv.Atoi(httpRequest.Form.Get("requestID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380004", "versionConfig", "[ERROR]Illegal requestID", err)
+		return
+	}
+	err = handle(requestID, goku_handler.UserIDFromRequest(httpRequest))
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"",
+		nil)
+}
+
+//ApprovePublish 审批通过发布申请
+func ApprovePublish(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	handlePublishRequest(httpResponse, httpRequest, versionConfig.ApprovePublish)
+}
+
+//RejectPublish 驳回发布申请
+func RejectPublish(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	handlePublishRequest(httpResponse, httpRequest, versionConfig.RejectPublish)
+}
+
+//CancelPublish 撤销发布申请
+func CancelPublish(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	handlePublishRequest(httpResponse, httpRequest, func(requestID, userID int) error {
+		return versionConfig.CancelPublish(requestID)
+	})
+}
+
+//GetPublishList 分页获取发布记录，可按status筛选
+func GetPublishList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	page, err := strconv.Atoi(httpRequest.Form.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(httpRequest.Form.Get("pageSize"))
+	if err != nil || pageSize < 1 {
+		pageSize = 15
+	}
+	list, count, err := versionConfig.GetPublishList(httpRequest.Form.Get("status"), page, pageSize)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfoWithPage(httpResponse,
+		"versionConfig",
+		"publishList",
+		list,
+		&controller.PageInfo{
+			ItemNum:  len(list),
+			TotalNum: count,
+			Page:     page,
+			PageSize: pageSize,
+		})
+}
+
+//RollbackVersion 回滚版本，versionID为空时回滚到上一个发布的版本，返回受影响的节点；未发布过的版本需要审批
+func RollbackVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	versionID, err := formInt(httpRequest, "versionID")
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	userID := goku_handler.UserIDFromRequest(httpRequest)
+	request, err := versionConfig.Rollback(versionID, userID, httpRequest.Form.Get("remark"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+	nodes, err := versionConfig.AffectedNodes(request.VersionID)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"rollback",
+		map[string]interface{}{
+			"publish": request,
+			"nodes":   nodes,
+		})
+}
+
+//GetAffectedNodes 获取各节点对版本的确认情况，versionID为空时使用当前发布版本
+func GetAffectedNodes(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	versionID, err := formInt(httpRequest, "versionID")
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	nodes, err := versionConfig.AffectedNodes(versionID)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"nodes",
+		nodes)
+}
@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/versionConfig"
+)
+
+// 发布被拒绝时错误信息中最多列出的问题数
+const maxProblemsInError = 5
+
+//ValidateVersion 校验版本配置，返回各集群配置中的问题
+func ValidateVersion(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	versionID, err := strconv.Atoi(httpRequest.Form.Get("versionID"))
+	if err != nil {
+		controller.WriteError(httpResponse, "380002", "versionConfig", "[ERROR]Illegal versionID", err)
+		return
+	}
+	problems, err := versionConfig.ValidateVersion(versionID)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return
+	}
+
+	controller.WriteResultInfo(httpResponse,
+		"versionConfig",
+		"problems",
+		problems)
+}
+
+// checkVersion 发布前校验版本配置，存在问题且未指定force=1时拒绝发布并返回false
+func checkVersion(httpResponse http.ResponseWriter, httpRequest *http.Request, versionID int) bool {
+	if httpRequest.Form.Get("force") == "1" {
+		return true
+	}
+	problems, err := versionConfig.ValidateVersion(versionID)
+	if err != nil {
+		controller.WriteError(httpResponse, "380000", "versionConfig", err.Error(), err)
+		return false
+	}
+	if len(problems) == 0 {
+		return true
+	}
+	msgs := make([]string, 0, maxProblemsInError)
+	for i, p := range problems {
+		if i == maxProblemsInError {
+			msgs = append(msgs, "...")
+			break
+		}
+		msgs = append(msgs, p.String())
+	}
+	msg := "[ERROR]The version has " + strconv.Itoa(len(problems)) + " config problems: " + strings.Join(msgs, "; ")
+	controller.WriteError(httpResponse, "380012", "versionConfig", msg, errors.New(msg))
+	return false
+}
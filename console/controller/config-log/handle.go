@@ -15,9 +15,10 @@ type Handlers struct {
 //Handlers handlers
 func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
 	return map[string]http.Handler{
-		"/console": NewLogHandler("console", factory),
-		"/node":    NewLogHandler("node", factory),
-		"/access":  NewAccessHandler(factory),
+		"/console":       NewLogHandler("console", factory),
+		"/node":          NewLogHandler("node", factory),
+		"/access":        NewAccessHandler(factory),
+		"/access/output": NewAccessOutputHandler(factory),
 	}
 }
 
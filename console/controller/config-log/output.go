@@ -0,0 +1,61 @@
+package config_log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	module "github.com/eolinker/goku-api-gateway/console/module/config-log"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+//AccessOutputGet 获取集群的access日志输出
+func AccessOutputGet(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		controller.WriteError(w, "260000", "data", "[param_check] Parse form body error | 解析form表单参数错误", err)
+		return
+	}
+	outputs, err := module.GetAccessOutputs(r.Form.Get("cluster"))
+	if err != nil {
+		controller.WriteError(w, "260000", "data", fmt.Sprintf("[param_check] %s", err.Error()), err)
+		return
+	}
+
+	controller.WriteResultInfo(w,
+		"data",
+		"data",
+		outputs)
+}
+
+//AccessOutputSet 设置集群的access日志输出，outputs为输出配置的json数组
+func AccessOutputSet(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		controller.WriteError(w, "260000", "data", "[param_check] Parse form body error | 解析form表单参数错误", err)
+		return
+	}
+	outputs := make([]*config.AccessLogOutput, 0)
+	if v := r.Form.Get("outputs"); v != "" {
+		if err := json.Unmarshal([]byte(v), &outputs); err != nil {
+			controller.WriteError(w, "260000", "data", "[param_check] inval outputs", err)
+			return
+		}
+	}
+	if err := module.SetAccessOutputs(r.Form.Get("cluster"), outputs); err != nil {
+		controller.WriteError(w, "260000", "data", fmt.Sprintf("[param_check] %s", err.Error()), err)
+		return
+	}
+	controller.WriteResultInfo(w,
+		"data",
+		"",
+		nil)
+}
+
+//NewAccessOutputHandler 集群access日志输出处理器
+func NewAccessOutputHandler(factory *goku_handler.AccountHandlerFactory) http.Handler {
+	return &LogHandler{
+		getHandler: factory.NewAccountHandleFunction(operationLog, false, AccessOutputGet),
+		setHandler: factory.NewAccountHandleFunction(operationLog, true, AccessOutputSet),
+	}
+}
@@ -0,0 +1,143 @@
+package open_api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	open_api "github.com/eolinker/goku-api-gateway/console/module/open-api"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationOpenAPI = "apiManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/save":        factory.NewAccountHandleFunction(operationOpenAPI, true, SaveOpenAPI),
+		"/getInfo":     factory.NewAccountHandleFunction(operationOpenAPI, false, GetOpenAPI),
+		"/getList":     factory.NewAccountHandleFunction(operationOpenAPI, false, GetOpenAPIList),
+		"/batchDelete": factory.NewAccountHandleFunction(operationOpenAPI, true, BatchDeleteOpenAPI),
+		"/import":      factory.NewAccountHandleFunction(operationOpenAPI, true, ImportOpenAPI),
+		"/export":      factory.NewAccountHandleFunction(operationOpenAPI, false, ExportOpenAPI),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// readContent 读取上传的文档，multipart请求读取file参数，否则读取content参数
+func readContent(httpRequest *http.Request) ([]byte, error) {
+	if !strings.Contains(httpRequest.Header.Get("Content-Type"), "multipart/form-data") {
+		content := httpRequest.PostFormValue("content")
+		if content == "" {
+			return nil, errors.New("[ERROR]Param content does not exist!")
+		}
+		return []byte(content), nil
+	}
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		return nil, errors.New("[ERROR]Param file does not exist!")
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return nil, errors.New("[ERROR]Fail to read file!")
+	}
+	return data, nil
+}
+
+//SaveOpenAPI 上传OpenAPI文档（OpenAPI 3或Swagger 2，JSON或YAML）
+func SaveOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	data, err := readContent(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480001",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	name := httpRequest.PostFormValue("name")
+	if name == "" {
+		errInfo := "[ERROR]Illegal name!"
+		controller.WriteError(httpResponse,
+			"480002",
+			"openAPI",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	document, err := open_api.SaveOpenAPI(name, httpRequest.PostFormValue("remark"), data)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480000",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "openAPI", "document", document)
+}
+
+//GetOpenAPI 获取文档详情
+func GetOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	document, err := open_api.GetOpenAPI(httpRequest.Form.Get("name"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480000",
+			"openAPI",
+			"[ERROR]The document does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "openAPI", "document", document)
+}
+
+//GetOpenAPIList 获取文档列表
+func GetOpenAPIList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := open_api.GetOpenAPIList()
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480000",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "openAPI", "documentList", list)
+}
+
+//BatchDeleteOpenAPI 批量删除文档，nameList以逗号分隔
+func BatchDeleteOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	nameList := httpRequest.Form.Get("nameList")
+	if nameList == "" {
+		errInfo := "[ERROR]Illegal nameList!"
+		controller.WriteError(httpResponse,
+			"480003",
+			"openAPI",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	err := open_api.BatchDeleteOpenAPI(strings.Split(nameList, ","))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480000",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "openAPI", "", nil)
+}
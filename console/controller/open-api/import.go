@@ -0,0 +1,84 @@
+package open_api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	open_api "github.com/eolinker/goku-api-gateway/console/module/open-api"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+//ImportOpenAPI 导入OpenAPI文档到项目，dryRun为true时只返回导入计划
+func ImportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	data, err := readContent(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480001",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	projectID, err := strconv.Atoi(httpRequest.PostFormValue("projectID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480004",
+			"openAPI",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	plan, err := open_api.Import(data, &open_api.ImportParam{
+		ProjectID:    projectID,
+		UserID:       goku_handler.UserIDFromRequest(httpRequest),
+		BalanceName:  httpRequest.PostFormValue("balanceName"),
+		ServiceName:  httpRequest.PostFormValue("serviceName"),
+		DocumentName: httpRequest.PostFormValue("documentName"),
+		DryRun:       httpRequest.PostFormValue("dryRun") == "true",
+	})
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480000",
+			"openAPI",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "openAPI", "plan", plan)
+}
+
+//ExportOpenAPI 导出项目接口为OpenAPI 3文档，servers为以逗号分隔的网关访问地址
+func ExportOpenAPI(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	projectID, err := strconv.Atoi(httpRequest.Form.Get("projectID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480004",
+			"openAPI",
+			"[ERROR]Illegal projectID!",
+			err)
+		return
+	}
+	servers := make([]string, 0)
+	for _, s := range strings.Split(httpRequest.Form.Get("servers"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			servers = append(servers, s)
+		}
+	}
+	data, err := open_api.Export(projectID, httpRequest.Form.Get("version"), servers)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"480005",
+			"openAPI",
+			"[ERROR]Fail to export project!",
+			err)
+		return
+	}
+	httpResponse.Header().Set("Content-Type", "application/json; charset=utf-8")
+	httpResponse.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"project-%d-openapi.json\"", projectID))
+	httpResponse.WriteHeader(http.StatusOK)
+	httpResponse.Write(data)
+}
@@ -104,6 +104,10 @@ func AddPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "210009", "plugin", "[ERROR]Illegal pluginType!", err)
 		return
 	}
+	policy, ok := parseErrorPolicy(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
 
 	exits, err := plugin.CheckIndexIsExist("", index)
 	if exits {
@@ -124,6 +128,11 @@ func AddPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "210000", "plugin", result, err)
 		return
 	}
+	err = plugin.EditPluginErrorPolicy(pluginName, policy.policy, policy.status, policy.body)
+	if err != nil {
+		controller.WriteError(httpResponse, "210000", "plugin", err.Error(), err)
+		return
+	}
 
 	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
 
@@ -157,6 +166,10 @@ func EditPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "210009", "plugin", "[ERROR]Illegal pluginType!", err)
 		return
 	}
+	policy, ok := parseErrorPolicy(httpResponse, httpRequest)
+	if !ok {
+		return
+	}
 
 	flag, err := plugin_config.CheckConfig(pluginName, []byte(pluginConfig))
 	if !flag {
@@ -178,10 +191,43 @@ func EditPlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 		controller.WriteError(httpResponse, "210000", "plugin", result, err)
 		return
 	}
+	err = plugin.EditPluginErrorPolicy(pluginName, policy.policy, policy.status, policy.body)
+	if err != nil {
+		controller.WriteError(httpResponse, "210000", "plugin", err.Error(), err)
+		return
+	}
 
 	controller.WriteResultInfo(httpResponse, "plugin", "", nil)
 }
 
+// errorPolicy 插件的错误处理策略：continue、stop或response，为空时按isStop处理
+type errorPolicy struct {
+	policy string
+	status int
+	body   string
+}
+
+// parseErrorPolicy 读取并检查错误处理策略，出错时写入错误信息
+func parseErrorPolicy(httpResponse http.ResponseWriter, httpRequest *http.Request) (*errorPolicy, bool) {
+	p := &errorPolicy{
+		policy: httpRequest.PostFormValue("errorPolicy"),
+		body:   httpRequest.PostFormValue("errorBody"),
+	}
+	if v := httpRequest.PostFormValue("errorStatus"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			controller.WriteError(httpResponse, "210010", "plugin", "[ERROR]Illegal errorStatus!", err)
+			return nil, false
+		}
+		p.status = status
+	}
+	if err := plugin.CheckErrorPolicy(p.policy, p.status); err != nil {
+		controller.WriteError(httpResponse, "210011", "plugin", err.Error(), err)
+		return nil, false
+	}
+	return p, true
+}
+
 //DeletePlugin 删除插件信息
 func DeletePlugin(httpResponse http.ResponseWriter, httpRequest *http.Request) {
 
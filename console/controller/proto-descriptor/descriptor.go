@@ -0,0 +1,141 @@
+package proto_descriptor
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	proto_descriptor "github.com/eolinker/goku-api-gateway/console/module/proto-descriptor"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationProtoDescriptor = "apiManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/save":        factory.NewAccountHandleFunction(operationProtoDescriptor, true, SaveProtoDescriptor),
+		"/getInfo":     factory.NewAccountHandleFunction(operationProtoDescriptor, false, GetProtoDescriptor),
+		"/getList":     factory.NewAccountHandleFunction(operationProtoDescriptor, false, GetProtoDescriptorList),
+		"/batchDelete": factory.NewAccountHandleFunction(operationProtoDescriptor, true, BatchDeleteProtoDescriptor),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+//SaveProtoDescriptor 上传protobuf描述文件（protoc --descriptor_set_out 生成）
+func SaveProtoDescriptor(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	contentType := httpRequest.Header.Get("Content-Type")
+	if !strings.Contains(contentType, "multipart/form-data") {
+		controller.WriteError(httpResponse,
+			"420001",
+			"protoDescriptor",
+			"[ERROR]Request Content-Type isn't multipart/form-data",
+			nil)
+		return
+	}
+	name := httpRequest.PostFormValue("name")
+	remark := httpRequest.PostFormValue("remark")
+	if name == "" {
+		errInfo := "[ERROR]Illegal name!"
+		controller.WriteError(httpResponse,
+			"420002",
+			"protoDescriptor",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	file, _, err := httpRequest.FormFile("file")
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420003",
+			"protoDescriptor",
+			"[ERROR]Param file does not exist!",
+			err)
+		return
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420004",
+			"protoDescriptor",
+			"[ERROR]Fail to read file!",
+			err)
+		return
+	}
+	descriptor, err := proto_descriptor.SaveProtoDescriptor(name, remark, data)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420000",
+			"protoDescriptor",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "protoDescriptor", "descriptor", descriptor)
+}
+
+//GetProtoDescriptor 获取描述文件详情
+func GetProtoDescriptor(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	name := httpRequest.Form.Get("name")
+	descriptor, err := proto_descriptor.GetProtoDescriptor(name)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420000",
+			"protoDescriptor",
+			"[ERROR]The descriptor does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "protoDescriptor", "descriptor", descriptor)
+}
+
+//GetProtoDescriptorList 获取描述文件列表
+func GetProtoDescriptorList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := proto_descriptor.GetProtoDescriptorList()
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420000",
+			"protoDescriptor",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "protoDescriptor", "descriptorList", list)
+}
+
+//BatchDeleteProtoDescriptor 批量删除描述文件，nameList以逗号分隔
+func BatchDeleteProtoDescriptor(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	nameList := httpRequest.Form.Get("nameList")
+	if nameList == "" {
+		errInfo := "[ERROR]Illegal nameList!"
+		controller.WriteError(httpResponse,
+			"420005",
+			"protoDescriptor",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	err := proto_descriptor.BatchDeleteProtoDescriptor(strings.Split(nameList, ","))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"420000",
+			"protoDescriptor",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "protoDescriptor", "", nil)
+}
@@ -0,0 +1,209 @@
+package rate_limit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	rate_limit "github.com/eolinker/goku-api-gateway/console/module/rate-limit"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationRateLimit = "strategyManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/add":         factory.NewAccountHandleFunction(operationRateLimit, true, AddRateLimit),
+		"/edit":        factory.NewAccountHandleFunction(operationRateLimit, true, EditRateLimit),
+		"/getInfo":     factory.NewAccountHandleFunction(operationRateLimit, false, GetRateLimit),
+		"/getList":     factory.NewAccountHandleFunction(operationRateLimit, false, GetRateLimitList),
+		"/batchDelete": factory.NewAccountHandleFunction(operationRateLimit, true, BatchDeleteRateLimit),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseRateLimit 读取表单中的限流规则，返回出错的参数名
+func parseRateLimit(httpRequest *http.Request) (*entity.RateLimit, string, error) {
+	r := &entity.RateLimit{
+		StrategyID: httpRequest.PostFormValue("strategyID"),
+		Algorithm:  httpRequest.PostFormValue("algorithm"),
+		Mode:       httpRequest.PostFormValue("mode"),
+		Key:        httpRequest.PostFormValue("key"),
+		Remark:     httpRequest.PostFormValue("remark"),
+		Enable:     1,
+	}
+	ints := map[string]*int{
+		"apiID":  &r.APIID,
+		"period": &r.Period,
+		"enable": &r.Enable,
+	}
+	for name, target := range ints {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	int64s := map[string]*int64{
+		"limit":        &r.Limit,
+		"burst":        &r.Burst,
+		"dailyQuota":   &r.DailyQuota,
+		"monthlyQuota": &r.MonthlyQuota,
+	}
+	for name, target := range int64s {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	return r, "", nil
+}
+
+//AddRateLimit 新增限流规则
+func AddRateLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	r, name, err := parseRateLimit(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430001",
+			"rateLimit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	id, err := rate_limit.AddRateLimit(r)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430000",
+			"rateLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "rateLimit", "id", id)
+}
+
+//EditRateLimit 编辑限流规则
+func EditRateLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	id, err := strconv.Atoi(httpRequest.PostFormValue("id"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430002",
+			"rateLimit",
+			"[ERROR]Illegal id!",
+			err)
+		return
+	}
+	r, name, err := parseRateLimit(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430001",
+			"rateLimit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	r.ID = id
+	err = rate_limit.EditRateLimit(r)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430000",
+			"rateLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "rateLimit", "", nil)
+}
+
+//GetRateLimit 获取限流规则
+func GetRateLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	id, err := strconv.Atoi(httpRequest.Form.Get("id"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430002",
+			"rateLimit",
+			"[ERROR]Illegal id!",
+			err)
+		return
+	}
+	r, err := rate_limit.GetRateLimit(id)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430000",
+			"rateLimit",
+			"[ERROR]The rate limit does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "rateLimit", "rateLimit", r)
+}
+
+//GetRateLimitList 获取限流规则列表，可按strategyID、apiID筛选
+func GetRateLimitList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	apiID, _ := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	list, err := rate_limit.GetRateLimitList(httpRequest.Form.Get("strategyID"), apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430000",
+			"rateLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "rateLimit", "rateLimitList", list)
+}
+
+//BatchDeleteRateLimit 批量删除限流规则，idList以逗号分隔
+func BatchDeleteRateLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	idList := httpRequest.PostFormValue("idList")
+	ids := make([]int, 0)
+	for _, v := range strings.Split(idList, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		errInfo := "[ERROR]Illegal idList!"
+		controller.WriteError(httpResponse,
+			"430003",
+			"rateLimit",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	err := rate_limit.BatchDeleteRateLimit(ids)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"430000",
+			"rateLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "rateLimit", "", nil)
+}
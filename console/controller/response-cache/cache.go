@@ -0,0 +1,170 @@
+package response_cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	response_cache "github.com/eolinker/goku-api-gateway/console/module/response-cache"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationResponseCache = "apiManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/edit":    factory.NewAccountHandleFunction(operationResponseCache, true, EditResponseCache),
+		"/getInfo": factory.NewAccountHandleFunction(operationResponseCache, false, GetResponseCache),
+		"/getList": factory.NewAccountHandleFunction(operationResponseCache, false, GetResponseCacheList),
+		"/purge":   factory.NewAccountHandleFunction(operationResponseCache, true, PurgeResponseCache),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+func splitList(v string) []string {
+	list := make([]string, 0)
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// parseResponseCache 读取表单中的缓存配置，返回出错的参数名
+func parseResponseCache(httpRequest *http.Request) (*config.ResponseCacheConfig, string, error) {
+	cfg := &config.ResponseCacheConfig{
+		Enable:      httpRequest.PostFormValue("enable") == "true",
+		Consumer:    httpRequest.PostFormValue("consumer") == "true",
+		Shared:      httpRequest.PostFormValue("shared") == "true",
+		QueryParams: splitList(httpRequest.PostFormValue("queryParams")),
+		Headers:     splitList(httpRequest.PostFormValue("headers")),
+	}
+	ints := map[string]*int{
+		"ttl":      &cfg.TTL,
+		"staleTTL": &cfg.StaleTTL,
+	}
+	for name, target := range ints {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	for _, v := range splitList(httpRequest.PostFormValue("statusCodes")) {
+		code, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, "statusCodes", err
+		}
+		cfg.StatusCodes = append(cfg.StatusCodes, code)
+	}
+	return cfg, "", nil
+}
+
+//EditResponseCache 编辑接口缓存配置
+func EditResponseCache(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	apiID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450002",
+			"responseCache",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	cfg, name, err := parseResponseCache(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450001",
+			"responseCache",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	err = response_cache.SaveResponseCache(apiID, cfg)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450000",
+			"responseCache",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "responseCache", "", nil)
+}
+
+//GetResponseCache 获取接口缓存配置
+func GetResponseCache(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	apiID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450002",
+			"responseCache",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	c, err := response_cache.GetResponseCache(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450000",
+			"responseCache",
+			"[ERROR]The response cache of api does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "responseCache", "responseCache", c)
+}
+
+//GetResponseCacheList 获取已配置缓存的接口列表
+func GetResponseCacheList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := response_cache.GetResponseCacheList()
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450000",
+			"responseCache",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "responseCache", "responseCacheList", list)
+}
+
+//PurgeResponseCache 清除接口缓存，key为空时清除接口下的全部缓存
+func PurgeResponseCache(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	apiID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450002",
+			"responseCache",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	count, err := response_cache.Purge(apiID, httpRequest.PostFormValue("key"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"450003",
+			"responseCache",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "responseCache", "nodeCount", count)
+}
@@ -0,0 +1,213 @@
+package script
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/script"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationScript = "pluginManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/add":         factory.NewAccountHandleFunction(operationScript, true, AddScript),
+		"/edit":        factory.NewAccountHandleFunction(operationScript, true, EditScript),
+		"/getInfo":     factory.NewAccountHandleFunction(operationScript, false, GetScript),
+		"/getList":     factory.NewAccountHandleFunction(operationScript, false, GetScriptList),
+		"/getHistory":  factory.NewAccountHandleFunction(operationScript, false, GetScriptHistory),
+		"/batchDelete": factory.NewAccountHandleFunction(operationScript, true, BatchDeleteScript),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseScript 读取表单中的脚本，返回出错的参数名
+func parseScript(httpRequest *http.Request) (*entity.Script, string, error) {
+	s := &entity.Script{
+		ScriptName: httpRequest.PostFormValue("scriptName"),
+		ScriptDesc: httpRequest.PostFormValue("scriptDesc"),
+		Language:   httpRequest.PostFormValue("language"),
+		Content:    httpRequest.PostFormValue("content"),
+	}
+	ints := map[string]*int{
+		"timeout":     &s.Timeout,
+		"maxDataSize": &s.MaxDataSize,
+	}
+	for name, target := range ints {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	return s, "", nil
+}
+
+//AddScript 新增脚本
+func AddScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	s, name, err := parseScript(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440001",
+			"script",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	id, err := script.AddScript(s)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "scriptID", id)
+}
+
+//EditScript 编辑脚本，每次编辑生成新的版本
+func EditScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	scriptID, err := strconv.Atoi(httpRequest.PostFormValue("scriptID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440002",
+			"script",
+			"[ERROR]Illegal scriptID!",
+			err)
+		return
+	}
+	s, name, err := parseScript(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440001",
+			"script",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	s.ScriptID = scriptID
+	err = script.EditScript(s)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "", nil)
+}
+
+//GetScript 获取脚本
+func GetScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	scriptID, err := strconv.Atoi(httpRequest.Form.Get("scriptID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440002",
+			"script",
+			"[ERROR]Illegal scriptID!",
+			err)
+		return
+	}
+	s, err := script.GetScript(scriptID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			"[ERROR]The script does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "script", s)
+}
+
+//GetScriptList 获取脚本列表
+func GetScriptList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	list, err := script.GetScriptList(httpRequest.Form.Get("keyword"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "scriptList", list)
+}
+
+//GetScriptHistory 获取脚本历史版本
+func GetScriptHistory(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	scriptID, err := strconv.Atoi(httpRequest.Form.Get("scriptID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440002",
+			"script",
+			"[ERROR]Illegal scriptID!",
+			err)
+		return
+	}
+	list, err := script.GetScriptHistory(scriptID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "historyList", list)
+}
+
+//BatchDeleteScript 批量删除脚本，scriptIDList以逗号分隔
+func BatchDeleteScript(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	idList := httpRequest.PostFormValue("scriptIDList")
+	ids := make([]int, 0)
+	for _, v := range strings.Split(idList, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		errInfo := "[ERROR]Illegal scriptIDList!"
+		controller.WriteError(httpResponse,
+			"440003",
+			"script",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	err := script.BatchDeleteScript(ids)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"440000",
+			"script",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "script", "", nil)
+}
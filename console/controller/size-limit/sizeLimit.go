@@ -0,0 +1,202 @@
+package size_limit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	size_limit "github.com/eolinker/goku-api-gateway/console/module/size-limit"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const operationSizeLimit = "strategyManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/add":         factory.NewAccountHandleFunction(operationSizeLimit, true, AddSizeLimit),
+		"/edit":        factory.NewAccountHandleFunction(operationSizeLimit, true, EditSizeLimit),
+		"/getInfo":     factory.NewAccountHandleFunction(operationSizeLimit, false, GetSizeLimit),
+		"/getList":     factory.NewAccountHandleFunction(operationSizeLimit, false, GetSizeLimitList),
+		"/batchDelete": factory.NewAccountHandleFunction(operationSizeLimit, true, BatchDeleteSizeLimit),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseSizeLimit 读取表单中的大小限制，返回出错的参数名
+func parseSizeLimit(httpRequest *http.Request) (*entity.SizeLimit, string, error) {
+	l := &entity.SizeLimit{
+		StrategyID: httpRequest.PostFormValue("strategyID"),
+		Remark:     httpRequest.PostFormValue("remark"),
+	}
+	ints := map[string]*int{
+		"apiID":     &l.APIID,
+		"maxHeader": &l.MaxHeader,
+	}
+	for name, target := range ints {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	int64s := map[string]*int64{
+		"maxRequestBody":  &l.MaxRequestBody,
+		"maxResponseBody": &l.MaxResponseBody,
+	}
+	for name, target := range int64s {
+		v := httpRequest.PostFormValue(name)
+		if v == "" {
+			continue
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, name, err
+		}
+		*target = i
+	}
+	return l, "", nil
+}
+
+//AddSizeLimit 新增大小限制
+func AddSizeLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	l, name, err := parseSizeLimit(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460001",
+			"sizeLimit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	id, err := size_limit.AddSizeLimit(l)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460000",
+			"sizeLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "sizeLimit", "id", id)
+}
+
+//EditSizeLimit 编辑大小限制
+func EditSizeLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	id, err := strconv.Atoi(httpRequest.PostFormValue("id"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460002",
+			"sizeLimit",
+			"[ERROR]Illegal id!",
+			err)
+		return
+	}
+	l, name, err := parseSizeLimit(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460001",
+			"sizeLimit",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	l.ID = id
+	err = size_limit.EditSizeLimit(l)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460000",
+			"sizeLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "sizeLimit", "", nil)
+}
+
+//GetSizeLimit 获取大小限制
+func GetSizeLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	id, err := strconv.Atoi(httpRequest.Form.Get("id"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460002",
+			"sizeLimit",
+			"[ERROR]Illegal id!",
+			err)
+		return
+	}
+	l, err := size_limit.GetSizeLimit(id)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460000",
+			"sizeLimit",
+			"[ERROR]The size limit does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "sizeLimit", "sizeLimit", l)
+}
+
+//GetSizeLimitList 获取大小限制列表，可按strategyID、apiID筛选
+func GetSizeLimitList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	apiID, _ := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	list, err := size_limit.GetSizeLimitList(httpRequest.Form.Get("strategyID"), apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460000",
+			"sizeLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "sizeLimit", "sizeLimitList", list)
+}
+
+//BatchDeleteSizeLimit 批量删除大小限制，idList以逗号分隔
+func BatchDeleteSizeLimit(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	idList := httpRequest.PostFormValue("idList")
+	ids := make([]int, 0)
+	for _, v := range strings.Split(idList, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		errInfo := "[ERROR]Illegal idList!"
+		controller.WriteError(httpResponse,
+			"460003",
+			"sizeLimit",
+			errInfo,
+			errors.New(errInfo))
+		return
+	}
+	err := size_limit.BatchDeleteSizeLimit(ids)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"460000",
+			"sizeLimit",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "sizeLimit", "", nil)
+}
@@ -0,0 +1,127 @@
+package traffic
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/traffic"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationTraffic = "gateway"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/series": factory.NewAccountHandleFunction(operationTraffic, false, GetTrafficSeries),
+		"/top":    factory.NewAccountHandleFunction(operationTraffic, false, GetTrafficTop),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+// parseInt 读取整数参数，参数为空时返回默认值
+func parseInt(httpRequest *http.Request, name string, def int64) (int64, error) {
+	v := httpRequest.Form.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// parseRange 读取时间范围，单位为unix秒，默认为最近一小时
+func parseRange(httpRequest *http.Request) (int64, int64, string, error) {
+	end, err := parseInt(httpRequest, "end", time.Now().Unix())
+	if err != nil {
+		return 0, 0, "end", err
+	}
+	start, err := parseInt(httpRequest, "start", end-3600)
+	if err != nil {
+		return 0, 0, "start", err
+	}
+	return start, end, "", nil
+}
+
+//GetTrafficSeries 获取流量时间序列
+func GetTrafficSeries(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	start, end, name, err := parseRange(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500001",
+			"traffic",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	step, err := parseInt(httpRequest, "step", 60)
+	if err != nil || step <= 0 {
+		controller.WriteError(httpResponse,
+			"500002",
+			"traffic",
+			"[ERROR]Illegal step!",
+			err)
+		return
+	}
+	apiID, err := parseInt(httpRequest, "apiID", 0)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500003",
+			"traffic",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	points, err := traffic.GetSeries(start, end, step, httpRequest.Form.Get("cluster"), httpRequest.Form.Get("strategyID"), int(apiID))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500000",
+			"traffic",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "traffic", "series", points)
+}
+
+//GetTrafficTop 获取按接口或策略汇总的流量排行，by为api（默认）或strategy
+func GetTrafficTop(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	start, end, name, err := parseRange(httpRequest)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500001",
+			"traffic",
+			"[ERROR]Illegal "+name+"!",
+			err)
+		return
+	}
+	limit, err := parseInt(httpRequest, "limit", 10)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500004",
+			"traffic",
+			"[ERROR]Illegal limit!",
+			err)
+		return
+	}
+	items, err := traffic.GetTop(start, end, httpRequest.Form.Get("cluster"), httpRequest.Form.Get("by"), int(limit))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"500000",
+			"traffic",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "traffic", "list", items)
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/controller"
+	"github.com/eolinker/goku-api-gateway/console/module/validation"
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+)
+
+const operationValidation = "apiManagement"
+
+//Handlers handlers
+type Handlers struct {
+}
+
+//Handlers handlers
+func (h *Handlers) Handlers(factory *goku_handler.AccountHandlerFactory) map[string]http.Handler {
+	return map[string]http.Handler{
+		"/edit":    factory.NewAccountHandleFunction(operationValidation, true, EditValidation),
+		"/getInfo": factory.NewAccountHandleFunction(operationValidation, false, GetValidation),
+		"/getList": factory.NewAccountHandleFunction(operationValidation, false, GetValidationList),
+	}
+}
+
+//NewHandlers new handlers
+func NewHandlers() *Handlers {
+	return &Handlers{}
+}
+
+//EditValidation 编辑接口请求校验配置
+func EditValidation(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	apiID, err := strconv.Atoi(httpRequest.PostFormValue("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"470002",
+			"validation",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	cfg := &config.ValidationConfig{
+		Enable:    httpRequest.PostFormValue("enable") == "true",
+		Body:      httpRequest.PostFormValue("body"),
+		Query:     httpRequest.PostFormValue("query"),
+		Headers:   httpRequest.PostFormValue("headers"),
+		OpenAPI:   httpRequest.PostFormValue("openAPI"),
+		Operation: httpRequest.PostFormValue("operation"),
+	}
+	err = validation.SaveValidation(apiID, cfg)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"470000",
+			"validation",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "validation", "", nil)
+}
+
+//GetValidation 获取接口请求校验配置
+func GetValidation(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	httpRequest.ParseForm()
+	apiID, err := strconv.Atoi(httpRequest.Form.Get("apiID"))
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"470002",
+			"validation",
+			"[ERROR]Illegal apiID!",
+			err)
+		return
+	}
+	v, err := validation.GetValidation(apiID)
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"470000",
+			"validation",
+			"[ERROR]The validation of api does not exist!",
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "validation", "validation", v)
+}
+
+//GetValidationList 获取已配置请求校验的接口列表
+func GetValidationList(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	list, err := validation.GetValidationList()
+	if err != nil {
+		controller.WriteError(httpResponse,
+			"470000",
+			"validation",
+			err.Error(),
+			err)
+		return
+	}
+	controller.WriteResultInfo(httpResponse, "validation", "validationList", list)
+}
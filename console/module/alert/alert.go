@@ -0,0 +1,204 @@
+package alert
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//MetricErrorRatio 5xx响应占比，单位%
+	MetricErrorRatio = "errorRatio"
+	//MetricP95Latency 95分位耗时，单位毫秒
+	MetricP95Latency = "p95Latency"
+	//MetricRequestDrop 请求数相对上一统计窗口的下降比例，单位%
+	MetricRequestDrop = "requestDrop"
+	//MetricNodeOffline 节点离线
+	MetricNodeOffline = "nodeOffline"
+	//MetricErrorCount 接口每分钟的5xx响应数，由接口的告警阈值（alertValve）生成，不可手动创建
+	MetricErrorCount = "errorCount"
+
+	//StatusFiring 告警中
+	StatusFiring = "firing"
+	//StatusResolved 已恢复
+	StatusResolved = "resolved"
+
+	timeFormat = "2006-01-02 15:04:05"
+
+	defaultDuration     = 300
+	defaultNodeDuration = 60
+	minDuration         = 30
+	maxDuration         = 86400
+)
+
+var (
+	alertDao   dao.AlertDao
+	trafficDao dao.TrafficDao
+)
+
+func init() {
+	pdao.Need(&alertDao, &trafficDao)
+}
+
+//CheckRule 检查告警规则
+func CheckRule(r *entity.AlertRule) error {
+	if r.Name == "" {
+		return errors.New("[ERROR]name can not be empty")
+	}
+	switch r.Metric {
+	case MetricErrorRatio, MetricRequestDrop:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return errors.New("[ERROR]threshold must be between 0 and 100")
+		}
+	case MetricP95Latency:
+		if r.Threshold <= 0 {
+			return errors.New("[ERROR]threshold must be greater than 0")
+		}
+	case MetricNodeOffline:
+		r.StrategyID, r.APIID, r.Threshold, r.MinRequests = "", 0, 0, 0
+	default:
+		return errors.New("[ERROR]Illegal metric")
+	}
+	if r.Duration == 0 {
+		r.Duration = defaultDuration
+		if r.Metric == MetricNodeOffline {
+			r.Duration = defaultNodeDuration
+		}
+	}
+	if r.Duration < minDuration || r.Duration > maxDuration {
+		return errors.New("[ERROR]duration must be between 30 and 86400 seconds")
+	}
+	if r.MinRequests < 0 {
+		return errors.New("[ERROR]minRequests can not be negative")
+	}
+	if r.Channels == nil {
+		r.Channels = make([]int, 0)
+	}
+	return nil
+}
+
+//AddRule 新增告警规则
+func AddRule(r *entity.AlertRule) (int, error) {
+	if err := CheckRule(r); err != nil {
+		return 0, err
+	}
+	r.UpdateTime = time.Now().Format(timeFormat)
+	return alertDao.AddAlertRule(r)
+}
+
+//EditRule 编辑告警规则
+func EditRule(r *entity.AlertRule) error {
+	if err := CheckRule(r); err != nil {
+		return err
+	}
+	r.UpdateTime = time.Now().Format(timeFormat)
+	return alertDao.EditAlertRule(r)
+}
+
+//GetRule 获取告警规则
+func GetRule(ruleID int) (*entity.AlertRule, error) {
+	return alertDao.GetAlertRule(ruleID)
+}
+
+//GetRuleList 获取告警规则列表
+func GetRuleList() ([]*entity.AlertRule, error) {
+	return alertDao.GetAlertRules()
+}
+
+//BatchDeleteRule 批量删除告警规则
+func BatchDeleteRule(ruleIDs []int) error {
+	return alertDao.DeleteAlertRules(ruleIDs)
+}
+
+//CheckChannel 检查通知渠道，渠道配置需能被对应类型解析
+func CheckChannel(c *entity.AlertChannel) error {
+	if c.Name == "" {
+		return errors.New("[ERROR]name can not be empty")
+	}
+	_, err := NewNotifier(c.Type, c.Config)
+	return err
+}
+
+//AddChannel 新增通知渠道
+func AddChannel(c *entity.AlertChannel) (int, error) {
+	if err := CheckChannel(c); err != nil {
+		return 0, err
+	}
+	c.UpdateTime = time.Now().Format(timeFormat)
+	return alertDao.AddAlertChannel(c)
+}
+
+//EditChannel 编辑通知渠道
+func EditChannel(c *entity.AlertChannel) error {
+	if err := CheckChannel(c); err != nil {
+		return err
+	}
+	c.UpdateTime = time.Now().Format(timeFormat)
+	return alertDao.EditAlertChannel(c)
+}
+
+//GetChannel 获取通知渠道
+func GetChannel(channelID int) (*entity.AlertChannel, error) {
+	return alertDao.GetAlertChannel(channelID)
+}
+
+//GetChannelList 获取通知渠道列表
+func GetChannelList() ([]*entity.AlertChannel, error) {
+	return alertDao.GetAlertChannels()
+}
+
+//BatchDeleteChannel 批量删除通知渠道
+func BatchDeleteChannel(channelIDs []int) error {
+	return alertDao.DeleteAlertChannels(channelIDs)
+}
+
+//TestChannel 通过通知渠道发送一条测试告警
+func TestChannel(channelID int) error {
+	c, err := alertDao.GetAlertChannel(channelID)
+	if err != nil {
+		return err
+	}
+	n, err := NewNotifier(c.Type, c.Config)
+	if err != nil {
+		return err
+	}
+	return n.Notify(&entity.AlertHistory{
+		RuleName: "test",
+		Metric:   "test",
+		Target:   c.Name,
+		Status:   StatusFiring,
+		Message:  "This is a test alert from goku console",
+		FireTime: time.Now().Unix(),
+	})
+}
+
+//AddSilence 新增静默，开始时间为0时从当前时间开始
+func AddSilence(s *entity.AlertSilence) (int, error) {
+	now := time.Now()
+	if s.StartTime == 0 {
+		s.StartTime = now.Unix()
+	}
+	if s.EndTime <= s.StartTime || s.EndTime <= now.Unix() {
+		return 0, errors.New("[ERROR]endTime must be later than startTime and now")
+	}
+	s.CreateTime = now.Format(timeFormat)
+	return alertDao.AddAlertSilence(s)
+}
+
+//GetSilenceList 获取未过期的静默列表
+func GetSilenceList() ([]*entity.AlertSilence, error) {
+	return alertDao.GetAlertSilences(time.Now().Unix())
+}
+
+//BatchDeleteSilence 批量删除静默
+func BatchDeleteSilence(silenceIDs []int) error {
+	return alertDao.DeleteAlertSilences(silenceIDs)
+}
+
+//GetHistoryList 分页获取告警记录
+func GetHistoryList(ruleID int, status string, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	return alertDao.GetAlertHistoryList(ruleID, status, page, pageSize)
+}
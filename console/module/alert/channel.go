@@ -0,0 +1,218 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/server/entity"
+	console_entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const (
+	//ChannelWebhook 以JSON格式POST告警记录
+	ChannelWebhook = "webhook"
+	//ChannelEmail 通过SMTP发送邮件
+	ChannelEmail = "email"
+	//ChannelChat 发送到即时通讯工具的群机器人
+	ChannelChat = "chat"
+
+	notifyTimeout = time.Second * 5
+)
+
+//Notifier 告警通知渠道
+type Notifier interface {
+	Notify(h *console_entity.AlertHistory) error
+}
+
+//NotifierFactory 根据渠道配置创建通知渠道，配置不合法时返回错误
+type NotifierFactory func(config string) (Notifier, error)
+
+var (
+	factories      = make(map[string]NotifierFactory)
+	factoriesMutex sync.RWMutex
+
+	httpClient = &http.Client{Timeout: notifyTimeout}
+)
+
+func init() {
+	RegisterNotifier(ChannelWebhook, newWebhook)
+	RegisterNotifier(ChannelEmail, newEmail)
+	RegisterNotifier(ChannelChat, newChat)
+}
+
+//RegisterNotifier 注册通知渠道类型
+func RegisterNotifier(channelType string, factory NotifierFactory) {
+	factoriesMutex.Lock()
+	factories[channelType] = factory
+	factoriesMutex.Unlock()
+}
+
+//NewNotifier 创建通知渠道
+func NewNotifier(channelType, config string) (Notifier, error) {
+	factoriesMutex.RLock()
+	factory, has := factories[channelType]
+	factoriesMutex.RUnlock()
+	if !has {
+		return nil, errors.New("[ERROR]Illegal channel type")
+	}
+	return factory(config)
+}
+
+//Title 通知标题
+func Title(h *console_entity.AlertHistory) string {
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(h.Status), h.RuleName)
+}
+
+//Text 通知正文
+func Text(h *console_entity.AlertHistory) string {
+	lines := []string{
+		Title(h),
+		h.Message,
+		"target: " + h.Target,
+		"fire time: " + time.Unix(h.FireTime, 0).Format(timeFormat),
+	}
+	if h.Status == StatusResolved {
+		lines = append(lines, "resolve time: "+time.Unix(h.ResolveTime, 0).Format(timeFormat))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func post(url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("[ERROR]%s responded with status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+type webhook struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+func newWebhook(config string) (Notifier, error) {
+	w := &webhook{}
+	if err := json.Unmarshal([]byte(config), w); err != nil {
+		return nil, err
+	}
+	if w.URL == "" {
+		return nil, errors.New("[ERROR]url can not be empty")
+	}
+	return w, nil
+}
+
+//Notify 以JSON格式POST告警记录
+func (w *webhook) Notify(h *console_entity.AlertHistory) error {
+	body, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return post(w.URL, w.Headers, body)
+}
+
+type email struct {
+	entity.SMTPInfo
+	To []string `json:"to"`
+}
+
+func newEmail(config string) (Notifier, error) {
+	e := &email{}
+	if err := json.Unmarshal([]byte(config), e); err != nil {
+		return nil, err
+	}
+	if e.Address == "" || e.Port == 0 {
+		return nil, errors.New("[ERROR]address and port can not be empty")
+	}
+	if len(e.To) == 0 {
+		return nil, errors.New("[ERROR]to can not be empty")
+	}
+	return e, nil
+}
+
+//Notify 发送告警邮件
+func (e *email) Notify(h *console_entity.AlertHistory) error {
+	body := strings.Replace(Text(h), "\n", "<br>", -1)
+	return utils.SendMails(e.SMTPInfo, e.To, Title(h), body)
+}
+
+const (
+	flavorSlack    = "slack"
+	flavorDingTalk = "dingtalk"
+	flavorWeCom    = "wecom"
+	flavorFeishu   = "feishu"
+)
+
+//chat 群机器人通知，Template不为空时作为请求体，其中的{{text}}替换为JSON转义后的通知正文
+type chat struct {
+	URL      string `json:"url"`
+	Flavor   string `json:"flavor"`
+	Template string `json:"template"`
+}
+
+func newChat(config string) (Notifier, error) {
+	c := &chat{}
+	if err := json.Unmarshal([]byte(config), c); err != nil {
+		return nil, err
+	}
+	if c.URL == "" {
+		return nil, errors.New("[ERROR]url can not be empty")
+	}
+	if c.Template != "" {
+		return c, nil
+	}
+	switch c.Flavor {
+	case flavorSlack, flavorDingTalk, flavorWeCom, flavorFeishu:
+	default:
+		return nil, errors.New("[ERROR]Illegal flavor")
+	}
+	return c, nil
+}
+
+func (c *chat) body(text string) ([]byte, error) {
+	if c.Template != "" {
+		escaped, err := json.Marshal(text)
+		if err != nil {
+			return nil, err
+		}
+		s := string(escaped)
+		return []byte(strings.Replace(c.Template, "{{text}}", s[1:len(s)-1], -1)), nil
+	}
+	var payload interface{}
+	switch c.Flavor {
+	case flavorSlack:
+		payload = map[string]interface{}{"text": text}
+	case flavorDingTalk, flavorWeCom:
+		payload = map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": text}}
+	case flavorFeishu:
+		payload = map[string]interface{}{"msg_type": "text", "content": map[string]string{"text": text}}
+	}
+	return json.Marshal(payload)
+}
+
+//Notify 发送群机器人消息
+func (c *chat) Notify(h *console_entity.AlertHistory) error {
+	body, err := c.body(Text(h))
+	if err != nil {
+		return err
+	}
+	return post(c.URL, nil, body)
+}
@@ -0,0 +1,49 @@
+package alert
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func TestChatNotifier(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got = nil
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid body %s: %v", body, err)
+		}
+	}))
+	defer srv.Close()
+
+	h := &entity.AlertHistory{RuleName: "r", Status: StatusFiring, Message: "a \"quoted\" message"}
+	cases := map[string]string{
+		`{"url":"` + srv.URL + `","flavor":"dingtalk"}`:                     "msgtype",
+		`{"url":"` + srv.URL + `","flavor":"feishu"}`:                       "msg_type",
+		`{"url":"` + srv.URL + `","template":"{\"message\":\"{{text}}\"}"}`: "message",
+	}
+	for config, field := range cases {
+		n, err := NewNotifier(ChannelChat, config)
+		if err != nil {
+			t.Fatal(config, err)
+		}
+		if err = n.Notify(h); err != nil {
+			t.Fatal(config, err)
+		}
+		if _, has := got[field]; !has {
+			t.Errorf("%s: missing field %s in %v", config, field, got)
+		}
+	}
+
+	if _, err := NewNotifier(ChannelChat, `{"url":"`+srv.URL+`","flavor":"unknown"}`); err == nil {
+		t.Error("unknown flavor should be rejected")
+	}
+	if _, err := NewNotifier("sms", `{}`); err == nil {
+		t.Error("unknown channel type should be rejected")
+	}
+}
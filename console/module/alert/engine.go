@@ -0,0 +1,239 @@
+package alert
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	checkInterval = time.Second * 30
+	// 告警评估只在持有租约的控制台实例上运行
+	leaseName = "alert"
+	leaseTTL  = checkInterval * 3
+	// valveWindow 接口告警阈值的统计窗口，单位秒
+	valveWindow = 60
+	// valveRuleName 接口告警阈值生成的告警使用的规则名，规则ID为0
+	valveRuleName = "alertValve"
+)
+
+var (
+	startOnce sync.Once
+	// offlineSince 仅由评估协程访问
+	offlineSince = make(map[string]int64)
+)
+
+// evaluated 一条评估结果及其所属规则
+type evaluated struct {
+	rule   *entity.AlertRule
+	metric string
+	*Result
+}
+
+//Start 开始定时评估告警规则
+func Start() {
+	startOnce.Do(func() {
+		go run()
+	})
+}
+
+func run() {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if replica.Leader(leaseName, leaseTTL) {
+			check(time.Now().Unix())
+		}
+	}
+}
+
+func alertKey(ruleID int, target string) string {
+	return strconv.Itoa(ruleID) + "|" + target
+}
+
+// evaluate 评估所有规则，返回评估结果及评估失败的规则ID
+func evaluate(now int64) ([]*evaluated, map[int]bool) {
+	results := make([]*evaluated, 0)
+	failed := make(map[int]bool)
+
+	rules, err := alertDao.GetAlertRules()
+	if err != nil {
+		log.Warn("get alert rules error:", err)
+		return nil, nil
+	}
+	var nodes []*entity.Node
+	for _, r := range rules {
+		if !r.Enable {
+			continue
+		}
+		if r.Metric == MetricNodeOffline {
+			if nodes == nil {
+				if nodes, err = node.GetAllNode(); err != nil {
+					log.Warn("get nodes for alert error:", err)
+					failed[r.RuleID] = true
+					continue
+				}
+				node.ResetNodeStatus(nodes...)
+			}
+			for _, res := range EvaluateNodes(r, nodes, offlineSince, now) {
+				results = append(results, &evaluated{rule: r, metric: r.Metric, Result: res})
+			}
+			continue
+		}
+		d := int64(r.Duration)
+		current, err := trafficDao.GetTrafficStats(now-d, now, r.Cluster, r.StrategyID, r.APIID)
+		if err != nil {
+			log.Warn("get traffic for alert rule ", r.RuleID, " error:", err)
+			failed[r.RuleID] = true
+			continue
+		}
+		var previous []*entity.TrafficStat
+		if r.Metric == MetricRequestDrop {
+			if previous, err = trafficDao.GetTrafficStats(now-2*d, now-d, r.Cluster, r.StrategyID, r.APIID); err != nil {
+				log.Warn("get traffic for alert rule ", r.RuleID, " error:", err)
+				failed[r.RuleID] = true
+				continue
+			}
+		}
+		results = append(results, &evaluated{rule: r, metric: r.Metric, Result: EvaluateTraffic(r, current, previous)})
+	}
+
+	valves, err := alertDao.GetAPIAlertValves()
+	if err == nil && len(valves) > 0 {
+		var stats []*entity.TrafficStat
+		stats, err = trafficDao.GetTrafficStats(now-valveWindow, now, "", "", 0)
+		if err == nil {
+			for _, res := range EvaluateValves(valves, stats) {
+				results = append(results, &evaluated{metric: MetricErrorCount, Result: res})
+			}
+		}
+	}
+	if err != nil {
+		log.Warn("evaluate api alert valves error:", err)
+		failed[0] = true
+	}
+	return results, failed
+}
+
+// check 评估规则，新触发的告警写入记录并通知，不再满足条件的告警置为恢复并通知。
+// 同一规则及告警对象同时只有一条告警中的记录，静默期间触发的告警只记录不通知
+func check(now int64) {
+	results, failed := evaluate(now)
+	if failed == nil {
+		return
+	}
+	firingList, err := alertDao.GetFiringAlerts()
+	if err != nil {
+		log.Warn("get firing alerts error:", err)
+		return
+	}
+	firing := make(map[string]*entity.AlertHistory, len(firingList))
+	for _, h := range firingList {
+		firing[alertKey(h.RuleID, h.Target)] = h
+	}
+	silences, err := alertDao.GetAlertSilences(now)
+	if err != nil {
+		log.Warn("get alert silences error:", err)
+	}
+	channels, err := alertDao.GetAlertChannels()
+	if err != nil {
+		log.Warn("get alert channels error:", err)
+	}
+
+	for _, e := range results {
+		ruleID, ruleName := 0, valveRuleName
+		if e.rule != nil {
+			ruleID, ruleName = e.rule.RuleID, e.rule.Name
+		}
+		key := alertKey(ruleID, e.Target)
+		h, has := firing[key]
+		delete(firing, key)
+		switch {
+		case e.Fire && !has:
+			h = &entity.AlertHistory{
+				RuleID:    ruleID,
+				RuleName:  ruleName,
+				Metric:    e.metric,
+				Target:    e.Target,
+				Status:    StatusFiring,
+				Value:     e.Value,
+				Threshold: e.Threshold,
+				Message:   message(e.metric, e.Target, e.Value, e.Threshold),
+				Silenced:  Silenced(silences, ruleID, e.Target, now),
+				FireTime:  now,
+			}
+			if h.AlertID, err = alertDao.AddAlertHistory(h); err != nil {
+				log.Warn("add alert history error:", err)
+				continue
+			}
+			if !h.Silenced {
+				notify(selectChannels(channels, e.rule), h)
+			}
+		case !e.Fire && has:
+			if err = resolve(h, e.Value, now); err != nil {
+				continue
+			}
+			if !h.Silenced {
+				notify(selectChannels(channels, e.rule), h)
+			}
+		}
+	}
+
+	// 规则被删除、停用或告警对象已不存在的告警直接恢复，不再通知
+	for _, h := range firing {
+		if !failed[h.RuleID] {
+			resolve(h, 0, now)
+		}
+	}
+}
+
+func resolve(h *entity.AlertHistory, value float64, now int64) error {
+	if err := alertDao.ResolveAlert(h.AlertID, value, now); err != nil {
+		log.Warn("resolve alert ", h.AlertID, " error:", err)
+		return err
+	}
+	h.Status = StatusResolved
+	h.Value = value
+	h.ResolveTime = now
+	return nil
+}
+
+// selectChannels 规则未指定通知渠道时使用默认渠道，接口告警阈值生成的告警使用默认渠道
+func selectChannels(channels []*entity.AlertChannel, r *entity.AlertRule) []*entity.AlertChannel {
+	selected := make([]*entity.AlertChannel, 0)
+	ids := make(map[int]bool)
+	if r != nil {
+		for _, id := range r.Channels {
+			ids[id] = true
+		}
+	}
+	for _, c := range channels {
+		if !c.Enable {
+			continue
+		}
+		if (len(ids) == 0 && c.IsDefault) || ids[c.ChannelID] {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+func notify(channels []*entity.AlertChannel, h *entity.AlertHistory) {
+	for _, c := range channels {
+		go func(c *entity.AlertChannel) {
+			n, err := NewNotifier(c.Type, c.Config)
+			if err == nil {
+				err = n.Notify(h)
+			}
+			if err != nil {
+				log.Warn("send alert ", h.AlertID, " to channel ", c.Name, " error:", err)
+			}
+		}(c)
+	}
+}
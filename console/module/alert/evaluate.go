@@ -0,0 +1,151 @@
+package alert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//Result 一次评估的结果，Target为告警对象，去重及静默均按规则和告警对象匹配
+type Result struct {
+	Target    string
+	Value     float64
+	Threshold float64
+	Fire      bool
+}
+
+//Target 规则统计范围的描述
+func Target(r *entity.AlertRule) string {
+	parts := make([]string, 0, 3)
+	if r.Cluster != "" {
+		parts = append(parts, "cluster:"+r.Cluster)
+	}
+	if r.StrategyID != "" {
+		parts = append(parts, "strategy:"+r.StrategyID)
+	}
+	if r.APIID != 0 {
+		parts = append(parts, "api:"+strconv.Itoa(r.APIID))
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, ",")
+}
+
+func mergeStats(stats []*entity.TrafficStat) *config.TrafficStat {
+	merged := config.NewTrafficStat("", 0)
+	for _, s := range stats {
+		merged.Merge(&s.TrafficStat)
+	}
+	return merged
+}
+
+//EvaluateTraffic 根据规则统计窗口内的流量评估规则，previous为上一统计窗口的流量，仅用于请求下降。
+//请求数少于MinRequests时不触发告警
+func EvaluateTraffic(r *entity.AlertRule, current, previous []*entity.TrafficStat) *Result {
+	res := &Result{Target: Target(r), Threshold: r.Threshold}
+	cur := mergeStats(current)
+	min := uint64(r.MinRequests)
+	switch r.Metric {
+	case MetricErrorRatio:
+		if cur.Requests == 0 || cur.Requests < min {
+			return res
+		}
+		res.Value = float64(cur.Status5xx) * 100 / float64(cur.Requests)
+	case MetricP95Latency:
+		if cur.Requests == 0 || cur.Requests < min {
+			return res
+		}
+		res.Value = cur.Percentile(0.95)
+	case MetricRequestDrop:
+		prev := mergeStats(previous)
+		if prev.Requests == 0 || prev.Requests < min || cur.Requests >= prev.Requests {
+			return res
+		}
+		res.Value = float64(prev.Requests-cur.Requests) * 100 / float64(prev.Requests)
+	default:
+		return res
+	}
+	res.Fire = res.Value >= r.Threshold
+	return res
+}
+
+//EvaluateNodes 评估节点离线规则，节点状态需已通过node.ResetNodeStatus刷新。
+//offlineSince记录各节点首次被发现离线的时间，离线持续Duration秒后触发告警
+func EvaluateNodes(r *entity.AlertRule, nodes []*entity.Node, offlineSince map[string]int64, now int64) []*Result {
+	results := make([]*Result, 0, len(nodes))
+	for _, n := range nodes {
+		if r.Cluster != "" && n.Cluster != r.Cluster {
+			continue
+		}
+		res := &Result{Target: "node:" + n.NodeKey}
+		if n.NodeStatus == 1 {
+			delete(offlineSince, n.NodeKey)
+		} else {
+			since, has := offlineSince[n.NodeKey]
+			if !has {
+				since = now
+				offlineSince[n.NodeKey] = now
+			}
+			res.Value = float64(now - since)
+			res.Fire = now-since >= int64(r.Duration)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+//EvaluateValves 按接口的告警阈值评估最近一分钟的5xx响应数，valves的key为apiID
+func EvaluateValves(valves map[int]int, stats []*entity.TrafficStat) []*Result {
+	counts := make(map[int]uint64)
+	for _, s := range stats {
+		if _, has := valves[s.APIID]; has {
+			counts[s.APIID] += s.Status5xx
+		}
+	}
+	results := make([]*Result, 0, len(valves))
+	for apiID, valve := range valves {
+		count := counts[apiID]
+		results = append(results, &Result{
+			Target:    "api:" + strconv.Itoa(apiID),
+			Value:     float64(count),
+			Threshold: float64(valve),
+			Fire:      count >= uint64(valve),
+		})
+	}
+	return results
+}
+
+//Silenced 判断告警在now时刻是否处于静默中
+func Silenced(silences []*entity.AlertSilence, ruleID int, target string, now int64) bool {
+	for _, s := range silences {
+		if now < s.StartTime || now >= s.EndTime {
+			continue
+		}
+		if (s.RuleID == 0 || s.RuleID == ruleID) && (s.Target == "" || s.Target == target) {
+			return true
+		}
+	}
+	return false
+}
+
+func message(metric, target string, value, threshold float64) string {
+	v := strconv.FormatFloat(value, 'f', 2, 64)
+	t := strconv.FormatFloat(threshold, 'f', 2, 64)
+	switch metric {
+	case MetricErrorRatio:
+		return fmt.Sprintf("5xx ratio of %s is %s%% (threshold %s%%)", target, v, t)
+	case MetricP95Latency:
+		return fmt.Sprintf("p95 latency of %s is %sms (threshold %sms)", target, v, t)
+	case MetricRequestDrop:
+		return fmt.Sprintf("requests of %s dropped by %s%% (threshold %s%%)", target, v, t)
+	case MetricNodeOffline:
+		return fmt.Sprintf("%s has been offline for %.0fs", target, value)
+	case MetricErrorCount:
+		return fmt.Sprintf("5xx responses of %s in the last minute: %.0f (alertValve %.0f)", target, value, threshold)
+	}
+	return fmt.Sprintf("%s of %s is %s (threshold %s)", metric, target, v, t)
+}
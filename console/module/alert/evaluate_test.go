@@ -0,0 +1,102 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func stat(apiID int, requests, status5xx uint64, latency float64) *entity.TrafficStat {
+	s := &entity.TrafficStat{TrafficStat: *config.NewTrafficStat("", apiID)}
+	for i := uint64(0); i < requests; i++ {
+		status := 200
+		if i < status5xx {
+			status = 500
+		}
+		s.ObserveRequest(status, latency)
+	}
+	return s
+}
+
+func TestEvaluateTraffic(t *testing.T) {
+	r := &entity.AlertRule{Metric: MetricErrorRatio, Threshold: 10, MinRequests: 10, APIID: 1}
+	res := EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 50, 5, 10), stat(1, 50, 10, 10)}, nil)
+	if !res.Fire || res.Value != 15 || res.Target != "api:1" {
+		t.Fatalf("error ratio: %+v", res)
+	}
+	if res = EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 5, 5, 10)}, nil); res.Fire {
+		t.Fatalf("below minRequests should not fire: %+v", res)
+	}
+
+	r = &entity.AlertRule{Metric: MetricP95Latency, Threshold: 500}
+	if res = EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 100, 0, 800)}, nil); !res.Fire {
+		t.Fatalf("p95 latency: %+v", res)
+	}
+	if res = EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 100, 0, 20)}, nil); res.Fire {
+		t.Fatalf("p95 latency: %+v", res)
+	}
+
+	r = &entity.AlertRule{Metric: MetricRequestDrop, Threshold: 50, MinRequests: 10}
+	res = EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 20, 0, 10)}, []*entity.TrafficStat{stat(1, 100, 0, 10)})
+	if !res.Fire || res.Value != 80 {
+		t.Fatalf("request drop: %+v", res)
+	}
+	if res = EvaluateTraffic(r, []*entity.TrafficStat{stat(1, 0, 0, 10)}, []*entity.TrafficStat{stat(1, 5, 0, 10)}); res.Fire {
+		t.Fatalf("previous below minRequests should not fire: %+v", res)
+	}
+}
+
+func TestEvaluateNodes(t *testing.T) {
+	r := &entity.AlertRule{Metric: MetricNodeOffline, Duration: 60, Cluster: "default"}
+	nodes := []*entity.Node{
+		{NodeKey: "a", Cluster: "default", NodeStatus: 1},
+		{NodeKey: "b", Cluster: "default", NodeStatus: 2},
+		{NodeKey: "c", Cluster: "other", NodeStatus: 0},
+	}
+	since := make(map[string]int64)
+	results := EvaluateNodes(r, nodes, since, 1000)
+	if len(results) != 2 || results[1].Fire {
+		t.Fatalf("first offline check should not fire: %+v", results)
+	}
+	results = EvaluateNodes(r, nodes, since, 1060)
+	if results[0].Fire || !results[1].Fire || results[1].Target != "node:b" {
+		t.Fatalf("offline node should fire after duration: %+v %+v", results[0], results[1])
+	}
+	nodes[1].NodeStatus = 1
+	EvaluateNodes(r, nodes, since, 1070)
+	if _, has := since["b"]; has {
+		t.Fatal("online node should be removed from offlineSince")
+	}
+}
+
+func TestEvaluateValves(t *testing.T) {
+	results := EvaluateValves(map[int]int{1: 3}, []*entity.TrafficStat{stat(1, 10, 2, 10), stat(1, 10, 1, 10), stat(2, 10, 10, 10)})
+	if len(results) != 1 || !results[0].Fire || results[0].Value != 3 || results[0].Target != "api:1" {
+		t.Fatalf("valve: %+v", results)
+	}
+}
+
+func TestSilenced(t *testing.T) {
+	silences := []*entity.AlertSilence{
+		{RuleID: 1, Target: "node:a", StartTime: 100, EndTime: 200},
+		{RuleID: 0, Target: "api:2", StartTime: 100, EndTime: 200},
+	}
+	cases := []struct {
+		ruleID int
+		target string
+		now    int64
+		want   bool
+	}{
+		{1, "node:a", 150, true},
+		{1, "node:b", 150, false},
+		{1, "node:a", 200, false},
+		{3, "api:2", 100, true},
+		{3, "api:2", 99, false},
+	}
+	for _, c := range cases {
+		if got := Silenced(silences, c.ruleID, c.target, c.now); got != c.want {
+			t.Errorf("Silenced(%d,%s,%d)=%v, want %v", c.ruleID, c.target, c.now, got, c.want)
+		}
+	}
+}
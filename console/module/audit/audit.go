@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//EntityVersion 版本配置，版本配置的修改不关联到版本
+	EntityVersion = "version"
+
+	//FormatJSON 以JSON数组导出
+	FormatJSON = "json"
+	//FormatCSV 以CSV导出
+	FormatCSV = "csv"
+
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+var (
+	auditDao dao.AuditDao
+)
+
+func init() {
+	pdao.Need(&auditDao)
+}
+
+//GetLogList 分页获取审计日志
+func GetLogList(filter *entity.AuditFilter, page, pageSize int) ([]*entity.AuditLog, int, error) {
+	return auditDao.GetAuditLogList(filter, page, pageSize)
+}
+
+//Export 导出符合条件的全部审计日志，format为json或csv
+func Export(filter *entity.AuditFilter, format string) ([]byte, error) {
+	logs, _, err := auditDao.GetAuditLogList(filter, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatJSON, "":
+		return json.MarshalIndent(logs, "", "  ")
+	case FormatCSV:
+		buf := &bytes.Buffer{}
+		w := csv.NewWriter(buf)
+		w.Write([]string{"logID", "userID", "loginCall", "operation", "entityType", "entityID", "params", "before", "after", "diff", "statusCode", "versionID", "createTime"})
+		for _, l := range logs {
+			w.Write([]string{strconv.Itoa(l.LogID), strconv.Itoa(l.UserID), l.LoginCall, l.Operation, l.EntityType, l.EntityID, l.Params, l.Before, l.After, l.Diff, l.StatusCode, strconv.Itoa(l.VersionID), l.CreateTime})
+		}
+		w.Flush()
+		return buf.Bytes(), w.Error()
+	}
+	return nil, errors.New("[ERROR]Illegal format")
+}
+
+//LinkVersion 新增版本时调用，将该版本之前尚未关联版本的修改关联到该版本
+func LinkVersion(versionID int) {
+	if err := auditDao.LinkAuditLogs(versionID, EntityVersion); err != nil {
+		log.Warn("link audit logs to version ", versionID, " error:", err)
+	}
+}
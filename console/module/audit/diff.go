@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maskValue 敏感字段的替换值
+const maskValue = "******"
+
+// sensitiveKeys 字段名（小写）包含其中任一项时视为敏感字段
+var sensitiveKeys = []string{"password", "secret", "token", "apikey", "privatekey"}
+
+//Change 字段级差异，Path为字段路径，如config.timeout、nodes[0]
+type Change struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+//Normalize 将对象转换为JSON通用结构并屏蔽敏感字段
+func Normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var n interface{}
+	if err = json.Unmarshal(data, &n); err != nil {
+		return nil
+	}
+	return mask(n)
+}
+
+func mask(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range t {
+			if isSensitive(k) && sub != nil && sub != "" {
+				t[k] = maskValue
+				continue
+			}
+			t[k] = mask(sub)
+		}
+	case []interface{}:
+		for i, sub := range t {
+			t[i] = mask(sub)
+		}
+	case string:
+		return maskJSON(t)
+	}
+	return v
+}
+
+// maskJSON 屏蔽以JSON字符串保存的对象（如鉴权凭证列表、插件配置）中的敏感字段
+func maskJSON(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return s
+	}
+	var n interface{}
+	if err := json.Unmarshal([]byte(trimmed), &n); err != nil {
+		return s
+	}
+	data, err := json.Marshal(mask(n))
+	if err != nil {
+		return maskValue
+	}
+	return string(data)
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+//Diff 比较两个经Normalize转换的对象，返回字段级差异
+func Diff(before, after interface{}) []*Change {
+	changes := make([]*Change, 0)
+	return diff("", before, after, changes)
+}
+
+func diff(path string, before, after interface{}, changes []*Change) []*Change {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		if a, ok := after.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(b)+len(a))
+			for k := range b {
+				keys = append(keys, k)
+			}
+			for k := range a {
+				if _, has := b[k]; !has {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				changes = diff(joinPath(path, k), b[k], a[k], changes)
+			}
+			return changes
+		}
+	case []interface{}:
+		if a, ok := after.([]interface{}); ok {
+			n := len(b)
+			if len(a) > n {
+				n = len(a)
+			}
+			for i := 0; i < n; i++ {
+				var bi, ai interface{}
+				if i < len(b) {
+					bi = b[i]
+				}
+				if i < len(a) {
+					ai = a[i]
+				}
+				changes = diff(path+"["+strconv.Itoa(i)+"]", bi, ai, changes)
+			}
+			return changes
+		}
+	}
+	if !reflect.DeepEqual(before, after) {
+		changes = append(changes, &Change{Path: path, Before: before, After: after})
+	}
+	return changes
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type node struct {
+		Name string `json:"name"`
+	}
+	before := Normalize(map[string]interface{}{
+		"apiName":  "a",
+		"timeout":  100,
+		"nodes":    []node{{"n1"}, {"n2"}},
+		"password": "p1",
+		"removed":  true,
+	})
+	after := Normalize(map[string]interface{}{
+		"apiName":  "a",
+		"timeout":  200,
+		"nodes":    []node{{"n1"}, {"n3"}, {"n4"}},
+		"password": "p2",
+		"added":    "x",
+	})
+	changes := Diff(before, after)
+	want := []string{"added", "nodes[1].name", "nodes[2]", "removed", "timeout"}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, c := range changes {
+		if c.Path != want[i] {
+			t.Errorf("change %d path %s, want %s", i, c.Path, want[i])
+		}
+	}
+	if changes[4].Before != float64(100) || changes[4].After != float64(200) {
+		t.Errorf("timeout change: %+v", changes[4])
+	}
+	if m := after.(map[string]interface{}); m["password"] != maskValue {
+		t.Errorf("password not masked: %v", m["password"])
+	}
+
+	if changes = Diff(nil, Normalize(node{"n1"})); len(changes) != 1 || changes[0].Path != "" {
+		t.Errorf("create diff: %+v", changes)
+	}
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goku_handler "github.com/eolinker/goku-api-gateway/goku-handler"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//Loader 读取实体的当前状态，实体不存在时返回错误
+type Loader func(id string) (interface{}, error)
+
+type watcher struct {
+	entityType string
+	key        string
+	load       Loader
+}
+
+var (
+	watchers     = make(map[string]*watcher)
+	watcherMutex sync.RWMutex
+
+	statusCodeRegexp = regexp.MustCompile(`"statusCode"\s*:\s*"(\d+)"`)
+)
+
+//Watch 将修改类接口关联到实体类型。key为请求参数中标识实体的参数名，参数值以逗号分隔时视为多个实体，
+//新增操作的实体ID从响应中同名字段读取；load不为空时记录实体修改前后的快照及差异
+func Watch(path, entityType, key string, load Loader) {
+	watcherMutex.Lock()
+	watchers[path] = &watcher{entityType: entityType, key: key, load: load}
+	watcherMutex.Unlock()
+}
+
+func getWatcher(path string) *watcher {
+	watcherMutex.RLock()
+	defer watcherMutex.RUnlock()
+	return watchers[path]
+}
+
+type recorder struct {
+}
+
+//NewRecorder 创建审计记录器，所有修改类请求都会记录请求参数，已关联实体的请求另外记录实体快照
+func NewRecorder() goku_handler.Recorder {
+	return &recorder{}
+}
+
+//Begin 记录修改前的实体快照，返回记录修改结果的函数
+func (*recorder) Begin(r *http.Request, userID int) func(response []byte) {
+	r.ParseForm()
+	l := &entity.AuditLog{
+		UserID:    userID,
+		Operation: r.URL.Path,
+		Params:    encode(params(r)),
+	}
+	w := getWatcher(r.URL.Path)
+	var before interface{}
+	if w != nil {
+		l.EntityType = w.entityType
+		l.EntityID = r.Form.Get(w.key)
+		if w.load != nil && l.EntityID != "" {
+			before = snapshot(w.load, l.EntityID)
+		}
+	}
+	return func(response []byte) {
+		if m := statusCodeRegexp.FindSubmatch(response); m != nil {
+			l.StatusCode = string(m[1])
+		}
+		if w != nil && w.load != nil {
+			if l.EntityID == "" {
+				l.EntityID = responseID(response, w.key)
+			}
+			var after interface{}
+			if l.EntityID != "" {
+				after = snapshot(w.load, l.EntityID)
+			}
+			l.Before, l.After = encode(before), encode(after)
+			l.Diff = encode(Diff(before, after))
+		}
+		l.CreateTime = time.Now().Format(timeFormat)
+		if _, err := auditDao.AddAuditLog(l); err != nil {
+			log.Warn("add audit log of ", l.Operation, " error:", err)
+		}
+	}
+}
+
+// params 请求参数，多值参数以逗号连接
+func params(r *http.Request) map[string]interface{} {
+	p := make(map[string]interface{}, len(r.Form))
+	for k, v := range r.Form {
+		p[k] = strings.Join(v, ",")
+	}
+	return mask(p).(map[string]interface{})
+}
+
+// snapshot 读取实体快照，多个实体时以ID为key
+func snapshot(load Loader, id string) interface{} {
+	ids := strings.Split(id, ",")
+	if len(ids) == 1 {
+		v, err := load(id)
+		if err != nil {
+			return nil
+		}
+		return Normalize(v)
+	}
+	sort.Strings(ids)
+	m := make(map[string]interface{}, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if v, err := load(id); err == nil {
+			m[id] = v
+		}
+	}
+	return Normalize(m)
+}
+
+// responseID 从响应中读取新增实体的ID
+func responseID(response []byte, key string) string {
+	var m map[string]interface{}
+	if err := json.Unmarshal(response, &m); err != nil {
+		return ""
+	}
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func encode(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestResponseID(t *testing.T) {
+	cases := map[string]string{
+		`{"statusCode":"000000","apiID":12}`:         "12",
+		`{"statusCode":"000000","strategyID":"abc"}`: "abc",
+		`{"statusCode":"000000"}`:                    "",
+		`not json`:                                   "",
+	}
+	for response, want := range cases {
+		key := "apiID"
+		if strings.Contains(response, "strategyID") {
+			key = "strategyID"
+		}
+		if got := responseID([]byte(response), key); got != want {
+			t.Errorf("responseID(%s)=%q, want %q", response, got, want)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	form := url.Values{"apiName": {"a"}, "loginPassword": {"p"}, "ids": {"1", "2"}}
+	r := httptest.NewRequest("POST", "/apis/edit", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ParseForm()
+	p := params(r)
+	if p["apiName"] != "a" || p["loginPassword"] != maskValue || p["ids"] != "1,2" {
+		t.Errorf("params: %v", p)
+	}
+}
+
+func TestParamsAuthInfo(t *testing.T) {
+	form := url.Values{
+		"strategyID":         {"abc"},
+		"basicAuthList":      {`[{"userName":"goku","password":"hunter2"}]`},
+		"hmacCredentialList": {`[{"hmacKey":"k","secret":"topsecret"}]`},
+		"pluginConfig":       {`{"oauth2CredentialList":[{"clientID":"c","clientSecret":"s3"}]}`},
+	}
+	r := httptest.NewRequest("POST", "/auth/editInfo", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ParseForm()
+	p := encode(params(r))
+	for _, secret := range []string{"hunter2", "topsecret", "s3"} {
+		if strings.Contains(p, secret) {
+			t.Errorf("credential %s not masked: %s", secret, p)
+		}
+	}
+	if !strings.Contains(p, "goku") || !strings.Contains(p, "abc") {
+		t.Errorf("unexpected params: %s", p)
+	}
+}
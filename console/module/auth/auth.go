@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"errors"
+
 	"github.com/eolinker/goku-api-gateway/common/pdao"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 )
@@ -21,10 +23,15 @@ func GetAuthInfo(strategyID string) (bool, map[string]interface{}, error) {
 	return authDao.GetAuthInfo(strategyID)
 }
 
-//EditAuthInfo 编辑认证信息
-func EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyList, jwtCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error) {
-	flag, err := authDao.EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyList,
-		jwtCredentialList, oauth2CredentialList, delClientIDList)
+//EditAuthInfo 编辑认证信息，authPolicy为多个鉴权方式的校验策略：any | all，为空时不修改
+func EditAuthInfo(strategyID, strategyName, authPolicy, basicAuthList, apikeyList, jwtCredentialList, hmacCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error) {
+	switch authPolicy {
+	case "", "any", "all":
+	default:
+		return false, errors.New("[ERROR]Illegal authPolicy")
+	}
+	flag, err := authDao.EditAuthInfo(strategyID, strategyName, authPolicy, basicAuthList, apikeyList,
+		jwtCredentialList, hmacCredentialList, oauth2CredentialList, delClientIDList)
 
 	return flag, err
 }
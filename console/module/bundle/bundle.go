@@ -0,0 +1,142 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"gopkg.in/yaml.v2"
+)
+
+//配置包格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var (
+	bundleDao dao.BundleDao
+)
+
+func init() {
+	pdao.Need(&bundleDao)
+}
+
+//Export 导出当前控制台配置，includeSecrets为false时鉴权凭证以占位值导出，应用时保留控制台中已保存的值
+func Export(includeSecrets bool) (*entity.Bundle, error) {
+	return bundleDao.ExportBundle(includeSecrets)
+}
+
+//Apply 对比当前配置生成变更计划，dryRun为false时在同一事务中应用有变更的资源
+func Apply(b *entity.Bundle, userID int, dryRun bool) (*Plan, error) {
+	if err := Validate(b); err != nil {
+		return nil, err
+	}
+	current, err := bundleDao.ExportBundle(true)
+	if err != nil {
+		return nil, err
+	}
+	plan, changed := MakePlan(current, b)
+	plan.DryRun = dryRun
+	if dryRun || !plan.HasChanges() {
+		return plan, nil
+	}
+	if err := bundleDao.ApplyBundle(changed, userID); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+//Encode 将配置包编码为YAML或JSON
+func Encode(b *entity.Bundle, format string) ([]byte, error) {
+	switch format {
+	case "", FormatYAML:
+		return yaml.Marshal(b)
+	case FormatJSON:
+		return json.MarshalIndent(b, "", "  ")
+	}
+	return nil, fmt.Errorf("[ERROR]Unsupported format: %s", format)
+}
+
+//Decode 解析YAML或JSON格式的配置包
+func Decode(data []byte) (*entity.Bundle, error) {
+	b := new(entity.Bundle)
+	// JSON是YAML的子集，统一按YAML解析
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+//Validate 校验配置包版本及资源自然键
+func Validate(b *entity.Bundle) error {
+	if b == nil {
+		return errors.New("[ERROR]Empty bundle")
+	}
+	if b.Version < 1 || b.Version > entity.BundleVersion {
+		return fmt.Errorf("[ERROR]Unsupported bundle version: %d", b.Version)
+	}
+	keys := make(map[string]bool)
+	unique := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("[ERROR]%s without name", kind)
+		}
+		key := kind + ":" + name
+		if keys[key] {
+			return fmt.Errorf("[ERROR]Duplicate %s: %s", kind, name)
+		}
+		keys[key] = true
+		return nil
+	}
+	for _, c := range b.Clusters {
+		if err := unique("cluster", c.Name); err != nil {
+			return err
+		}
+	}
+	for _, s := range b.Discovery {
+		if err := unique("discovery", s.Name); err != nil {
+			return err
+		}
+	}
+	for _, balance := range b.Balances {
+		if err := unique("balance", balance.Name); err != nil {
+			return err
+		}
+	}
+	for _, l := range b.LogConfigs {
+		if err := unique("logConfig", l.Name); err != nil {
+			return err
+		}
+	}
+	for _, p := range b.Plugins {
+		if err := unique("plugin", p.Name); err != nil {
+			return err
+		}
+	}
+	for _, p := range b.Projects {
+		if err := unique("project", p.Name); err != nil {
+			return err
+		}
+		for _, a := range p.APIs {
+			if a.RequestURL == "" || a.RequestMethod == "" {
+				return fmt.Errorf("[ERROR]API of project %s without requestURL or requestMethod", p.Name)
+			}
+			if err := unique("api", apiKey(p.Name, a.RequestMethod, a.RequestURL)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, s := range b.Strategies {
+		if err := unique("strategy", s.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func apiKey(project, method, requestURL string) string {
+	return project + " [" + method + "]" + requestURL
+}
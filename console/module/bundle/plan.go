@@ -0,0 +1,319 @@
+package bundle
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//变更计划中的操作，配置包中未出现的资源不会被删除
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+//maxValueLength 超过该长度的字段值在差异中只标记为已修改
+const maxValueLength = 64
+
+//Change 资源变更
+type Change struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Diff   []string `json:"diff,omitempty"`
+}
+
+//Plan 变更计划
+type Plan struct {
+	DryRun    bool      `json:"dryRun"`
+	Create    int       `json:"create"`
+	Update    int       `json:"update"`
+	Unchanged int       `json:"unchanged"`
+	Changes   []*Change `json:"changes"`
+}
+
+//HasChanges 是否存在需要应用的变更
+func (p *Plan) HasChanges() bool {
+	return p.Create+p.Update > 0
+}
+
+//String 以文本形式输出变更，未变更的资源不输出
+func (p *Plan) String() string {
+	buf := new(strings.Builder)
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			fmt.Fprintf(buf, "+ %s %s\n", c.Kind, c.Name)
+		case ActionUpdate:
+			fmt.Fprintf(buf, "~ %s %s\n", c.Kind, c.Name)
+			for _, d := range c.Diff {
+				fmt.Fprintf(buf, "    %s\n", d)
+			}
+		}
+	}
+	fmt.Fprintf(buf, "Plan: %d to create, %d to update, %d unchanged.\n", p.Create, p.Update, p.Unchanged)
+	return buf.String()
+}
+
+func (p *Plan) add(kind, name string, diff []string, has bool) bool {
+	c := &Change{Kind: kind, Name: name, Diff: diff}
+	switch {
+	case !has:
+		c.Action = ActionCreate
+		c.Diff = nil
+		p.Create++
+	case len(diff) > 0:
+		c.Action = ActionUpdate
+		p.Update++
+	default:
+		c.Action = ActionUnchanged
+		p.Unchanged++
+	}
+	p.Changes = append(p.Changes, c)
+	return c.Action != ActionUnchanged
+}
+
+//MakePlan 对比当前配置与目标配置，返回变更计划及只包含需要新增或更新的资源的配置包
+func MakePlan(current, desired *entity.Bundle) (*Plan, *entity.Bundle) {
+	plan := &Plan{Changes: make([]*Change, 0)}
+	changed := &entity.Bundle{Version: desired.Version}
+
+	clusters := make(map[string]*entity.BundleCluster)
+	for _, c := range current.Clusters {
+		clusters[c.Name] = c
+	}
+	for _, c := range desired.Clusters {
+		old, has := clusters[c.Name]
+		if plan.add("cluster", c.Name, fieldDiff(old, c), has) {
+			changed.Clusters = append(changed.Clusters, c)
+		}
+	}
+
+	discovery := make(map[string]*entity.BundleDiscovery)
+	for _, s := range current.Discovery {
+		discovery[s.Name] = s
+	}
+	for _, s := range desired.Discovery {
+		old, has := discovery[s.Name]
+		if plan.add("discovery", s.Name, fieldDiff(old, s), has) {
+			changed.Discovery = append(changed.Discovery, s)
+		}
+	}
+
+	balances := make(map[string]*entity.BundleBalance)
+	for _, b := range current.Balances {
+		balances[b.Name] = b
+	}
+	for _, b := range desired.Balances {
+		old, has := balances[b.Name]
+		if plan.add("balance", b.Name, fieldDiff(old, b), has) {
+			changed.Balances = append(changed.Balances, b)
+		}
+	}
+
+	logConfigs := make(map[string]*entity.BundleLogConfig)
+	for _, l := range current.LogConfigs {
+		logConfigs[l.Name] = l
+	}
+	for _, l := range desired.LogConfigs {
+		old, has := logConfigs[l.Name]
+		if plan.add("logConfig", l.Name, fieldDiff(old, l), has) {
+			changed.LogConfigs = append(changed.LogConfigs, l)
+		}
+	}
+
+	plugins := make(map[string]*entity.BundlePlugin)
+	for _, p := range current.Plugins {
+		plugins[p.Name] = p
+	}
+	for _, p := range desired.Plugins {
+		old, has := plugins[p.Name]
+		if plan.add("plugin", p.Name, fieldDiff(old, p), has) {
+			changed.Plugins = append(changed.Plugins, p)
+		}
+	}
+
+	projects := make(map[string]*entity.BundleProject)
+	for _, p := range current.Projects {
+		projects[p.Name] = p
+	}
+	for _, p := range desired.Projects {
+		if sub := planProject(plan, projects[p.Name], p); sub != nil {
+			changed.Projects = append(changed.Projects, sub)
+		}
+	}
+
+	strategies := make(map[string]*entity.BundleStrategy)
+	for _, s := range current.Strategies {
+		strategies[s.ID] = s
+	}
+	for _, s := range desired.Strategies {
+		old, has := strategies[s.ID]
+		var diff []string
+		if has {
+			diff = strategyDiff(old, s)
+		}
+		if plan.add("strategy", s.ID, diff, has) {
+			changed.Strategies = append(changed.Strategies, s)
+		}
+	}
+	return plan, changed
+}
+
+// planProject 对比项目的分组及接口，返回需要应用的部分，无变更时返回nil
+func planProject(plan *Plan, old, p *entity.BundleProject) *entity.BundleProject {
+	groups := make(map[string]bool)
+	apis := make(map[string]*entity.BundleAPI)
+	if old != nil {
+		for _, g := range old.Groups {
+			groups[g] = true
+		}
+		for _, a := range old.APIs {
+			apis[apiKey(p.Name, a.RequestMethod, a.RequestURL)] = a
+		}
+	}
+	sub := &entity.BundleProject{Name: p.Name}
+	diff := make([]string, 0)
+	for _, g := range p.Groups {
+		if !groups[g] {
+			sub.Groups = append(sub.Groups, g)
+			diff = append(diff, "groups: + "+g)
+		}
+	}
+	projectChanged := plan.add("project", p.Name, diff, old != nil)
+	for _, a := range p.APIs {
+		key := apiKey(p.Name, a.RequestMethod, a.RequestURL)
+		o, has := apis[key]
+		if plan.add("api", key, fieldDiff(o, a), has) {
+			sub.APIs = append(sub.APIs, a)
+		}
+	}
+	if !projectChanged && len(sub.APIs) == 0 {
+		return nil
+	}
+	return sub
+}
+
+func strategyDiff(old, s *entity.BundleStrategy) []string {
+	diff := fieldDiff(old, s)
+	diff = append(diff, pluginsDiff("plugins", old.Plugins, s.Plugins)...)
+	apis := make(map[string]*entity.BundleStrategyAPI)
+	for _, a := range old.APIs {
+		apis[apiKey(a.Project, a.RequestMethod, a.RequestURL)] = a
+	}
+	seen := make(map[string]bool)
+	for _, a := range s.APIs {
+		key := apiKey(a.Project, a.RequestMethod, a.RequestURL)
+		seen[key] = true
+		o, has := apis[key]
+		if !has {
+			diff = append(diff, "apis: + "+key)
+			continue
+		}
+		for _, d := range fieldDiff(o, a) {
+			diff = append(diff, "apis["+key+"]."+d)
+		}
+		diff = append(diff, pluginsDiff("apis["+key+"].plugins", o.Plugins, a.Plugins)...)
+	}
+	removed := make([]string, 0)
+	for key := range apis {
+		if !seen[key] {
+			removed = append(removed, "apis: - "+key)
+		}
+	}
+	sort.Strings(removed)
+	return append(diff, removed...)
+}
+
+// pluginsDiff 对比插件列表，鉴权插件的配置解密后再比较，差异中不输出配置内容
+func pluginsDiff(prefix string, old, plugins []*entity.BundleStrategyPlugin) []string {
+	diff := make([]string, 0)
+	olds := make(map[string]*entity.BundleStrategyPlugin)
+	for _, p := range old {
+		olds[p.Name] = p
+	}
+	seen := make(map[string]bool)
+	for _, p := range plugins {
+		seen[p.Name] = true
+		o, has := olds[p.Name]
+		if !has {
+			diff = append(diff, prefix+": + "+p.Name)
+			continue
+		}
+		if o.Status != p.Status {
+			diff = append(diff, fmt.Sprintf("%s[%s].status: %d -> %d", prefix, p.Name, o.Status, p.Status))
+		}
+		if openConfig(o.Name, o.Config) != openConfig(p.Name, restoreConfig(p.Name, p.Config, o.Config)) {
+			diff = append(diff, fmt.Sprintf("%s[%s].config: changed", prefix, p.Name))
+		}
+	}
+	removed := make([]string, 0)
+	for name := range olds {
+		if !seen[name] {
+			removed = append(removed, prefix+": - "+name)
+		}
+	}
+	sort.Strings(removed)
+	return append(diff, removed...)
+}
+
+// restoreConfig 以当前配置还原占位值，无法还原时原样返回
+func restoreConfig(name, config, current string) string {
+	if !secret.IsAuthPlugin(name) {
+		return config
+	}
+	c, err := secret.RestoreAuthConfig(name, config, current)
+	if err != nil {
+		return config
+	}
+	return c
+}
+
+func openConfig(name, config string) string {
+	if !secret.IsAuthPlugin(name) {
+		return config
+	}
+	c, err := secret.OpenAuthConfig(name, config)
+	if err != nil {
+		return config
+	}
+	return c
+}
+
+// fieldDiff 对比两个同类型结构体的非切片字段，old为nil时返回nil
+func fieldDiff(old, v interface{}) []string {
+	ov := reflect.ValueOf(old)
+	if !ov.IsValid() || ov.IsNil() {
+		return nil
+	}
+	ov = ov.Elem()
+	nv := reflect.ValueOf(v).Elem()
+	t := nv.Type()
+	diff := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Slice {
+			continue
+		}
+		o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if o == n {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		os, ns := fmt.Sprintf("%q", o), fmt.Sprintf("%q", n)
+		if f.Type.Kind() != reflect.String {
+			os, ns = fmt.Sprint(o), fmt.Sprint(n)
+		}
+		if len(os) > maxValueLength || len(ns) > maxValueLength {
+			diff = append(diff, name+": changed")
+			continue
+		}
+		diff = append(diff, name+": "+os+" -> "+ns)
+	}
+	return diff
+}
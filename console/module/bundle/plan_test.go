@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"testing"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func testBundle() *entity.Bundle {
+	return &entity.Bundle{
+		Version:  entity.BundleVersion,
+		Clusters: []*entity.BundleCluster{{Name: "default", Title: "默认集群"}},
+		Balances: []*entity.BundleBalance{{Name: "user", ServiceName: "static", Static: "10.0.0.1:8080"}},
+		Projects: []*entity.BundleProject{{
+			Name:   "demo",
+			Groups: []string{"user", "user/admin"},
+			APIs: []*entity.BundleAPI{
+				{Name: "login", Group: "user", RequestURL: "/login", RequestMethod: "POST", BalanceName: "user", IsFollow: "false", Timeout: 2000},
+				{Name: "info", Group: "user/admin", RequestURL: "/info", RequestMethod: "GET", BalanceName: "user", IsFollow: "false", Timeout: 2000},
+			},
+		}},
+		Strategies: []*entity.BundleStrategy{{
+			ID:      "abc123",
+			Name:    "default",
+			Plugins: []*entity.BundleStrategyPlugin{{Name: "goku-rate_limiting", Status: 1, Config: `{"second":10}`}},
+			APIs:    []*entity.BundleStrategyAPI{{Project: "demo", RequestURL: "/login", RequestMethod: "POST"}},
+		}},
+	}
+}
+
+func TestPlanIdempotent(t *testing.T) {
+	plan, changed := MakePlan(testBundle(), testBundle())
+	if plan.HasChanges() {
+		t.Fatalf("unexpected changes:\n%s", plan)
+	}
+	if len(changed.Clusters)+len(changed.Balances)+len(changed.Projects)+len(changed.Strategies) != 0 {
+		t.Fatalf("unexpected resources to apply: %+v", changed)
+	}
+	if plan.Unchanged != 6 {
+		t.Errorf("unchanged = %d, want 6", plan.Unchanged)
+	}
+}
+
+func TestPlanRedacted(t *testing.T) {
+	current := testBundle()
+	current.Strategies[0].Plugins = append(current.Strategies[0].Plugins, &entity.BundleStrategyPlugin{Name: "goku-hmac_auth", Status: 1, Config: `[{"secret":"s","userName":"a"}]`})
+	desired := testBundle()
+	desired.Strategies[0].Plugins = append(desired.Strategies[0].Plugins, &entity.BundleStrategyPlugin{Name: "goku-hmac_auth", Status: 1, Config: `[{"secret":"******","userName":"a"}]`})
+	if plan, _ := MakePlan(current, desired); plan.HasChanges() {
+		t.Fatalf("redacted credential should keep the current value:\n%s", plan)
+	}
+	desired.Strategies[0].Plugins[1].Config = `[{"secret":"******","userName":"b"}]`
+	if plan, _ := MakePlan(current, desired); !plan.HasChanges() {
+		t.Fatal("credential of another user should be a change")
+	}
+}
+
+func TestPlanChanges(t *testing.T) {
+	current := testBundle()
+	desired := testBundle()
+	desired.Clusters = append(desired.Clusters, &entity.BundleCluster{Name: "prod", Title: "生产"})
+	desired.Projects[0].APIs[1].Timeout = 3000
+	desired.Projects[0].Groups = append(desired.Projects[0].Groups, "order")
+	desired.Strategies[0].APIs = nil
+	desired.Strategies[0].Plugins[0].Config = `{"second":20}`
+
+	plan, changed := MakePlan(current, desired)
+	if plan.Create != 1 || plan.Update != 3 {
+		t.Fatalf("create = %d, update = %d\n%s", plan.Create, plan.Update, plan)
+	}
+	if len(changed.Clusters) != 1 || changed.Clusters[0].Name != "prod" {
+		t.Errorf("clusters to apply: %+v", changed.Clusters)
+	}
+	p := changed.Projects[0]
+	if len(p.Groups) != 1 || p.Groups[0] != "order" || len(p.APIs) != 1 || p.APIs[0].RequestURL != "/info" {
+		t.Errorf("project to apply: %+v", p)
+	}
+	want := map[string][]string{
+		"demo [GET]/info": {"timeout: 2000 -> 3000"},
+		"abc123":          {"plugins[goku-rate_limiting].config: changed", "apis: - demo [POST]/login"},
+	}
+	for _, c := range plan.Changes {
+		w, has := want[c.Name]
+		if !has {
+			continue
+		}
+		if len(c.Diff) != len(w) {
+			t.Fatalf("%s diff = %v, want %v", c.Name, c.Diff, w)
+		}
+		for i := range w {
+			if c.Diff[i] != w[i] {
+				t.Errorf("%s diff = %v, want %v", c.Name, c.Diff, w)
+			}
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		data, err := Encode(testBundle(), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Decode(data)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if err := Validate(b); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if plan, _ := MakePlan(testBundle(), b); plan.HasChanges() {
+			t.Errorf("%s round trip changed:\n%s", format, plan)
+		}
+	}
+	if err := Validate(&entity.Bundle{Version: 99}); err == nil {
+		t.Error("expected version error")
+	}
+}
@@ -0,0 +1,42 @@
+package config_log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/cluster"
+	"github.com/eolinker/goku-api-gateway/goku-node/access-log/output"
+)
+
+//AccessOutputs 集群的access日志输出，Outputs为空时节点输出到access日志配置的文件
+type AccessOutputs struct {
+	Cluster string                    `json:"cluster"`
+	Outputs []*config.AccessLogOutput `json:"outputs"`
+	Types   []string                  `json:"types"`
+}
+
+//GetAccessOutputs 获取集群的access日志输出
+func GetAccessOutputs(clusterName string) (*AccessOutputs, error) {
+	if !cluster.CheckClusterNameIsExist(clusterName) {
+		return nil, fmt.Errorf("cluster %s does not exist", clusterName)
+	}
+	outputs, err := configLogDao.GetAccessOutputs(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return &AccessOutputs{Cluster: clusterName, Outputs: outputs, Types: output.Types}, nil
+}
+
+//SetAccessOutputs 设置集群的access日志输出，发布版本后生效
+func SetAccessOutputs(clusterName string, outputs []*config.AccessLogOutput) error {
+	if !cluster.CheckClusterNameIsExist(clusterName) {
+		return fmt.Errorf("cluster %s does not exist", clusterName)
+	}
+	for i, o := range outputs {
+		if err := output.Check(o); err != nil {
+			return fmt.Errorf("output[%d]: %s", i, err)
+		}
+	}
+	return configLogDao.SetAccessOutputs(clusterName, outputs, time.Now().Format("2006-01-02 15:04:05"))
+}
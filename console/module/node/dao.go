@@ -8,8 +8,9 @@ import (
 var (
 	nodeDao dao.NodeDao
 	nodeGroupDao dao.NodeGroupDao
+	replicaDao dao.ReplicaDao
 )
 
 func init() {
-	pdao.Need(&nodeDao,&nodeGroupDao)
+	pdao.Need(&nodeDao,&nodeGroupDao,&replicaDao)
 }
\ No newline at end of file
@@ -0,0 +1,31 @@
+package node
+
+import (
+	"sync"
+)
+
+var (
+	pluginErrors       = make(map[string]map[string]string)
+	pluginErrorsLocker sync.RWMutex
+)
+
+//SetPluginErrors 记录节点上报的插件加载错误，errs为空时清除
+func SetPluginErrors(instance string, errs map[string]string) {
+	pluginErrorsLocker.Lock()
+	if len(errs) == 0 {
+		delete(pluginErrors, instance)
+	} else {
+		pluginErrors[instance] = errs
+	}
+	pluginErrorsLocker.Unlock()
+	if instanceLocker.IsLock(instance) {
+		setSessionPluginErrors(instance, errs)
+	}
+}
+
+//GetPluginErrors 获取节点插件加载错误
+func GetPluginErrors(instance string) map[string]string {
+	pluginErrorsLocker.RLock()
+	defer pluginErrorsLocker.RUnlock()
+	return pluginErrors[instance]
+}
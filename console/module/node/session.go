@@ -0,0 +1,87 @@
+package node
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var sessionOnce sync.Once
+
+// lockSession 在共享数据库中占用节点会话，避免同一节点同时连接多个控制台实例
+func lockSession(key string) bool {
+	now := time.Now()
+	locked, err := replicaDao.LockNodeSession(key, replica.ID(), now.Format(replica.TimeFormat), now.Add(-EXPIRE).Format(replica.TimeFormat))
+	if err != nil {
+		log.Warn("lock session of node ", key, " error:", err)
+		return false
+	}
+	return locked
+}
+
+func unlockSession(key string) {
+	err := replicaDao.UnlockNodeSession(key, replica.ID())
+	if err != nil {
+		log.Warn("unlock session of node ", key, " error:", err)
+	}
+}
+
+// liveSessions 获取所有控制台实例上在线节点的会话
+func liveSessions() map[string]*entity.NodeSession {
+	sessions, err := replicaDao.GetNodeSessions(time.Now().Add(-EXPIRE).Format(replica.TimeFormat))
+	if err != nil {
+		log.Warn("get node sessions error:", err)
+		return nil
+	}
+	m := make(map[string]*entity.NodeSession, len(sessions))
+	for _, s := range sessions {
+		m[s.NodeKey] = s
+	}
+	return m
+}
+
+func setSessionPluginErrors(instance string, errs map[string]string) {
+	data := ""
+	if len(errs) > 0 {
+		d, err := json.Marshal(errs)
+		if err != nil {
+			return
+		}
+		data = string(d)
+	}
+	err := replicaDao.SetNodeSessionPluginErrors(instance, replica.ID(), data)
+	if err != nil {
+		log.Warn("save plugin errors of node ", instance, " error:", err)
+	}
+}
+
+func sessionPluginErrors(s *entity.NodeSession) map[string]string {
+	if s == nil || s.PluginErrors == "" {
+		return nil
+	}
+	errs := make(map[string]string)
+	if json.Unmarshal([]byte(s.PluginErrors), &errs) != nil {
+		return nil
+	}
+	return errs
+}
+
+//KeepSessions 定时刷新当前控制台实例持有的节点会话，控制台退出后会话在EXPIRE后过期
+func KeepSessions() {
+	sessionOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(EXPIRE / 3)
+			defer ticker.Stop()
+			for range ticker.C {
+				err := replicaDao.RefreshNodeSessions(replica.ID(), time.Now().Format(replica.TimeFormat))
+				if err != nil {
+					log.Warn("refresh node sessions error:", err)
+				}
+			}
+		}()
+	})
+}
@@ -110,7 +110,14 @@ func IsLive(instance string) bool {
 	if instanceLocker.IsLock(instance) {
 		return true
 	}
+	if isHeartBeatLive(instance) {
+		return true
+	}
+	_, has := liveSessions()[instance]
+	return has
+}
 
+func isHeartBeatLive(instance string) bool {
 	t, has := manager.get(instance)
 
 	if !has {
@@ -123,10 +130,19 @@ func IsLive(instance string) bool {
 	return true
 }
 
-//ResetNodeStatus 重置节点状态
+//ResetNodeStatus 重置节点状态，连接到其他控制台实例的节点通过共享数据库中的会话判断
 func ResetNodeStatus(nodes ...*entity.Node) {
+	if len(nodes) == 0 {
+		return
+	}
+	sessions := liveSessions()
 	for _, node := range nodes {
-		if instanceLocker.IsLock(node.NodeKey) || IsLive(node.NodeKey) {
+		session, hasSession := sessions[node.NodeKey]
+		node.PluginErrors = GetPluginErrors(node.NodeKey)
+		if node.PluginErrors == nil {
+			node.PluginErrors = sessionPluginErrors(session)
+		}
+		if instanceLocker.IsLock(node.NodeKey) || hasSession || isHeartBeatLive(node.NodeKey) {
 			node.NodeStatus = 1
 		} else {
 			if node.NodeStatus == 1 {
@@ -138,11 +154,22 @@ func ResetNodeStatus(nodes ...*entity.Node) {
 	}
 }
 
+//Lock 节点连接时加锁，同一节点同时只能连接一个控制台实例
 func Lock(key string) bool {
-	return instanceLocker.Lock(key)
+	if !instanceLocker.Lock(key) {
+		return false
+	}
+	if !lockSession(key) {
+		instanceLocker.UnLock(key)
+		return false
+	}
+	return true
 }
+
+//UnLock 节点断开时解锁
 func UnLock(key string) {
 	instanceLocker.UnLock(key)
+	unlockSession(key)
 	Refresh(key)
 
 }
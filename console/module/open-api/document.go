@@ -0,0 +1,96 @@
+package open_api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/openapi"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	openAPIDao dao.OpenAPIDao
+)
+
+func init() {
+	pdao.Need(&openAPIDao)
+}
+
+//Document 文档详情
+type Document struct {
+	*entity.OpenAPI
+	BasePath   string               `json:"basePath"`
+	Operations []*openapi.Operation `json:"operations"`
+}
+
+//SaveOpenAPI 新增或更新OpenAPI文档，data支持JSON及YAML格式
+func SaveOpenAPI(name, remark string, data []byte) (*Document, error) {
+	if name == "" {
+		return nil, errors.New("[ERROR]Illegal name")
+	}
+	d, err := openapi.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	now := timeNow()
+	o := &entity.OpenAPI{
+		Name:       name,
+		Remark:     remark,
+		Title:      d.Title,
+		Version:    d.APIVersion,
+		Content:    string(data),
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	err = openAPIDao.SaveOpenAPI(o)
+	if err != nil {
+		return nil, err
+	}
+	return toDocument(o, d), nil
+}
+
+//GetOpenAPIList 获取文档列表
+func GetOpenAPIList() ([]*entity.OpenAPI, error) {
+	return openAPIDao.GetOpenAPIList()
+}
+
+//GetOpenAPI 获取文档详情，包含操作列表
+func GetOpenAPI(name string) (*Document, error) {
+	o, err := openAPIDao.GetOpenAPI(name)
+	if err != nil {
+		return nil, err
+	}
+	d, err := openapi.Parse([]byte(o.Content))
+	if err != nil {
+		return nil, err
+	}
+	return toDocument(o, d), nil
+}
+
+//GetDocument 获取解析后的文档
+func GetDocument(name string) (*openapi.Document, error) {
+	o, err := openAPIDao.GetOpenAPI(name)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Parse([]byte(o.Content))
+}
+
+//BatchDeleteOpenAPI 批量删除文档
+func BatchDeleteOpenAPI(names []string) error {
+	return openAPIDao.BatchDeleteOpenAPI(names)
+}
+
+func timeNow() string {
+	return time.Now().Format("2006-01-02 15:04:05")
+}
+
+func toDocument(o *entity.OpenAPI, d *openapi.Document) *Document {
+	return &Document{
+		OpenAPI:    o,
+		BasePath:   d.BasePath,
+		Operations: d.Operations,
+	}
+}
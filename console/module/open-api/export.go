@@ -0,0 +1,63 @@
+package open_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/openapi"
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//Export 导出项目接口为OpenAPI 3文档，servers为网关的访问地址
+func Export(projectID int, version string, servers []string) ([]byte, error) {
+	_, project, err := projectDao.GetProjectInfo(projectID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := importDao.GetProjectOperations(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version = "1.0.0"
+	}
+	operations := make([]*openapi.ExportOperation, 0, len(list))
+	for _, o := range list {
+		body := ""
+		if o.Validation != "" {
+			var cfg config.ValidationConfig
+			if json.Unmarshal([]byte(o.Validation), &cfg) == nil && cfg.Enable {
+				body = cfg.Body
+			}
+		}
+		var tags []string
+		if o.GroupName != "" {
+			tags = []string{o.GroupName}
+		}
+		methods := strings.Split(o.RequestMethod, ",")
+		for _, method := range methods {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method == "" {
+				continue
+			}
+			id := fmt.Sprintf("api-%d", o.APIID)
+			if len(methods) > 1 {
+				id = fmt.Sprintf("%s-%s", id, strings.ToLower(method))
+			}
+			operations = append(operations, &openapi.ExportOperation{
+				ID:      id,
+				Method:  method,
+				Path:    o.RequestURL,
+				Summary: o.APIName,
+				Tags:    tags,
+				Body:    body,
+			})
+		}
+	}
+	return openapi.Export(&openapi.ExportInfo{
+		Title:   project.ProjectName,
+		Version: version,
+		Servers: servers,
+	}, operations)
+}
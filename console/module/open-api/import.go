@@ -0,0 +1,313 @@
+package open_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/openapi"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/balance"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//导入计划中的操作
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+//defaultGroup 未设置tag的操作导入的分组
+const defaultGroup = "default"
+
+var (
+	importDao     dao.ImportDao
+	apiGroupDao   dao.APIGroupDao
+	projectDao    dao.ProjectDao
+	validationDao dao.ValidationDao
+)
+
+func init() {
+	pdao.Need(&importDao, &apiGroupDao, &projectDao, &validationDao)
+}
+
+//ImportParam 导入参数
+type ImportParam struct {
+	ProjectID int
+	UserID    int
+	// BalanceName 不为空时所有接口使用该负载，否则按文档的服务地址创建静态负载
+	BalanceName string
+	// ServiceName 按服务地址创建负载时使用的静态服务
+	ServiceName string
+	// DocumentName 不为空时保存文档，并为导入的接口开启请求校验
+	DocumentName string
+	DryRun       bool
+}
+
+//PlanGroup 分组变更
+type PlanGroup struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+}
+
+//PlanBalance 负载变更
+type PlanBalance struct {
+	Name   string `json:"balanceName"`
+	Static string `json:"static"`
+	Action string `json:"action"`
+}
+
+//PlanAPI 接口变更
+type PlanAPI struct {
+	Action      string   `json:"action"`
+	APIID       int      `json:"apiID,omitempty"`
+	Operation   string   `json:"operation"`
+	APIName     string   `json:"apiName"`
+	Method      string   `json:"method"`
+	RequestURL  string   `json:"requestURL"`
+	TargetURL   string   `json:"targetURL"`
+	GroupName   string   `json:"groupName"`
+	BalanceName string   `json:"balanceName"`
+	Changes     []string `json:"changes,omitempty"`
+
+	operation *entity.OpenAPIOperation
+}
+
+//ImportPlan 导入计划，DryRun为true时只对比不写入
+type ImportPlan struct {
+	DryRun   bool           `json:"dryRun"`
+	Title    string         `json:"title"`
+	Groups   []*PlanGroup   `json:"groups"`
+	Balances []*PlanBalance `json:"balances"`
+	APIs     []*PlanAPI     `json:"apis"`
+}
+
+//Import 导入OpenAPI 3或Swagger 2文档到项目，按tag创建分组，按服务地址创建负载
+func Import(data []byte, param *ImportParam) (*ImportPlan, error) {
+	d, err := openapi.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := makePlan(d, param)
+	if err != nil {
+		return nil, err
+	}
+	if param.DryRun {
+		return plan, nil
+	}
+	if param.DocumentName != "" {
+		if _, err := SaveOpenAPI(param.DocumentName, "imported to project "+fmt.Sprint(param.ProjectID), data); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range plan.Balances {
+		if b.Action != ActionCreate {
+			continue
+		}
+		result, err := balance.Add(&balance.Param{
+			Name:        b.Name,
+			ServiceName: param.ServiceName,
+			Static:      b.Static,
+			Desc:        "imported from openapi",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[ERROR]Fail to create balance %s: %s", b.Name, result)
+		}
+	}
+	operations := make([]*entity.OpenAPIOperation, 0, len(plan.APIs))
+	for _, a := range plan.APIs {
+		if a.Action != ActionUnchanged {
+			operations = append(operations, a.operation)
+		}
+	}
+	if err := importDao.ImportOpenAPI(param.ProjectID, param.UserID, operations); err != nil {
+		return nil, err
+	}
+	for _, a := range plan.APIs {
+		a.APIID = a.operation.APIID
+		if param.DocumentName == "" {
+			continue
+		}
+		cfg, _ := json.Marshal(&config.ValidationConfig{
+			Enable:    true,
+			OpenAPI:   param.DocumentName,
+			Operation: a.Operation,
+		})
+		if err := validationDao.SaveValidation(a.APIID, string(cfg), timeNow()); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// makePlan 对比文档与项目中已有的分组、负载及接口，生成导入计划
+func makePlan(d *openapi.Document, param *ImportParam) (*ImportPlan, error) {
+	plan := &ImportPlan{
+		DryRun:   param.DryRun,
+		Title:    d.Title,
+		Groups:   make([]*PlanGroup, 0),
+		Balances: make([]*PlanBalance, 0),
+		APIs:     make([]*PlanAPI, 0, len(d.Operations)),
+	}
+
+	balanceName, protocol := param.BalanceName, "http"
+	for _, server := range d.Servers {
+		u, err := url.Parse(server)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		if len(plan.Balances) == 0 && u.Scheme != "" {
+			protocol = strings.ToLower(u.Scheme)
+		}
+		if param.BalanceName != "" {
+			break
+		}
+		b := &PlanBalance{Name: serverBalanceName(u), Static: serverAddress(u), Action: ActionCreate}
+		if _, err := balance.Get(b.Name); err == nil {
+			b.Action = ActionUnchanged
+		} else if param.ServiceName == "" {
+			return nil, errors.New("[ERROR]serviceName is required to create balances from servers")
+		}
+		plan.Balances = append(plan.Balances, b)
+	}
+	if balanceName == "" {
+		if len(plan.Balances) == 0 {
+			return nil, errors.New("[ERROR]balanceName is required when the document has no servers")
+		}
+		balanceName = plan.Balances[0].Name
+	}
+
+	_, groupList, err := apiGroupDao.GetAPIGroupList(param.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]int)
+	for _, g := range groupList {
+		name, _ := g["groupName"].(string)
+		if depth, _ := g["groupDepth"].(int); depth > 1 {
+			continue
+		}
+		if _, has := groups[name]; !has {
+			groups[name], _ = g["groupID"].(int)
+		}
+	}
+
+	existing, err := importDao.GetProjectOperations(param.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	basePath := strings.TrimSuffix(d.BasePath, "/")
+	planned := make(map[string]bool)
+	for _, op := range d.Operations {
+		groupName := defaultGroup
+		if len(op.Tags) > 0 && op.Tags[0] != "" {
+			groupName = op.Tags[0]
+		}
+		if _, has := groups[groupName]; !has && !planned[groupName] {
+			planned[groupName] = true
+			plan.Groups = append(plan.Groups, &PlanGroup{Name: groupName, Action: ActionCreate})
+		}
+		name := op.Summary
+		if name == "" {
+			name = op.ID
+		}
+		o := &entity.OpenAPIOperation{
+			APIName:       name,
+			GroupID:       groups[groupName],
+			GroupName:     groupName,
+			RequestURL:    basePath + openapi.GatewayPath(op.Path),
+			RequestMethod: op.Method,
+			TargetURL:     basePath + op.Path,
+			TargetMethod:  op.Method,
+			BalanceName:   balanceName,
+			Protocol:      protocol,
+		}
+		a := &PlanAPI{
+			Action:      ActionCreate,
+			Operation:   op.ID,
+			APIName:     o.APIName,
+			Method:      o.RequestMethod,
+			RequestURL:  o.RequestURL,
+			TargetURL:   o.TargetURL,
+			GroupName:   groupName,
+			BalanceName: balanceName,
+			operation:   o,
+		}
+		if old := findOperation(existing, o.RequestURL, o.RequestMethod); old != nil {
+			o.APIID, a.APIID = old.APIID, old.APIID
+			a.Changes = diffOperation(old, o)
+			a.Action = ActionUpdate
+			if len(a.Changes) == 0 {
+				a.Action = ActionUnchanged
+			}
+		}
+		plan.APIs = append(plan.APIs, a)
+	}
+	return plan, nil
+}
+
+func findOperation(list []*entity.OpenAPIOperation, requestURL, method string) *entity.OpenAPIOperation {
+	for _, o := range list {
+		if o.RequestURL != requestURL {
+			continue
+		}
+		for _, m := range strings.Split(o.RequestMethod, ",") {
+			if strings.EqualFold(strings.TrimSpace(m), method) {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+func diffOperation(old, o *entity.OpenAPIOperation) []string {
+	changes := make([]string, 0)
+	for _, field := range []struct {
+		name     string
+		old, new string
+	}{
+		{"apiName", old.APIName, o.APIName},
+		{"groupName", old.GroupName, o.GroupName},
+		{"targetURL", old.TargetURL, o.TargetURL},
+		{"targetMethod", old.TargetMethod, o.TargetMethod},
+		{"balanceName", old.BalanceName, o.BalanceName},
+		{"protocol", old.Protocol, o.Protocol},
+	} {
+		if field.old != field.new {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field.name, field.old, field.new))
+		}
+	}
+	if old.GroupName == o.GroupName {
+		o.GroupID = old.GroupID
+	}
+	return changes
+}
+
+// serverBalanceName 由服务地址生成负载名称，如api.example.com:8080生成api_example_com_8080
+func serverBalanceName(u *url.URL) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, u.Host)
+}
+
+// serverAddress 服务地址的host:port，未指定端口时按协议补充
+func serverAddress(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	if strings.EqualFold(u.Scheme, "https") {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -1,7 +1,10 @@
 package plugin
 
 import (
+	"errors"
+
 	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 )
@@ -82,3 +85,22 @@ func BatchStartPlugin(pluginNameList string) (bool, string, error) {
 func EditPluginCheckStatus(pluginName string, isCheck int) (bool, string, error) {
 	return pluginDao.EditPluginCheckStatus(pluginName, isCheck)
 }
+
+//CheckErrorPolicy 检查插件错误处理策略
+func CheckErrorPolicy(errorPolicy string, errorStatus int) error {
+	if !config.IsPluginErrorPolicy(errorPolicy) {
+		return errors.New("[ERROR]Illegal errorPolicy")
+	}
+	if errorStatus != 0 && (errorStatus < 100 || errorStatus > 599) {
+		return errors.New("[ERROR]Illegal errorStatus")
+	}
+	return nil
+}
+
+//EditPluginErrorPolicy 修改插件错误处理策略
+func EditPluginErrorPolicy(pluginName, errorPolicy string, errorStatus int, errorBody string) error {
+	if err := CheckErrorPolicy(errorPolicy, errorStatus); err != nil {
+		return err
+	}
+	return pluginDao.EditPluginErrorPolicy(pluginName, errorPolicy, errorStatus, errorBody)
+}
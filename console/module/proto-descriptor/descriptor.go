@@ -0,0 +1,105 @@
+package proto_descriptor
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	proto_codec "github.com/eolinker/goku-api-gateway/common/proto-codec"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	protoDescriptorDao dao.ProtoDescriptorDao
+)
+
+func init() {
+	pdao.Need(&protoDescriptorDao)
+}
+
+//Method grpc方法信息
+type Method struct {
+	Path            string `json:"path"`
+	Input           string `json:"input"`
+	Output          string `json:"output"`
+	ClientStreaming bool   `json:"clientStreaming"`
+	ServerStreaming bool   `json:"serverStreaming"`
+}
+
+//Descriptor 描述文件详情
+type Descriptor struct {
+	*entity.ProtoDescriptor
+	Messages []string  `json:"messages"`
+	Methods  []*Method `json:"methods"`
+}
+
+//SaveProtoDescriptor 新增或更新描述文件，data为 protoc --descriptor_set_out 生成的文件内容
+func SaveProtoDescriptor(name, remark string, data []byte) (*Descriptor, error) {
+	if name == "" {
+		return nil, errors.New("[ERROR]Illegal name")
+	}
+	registry, err := proto_codec.ParseDescriptorSet(data)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	content := base64.StdEncoding.EncodeToString(data)
+	err = protoDescriptorDao.SaveProtoDescriptor(name, remark, content, now)
+	if err != nil {
+		return nil, err
+	}
+	return toDescriptor(&entity.ProtoDescriptor{
+		Name:       name,
+		Remark:     remark,
+		Content:    content,
+		UpdateTime: now,
+	}, registry), nil
+}
+
+//GetProtoDescriptorList 获取描述文件列表
+func GetProtoDescriptorList() ([]*entity.ProtoDescriptor, error) {
+	return protoDescriptorDao.GetProtoDescriptorList()
+}
+
+//GetProtoDescriptor 获取描述文件详情，包含消息与方法列表
+func GetProtoDescriptor(name string) (*Descriptor, error) {
+	p, err := protoDescriptorDao.GetProtoDescriptor(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(p.Content)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := proto_codec.ParseDescriptorSet(data)
+	if err != nil {
+		return nil, err
+	}
+	return toDescriptor(p, registry), nil
+}
+
+//BatchDeleteProtoDescriptor 批量删除描述文件
+func BatchDeleteProtoDescriptor(names []string) error {
+	return protoDescriptorDao.BatchDeleteProtoDescriptor(names)
+}
+
+func toDescriptor(p *entity.ProtoDescriptor, registry *proto_codec.Registry) *Descriptor {
+	methods := registry.Methods()
+	d := &Descriptor{
+		ProtoDescriptor: p,
+		Messages:        registry.Messages(),
+		Methods:         make([]*Method, 0, len(methods)),
+	}
+	for _, m := range methods {
+		d.Methods = append(d.Methods, &Method{
+			Path:            m.FullPath(),
+			Input:           m.Input,
+			Output:          m.Output,
+			ClientStreaming: m.ClientStreaming,
+			ServerStreaming: m.ServerStreaming,
+		})
+	}
+	return d
+}
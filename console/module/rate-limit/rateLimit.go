@@ -0,0 +1,92 @@
+package rate_limit
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	rateLimitDao dao.RateLimitDao
+)
+
+func init() {
+	pdao.Need(&rateLimitDao)
+}
+
+//Check 检查限流规则
+func Check(r *entity.RateLimit) error {
+	if r.StrategyID == "" && r.APIID == 0 {
+		return errors.New("[ERROR]strategyID and apiID can not be both empty")
+	}
+	switch r.Algorithm {
+	case "":
+		r.Algorithm = config.RateLimitTokenBucket
+	case config.RateLimitTokenBucket, config.RateLimitSlidingWindow:
+	default:
+		return errors.New("[ERROR]Illegal algorithm")
+	}
+	switch r.Mode {
+	case "":
+		r.Mode = config.RateLimitModeLocal
+	case config.RateLimitModeLocal, config.RateLimitModeRedis:
+	default:
+		return errors.New("[ERROR]Illegal mode")
+	}
+	switch {
+	case r.Key == "":
+		r.Key = "ip"
+	case r.Key == "ip", r.Key == "credential", r.Key == "strategy":
+	case strings.HasPrefix(r.Key, "header:") && strings.TrimSpace(strings.TrimPrefix(r.Key, "header:")) != "":
+	default:
+		return errors.New("[ERROR]Illegal key")
+	}
+	if r.Limit < 0 || r.Burst < 0 || r.DailyQuota < 0 || r.MonthlyQuota < 0 {
+		return errors.New("[ERROR]limit, burst and quota can not be negative")
+	}
+	if r.Limit == 0 && r.DailyQuota == 0 && r.MonthlyQuota == 0 {
+		return errors.New("[ERROR]limit, dailyQuota and monthlyQuota can not be all zero")
+	}
+	if r.Period <= 0 {
+		r.Period = 1
+	}
+	return nil
+}
+
+//AddRateLimit 新增限流规则
+func AddRateLimit(r *entity.RateLimit) (int, error) {
+	if err := Check(r); err != nil {
+		return 0, err
+	}
+	r.UpdateTime = time.Now().Format("2006-01-02 15:04:05")
+	return rateLimitDao.AddRateLimit(r)
+}
+
+//EditRateLimit 编辑限流规则
+func EditRateLimit(r *entity.RateLimit) error {
+	if err := Check(r); err != nil {
+		return err
+	}
+	r.UpdateTime = time.Now().Format("2006-01-02 15:04:05")
+	return rateLimitDao.EditRateLimit(r)
+}
+
+//GetRateLimit 获取限流规则
+func GetRateLimit(id int) (*entity.RateLimit, error) {
+	return rateLimitDao.GetRateLimit(id)
+}
+
+//GetRateLimitList 获取限流规则列表
+func GetRateLimitList(strategyID string, apiID int) ([]*entity.RateLimit, error) {
+	return rateLimitDao.GetRateLimitList(strategyID, apiID)
+}
+
+//BatchDeleteRateLimit 批量删除限流规则
+func BatchDeleteRateLimit(ids []int) error {
+	return rateLimitDao.BatchDeleteRateLimit(ids)
+}
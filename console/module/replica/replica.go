@@ -0,0 +1,122 @@
+package replica
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const (
+	//EventConfig 发布了新的版本配置
+	EventConfig = "config"
+	//EventCachePurge 清除接口响应缓存
+	EventCachePurge = "cachePurge"
+	//EventNodeCommand 向节点发送停止或重启指令
+	EventNodeCommand = "nodeCommand"
+)
+
+//TimeFormat 会话及事件的时间格式
+const TimeFormat = "2006-01-02 15:04:05"
+
+const (
+	pollInterval = time.Second
+	eventExpire  = time.Minute * 10
+)
+
+//HandlerFunc 事件处理函数
+type HandlerFunc func(data string)
+
+var (
+	replicaDao dao.ReplicaDao
+
+	consoleID = utils.Md5(utils.GetRandomString(16) + time.Now().Format("20060102150405"))
+
+	handlers = make(map[string][]HandlerFunc)
+	locker   sync.RWMutex
+	once     sync.Once
+)
+
+func init() {
+	pdao.Need(&replicaDao)
+}
+
+//ID 当前控制台实例ID
+func ID() string {
+	return consoleID
+}
+
+//Subscribe 订阅其他控制台实例广播的事件
+func Subscribe(eventType string, handler HandlerFunc) {
+	locker.Lock()
+	handlers[eventType] = append(handlers[eventType], handler)
+	locker.Unlock()
+}
+
+//Publish 广播事件，仅由其他控制台实例处理
+func Publish(eventType, data string) error {
+	return replicaDao.AddConsoleEvent(consoleID, eventType, data, time.Now().Format(TimeFormat))
+}
+
+//Start 开始接收其他控制台实例广播的事件，只接收启动之后的事件
+func Start() {
+	once.Do(func() {
+		lastID, err := replicaDao.GetLastConsoleEventID()
+		if err != nil {
+			log.Warn("get last console event error:", err)
+		}
+		go run(lastID)
+	})
+}
+
+func run(lastID int) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	lastClean := time.Now()
+	for range ticker.C {
+		lastID = poll(lastID)
+		if time.Since(lastClean) > eventExpire {
+			lastClean = time.Now()
+			err := replicaDao.DeleteConsoleEvents(lastClean.Add(-eventExpire).Format(TimeFormat))
+			if err != nil {
+				log.Warn("delete console events error:", err)
+			}
+		}
+	}
+}
+
+func poll(lastID int) int {
+	events, err := replicaDao.GetConsoleEvents(lastID)
+	if err != nil {
+		log.Warn("get console events error:", err)
+		return lastID
+	}
+	for _, e := range events {
+		lastID = e.ID
+		if e.ConsoleID == consoleID {
+			continue
+		}
+		locker.RLock()
+		hs := handlers[e.EventType]
+		locker.RUnlock()
+		for _, h := range hs {
+			h(e.Data)
+		}
+	}
+	return lastID
+}
+
+//Leader 获取或续期名为name的租约，返回当前实例是否持有租约。
+//用于只需在一个控制台实例上运行的后台任务，持有者超过ttl未续期时由其他实例接管
+func Leader(name string, ttl time.Duration) bool {
+	now := time.Now()
+	ok, err := replicaDao.AcquireLease(name, consoleID, now.Format(TimeFormat), now.Add(-ttl).Format(TimeFormat))
+	if err != nil {
+		log.Warn("acquire lease ", name, " error:", err)
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,118 @@
+package response_cache
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//PurgeFunc 通知节点清除缓存，返回已通知的节点数
+type PurgeFunc func(apiID int, key string) int
+
+var (
+	responseCacheDao dao.ResponseCacheDao
+
+	purgeLock      sync.RWMutex
+	purgeCallbacks []PurgeFunc
+)
+
+// purgeEvent 广播到其他控制台实例的缓存清除事件
+type purgeEvent struct {
+	APIID int    `json:"apiID"`
+	Key   string `json:"key"`
+}
+
+func init() {
+	pdao.Need(&responseCacheDao)
+	replica.Subscribe(replica.EventCachePurge, onPurgeEvent)
+}
+
+//AddPurgeCallback 注册缓存清除回调
+func AddPurgeCallback(f PurgeFunc) {
+	purgeLock.Lock()
+	purgeCallbacks = append(purgeCallbacks, f)
+	purgeLock.Unlock()
+}
+
+//Check 检查缓存配置
+func Check(cfg *config.ResponseCacheConfig) error {
+	if cfg.TTL <= 0 && cfg.Enable {
+		return errors.New("[ERROR]ttl must be greater than zero")
+	}
+	if cfg.TTL < 0 || cfg.StaleTTL < 0 {
+		return errors.New("[ERROR]ttl and staleTTL can not be negative")
+	}
+	for _, code := range cfg.StatusCodes {
+		if code < 200 || code > 599 || code == 206 || code == 304 {
+			return errors.New("[ERROR]Illegal statusCodes")
+		}
+	}
+	for _, name := range append(append([]string{}, cfg.QueryParams...), cfg.Headers...) {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("[ERROR]queryParams and headers can not contain empty name")
+		}
+	}
+	return nil
+}
+
+//SaveResponseCache 保存接口缓存配置，发布新版本后生效
+func SaveResponseCache(apiID int, cfg *config.ResponseCacheConfig) error {
+	if err := Check(cfg); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return responseCacheDao.SaveResponseCache(apiID, string(data), time.Now().Format("2006-01-02 15:04:05"))
+}
+
+//GetResponseCache 获取接口缓存配置
+func GetResponseCache(apiID int) (*entity.ResponseCache, error) {
+	return responseCacheDao.GetResponseCache(apiID)
+}
+
+//GetResponseCacheList 获取已配置缓存的接口列表
+func GetResponseCacheList() ([]*entity.ResponseCache, error) {
+	return responseCacheDao.GetResponseCacheList()
+}
+
+//Purge 通知在线节点清除接口缓存，key为空时清除接口下的全部缓存，返回当前控制台实例通知的节点数
+func Purge(apiID int, key string) (int, error) {
+	if key != "" && !strings.HasPrefix(key, config.ResponseCacheKeyPrefix(apiID)) {
+		return 0, errors.New("[ERROR]The key does not belong to the api")
+	}
+	data, _ := json.Marshal(&purgeEvent{APIID: apiID, Key: key})
+	if err := replica.Publish(replica.EventCachePurge, string(data)); err != nil {
+		log.Warn("publish cache purge event error:", err)
+	}
+	return purge(apiID, key), nil
+}
+
+func onPurgeEvent(data string) {
+	e := new(purgeEvent)
+	if err := json.Unmarshal([]byte(data), e); err != nil {
+		return
+	}
+	purge(e.APIID, e.Key)
+}
+
+func purge(apiID int, key string) int {
+	purgeLock.RLock()
+	callbacks := purgeCallbacks
+	purgeLock.RUnlock()
+	count := 0
+	for _, f := range callbacks {
+		count += f(apiID, key)
+	}
+	return count
+}
@@ -0,0 +1,95 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	node_script "github.com/eolinker/goku-api-gateway/node/script"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	scriptDao dao.ScriptDao
+
+	scriptNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+)
+
+func init() {
+	pdao.Need(&scriptDao)
+}
+
+//Check 检查脚本，脚本需能编译并至少定义before、access、proxy中的一个函数
+func Check(s *entity.Script) error {
+	if !scriptNameRegexp.MatchString(s.ScriptName) {
+		return errors.New("[ERROR]Illegal scriptName")
+	}
+	if s.Language == "" {
+		s.Language = config.ScriptLanguageJavaScript
+	}
+	if s.Timeout < 0 || s.MaxDataSize < 0 {
+		return errors.New("[ERROR]timeout and maxDataSize can not be negative")
+	}
+	if time.Duration(s.Timeout)*time.Millisecond > node_script.MaxTimeout {
+		return fmt.Errorf("[ERROR]timeout can not be greater than %d", node_script.MaxTimeout/time.Millisecond)
+	}
+	_, err := node_script.Compile(&config.ScriptConfig{
+		Name:        s.ScriptName,
+		Language:    s.Language,
+		Content:     s.Content,
+		Timeout:     s.Timeout,
+		MaxDataSize: s.MaxDataSize,
+	})
+	if err != nil {
+		return errors.New("[ERROR]" + err.Error())
+	}
+	return nil
+}
+
+//AddScript 新增脚本
+func AddScript(s *entity.Script) (int, error) {
+	if err := Check(s); err != nil {
+		return 0, err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	s.CreateTime, s.UpdateTime = now, now
+	return scriptDao.AddScript(s)
+}
+
+//EditScript 编辑脚本，脚本名称不可修改
+func EditScript(s *entity.Script) error {
+	old, err := scriptDao.GetScript(s.ScriptID)
+	if err != nil {
+		return errors.New("[ERROR]The script does not exist")
+	}
+	s.ScriptName = old.ScriptName
+	if err := Check(s); err != nil {
+		return err
+	}
+	s.UpdateTime = time.Now().Format("2006-01-02 15:04:05")
+	return scriptDao.EditScript(s)
+}
+
+//GetScript 获取脚本
+func GetScript(scriptID int) (*entity.Script, error) {
+	return scriptDao.GetScript(scriptID)
+}
+
+//GetScriptList 获取脚本列表
+func GetScriptList(keyword string) ([]*entity.Script, error) {
+	return scriptDao.GetScriptList(keyword)
+}
+
+//BatchDeleteScript 批量删除脚本
+func BatchDeleteScript(scriptIDs []int) error {
+	return scriptDao.BatchDeleteScript(scriptIDs)
+}
+
+//GetScriptHistory 获取脚本历史版本
+func GetScriptHistory(scriptID int) ([]*entity.ScriptHistory, error) {
+	return scriptDao.GetScriptHistory(scriptID)
+}
@@ -0,0 +1,62 @@
+package size_limit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	sizeLimitDao dao.SizeLimitDao
+)
+
+func init() {
+	pdao.Need(&sizeLimitDao)
+}
+
+//Check 检查大小限制，strategyID及apiID都为空时作用于整个网关
+func Check(l *entity.SizeLimit) error {
+	if l.MaxRequestBody < 0 || l.MaxResponseBody < 0 || l.MaxHeader < 0 {
+		return errors.New("[ERROR]size limit can not be negative")
+	}
+	if l.MaxRequestBody == 0 && l.MaxResponseBody == 0 && l.MaxHeader == 0 {
+		return errors.New("[ERROR]maxRequestBody, maxResponseBody and maxHeader can not be all zero")
+	}
+	return nil
+}
+
+//AddSizeLimit 新增大小限制
+func AddSizeLimit(l *entity.SizeLimit) (int, error) {
+	if err := Check(l); err != nil {
+		return 0, err
+	}
+	l.UpdateTime = time.Now().Format("2006-01-02 15:04:05")
+	return sizeLimitDao.AddSizeLimit(l)
+}
+
+//EditSizeLimit 编辑大小限制
+func EditSizeLimit(l *entity.SizeLimit) error {
+	if err := Check(l); err != nil {
+		return err
+	}
+	l.UpdateTime = time.Now().Format("2006-01-02 15:04:05")
+	return sizeLimitDao.EditSizeLimit(l)
+}
+
+//GetSizeLimit 获取大小限制
+func GetSizeLimit(id int) (*entity.SizeLimit, error) {
+	return sizeLimitDao.GetSizeLimit(id)
+}
+
+//GetSizeLimitList 获取大小限制列表
+func GetSizeLimitList(strategyID string, apiID int) ([]*entity.SizeLimit, error) {
+	return sizeLimitDao.GetSizeLimitList(strategyID, apiID)
+}
+
+//BatchDeleteSizeLimit 批量删除大小限制
+func BatchDeleteSizeLimit(ids []int) error {
+	return sizeLimitDao.BatchDeleteSizeLimit(ids)
+}
@@ -0,0 +1,220 @@
+package traffic
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//GroupByAPI 按接口排行
+	GroupByAPI = "api"
+	//GroupByStrategy 按策略排行
+	GroupByStrategy = "strategy"
+
+	// maxPoints 单次查询返回的最大时间点数
+	maxPoints = 1440
+	// defaultRetentionDays 默认保留天数，可通过配置文件traffic_retention_days修改
+	defaultRetentionDays = 7
+	cleanInterval        = time.Hour
+)
+
+var (
+	trafficDao dao.TrafficDao
+	cleanOnce  sync.Once
+)
+
+func init() {
+	pdao.Need(&trafficDao)
+}
+
+//Point 时间序列中的一个统计点，Time为统计区间的开始时间（unix秒）
+type Point struct {
+	Time int64 `json:"time"`
+	Summary
+}
+
+//Summary 汇总后的流量指标，耗时单位毫秒
+type Summary struct {
+	Requests         uint64  `json:"requests"`
+	QPS              float64 `json:"qps"`
+	Status2xx        uint64  `json:"status2xx"`
+	Status3xx        uint64  `json:"status3xx"`
+	Status4xx        uint64  `json:"status4xx"`
+	Status5xx        uint64  `json:"status5xx"`
+	AvgLatency       float64 `json:"avgLatency"`
+	P50              float64 `json:"p50"`
+	P90              float64 `json:"p90"`
+	P99              float64 `json:"p99"`
+	MaxLatency       float64 `json:"maxLatency"`
+	UpstreamRequests uint64  `json:"upstreamRequests"`
+	UpstreamErrors   uint64  `json:"upstreamErrors"`
+}
+
+//TopItem 按接口或策略汇总的流量排行
+type TopItem struct {
+	StrategyID string `json:"strategyID,omitempty"`
+	APIID      int    `json:"apiID,omitempty"`
+	Summary
+}
+
+func summarize(s *config.TrafficStat, seconds int64) Summary {
+	r := Summary{
+		Requests:         s.Requests,
+		Status2xx:        s.Status2xx,
+		Status3xx:        s.Status3xx,
+		Status4xx:        s.Status4xx,
+		Status5xx:        s.Status5xx,
+		MaxLatency:       s.LatencyMax,
+		UpstreamRequests: s.UpstreamRequests,
+		UpstreamErrors:   s.UpstreamErrors,
+	}
+	if seconds > 0 {
+		r.QPS = float64(s.Requests) / float64(seconds)
+	}
+	if s.Requests > 0 {
+		r.AvgLatency = s.LatencySum / float64(s.Requests)
+		r.P50 = s.Percentile(0.5)
+		r.P90 = s.Percentile(0.9)
+		r.P99 = s.Percentile(0.99)
+	}
+	return r
+}
+
+//Series 将统计记录按step秒聚合为[start,end)内的时间序列，没有数据的区间补零
+func Series(stats []*entity.TrafficStat, start, end, step int64) []*Point {
+	count := (end - start + step - 1) / step
+	merged := make([]*config.TrafficStat, count)
+	for i := range merged {
+		merged[i] = config.NewTrafficStat("", 0)
+	}
+	for _, s := range stats {
+		if s.ReportTime < start || s.ReportTime >= end {
+			continue
+		}
+		merged[(s.ReportTime-start)/step].Merge(&s.TrafficStat)
+	}
+	points := make([]*Point, 0, count)
+	for i, s := range merged {
+		t := start + int64(i)*step
+		seconds := step
+		if t+step > end {
+			seconds = end - t
+		}
+		points = append(points, &Point{Time: t, Summary: summarize(s, seconds)})
+	}
+	return points
+}
+
+//Top 将统计记录按接口或策略汇总，按请求数降序返回前limit项，limit不大于0时返回全部
+func Top(stats []*entity.TrafficStat, start, end int64, by string, limit int) []*TopItem {
+	merged := make(map[string]*config.TrafficStat)
+	for _, s := range stats {
+		key := s.StrategyID
+		if by == GroupByAPI {
+			key = strconv.Itoa(s.APIID)
+		}
+		m, has := merged[key]
+		if !has {
+			if by == GroupByAPI {
+				m = config.NewTrafficStat("", s.APIID)
+			} else {
+				m = config.NewTrafficStat(s.StrategyID, 0)
+			}
+			merged[key] = m
+		}
+		m.Merge(&s.TrafficStat)
+	}
+	items := make([]*TopItem, 0, len(merged))
+	for _, m := range merged {
+		items = append(items, &TopItem{StrategyID: m.StrategyID, APIID: m.APIID, Summary: summarize(m, end-start)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Requests != items[j].Requests {
+			return items[i].Requests > items[j].Requests
+		}
+		if items[i].APIID != items[j].APIID {
+			return items[i].APIID < items[j].APIID
+		}
+		return items[i].StrategyID < items[j].StrategyID
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+func checkRange(start, end int64) error {
+	if start <= 0 || end <= start {
+		return errors.New("[ERROR]Illegal time range")
+	}
+	return nil
+}
+
+//GetSeries 获取流量时间序列，step过小时按最多1440个时间点调整
+func GetSeries(start, end, step int64, cluster, strategyID string, apiID int) ([]*Point, error) {
+	if err := checkRange(start, end); err != nil {
+		return nil, err
+	}
+	if min := (end - start + maxPoints - 1) / maxPoints; step < min {
+		step = min
+	}
+	stats, err := trafficDao.GetTrafficStats(start, end, cluster, strategyID, apiID)
+	if err != nil {
+		return nil, err
+	}
+	return Series(stats, start, end, step), nil
+}
+
+//GetTop 获取流量排行
+func GetTop(start, end int64, cluster, by string, limit int) ([]*TopItem, error) {
+	if err := checkRange(start, end); err != nil {
+		return nil, err
+	}
+	if by != GroupByStrategy {
+		by = GroupByAPI
+	}
+	stats, err := trafficDao.GetTrafficStats(start, end, cluster, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	return Top(stats, start, end, by, limit), nil
+}
+
+//AddReport 保存节点上报的流量统计，并定期清理过期数据
+func AddReport(cluster, nodeKey string, report *config.TrafficReport) error {
+	cleanOnce.Do(func() {
+		go clean()
+	})
+	if len(report.Stats) == 0 {
+		return nil
+	}
+	return trafficDao.AddTrafficReport(cluster, nodeKey, report)
+}
+
+func retention() time.Duration {
+	days, err := strconv.Atoi(conf.MastValue("traffic_retention_days", strconv.Itoa(defaultRetentionDays)))
+	if err != nil || days <= 0 {
+		days = defaultRetentionDays
+	}
+	return time.Hour * 24 * time.Duration(days)
+}
+
+func clean() {
+	for {
+		before := time.Now().Add(-retention()).Unix()
+		if err := trafficDao.DeleteTrafficStats(before); err != nil {
+			log.Warn("delete traffic stats error:", err)
+		}
+		time.Sleep(cleanInterval)
+	}
+}
@@ -0,0 +1,68 @@
+package traffic
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func testStat(t int64, strategyID string, apiID int, latencies ...float64) *entity.TrafficStat {
+	s := &entity.TrafficStat{ReportTime: t, TrafficStat: *config.NewTrafficStat(strategyID, apiID)}
+	for _, l := range latencies {
+		s.ObserveRequest(200, l)
+	}
+	s.ObserveUpstream(0)
+	return s
+}
+
+func TestSeries(t *testing.T) {
+	stats := []*entity.TrafficStat{
+		testStat(100, "s1", 1, 10, 20),
+		testStat(110, "s1", 2, 30),
+		testStat(170, "s2", 1, 4000),
+		testStat(300, "s2", 1, 1),
+	}
+	points := Series(stats, 100, 220, 60)
+	if len(points) != 2 {
+		t.Fatalf("points = %d, want 2", len(points))
+	}
+	if points[0].Time != 100 || points[0].Requests != 3 || points[0].UpstreamErrors != 2 {
+		t.Errorf("point 0 = %+v", points[0])
+	}
+	if points[0].AvgLatency != 20 || points[0].QPS != 0.05 {
+		t.Errorf("point 0 latency = %v, qps = %v", points[0].AvgLatency, points[0].QPS)
+	}
+	if points[1].Requests != 1 || points[1].P99 <= 2500 || points[1].P99 > 4000 {
+		t.Errorf("point 1 = %+v", points[1])
+	}
+}
+
+func TestTop(t *testing.T) {
+	stats := []*entity.TrafficStat{
+		testStat(100, "s1", 1, 10, 20),
+		testStat(110, "s1", 2, 30),
+		testStat(170, "s2", 1, 40),
+	}
+	items := Top(stats, 100, 200, GroupByAPI, 1)
+	if len(items) != 1 || items[0].APIID != 1 || items[0].Requests != 3 {
+		t.Fatalf("top api = %+v", items)
+	}
+	items = Top(stats, 100, 200, GroupByStrategy, 0)
+	if len(items) != 2 || items[0].StrategyID != "s1" || items[1].StrategyID != "s2" {
+		t.Fatalf("top strategy = %+v", items)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	s := config.NewTrafficStat("", 0)
+	for i := 0; i < 100; i++ {
+		s.ObserveRequest(200, float64(i%50))
+	}
+	if p := s.Percentile(0.5); p < 5 || p > 25 {
+		t.Errorf("p50 = %v", p)
+	}
+	if p := s.Percentile(1); p != 49 {
+		t.Errorf("p100 = %v, want 49", p)
+	}
+}
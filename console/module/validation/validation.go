@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	json_schema "github.com/eolinker/goku-api-gateway/common/json-schema"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	open_api "github.com/eolinker/goku-api-gateway/console/module/open-api"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+var (
+	validationDao dao.ValidationDao
+)
+
+func init() {
+	pdao.Need(&validationDao)
+}
+
+//Check 检查校验配置，编译JSON Schema并确认引用的文档及操作存在
+func Check(cfg *config.ValidationConfig) error {
+	for name, raw := range map[string]string{"body": cfg.Body, "query": cfg.Query, "headers": cfg.Headers} {
+		if raw == "" {
+			continue
+		}
+		if _, err := json_schema.Compile([]byte(raw)); err != nil {
+			return fmt.Errorf("[ERROR]Illegal %s schema: %s", name, err)
+		}
+	}
+	if cfg.OpenAPI == "" {
+		if cfg.Operation != "" {
+			return fmt.Errorf("[ERROR]openAPI can not be empty when operation is set")
+		}
+		return nil
+	}
+	d, err := open_api.GetDocument(cfg.OpenAPI)
+	if err != nil {
+		return fmt.Errorf("[ERROR]The openapi document %s does not exist", cfg.OpenAPI)
+	}
+	if cfg.Operation != "" {
+		if _, has := d.Operation(cfg.Operation); !has {
+			return fmt.Errorf("[ERROR]The operation %s does not exist", cfg.Operation)
+		}
+	}
+	return nil
+}
+
+//SaveValidation 保存接口请求校验配置，发布新版本后生效
+func SaveValidation(apiID int, cfg *config.ValidationConfig) error {
+	if err := Check(cfg); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return validationDao.SaveValidation(apiID, string(data), time.Now().Format("2006-01-02 15:04:05"))
+}
+
+//GetValidation 获取接口请求校验配置
+func GetValidation(apiID int) (*entity.Validation, error) {
+	return validationDao.GetValidation(apiID)
+}
+
+//GetValidationList 获取已配置请求校验的接口列表
+func GetValidationList() ([]*entity.Validation, error) {
+	return validationDao.GetValidationList()
+}
@@ -0,0 +1,334 @@
+package versionConfig
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//CanaryRunning 灰度中
+	CanaryRunning = "running"
+	//CanaryPromoted 已全量发布
+	CanaryPromoted = "promoted"
+	//CanaryRolledBack 已回滚
+	CanaryRolledBack = "rolledBack"
+
+	//DecisionObserve 继续观察
+	DecisionObserve = "observe"
+	//DecisionConfirm 观察期结束，等待确认全量发布
+	DecisionConfirm = "confirm"
+	//DecisionPromote 自动全量发布
+	DecisionPromote = "promote"
+	//DecisionRollback 错误率升高，自动回滚
+	DecisionRollback = "rollback"
+	//DecisionHold 观察期结束但灰度节点离线或请求数不足，无法评估，等待人工确认或回滚
+	DecisionHold = "hold"
+
+	canaryInterval = time.Second * 30
+	// 灰度评估只在持有租约的控制台实例上运行
+	canaryLeaseName = "versionCanary"
+	canaryLeaseTTL  = canaryInterval * 3
+)
+
+var (
+	versionCanaryDao dao.VersionCanaryDao
+	trafficDao       dao.TrafficDao
+	canaryOnce       sync.Once
+
+	errCanaryRunning    = errors.New("[ERROR]Another canary release is running")
+	errCanaryNotRunning = errors.New("[ERROR]The canary release is not running")
+	errGroupNoNode      = errors.New("[ERROR]The node group has no node")
+	errCanaryThreshold  = errors.New("[ERROR]The errorThreshold must be greater than 0")
+	errGroupNotExist    = errors.New("[ERROR]The node group does not exist")
+)
+
+func init() {
+	pdao.Need(&versionCanaryDao, &trafficDao)
+}
+
+type canaryConfig struct {
+	groupID int
+	conf    map[string]*config.GokuConfig
+}
+
+//CanaryStatus 灰度节点与其余节点在灰度期间的5xx、4xx比例（百分比）及评估结论
+type CanaryStatus struct {
+	*entity.VersionCanary
+	Nodes          []string `json:"nodes"`
+	OfflineNodes   []string `json:"offlineNodes"`
+	CanaryRequests uint64   `json:"canaryRequests"`
+	CanaryRatio    float64  `json:"canaryRatio"`
+	Canary4xxRatio float64  `json:"canary4xxRatio"`
+	BaseRequests   uint64   `json:"baseRequests"`
+	BaseRatio      float64  `json:"baseRatio"`
+	Base4xxRatio   float64  `json:"base4xxRatio"`
+	Decision       string   `json:"decision"`
+}
+
+func loadCanary(clusters []*entity.Cluster) *canaryConfig {
+	c, err := versionCanaryDao.GetVersionCanaryByStatus(CanaryRunning)
+	if err != nil {
+		return nil
+	}
+	cf, bf, df, err := versionDao.GetVersionConfigByID(c.VersionID)
+	if err != nil {
+		log.Warn("load canary config error:", err)
+		return nil
+	}
+	return &canaryConfig{
+		groupID: c.GroupID,
+		conf:    buildConfig(clusters, c.VersionID, cf, bf, df),
+	}
+}
+
+//CanaryConfig 获取灰度中节点分组的配置，分组不在灰度中时返回nil
+func CanaryConfig(cluster string, groupID int) *config.GokuConfig {
+	lock.RLock()
+	defer lock.RUnlock()
+	if canaryConf == nil || groupID == 0 || canaryConf.groupID != groupID {
+		return nil
+	}
+	return canaryConf.conf[cluster]
+}
+
+//GetNodeConfig 获取节点应使用的配置，灰度中的节点分组使用灰度版本
+func GetNodeConfig(cluster string, groupID int) (*config.GokuConfig, error) {
+	if c := CanaryConfig(cluster, groupID); c != nil {
+		return c, nil
+	}
+	return GetConfig(cluster)
+}
+
+func notifyConfig() {
+	load()
+	if e := replica.Publish(replica.EventConfig, ""); e != nil {
+		log.Warn("publish config event error:", e)
+	}
+}
+
+//StartCanary 将版本灰度发布到节点分组，同一时间只允许一个灰度发布
+func StartCanary(c *entity.VersionCanary) (*entity.VersionCanary, error) {
+	if c.ErrorThreshold <= 0 {
+		return nil, errCanaryThreshold
+	}
+	if _, err := versionCanaryDao.GetVersionCanaryByStatus(CanaryRunning); err == nil {
+		return nil, errCanaryRunning
+	}
+	if exist, _ := node.CheckNodeGroupIsExist(c.GroupID); !exist {
+		return nil, errGroupNotExist
+	}
+	if _, _, _, err := versionDao.GetVersionConfigByID(c.VersionID); err != nil {
+		return nil, errVersionNotExist
+	}
+	c.BaseVersionID = versionDao.GetPublishVersionID()
+	if c.VersionID == c.BaseVersionID {
+		return nil, errSameVersion
+	}
+	nodes, _, err := canaryNodes(c.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, errGroupNoNode
+	}
+	now := time.Now()
+	c.Status = CanaryRunning
+	c.StartTime = now.Unix()
+	c.CreateTime = now.Format(timeFormat)
+	c.UpdateTime = c.CreateTime
+	id, err := versionCanaryDao.AddVersionCanary(c)
+	if err != nil {
+		return nil, err
+	}
+	c.CanaryID = id
+	notifyConfig()
+	return c, nil
+}
+
+//PromoteCanary 将灰度版本发布到全部节点，开启审批时结束灰度并生成待审批的发布申请
+func PromoteCanary(canaryID, userID int, reason string) (*entity.VersionPublish, error) {
+	c, err := versionCanaryDao.GetVersionCanary(canaryID)
+	if err != nil || c.Status != CanaryRunning {
+		return nil, errCanaryNotRunning
+	}
+	ok, err := versionCanaryDao.FinishVersionCanary(canaryID, CanaryRunning, CanaryPromoted, reason, time.Now().Unix(), time.Now().Format(timeFormat))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errCanaryNotRunning
+	}
+	remark := "canary " + strconv.Itoa(canaryID) + " promoted"
+	if ApprovalRequired() {
+		p, err := RequestPublish(c.VersionID, userID, 0, remark)
+		notifyConfig()
+		return p, err
+	}
+	return publishNow(c.VersionID, userID, remark, false)
+}
+
+//RollbackCanary 结束灰度发布，灰度节点恢复为当前发布版本
+func RollbackCanary(canaryID int, reason string) error {
+	ok, err := versionCanaryDao.FinishVersionCanary(canaryID, CanaryRunning, CanaryRolledBack, reason, time.Now().Unix(), time.Now().Format(timeFormat))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errCanaryNotRunning
+	}
+	notifyConfig()
+	return nil
+}
+
+//GetCanaryList 分页获取灰度发布记录
+func GetCanaryList(page, pageSize int) ([]*entity.VersionCanary, int, error) {
+	return versionCanaryDao.GetVersionCanaryList(page, pageSize)
+}
+
+//GetRunningCanary 获取进行中的灰度发布及当前评估结果，没有时返回nil
+func GetRunningCanary() (*CanaryStatus, error) {
+	c, err := versionCanaryDao.GetVersionCanaryByStatus(CanaryRunning)
+	if err != nil {
+		return nil, nil
+	}
+	return evaluateCanary(c, time.Now().Unix())
+}
+
+// canaryNodes 获取分组内的节点及其是否在线，以及分组所属集群
+func canaryNodes(groupID int) (map[string]bool, string, error) {
+	nodes, err := node.GetAllNode()
+	if err != nil {
+		return nil, "", err
+	}
+	node.ResetNodeStatus(nodes...)
+	keys := make(map[string]bool)
+	cluster := ""
+	for _, n := range nodes {
+		if n.GroupID == groupID {
+			keys[n.NodeKey] = n.NodeStatus == 1
+			cluster = n.Cluster
+		}
+	}
+	return keys, cluster, nil
+}
+
+func evaluateCanary(c *entity.VersionCanary, now int64) (*CanaryStatus, error) {
+	keys, cluster, err := canaryNodes(c.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := trafficDao.GetTrafficStats(c.StartTime, now+1, cluster, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateCanary(c, keys, stats, now), nil
+}
+
+//EvaluateCanary 比较灰度节点与同集群其余节点的5xx及4xx比例，nodes为灰度节点及其是否在线：
+//灰度节点请求数达到MinRequests且任一比例升高不小于ErrorThreshold时回滚；观察期结束且未升高时，开启自动发布的全量发布，否则等待确认；
+//观察期结束时灰度节点离线或请求数不足，无法证明灰度版本正常，不自动发布
+func EvaluateCanary(c *entity.VersionCanary, nodes map[string]bool, stats []*entity.TrafficStat, now int64) *CanaryStatus {
+	s := &CanaryStatus{VersionCanary: c, Nodes: make([]string, 0, len(nodes)), OfflineNodes: make([]string, 0), Decision: DecisionObserve}
+	for key, online := range nodes {
+		s.Nodes = append(s.Nodes, key)
+		if !online {
+			s.OfflineNodes = append(s.OfflineNodes, key)
+		}
+	}
+	sort.Strings(s.Nodes)
+	sort.Strings(s.OfflineNodes)
+	var canary5xx, canary4xx, base5xx, base4xx uint64
+	for _, st := range stats {
+		if _, has := nodes[st.NodeKey]; has {
+			s.CanaryRequests += st.Requests
+			canary5xx += st.Status5xx
+			canary4xx += st.Status4xx
+		} else {
+			s.BaseRequests += st.Requests
+			base5xx += st.Status5xx
+			base4xx += st.Status4xx
+		}
+	}
+	s.BaseRatio, s.Base4xxRatio = ratio(base5xx, s.BaseRequests), ratio(base4xx, s.BaseRequests)
+	s.CanaryRatio, s.Canary4xxRatio = ratio(canary5xx, s.CanaryRequests), ratio(canary4xx, s.CanaryRequests)
+
+	observed := now >= c.StartTime+int64(c.ObserveTime)
+	if s.CanaryRequests == 0 || s.CanaryRequests < uint64(c.MinRequests) {
+		if observed {
+			s.Decision = DecisionHold
+		}
+		return s
+	}
+	switch {
+	case s.CanaryRatio-s.BaseRatio >= c.ErrorThreshold, s.Canary4xxRatio-s.Base4xxRatio >= c.ErrorThreshold:
+		s.Decision = DecisionRollback
+	case !observed:
+		// 观察期内继续观察
+	case len(s.OfflineNodes) > 0:
+		s.Decision = DecisionHold
+	case c.AutoPromote:
+		s.Decision = DecisionPromote
+	default:
+		s.Decision = DecisionConfirm
+	}
+	return s
+}
+
+// ratio 百分比，total为0时返回0
+func ratio(count, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
+
+//StartCanaryWatcher 开始定时评估进行中的灰度发布
+func StartCanaryWatcher() {
+	canaryOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(canaryInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if replica.Leader(canaryLeaseName, canaryLeaseTTL) {
+					watchCanary()
+				}
+			}
+		}()
+	})
+}
+
+func watchCanary() {
+	c, err := versionCanaryDao.GetVersionCanaryByStatus(CanaryRunning)
+	if err != nil {
+		return
+	}
+	s, err := evaluateCanary(c, time.Now().Unix())
+	if err != nil {
+		log.Warn("evaluate canary ", c.CanaryID, " error:", err)
+		return
+	}
+	switch s.Decision {
+	case DecisionRollback:
+		reason := "5xx ratio " + strconv.FormatFloat(s.CanaryRatio, 'f', 2, 64) + "%, 4xx ratio " + strconv.FormatFloat(s.Canary4xxRatio, 'f', 2, 64) + "% of canary nodes exceeds " + strconv.FormatFloat(s.BaseRatio, 'f', 2, 64) + "%, " + strconv.FormatFloat(s.Base4xxRatio, 'f', 2, 64) + "% of other nodes"
+		log.Warn("canary ", c.CanaryID, " rolled back: ", reason)
+		err = RollbackCanary(c.CanaryID, reason)
+	case DecisionHold:
+		log.Warn("canary ", c.CanaryID, " is held: ", s.CanaryRequests, " requests from canary nodes, offline nodes ", s.OfflineNodes)
+	case DecisionPromote:
+		_, err = PromoteCanary(c.CanaryID, c.UserID, "observed without error ratio degradation")
+	}
+	if err != nil {
+		log.Warn("finish canary ", c.CanaryID, " error:", err)
+	}
+}
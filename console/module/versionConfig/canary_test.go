@@ -0,0 +1,54 @@
+package versionConfig
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+func canaryStat(nodeKey string, requests, status5xx uint64) *entity.TrafficStat {
+	return &entity.TrafficStat{NodeKey: nodeKey, TrafficStat: config.TrafficStat{Requests: requests, Status5xx: status5xx}}
+}
+
+func canaryStat4xx(nodeKey string, requests, status4xx uint64) *entity.TrafficStat {
+	return &entity.TrafficStat{NodeKey: nodeKey, TrafficStat: config.TrafficStat{Requests: requests, Status4xx: status4xx}}
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	nodes := map[string]bool{"canary": true}
+	c := &entity.VersionCanary{StartTime: 100, ObserveTime: 60, ErrorThreshold: 5, MinRequests: 100}
+	cases := []struct {
+		name     string
+		auto     bool
+		stats    []*entity.TrafficStat
+		now      int64
+		decision string
+	}{
+		{"too few requests", false, []*entity.TrafficStat{canaryStat("canary", 50, 50)}, 120, DecisionObserve},
+		{"too few requests after observe time", true, []*entity.TrafficStat{canaryStat("canary", 50, 0)}, 200, DecisionHold},
+		{"no telemetry after observe time", true, nil, 200, DecisionHold},
+		{"4xx degraded", true, []*entity.TrafficStat{canaryStat4xx("canary", 100, 40), canaryStat4xx("other", 100, 1)}, 160, DecisionRollback},
+		{"degraded", false, []*entity.TrafficStat{canaryStat("canary", 100, 10), canaryStat("other", 100, 2)}, 120, DecisionRollback},
+		{"within threshold", false, []*entity.TrafficStat{canaryStat("canary", 100, 6), canaryStat("other", 100, 2)}, 120, DecisionObserve},
+		{"wait confirm", false, []*entity.TrafficStat{canaryStat("canary", 100, 6), canaryStat("other", 100, 2)}, 160, DecisionConfirm},
+		{"auto promote", true, []*entity.TrafficStat{canaryStat("canary", 100, 0)}, 160, DecisionPromote},
+	}
+	for _, cs := range cases {
+		c.AutoPromote = cs.auto
+		s := EvaluateCanary(c, nodes, cs.stats, cs.now)
+		if s.Decision != cs.decision {
+			t.Errorf("%s: decision %s, want %s (canary %.2f, base %.2f)", cs.name, s.Decision, cs.decision, s.CanaryRatio, s.BaseRatio)
+		}
+	}
+
+	c.AutoPromote = true
+	offline := map[string]bool{"canary": true, "down": false}
+	s := EvaluateCanary(c, offline, []*entity.TrafficStat{canaryStat("canary", 100, 0)}, 160)
+	if s.Decision != DecisionHold || len(s.OfflineNodes) != 1 || s.OfflineNodes[0] != "down" {
+		t.Errorf("offline canary node: decision %s, offline %v", s.Decision, s.OfflineNodes)
+	}
+	if s := EvaluateCanary(c, offline, []*entity.TrafficStat{canaryStat("canary", 100, 10)}, 160); s.Decision != DecisionRollback {
+		t.Errorf("degraded with offline canary node: decision %s", s.Decision)
+	}
+}
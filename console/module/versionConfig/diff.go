@@ -0,0 +1,241 @@
+package versionConfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/console/module/audit"
+)
+
+const (
+	//ActionAdd 新版本中新增
+	ActionAdd = "add"
+	//ActionRemove 新版本中删除
+	ActionRemove = "remove"
+	//ActionModify 两个版本中均存在但配置不同
+	ActionModify = "modify"
+)
+
+//Snapshot 版本配置快照
+type Snapshot struct {
+	Config   *config.GokuConfig
+	Balance  map[string]map[string]*config.BalanceConfig
+	Discover map[string]map[string]*config.DiscoverConfig
+}
+
+//EntityDiff 单个对象的差异，Key为对象标识（接口ID、策略ID、插件名或集群/名称）
+type EntityDiff struct {
+	Key     string          `json:"key"`
+	Name    string          `json:"name"`
+	Action  string          `json:"action"`
+	Changes []*audit.Change `json:"changes"`
+}
+
+//VersionDiff 两个版本之间的结构化差异
+type VersionDiff struct {
+	FromVersionID int           `json:"fromVersionID"`
+	ToVersionID   int           `json:"toVersionID"`
+	APIs          []*EntityDiff `json:"apis"`
+	Strategies    []*EntityDiff `json:"strategies"`
+	Plugins       []*EntityDiff `json:"plugins"`
+	Balances      []*EntityDiff `json:"balances"`
+	Discovery     []*EntityDiff `json:"discovery"`
+}
+
+//Empty 两个版本是否没有差异
+func (d *VersionDiff) Empty() bool {
+	return len(d.APIs)+len(d.Strategies)+len(d.Plugins)+len(d.Balances)+len(d.Discovery) == 0
+}
+
+type entry struct {
+	name  string
+	value interface{}
+}
+
+//DiffVersions 比较两个版本的配置，fromID为0时与当前发布版本比较
+func DiffVersions(fromID, toID int) (*VersionDiff, error) {
+	if fromID == 0 {
+		fromID = versionDao.GetPublishVersionID()
+	}
+	from, err := getSnapshot(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := getSnapshot(toID)
+	if err != nil {
+		return nil, err
+	}
+	d := Compare(from, to)
+	d.FromVersionID, d.ToVersionID = fromID, toID
+	return d, nil
+}
+
+func getSnapshot(versionID int) (*Snapshot, error) {
+	cf, bf, df, err := versionDao.GetVersionConfigByID(versionID)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Config: cf, Balance: bf, Discover: df}, nil
+}
+
+//Compare 比较两个版本快照，快照为nil时视为空配置
+func Compare(from, to *Snapshot) *VersionDiff {
+	if from == nil {
+		from = &Snapshot{}
+	}
+	if to == nil {
+		to = &Snapshot{}
+	}
+	return &VersionDiff{
+		APIs:       diffEntries(apiEntries(from.Config), apiEntries(to.Config)),
+		Strategies: diffEntries(strategyEntries(from.Config), strategyEntries(to.Config)),
+		Plugins:    diffEntries(pluginEntries(from.Config), pluginEntries(to.Config)),
+		Balances:   diffEntries(balanceEntries(from.Balance), balanceEntries(to.Balance)),
+		Discovery:  diffEntries(discoverEntries(from.Discover), discoverEntries(to.Discover)),
+	}
+}
+
+func diffEntries(before, after map[string]*entry) []*EntityDiff {
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, has := before[k]; !has {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	diffs := make([]*EntityDiff, 0)
+	for _, k := range keys {
+		b, a := before[k], after[k]
+		switch {
+		case b == nil:
+			diffs = append(diffs, &EntityDiff{Key: k, Name: a.name, Action: ActionAdd, Changes: audit.Diff(nil, a.value)})
+		case a == nil:
+			diffs = append(diffs, &EntityDiff{Key: k, Name: b.name, Action: ActionRemove, Changes: audit.Diff(b.value, nil)})
+		default:
+			changes := audit.Diff(b.value, a.value)
+			if len(changes) > 0 {
+				diffs = append(diffs, &EntityDiff{Key: k, Name: a.name, Action: ActionModify, Changes: changes})
+			}
+		}
+	}
+	return diffs
+}
+
+func apiEntries(c *config.GokuConfig) map[string]*entry {
+	entries := make(map[string]*entry)
+	if c == nil {
+		return entries
+	}
+	for _, api := range c.APIS {
+		if api != nil {
+			entries[strconv.Itoa(api.ID)] = &entry{name: api.Name, value: audit.Normalize(api)}
+		}
+	}
+	return entries
+}
+
+// strategyEntries 策略内的接口和插件按ID、名称展开，避免顺序变化产生差异；鉴权凭证只比较摘要
+func strategyEntries(c *config.GokuConfig) map[string]*entry {
+	entries := make(map[string]*entry)
+	if c == nil {
+		return entries
+	}
+	for _, s := range c.Strategy {
+		if s == nil {
+			continue
+		}
+		auth := make(map[string]string, len(s.AUTH))
+		for name, v := range s.AUTH {
+			auth[name] = fingerprint(v)
+		}
+		apis := make(map[string]interface{}, len(s.APIS))
+		for _, api := range s.APIS {
+			if api != nil {
+				apis[strconv.Itoa(api.ID)] = map[string]interface{}{
+					"balance": api.Balance,
+					"plugins": pluginMap(api.Plugins),
+				}
+			}
+		}
+		entries[s.ID] = &entry{name: s.Name, value: audit.Normalize(map[string]interface{}{
+			"name":       s.Name,
+			"enable":     s.Enable,
+			"authPolicy": s.AuthPolicy,
+			"auth":       auth,
+			"apis":       apis,
+			"plugins":    pluginMap(s.Plugins),
+		})}
+	}
+	return entries
+}
+
+func pluginEntries(c *config.GokuConfig) map[string]*entry {
+	entries := make(map[string]*entry)
+	if c == nil {
+		return entries
+	}
+	for stage, plugins := range map[string][]*config.PluginConfig{"before": c.Plugins.BeforePlugins, "global": c.Plugins.GlobalPlugins} {
+		for _, p := range plugins {
+			if p != nil {
+				entries[stage+"/"+p.Name] = &entry{name: p.Name, value: normalizePlugin(p)}
+			}
+		}
+	}
+	return entries
+}
+
+func balanceEntries(balances map[string]map[string]*config.BalanceConfig) map[string]*entry {
+	entries := make(map[string]*entry)
+	for cluster, bs := range balances {
+		for name, b := range bs {
+			entries[cluster+"/"+name] = &entry{name: name, value: audit.Normalize(b)}
+		}
+	}
+	return entries
+}
+
+func discoverEntries(discovers map[string]map[string]*config.DiscoverConfig) map[string]*entry {
+	entries := make(map[string]*entry)
+	for cluster, ds := range discovers {
+		for name, d := range ds {
+			entries[cluster+"/"+name] = &entry{name: name, value: audit.Normalize(d)}
+		}
+	}
+	return entries
+}
+
+func pluginMap(plugins []*config.PluginConfig) map[string]interface{} {
+	m := make(map[string]interface{}, len(plugins))
+	for _, p := range plugins {
+		if p != nil {
+			m[p.Name] = normalizePlugin(p)
+		}
+	}
+	return m
+}
+
+// normalizePlugin UpdateTag随每次保存变化，不参与比较；鉴权插件配置只比较摘要
+func normalizePlugin(p *config.PluginConfig) interface{} {
+	c := *p
+	c.UpdateTag = ""
+	if secret.IsAuthPlugin(c.Name) {
+		c.Config = fingerprint(c.Config)
+	}
+	return audit.Normalize(&c)
+}
+
+func fingerprint(v string) string {
+	if v == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(v))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,63 @@
+package versionConfig
+
+import (
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestCompare(t *testing.T) {
+	from := &Snapshot{
+		Config: &config.GokuConfig{
+			APIS: []*config.APIContent{{ID: 1, Name: "a", RequestURL: "/a"}, {ID: 2, Name: "b"}},
+			Strategy: []*config.StrategyConfig{{
+				ID:   "s1",
+				APIS: []*config.APIOfStrategy{{ID: 1}, {ID: 2}},
+				Plugins: []*config.PluginConfig{
+					{Name: "p1", UpdateTag: "1"},
+				},
+			}},
+		},
+		Balance: map[string]map[string]*config.BalanceConfig{"c": {"b1": {Name: "b1", Config: "10.0.0.1"}}},
+	}
+	to := &Snapshot{
+		Config: &config.GokuConfig{
+			APIS: []*config.APIContent{{ID: 1, Name: "a", RequestURL: "/a2"}, {ID: 3, Name: "c"}},
+			Strategy: []*config.StrategyConfig{{
+				ID:   "s1",
+				APIS: []*config.APIOfStrategy{{ID: 2}, {ID: 1}},
+				Plugins: []*config.PluginConfig{
+					{Name: "p1", UpdateTag: "2"},
+				},
+			}},
+			Plugins: config.GatewayPluginConfig{GlobalPlugins: []*config.PluginConfig{{Name: "g"}}},
+		},
+		Balance: map[string]map[string]*config.BalanceConfig{"c": {"b1": {Name: "b1", Config: "10.0.0.1"}}},
+	}
+
+	d := Compare(from, to)
+	if len(d.APIs) != 3 {
+		t.Fatalf("apis: %d", len(d.APIs))
+	}
+	if d.APIs[0].Key != "1" || d.APIs[0].Action != ActionModify || len(d.APIs[0].Changes) != 1 || d.APIs[0].Changes[0].Path != "requestUrl" {
+		t.Errorf("api 1: %+v", d.APIs[0])
+	}
+	if d.APIs[1].Key != "2" || d.APIs[1].Action != ActionRemove {
+		t.Errorf("api 2: %+v", d.APIs[1])
+	}
+	if d.APIs[2].Key != "3" || d.APIs[2].Action != ActionAdd {
+		t.Errorf("api 3: %+v", d.APIs[2])
+	}
+	if len(d.Strategies) != 0 {
+		t.Errorf("strategy order or update tag should not differ: %+v", d.Strategies[0].Changes[0])
+	}
+	if len(d.Plugins) != 1 || d.Plugins[0].Key != "global/g" || d.Plugins[0].Action != ActionAdd {
+		t.Errorf("plugins: %+v", d.Plugins)
+	}
+	if len(d.Balances) != 0 || len(d.Discovery) != 0 {
+		t.Errorf("balances: %+v, discovery: %+v", d.Balances, d.Discovery)
+	}
+	if !Compare(to, to).Empty() {
+		t.Error("same snapshot should be empty")
+	}
+}
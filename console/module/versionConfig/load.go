@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
@@ -18,6 +20,8 @@ var (
 	lock          sync.RWMutex
 
 	lastConf = make(map[string]*config.GokuConfig)
+	// canaryConf 灰度中节点分组使用的配置，没有进行中的灰度发布时为nil
+	canaryConf *canaryConfig
 )
 
 func AddCallback(f ConfigChangeEventFunc) {
@@ -52,9 +56,12 @@ func call() {
 //	}
 //}
 
-////InitVersionConfig 初始化版本配置
+////InitVersionConfig 初始化版本配置，其他控制台实例发布版本时重新加载
 func InitVersionConfig() {
 	load()
+	replica.Subscribe(replica.EventConfig, func(string) {
+		load()
+	})
 }
 
 //func (c *versionConfig) GetV(cluster string) *telegraph.Telegraph {
@@ -108,9 +115,11 @@ func GetConfig(cluster string) (*config.GokuConfig, error) {
 
 }
 
-func reset(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceConfig map[string]map[string]*config.BalanceConfig, discoverConfig map[string]map[string]*config.DiscoverConfig) {
+// buildConfig 按集群生成下发到节点的配置
+func buildConfig(clusters []*entity.Cluster, versionID int, gokuConfig *config.GokuConfig, balanceConfig map[string]map[string]*config.BalanceConfig, discoverConfig map[string]map[string]*config.DiscoverConfig) map[string]*config.GokuConfig {
 	newConfig := make(map[string]*config.GokuConfig)
 	now := time.Now().Format("20060102150405")
+	strategies := openStrategies(gokuConfig.Strategy)
 	for _, cl := range clusters {
 		bf := make(map[string]*config.BalanceConfig)
 		if v, ok := balanceConfig[cl.Name]; ok {
@@ -128,31 +137,82 @@ func reset(clusters []*entity.Cluster, gokuConfig *config.GokuConfig, balanceCon
 		}
 		configByte := &config.GokuConfig{
 			Version:             now,
+			VersionID:           versionID,
 			Cluster:             cl.Name,
 			DiscoverConfig:      df,
 			Balance:             bf,
 			Plugins:             gokuConfig.Plugins,
 			APIS:                gokuConfig.APIS,
-			Strategy:            gokuConfig.Strategy,
+			Strategy:            strategies,
 			AuthPlugin:          gokuConfig.AuthPlugin,
 			AnonymousStrategyID: gokuConfig.AnonymousStrategyID,
 			Log:                 gokuConfig.Log,
-			AccessLog:           gokuConfig.AccessLog,
+			AccessLog:           accessLogConfig(gokuConfig, cl.Name),
 			MonitorModules:      gokuConfig.MonitorModules,
 			Routers:             gokuConfig.Routers,
 			GatewayBasicInfo:    gokuConfig.GatewayBasicInfo,
+			ProtoDescriptors:    gokuConfig.ProtoDescriptors,
+			RateLimits:          gokuConfig.RateLimits,
+			Scripts:             gokuConfig.Scripts,
+			SizeLimits:          gokuConfig.SizeLimits,
+			OpenAPIs:            gokuConfig.OpenAPIs,
 			ExtendsConfig: map[string]interface{}{
 				"redis": redisConfig,
 			},
 		}
 		newConfig[cl.Name] = configByte
 	}
-	lock.Lock()
+	return newConfig
+}
 
-	lastConf = newConfig
-	lock.Unlock()
-	call()
+// accessLogConfig 使用集群的access日志输出
+func accessLogConfig(gokuConfig *config.GokuConfig, cluster string) *config.AccessLogConfig {
+	outputs := gokuConfig.AccessLogOutputs[cluster]
+	if gokuConfig.AccessLog == nil || len(outputs) == 0 {
+		return gokuConfig.AccessLog
+	}
+	c := *gokuConfig.AccessLog
+	c.Outputs = outputs
+	return &c
+}
+
+// openStrategies 下发到节点前解密鉴权凭证，不修改已保存的版本配置
+func openStrategies(strategies []*config.StrategyConfig) []*config.StrategyConfig {
+	opened := make([]*config.StrategyConfig, 0, len(strategies))
+	for _, s := range strategies {
+		if s == nil {
+			continue
+		}
+		o := *s
+		if s.AUTH != nil {
+			o.AUTH = make(map[string]string, len(s.AUTH))
+			for name, c := range s.AUTH {
+				o.AUTH[name] = openAuthConfig(s.ID, name, c)
+			}
+		}
+		if s.Plugins != nil {
+			o.Plugins = make([]*config.PluginConfig, 0, len(s.Plugins))
+			for _, p := range s.Plugins {
+				if p != nil && secret.IsAuthPlugin(p.Name) {
+					pc := *p
+					pc.Config = openAuthConfig(s.ID, p.Name, p.Config)
+					p = &pc
+				}
+				o.Plugins = append(o.Plugins, p)
+			}
+		}
+		opened = append(opened, &o)
+	}
+	return opened
+}
 
+func openAuthConfig(strategyID, name, c string) string {
+	v, err := secret.OpenAuthConfig(name, c)
+	if err != nil {
+		log.Warn("open auth config of strategy ", strategyID, " error:", err)
+		return c
+	}
+	return v
 }
 
 func load() {
@@ -160,10 +220,18 @@ func load() {
 	if err != nil {
 		return
 	}
-	cf, bf, df, err := versionDao.GetVersionConfig()
+	versionID := versionDao.GetPublishVersionID()
+	cf, bf, df, err := versionDao.GetVersionConfigByID(versionID)
 	if err != nil {
 		log.Warn("load config error:", err)
 		return
 	}
-	reset(clusters, cf, bf, df)
+	newConfig := buildConfig(clusters, versionID, cf, bf, df)
+	newCanary := loadCanary(clusters)
+
+	lock.Lock()
+	lastConf = newConfig
+	canaryConf = newCanary
+	lock.Unlock()
+	call()
 }
@@ -0,0 +1,292 @@
+package versionConfig
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/conf"
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/console/module/node"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const (
+	//StatusPending 待审批
+	StatusPending = "pending"
+	//StatusApproved 已审批，等待计划发布时间
+	StatusApproved = "approved"
+	//StatusPublished 已发布
+	StatusPublished = "published"
+	//StatusRejected 已驳回
+	StatusRejected = "rejected"
+	//StatusCanceled 已撤销
+	StatusCanceled = "canceled"
+	//StatusFailed 发布失败
+	StatusFailed = "failed"
+
+	publishInterval = time.Second * 10
+	// 计划发布只在持有租约的控制台实例上执行
+	publishLeaseName = "versionPublish"
+	publishLeaseTTL  = publishInterval * 3
+	timeFormat       = "2006-01-02 15:04:05"
+)
+
+var (
+	versionPublishDao dao.VersionPublishDao
+	schedulerOnce     sync.Once
+
+	errVersionNotExist   = errors.New("[ERROR]The version does not exist")
+	errRequestNotExist   = errors.New("[ERROR]The publish request does not exist")
+	errRequestState      = errors.New("[ERROR]The publish request has been handled")
+	errApproveSelf       = errors.New("[ERROR]The publish request can not be approved by the requester")
+	errNoPreviousVersion = errors.New("[ERROR]There is no previous version to roll back to")
+	errSameVersion       = errors.New("[ERROR]The version is already published")
+)
+
+func init() {
+	pdao.Need(&versionPublishDao)
+}
+
+//NodeConfirm 节点对发布版本的确认情况
+type NodeConfirm struct {
+	NodeID    int    `json:"nodeID"`
+	NodeName  string `json:"nodeName"`
+	NodeKey   string `json:"nodeKey"`
+	Cluster   string `json:"cluster"`
+	Online    bool   `json:"online"`
+	VersionID int    `json:"versionID"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+//ApprovalRequired 发布版本是否需要审批，由配置项version_publish_approval开启
+func ApprovalRequired() bool {
+	return conf.Value("version_publish_approval") == "true"
+}
+
+//RequestPublish 申请发布版本，publishTime为计划发布时间（unix秒），0表示立即发布；
+//开启审批时生成待审批记录，未开启审批时按计划时间发布
+func RequestPublish(versionID, userID int, publishTime int64, remark string) (*entity.VersionPublish, error) {
+	if _, _, _, err := versionDao.GetVersionConfigByID(versionID); err != nil {
+		return nil, errVersionNotExist
+	}
+	now := time.Now()
+	p := &entity.VersionPublish{
+		VersionID:     versionID,
+		RequestUserID: userID,
+		PublishTime:   publishTime,
+		Remark:        remark,
+		CreateTime:    now.Format(timeFormat),
+		UpdateTime:    now.Format(timeFormat),
+	}
+	switch {
+	case ApprovalRequired():
+		p.Status = StatusPending
+	case publishTime > now.Unix():
+		p.Status = StatusApproved
+	default:
+		if err := PublishVersion(versionID, userID, p.CreateTime); err != nil {
+			return nil, err
+		}
+		p.Status = StatusPublished
+	}
+	id, err := versionPublishDao.AddVersionPublish(p)
+	if err != nil {
+		return nil, err
+	}
+	p.RequestID = id
+	return p, nil
+}
+
+//ApprovePublish 审批通过发布申请，已到计划时间的立即发布
+func ApprovePublish(requestID, userID int) error {
+	p, err := versionPublishDao.GetVersionPublish(requestID)
+	if err != nil {
+		return errRequestNotExist
+	}
+	if p.Status != StatusPending {
+		return errRequestState
+	}
+	if p.RequestUserID == userID {
+		return errApproveSelf
+	}
+	ok, err := versionPublishDao.UpdateVersionPublishStatus(requestID, StatusPending, StatusApproved, userID, time.Now().Format(timeFormat))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errRequestState
+	}
+	if p.PublishTime > time.Now().Unix() {
+		return nil
+	}
+	p.ApproveUserID = userID
+	return publishRequest(p)
+}
+
+//RejectPublish 驳回发布申请
+func RejectPublish(requestID, userID int) error {
+	ok, err := versionPublishDao.UpdateVersionPublishStatus(requestID, StatusPending, StatusRejected, userID, time.Now().Format(timeFormat))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errRequestState
+	}
+	return nil
+}
+
+//CancelPublish 撤销尚未发布的申请
+func CancelPublish(requestID int) error {
+	now := time.Now().Format(timeFormat)
+	for _, status := range []string{StatusPending, StatusApproved} {
+		ok, err := versionPublishDao.UpdateVersionPublishStatus(requestID, status, StatusCanceled, 0, now)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return errRequestState
+}
+
+//GetPublishList 获取发布记录列表
+func GetPublishList(status string, page, pageSize int) ([]*entity.VersionPublish, int, error) {
+	return versionPublishDao.GetVersionPublishList(status, page, pageSize)
+}
+
+// publishRequest 将已审批的申请置为已发布后发布版本，状态更新失败说明已由其他实例处理
+func publishRequest(p *entity.VersionPublish) error {
+	now := time.Now().Format(timeFormat)
+	ok, err := versionPublishDao.UpdateVersionPublishStatus(p.RequestID, StatusApproved, StatusPublished, 0, now)
+	if err != nil || !ok {
+		return err
+	}
+	userID := p.ApproveUserID
+	if userID == 0 {
+		userID = p.RequestUserID
+	}
+	if err = PublishVersion(p.VersionID, userID, now); err != nil {
+		if _, e := versionPublishDao.UpdateVersionPublishStatus(p.RequestID, StatusPublished, StatusFailed, 0, now); e != nil {
+			log.Warn("update publish request ", p.RequestID, " status error:", e)
+		}
+	}
+	return err
+}
+
+//StartPublishScheduler 开始定时发布已审批且到达计划时间的版本
+func StartPublishScheduler() {
+	schedulerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(publishInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if replica.Leader(publishLeaseName, publishLeaseTTL) {
+					publishDue()
+				}
+			}
+		}()
+	})
+}
+
+func publishDue() {
+	list, err := versionPublishDao.GetDueVersionPublishes(StatusApproved, time.Now().Unix())
+	if err != nil {
+		log.Warn("get due publish requests error:", err)
+		return
+	}
+	for _, p := range list {
+		if err = publishRequest(p); err != nil {
+			log.Warn("publish request ", p.RequestID, " error:", err)
+		}
+	}
+}
+
+//Rollback 回滚到指定版本，versionID为0时回滚到上一个发布的版本；
+//只有发布过的版本可以不经审批直接回滚，其他版本按发布申请处理
+func Rollback(versionID, userID int, remark string) (*entity.VersionPublish, error) {
+	current := versionDao.GetPublishVersionID()
+	if versionID == 0 {
+		id, err := versionPublishDao.GetLastPublishedVersionID(StatusPublished, current)
+		if err != nil {
+			return nil, errNoPreviousVersion
+		}
+		versionID = id
+	}
+	if versionID == current {
+		return nil, errSameVersion
+	}
+	if _, _, _, err := versionDao.GetVersionConfigByID(versionID); err != nil {
+		return nil, errVersionNotExist
+	}
+	published, err := versionPublishDao.CheckVersionPublishIsExist(versionID, StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	if !published {
+		return RequestPublish(versionID, userID, 0, remark)
+	}
+	return publishNow(versionID, userID, remark, true)
+}
+
+// publishNow 不经审批立即发布版本并记录发布记录
+func publishNow(versionID, userID int, remark string, isRollback bool) (*entity.VersionPublish, error) {
+	now := time.Now()
+	p := &entity.VersionPublish{
+		VersionID:     versionID,
+		Status:        StatusPublished,
+		IsRollback:    isRollback,
+		RequestUserID: userID,
+		PublishTime:   now.Unix(),
+		Remark:        remark,
+		CreateTime:    now.Format(timeFormat),
+		UpdateTime:    now.Format(timeFormat),
+	}
+	if err := PublishVersion(versionID, userID, p.CreateTime); err != nil {
+		return nil, err
+	}
+	id, err := versionPublishDao.AddVersionPublish(p)
+	if err != nil {
+		return nil, err
+	}
+	p.RequestID = id
+	return p, nil
+}
+
+//AffectedNodes 获取受发布版本影响的节点及其确认情况，versionID为0时使用当前发布版本
+func AffectedNodes(versionID int) ([]*NodeConfirm, error) {
+	if versionID == 0 {
+		versionID = versionDao.GetPublishVersionID()
+	}
+	nodes, err := node.GetAllNode()
+	if err != nil {
+		return nil, err
+	}
+	node.ResetNodeStatus(nodes...)
+	versions, err := versionPublishDao.GetNodeConfigVersions()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*NodeConfirm, 0, len(nodes))
+	for _, n := range nodes {
+		list = append(list, &NodeConfirm{
+			NodeID:    n.NodeID,
+			NodeName:  n.NodeName,
+			NodeKey:   n.NodeKey,
+			Cluster:   n.Cluster,
+			Online:    n.NodeStatus == 1,
+			VersionID: versions[n.NodeKey],
+			Confirmed: versions[n.NodeKey] == versionID,
+		})
+	}
+	return list, nil
+}
+
+//ConfirmNodeConfig 记录节点已应用的配置版本
+func ConfirmNodeConfig(nodeKey string, versionID int) error {
+	return versionPublishDao.SetNodeConfigVersion(nodeKey, versionID, time.Now().Format(timeFormat))
+}
@@ -0,0 +1,47 @@
+package versionConfig
+
+import (
+	"sort"
+
+	config_validator "github.com/eolinker/goku-api-gateway/node/config-validator"
+)
+
+//VersionProblem 版本配置问题，Clusters为出现该问题的集群
+type VersionProblem struct {
+	*config_validator.Problem
+	Clusters []string `json:"clusters"`
+}
+
+//ValidateVersion 按各集群生成版本配置并校验，控制台不加载插件，也不校验服务发现驱动
+func ValidateVersion(versionID int) ([]*VersionProblem, error) {
+	cf, bf, df, err := versionDao.GetVersionConfigByID(versionID)
+	if err != nil {
+		return nil, errVersionNotExist
+	}
+	clusters, err := clusterDao.GetClusters()
+	if err != nil {
+		return nil, err
+	}
+	confs := buildConfig(clusters, versionID, cf, bf, df)
+	names := make([]string, 0, len(confs))
+	for name := range confs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := make([]*VersionProblem, 0)
+	index := make(map[string]*VersionProblem)
+	for _, name := range names {
+		for _, p := range config_validator.Validate(confs[name], nil) {
+			key := p.String()
+			if vp, has := index[key]; has {
+				vp.Clusters = append(vp.Clusters, name)
+				continue
+			}
+			vp := &VersionProblem{Problem: p, Clusters: []string{name}}
+			index[key] = vp
+			problems = append(problems, vp)
+		}
+	}
+	return problems, nil
+}
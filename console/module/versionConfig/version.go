@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 
 	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/console/module/audit"
+	"github.com/eolinker/goku-api-gateway/console/module/replica"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
 
@@ -21,6 +24,7 @@ var (
 		"Apikey": "goku-apikey_auth",
 		"Basic":  "goku-basic_auth",
 		"Jwt":    "goku-jwt_auth",
+		"Hmac":   "goku-hmac_auth",
 	}
 )
 
@@ -33,10 +37,14 @@ func GetVersionList(keyword string) ([]config.VersionConfig, error) {
 	return versionDao.GetVersionList(keyword)
 }
 
-//AddVersionConfig 新增版本配置
+//AddVersionConfig 新增版本配置，版本包含的修改记录关联到该版本
 func AddVersionConfig(name, version, remark, now string, userID int) (int, error) {
 	config, balanceConfig, discoverConfig := buildVersionConfig(version)
-	return versionDao.AddVersionConfig(name, version, remark, config, balanceConfig, discoverConfig, now, userID)
+	id, err := versionDao.AddVersionConfig(name, version, remark, config, balanceConfig, discoverConfig, now, userID)
+	if err == nil {
+		audit.LinkVersion(id)
+	}
+	return id, err
 }
 func EditVersionBasicConfig(name, version, remark string, userID, versionID int) error {
 	return versionDao.EditVersionBasicConfig(name, version, remark, userID, versionID)
@@ -53,6 +61,9 @@ func PublishVersion(id, userID int, now string) error {
 	err := versionDao.PublishVersion(id, userID, now)
 	if err == nil {
 		load()
+		if e := replica.Publish(replica.EventConfig, ""); e != nil {
+			log.Warn("publish config event error:", e)
+		}
 	}
 	return err
 }
@@ -101,6 +112,12 @@ func buildVersionConfig(v string) (string, string, string) {
 	}
 
 	g, _ := versionConfigDao.GetGatewayBasicConfig()
+	protoDescriptors, _ := versionConfigDao.GetProtoDescriptors()
+	rateLimits, _ := versionConfigDao.GetRateLimits()
+	scripts, _ := versionConfigDao.GetScripts()
+	sizeLimits, _ := versionConfigDao.GetSizeLimits()
+	openAPIs, _ := versionConfigDao.GetOpenAPIs()
+	accessLogOutputs, _ := versionConfigDao.GetAccessLogOutputs()
 	routers, _ := versionConfigDao.GetRouterRules(1)
 	ms := make(map[string]string)
 	modules, _ := versionConfigDao.GetMonitorModules(1, false)
@@ -126,6 +143,12 @@ func buildVersionConfig(v string) (string, string, string) {
 		Routers:             routers,
 		GatewayBasicInfo:    g,
 		RedisConfig:         getRedisConfig(clusters),
+		ProtoDescriptors:    protoDescriptors,
+		RateLimits:          rateLimits,
+		Scripts:             scripts,
+		SizeLimits:          sizeLimits,
+		OpenAPIs:            openAPIs,
+		AccessLogOutputs:    accessLogOutputs,
 	}
 
 	cByte, err := json.Marshal(c)
@@ -3,7 +3,7 @@ package goku311
 import (
 	"github.com/eolinker/goku-api-gateway/common/database"
 	"github.com/eolinker/goku-api-gateway/console/updater"
-	updaterDao "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+	sqlite_updater "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
 )
 
 //Version 版本号
@@ -38,6 +38,7 @@ func (f *factory) Exec() error {
 func Exec() error {
 
 	db := database.GetConnection()
+	updaterDao := sqlite_updater.NewUpdaterDaoWidthDB(db)
 	existed := updaterDao.IsTableExist("goku_table_version")
 	if !existed {
 		err := createGokuTableVersion(db)
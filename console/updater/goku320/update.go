@@ -0,0 +1,44 @@
+package goku320
+
+import (
+	"github.com/eolinker/goku-api-gateway/common/database"
+	"github.com/eolinker/goku-api-gateway/console/updater"
+	sqlite_updater "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//Version 版本号
+const Version = "3.2.0"
+
+type factory struct {
+	version string
+}
+
+var updaterFactory = &factory{version: Version}
+
+func init() {
+	updater.Add(Version, updaterFactory)
+}
+
+func (f *factory) GetVersion() string {
+	return f.version
+}
+
+func (f *factory) UpdateVersion() {
+	return
+}
+func (f *factory) Exec() error {
+	return Exec()
+}
+
+//Exec 对已保存的鉴权凭证进行哈希或加密
+func Exec() error {
+	updaterDao := sqlite_updater.NewUpdaterDaoWidthDB(database.GetConnection())
+	if version := updaterDao.GetTableVersion("goku_conn_plugin_strategy"); version != Version {
+		err := updaterDao.SealAuthCredentials()
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_conn_plugin_strategy", Version)
+	}
+	return nil
+}
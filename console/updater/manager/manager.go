@@ -3,4 +3,6 @@ package manager
 import (
 	// 311版本更新器
 	_ "github.com/eolinker/goku-api-gateway/console/updater/goku311"
+	// 320版本更新器
+	_ "github.com/eolinker/goku-api-gateway/console/updater/goku320"
 )
@@ -1,6 +1,7 @@
 package updater
 
 import (
+	"github.com/eolinker/goku-api-gateway/common/database"
 	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
 )
 
@@ -38,7 +39,7 @@ func InitUpdater() error {
 	for _, u := range updateManager.updaters {
 		err := u.updater.Exec()
 		if err != nil {
-			updater.SetGokuVersion(u.version)
+			updater.NewUpdaterDaoWidthDB(database.GetConnection()).SetGokuVersion(u.version)
 			return err
 		}
 	}
@@ -1,21 +1,47 @@
 package goku_handler
 
 import (
+	"bytes"
 	"net/http"
 )
 
+// maxRecordSize 记录器可读取的最大响应长度
+const maxRecordSize = 64 * 1024
+
 //Account 账号处理器
 type Account interface {
 	CheckLogin(r *http.Request) (int, error)
 	CheckPermission(pre string, isEdit bool, userID int) (bool, error)
 }
 
+//Recorder 修改类请求的记录器
+type Recorder interface {
+	//Begin 在处理请求前调用，返回的函数在请求处理完成后以响应内容调用，响应超过64KB时只包含前64KB
+	Begin(r *http.Request, userID int) func(response []byte)
+}
+
 //AccountHandler 账号处理器
 type AccountHandler struct {
 	account    Account
 	handler    http.Handler
 	permission string
 	isEdit     bool
+	recorder   Recorder
+}
+
+type recordWriter struct {
+	http.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	if n := maxRecordSize - w.buf.Len(); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf.Write(p[:n])
+	}
+	return w.ResponseWriter.Write(p)
 }
 
 func (h *AccountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -34,12 +60,20 @@ func (h *AccountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	r = SetUserIDToRequest(r, userID)
-	h.handler.ServeHTTP(w, r)
+	if !h.isEdit || h.recorder == nil {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	end := h.recorder.Begin(r, userID)
+	rw := &recordWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(rw, r)
+	end(rw.buf.Bytes())
 }
 
 //AccountHandlerFactory 账号处理工厂
 type AccountHandlerFactory struct {
-	account Account
+	account  Account
+	recorder Recorder
 }
 
 //NewAccountHandlerFactory new AccountHandlerFactory
@@ -47,6 +81,11 @@ func NewAccountHandlerFactory(account Account) *AccountHandlerFactory {
 	return &AccountHandlerFactory{account: account}
 }
 
+//SetRecorder 设置修改类请求的记录器，只对之后创建的处理器生效
+func (f *AccountHandlerFactory) SetRecorder(recorder Recorder) {
+	f.recorder = recorder
+}
+
 //NewAccountHandler new accountHandler
 func (f *AccountHandlerFactory) NewAccountHandler(permission string, isEdit bool, handler http.Handler) http.Handler {
 	return &AccountHandler{
@@ -54,6 +93,7 @@ func (f *AccountHandlerFactory) NewAccountHandler(permission string, isEdit bool
 		handler:    handler,
 		permission: permission,
 		isEdit:     isEdit,
+		recorder:   f.recorder,
 	}
 }
 
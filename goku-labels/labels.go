@@ -12,6 +12,14 @@ const (
 	APIName = "api"
 	//ProxyName proxyName
 	ProxyName = "proxy"
+	//UpgradeConnectionsName 协议升级连接数
+	UpgradeConnectionsName = "upgrade_connections"
+	//UpgradeDurationName 协议升级连接时长
+	UpgradeDurationName = "upgrade_duration"
+	//PluginName 插件执行耗时
+	PluginName = "plugin"
+	//PluginErrorName 插件错误计数
+	PluginErrorName = "plugin_error"
 
 	API      = "api"
 	Strategy = "strategy"
@@ -20,6 +28,9 @@ const (
 	Method   = "method"
 	Host     = "host"
 	Path     = "path"
+	Upgrade  = "upgrade"
+	Plugin   = "plugin"
+	Phase    = "phase"
 )
 
 var (
@@ -28,6 +39,11 @@ var (
 	//ProxyBuckets proxyBuckets
 	ProxyBuckets = []float64{5, 25, 50, 100, 200, 400, 600, 800, 1000, 2500, 5000}
 
+	//UpgradeBuckets 协议升级连接时长分桶，单位秒
+	UpgradeBuckets = []float64{1, 5, 30, 60, 300, 900, 1800, 3600, 7200}
+	//PluginBuckets 插件执行耗时分桶，单位毫秒
+	PluginBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}
+
 	//APIDelayLabelNames apiDelayLabelNames
 	APIDelayLabelNames = []string{
 		Cluster,
@@ -48,4 +64,28 @@ var (
 		Method,
 		Status,
 	}
+	//UpgradeConnectionsLabelNames 协议升级连接数标签
+	UpgradeConnectionsLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Upgrade,
+	}
+	//UpgradeDurationLabelNames 协议升级连接时长标签
+	UpgradeDurationLabelNames = []string{
+		Cluster,
+		Instance,
+		API,
+		Strategy,
+		Upgrade,
+		Host,
+	}
+	//PluginLabelNames 插件执行耗时及错误计数标签
+	PluginLabelNames = []string{
+		Cluster,
+		Instance,
+		Plugin,
+		Phase,
+	}
 )
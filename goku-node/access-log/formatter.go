@@ -2,7 +2,9 @@ package access_log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,28 +28,34 @@ func (f *AccessLogFormatter) SetFields(fields []access_field.AccessFieldKey) {
 
 //Format 格式化
 func (f *AccessLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	var b *bytes.Buffer
-	if entry.Buffer != nil {
-		b = entry.Buffer
-	} else {
-		b = &bytes.Buffer{}
-	}
+	f.prepare(entry.Data, entry.Time)
+	return f.formatText(entry.Data), nil
+}
+
+// prepare 填充日志时间相关的域
+func (f *AccessLogFormatter) prepare(data logrus.Fields, t time.Time) {
 	timestampFormat := f.TimestampFormat
 	if timestampFormat == "" {
 		timestampFormat = DefaultTimeStampFormatter
 	}
 
-	data := entry.Data
-	data[access_field.TimeLocal] = entry.Time.Format(timestampFormat)
-	data[access_field.TimeIso8601] = entry.Time.Format(TimeIso8601Formatter)
+	data[access_field.TimeLocal] = t.Format(timestampFormat)
+	data[access_field.TimeIso8601] = t.Format(TimeIso8601Formatter)
 
-	msec := entry.Time.UnixNano() / int64(time.Millisecond)
+	msec := t.UnixNano() / int64(time.Millisecond)
 	data[access_field.Msec] = fmt.Sprintf("%d.%d", msec/1000, msec%1000)
+}
 
-	requestTIme := data[access_field.RequestTime].(time.Duration)
-	data[access_field.RequestTime] = fmt.Sprintf("%dms", requestTIme/time.Millisecond)
+func (f *AccessLogFormatter) getFields() []access_field.AccessFieldKey {
+	f.locker.RLock()
+	defer f.locker.RUnlock()
+	return f.fields
+}
 
-	for _, key := range f.fields {
+// formatText 按域的顺序以tab分隔输出，缺少的域输出为-
+func (f *AccessLogFormatter) formatText(data logrus.Fields) []byte {
+	b := &bytes.Buffer{}
+	for _, key := range f.getFields() {
 		b.WriteByte('\t')
 		if v, has := data[key.Key()]; has {
 			f.appendValue(b, v)
@@ -57,12 +65,45 @@ func (f *AccessLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	}
 	b.WriteByte('\n')
 	p := b.Bytes()
-	return p[1:], nil
+	return p[1:]
+}
+
+// formatJSON 按域的顺序输出一行json，key去掉$前缀，缺少的域不输出，$request_time为毫秒数
+func (f *AccessLogFormatter) formatJSON(data logrus.Fields) []byte {
+	b := &bytes.Buffer{}
+	b.WriteByte('{')
+	for _, key := range f.getFields() {
+		v, has := data[key.Key()]
+		if !has {
+			continue
+		}
+		if d, ok := v.(time.Duration); ok {
+			v = int64(d / time.Millisecond)
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(v))
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(strings.TrimPrefix(key.Key(), "$"))
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
 }
 
 func (f *AccessLogFormatter) appendValue(b *bytes.Buffer, value interface{}) {
-	stringVal, ok := value.(string)
-	if !ok {
+	var stringVal string
+	switch v := value.(type) {
+	case string:
+		stringVal = v
+	case time.Duration:
+		stringVal = fmt.Sprintf("%dms", v/time.Millisecond)
+	default:
 		stringVal = fmt.Sprint(value)
 	}
 
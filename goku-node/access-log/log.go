@@ -1,17 +1,25 @@
 package access_log
 
 import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/config"
 	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/access-log/output"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	logger    *logrus.Logger
 	formatter *AccessLogFormatter
-	writer    *log.FileWriterByPeriod
+	// active 当前的输出列表，Log时无锁读取
+	active atomic.Value
+	locker sync.Mutex
+	// current 按配置索引的输出，配置未变化的输出在重新设置时保留
+	current = make(map[string]*output.Output)
 )
 
 //Fields 域
@@ -19,10 +27,25 @@ type Fields = logrus.Fields
 
 //Log log
 func Log(fields Fields) {
-	if logger == nil {
+	list, _ := active.Load().([]*output.Output)
+	if len(list) == 0 || formatter == nil {
 		return
 	}
-	logger.WithFields(fields).Info()
+	formatter.prepare(fields, time.Now())
+	var text, js []byte
+	for _, o := range list {
+		if o.JSON() {
+			if js == nil {
+				js = formatter.formatJSON(fields)
+			}
+			o.Write(js)
+			continue
+		}
+		if text == nil {
+			text = formatter.formatText(fields)
+		}
+		o.Write(text)
+	}
 }
 
 //SetFields 设置access域
@@ -34,28 +57,61 @@ func SetFields(fields []access_field.AccessFieldKey) {
 	}
 }
 
-//SetOutput 设置输出
-func SetOutput(enable bool, dir, file string, period log.LogPeriod, expire int) {
+//SetOutput 设置输出，outputs为空时输出到dir下的文件
+func SetOutput(enable bool, dir, file string, period log.LogPeriod, expire int, outputs []*config.AccessLogOutput) {
+	locker.Lock()
+	defer locker.Unlock()
 
-	if enable {
-		if writer == nil {
-			writer = log.NewFileWriteBytePeriod()
+	if !enable {
+		outputs = nil
+	} else if len(outputs) == 0 {
+		outputs = []*config.AccessLogOutput{{Type: output.TypeFile}}
+	}
+	fileConfig := &output.FileConfig{Dir: dir, File: file, Period: period, Expire: expire}
+	next := make(map[string]*output.Output, len(outputs))
+	list := make([]*output.Output, 0, len(outputs))
+	for _, c := range outputs {
+		key := outputKey(c, fileConfig)
+		if _, has := next[key]; has {
+			continue
 		}
-
-		writer.Set(dir, file, period, time.Duration(expire)*time.Hour*24)
-		writer.Open()
-		if logger == nil {
-			logger = logrus.New()
-			logger.SetFormatter(formatter)
-			logger.SetOutput(writer)
-			logger.SetLevel(logrus.InfoLevel)
+		o, has := current[key]
+		if !has {
+			var err error
+			o, err = output.New(c, fileConfig)
+			if err != nil {
+				log.Warn("create access log output ", c.Type, " error:", err)
+				continue
+			}
 		}
-
-	} else {
-		if writer != nil {
-			writer.Close()
+		next[key] = o
+		list = append(list, o)
+	}
+	active.Store(list)
+	for key, o := range current {
+		if _, has := next[key]; !has {
+			go o.Close()
 		}
+	}
+	current = next
+}
 
+// outputKey 输出配置的标识，文件输出包含文件配置
+func outputKey(c *config.AccessLogOutput, file *output.FileConfig) string {
+	v := map[string]interface{}{"output": c}
+	if c.Type == output.TypeFile {
+		v["file"] = file
 	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
 
+//Stats 获取各输出的写入、丢弃统计
+func Stats() []*output.Stat {
+	list, _ := active.Load().([]*output.Output)
+	stats := make([]*output.Stat, 0, len(list))
+	for _, o := range list {
+		stats = append(stats, o.Stat())
+	}
+	return stats
 }
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"os"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+type fileSink struct {
+	writer *log.FileWriterByPeriod
+}
+
+func newFileSink(c *FileConfig) *fileSink {
+	w := log.NewFileWriteBytePeriod()
+	w.Set(c.Dir, c.File, c.Period, time.Duration(c.Expire)*time.Hour*24)
+	w.Open()
+	return &fileSink{writer: w}
+}
+
+func (s *fileSink) Write(records [][]byte) error {
+	for _, r := range records {
+		if _, err := s.writer.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.writer.Close()
+	return nil
+}
+
+type stdoutSink struct {
+	file *os.File
+}
+
+func newStdoutSink() *stdoutSink {
+	return &stdoutSink{file: os.Stdout}
+}
+
+func (s *stdoutSink) Write(records [][]byte) error {
+	buf := make([]byte, 0, len(records)*256)
+	for _, r := range records {
+		buf = append(buf, r...)
+	}
+	_, err := s.file.Write(buf)
+	return err
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
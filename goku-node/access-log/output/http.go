@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+// httpSink 每批日志以换行分隔的json（或文本）POST到指定地址
+type httpSink struct {
+	address string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPSink(c *config.AccessLogOutput) *httpSink {
+	return &httpSink{
+		address: c.Address,
+		headers: c.Headers,
+		client:  &http.Client{Timeout: writeTimeout},
+	}
+}
+
+func (s *httpSink) Write(records [][]byte) error {
+	body := bytes.Join(records, nil)
+	req, err := http.NewRequest(http.MethodPost, s.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("http status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *httpSink) Close() error {
+	return nil
+}
@@ -0,0 +1,343 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	kafkaAPIProduce  = 0
+	kafkaAPIMetadata = 3
+	kafkaClientID    = "goku-access-log"
+	// 分区信息的刷新周期，写入失败时立即刷新
+	kafkaMetadataTTL = time.Minute * 5
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaSink 最小实现的kafka生产者：Metadata v1获取分区leader，Produce v3（RecordBatch）按批轮流写入各分区，acks=1
+type kafkaSink struct {
+	brokers       []string
+	topic         string
+	leaders       map[int32]string
+	partitions    []int32
+	refreshed     time.Time
+	next          int
+	conns         map[string]net.Conn
+	correlationID int32
+}
+
+func newKafkaSink(c *config.AccessLogOutput) *kafkaSink {
+	return &kafkaSink{
+		brokers: splitAddress(c.Address),
+		topic:   c.Topic,
+		conns:   make(map[string]net.Conn),
+	}
+}
+
+func (s *kafkaSink) Write(records [][]byte) error {
+	if len(s.partitions) == 0 || time.Since(s.refreshed) > kafkaMetadataTTL {
+		if err := s.refreshMetadata(); err != nil {
+			return err
+		}
+	}
+	partition := s.partitions[s.next%len(s.partitions)]
+	s.next++
+	addr := s.leaders[partition]
+	err := s.produce(addr, partition, records)
+	if err != nil {
+		s.closeConn(addr)
+		s.partitions = nil
+	}
+	return err
+}
+
+func (s *kafkaSink) Close() error {
+	for addr := range s.conns {
+		s.closeConn(addr)
+	}
+	return nil
+}
+
+func (s *kafkaSink) closeConn(addr string) {
+	if conn, has := s.conns[addr]; has {
+		conn.Close()
+		delete(s.conns, addr)
+	}
+}
+
+func (s *kafkaSink) conn(addr string) (net.Conn, error) {
+	if conn, has := s.conns[addr]; has {
+		return conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[addr] = conn
+	return conn, nil
+}
+
+// request 发送请求并读取响应，返回去掉响应头后的内容
+func (s *kafkaSink) request(addr string, apiKey, version int16, body []byte) (*kafkaDecoder, error) {
+	conn, err := s.conn(addr)
+	if err != nil {
+		return nil, err
+	}
+	s.correlationID++
+	e := &kafkaEncoder{}
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(s.correlationID)
+	e.string(kafkaClientID)
+	e.buf.Write(body)
+
+	_ = conn.SetDeadline(time.Now().Add(writeTimeout))
+	if _, err = conn.Write(e.sized()); err != nil {
+		return nil, err
+	}
+	var size int32
+	if err = binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	if _, err = io.ReadFull(conn, data); err != nil {
+		return nil, err
+	}
+	d := &kafkaDecoder{data: data}
+	if id := d.int32(); id != s.correlationID {
+		return nil, fmt.Errorf("kafka: correlation id %d, want %d", id, s.correlationID)
+	}
+	return d, d.err
+}
+
+func (s *kafkaSink) refreshMetadata() error {
+	e := &kafkaEncoder{}
+	e.int32(1)
+	e.string(s.topic)
+	var lastErr error
+	for _, broker := range s.brokers {
+		d, err := s.request(broker, kafkaAPIMetadata, 1, e.buf.Bytes())
+		if err != nil {
+			s.closeConn(broker)
+			lastErr = err
+			continue
+		}
+		return s.readMetadata(d)
+	}
+	return lastErr
+}
+
+func (s *kafkaSink) readMetadata(d *kafkaDecoder) error {
+	brokers := make(map[int32]string)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller_id
+	leaders := make(map[int32]string)
+	partitions := make([]int32, 0)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		code := d.int16()
+		name := d.string()
+		d.int8() // is_internal
+		if code != 0 && name == s.topic {
+			return fmt.Errorf("kafka: topic %s error code %d", name, code)
+		}
+		for p := d.int32(); p > 0 && d.err == nil; p-- {
+			d.int16() // error_code
+			partition := d.int32()
+			leader := d.int32()
+			d.int32Array() // replicas
+			d.int32Array() // isr
+			if addr, has := brokers[leader]; has && name == s.topic {
+				leaders[partition] = addr
+				partitions = append(partitions, partition)
+			}
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("kafka: no available partition of topic %s", s.topic)
+	}
+	s.leaders, s.partitions, s.refreshed = leaders, partitions, time.Now()
+	return nil
+}
+
+func (s *kafkaSink) produce(addr string, partition int32, records [][]byte) error {
+	batch := encodeRecordBatch(records, time.Now())
+	e := &kafkaEncoder{}
+	e.int16(-1) // transactional_id
+	e.int16(1)  // acks
+	e.int32(int32(writeTimeout / time.Millisecond))
+	e.int32(1)
+	e.string(s.topic)
+	e.int32(1)
+	e.int32(partition)
+	e.int32(int32(len(batch)))
+	e.buf.Write(batch)
+
+	d, err := s.request(addr, kafkaAPIProduce, 3, e.buf.Bytes())
+	if err != nil {
+		return err
+	}
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		d.string()
+		for p := d.int32(); p > 0 && d.err == nil; p-- {
+			d.int32()
+			if code := d.int16(); code != 0 {
+				return fmt.Errorf("kafka: produce to partition %d error code %d", partition, code)
+			}
+			d.int64() // base_offset
+			d.int64() // log_append_time
+		}
+	}
+	return d.err
+}
+
+// encodeRecordBatch 编码magic为2的RecordBatch，记录不带key和header
+func encodeRecordBatch(records [][]byte, t time.Time) []byte {
+	timestamp := t.UnixNano() / int64(time.Millisecond)
+	body := &kafkaEncoder{}
+	body.int16(0) // attributes
+	body.int32(int32(len(records) - 1))
+	body.int64(timestamp)
+	body.int64(timestamp)
+	body.int64(-1) // producer_id
+	body.int16(-1) // producer_epoch
+	body.int32(-1) // base_sequence
+	body.int32(int32(len(records)))
+	for i, r := range records {
+		r = bytes.TrimRight(r, "\n")
+		rec := &kafkaEncoder{}
+		rec.buf.WriteByte(0) // attributes
+		rec.varint(0)        // timestamp_delta
+		rec.varint(int64(i))
+		rec.varint(-1) // key
+		rec.varint(int64(len(r)))
+		rec.buf.Write(r)
+		rec.varint(0) // headers
+		body.varint(int64(rec.buf.Len()))
+		body.buf.Write(rec.buf.Bytes())
+	}
+
+	e := &kafkaEncoder{}
+	e.int64(0) // base_offset
+	e.int32(int32(4 + 1 + 4 + body.buf.Len()))
+	e.int32(-1) // partition_leader_epoch
+	e.buf.WriteByte(2)
+	e.int32(int32(crc32.Checksum(body.buf.Bytes(), crc32c)))
+	e.buf.Write(body.buf.Bytes())
+	return e.buf.Bytes()
+}
+
+type kafkaEncoder struct {
+	buf bytes.Buffer
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	_ = binary.Write(&e.buf, binary.BigEndian, v)
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.buf.WriteString(v)
+}
+
+func (e *kafkaEncoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf.Write(b[:binary.PutVarint(b[:], v)])
+}
+
+// sized 加上4字节的长度前缀
+func (e *kafkaEncoder) sized() []byte {
+	data := make([]byte, 4+e.buf.Len())
+	binary.BigEndian.PutUint32(data, uint32(e.buf.Len()))
+	copy(data[4:], e.buf.Bytes())
+	return data
+}
+
+var errShortResponse = errors.New("kafka: short response")
+
+type kafkaDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.data) < n {
+		d.err = errShortResponse
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string 读取字符串，长度为-1的null返回空字符串
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) int32Array() {
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		d.int32()
+	}
+}
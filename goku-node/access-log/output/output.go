@@ -0,0 +1,304 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+const (
+	//TypeFile 按周期滚动的本地文件
+	TypeFile = "file"
+	//TypeStdout 标准输出
+	TypeStdout = "stdout"
+	//TypeSyslog RFC5424格式的syslog
+	TypeSyslog = "syslog"
+	//TypeKafka kafka
+	TypeKafka = "kafka"
+	//TypeHTTP 批量POST到http接口
+	TypeHTTP = "http"
+
+	//FormatText 按字段顺序以tab分隔的文本
+	FormatText = "text"
+	//FormatJSON 每条日志一行json
+	FormatJSON = "json"
+
+	defaultBufferSize = 10000
+	// 丢弃及写入失败的日志数按该间隔汇总输出
+	reportInterval = time.Minute
+)
+
+//Types 支持的输出类型
+var Types = []string{TypeFile, TypeStdout, TypeSyslog, TypeKafka, TypeHTTP}
+
+//Sink 日志的实际写入目标，由输出的协程串行调用
+type Sink interface {
+	Write(records [][]byte) error
+	Close() error
+}
+
+//FileConfig 文件输出使用的access日志文件配置
+type FileConfig struct {
+	Dir    string
+	File   string
+	Period log.LogPeriod
+	Expire int
+}
+
+//Stat 输出的统计
+type Stat struct {
+	Type      string `json:"type"`
+	Address   string `json:"address"`
+	Buffered  int    `json:"buffered"`
+	Written   uint64 `json:"written"`
+	Dropped   uint64 `json:"dropped"`
+	Failed    uint64 `json:"failed"`
+	LastError string `json:"lastError"`
+}
+
+//Output 带缓冲的日志输出，缓冲已满时丢弃新日志并计数，不阻塞请求
+type Output struct {
+	conf          *config.AccessLogOutput
+	json          bool
+	sink          Sink
+	records       chan []byte
+	batchSize     int
+	flushInterval time.Duration
+
+	written   uint64
+	dropped   uint64
+	failed    uint64
+	lastError atomic.Value
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+//Check 检查输出配置
+func Check(c *config.AccessLogOutput) error {
+	if c == nil {
+		return errors.New("empty output")
+	}
+	if c.Format != "" && c.Format != FormatText && c.Format != FormatJSON {
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+	if c.BufferSize < 0 || c.BatchSize < 0 || c.FlushInterval < 0 {
+		return errors.New("bufferSize, batchSize and flushInterval must not be negative")
+	}
+	switch c.Type {
+	case TypeFile, TypeStdout:
+		return nil
+	case TypeSyslog:
+		_, _, err := parseSyslogAddress(c.Address)
+		if err == nil && (c.Facility < 0 || c.Facility > 23) {
+			err = fmt.Errorf("invalid facility %d", c.Facility)
+		}
+		return err
+	case TypeKafka:
+		if len(splitAddress(c.Address)) == 0 {
+			return errors.New("no kafka broker")
+		}
+		if c.Topic == "" {
+			return errors.New("no kafka topic")
+		}
+		return nil
+	case TypeHTTP:
+		u, err := url.Parse(c.Address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid http address %q", c.Address)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown output type %q", c.Type)
+}
+
+func splitAddress(address string) []string {
+	list := make([]string, 0)
+	for _, a := range strings.Split(address, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+//New 创建输出，file为文件输出使用的文件配置
+func New(c *config.AccessLogOutput, file *FileConfig) (*Output, error) {
+	if err := Check(c); err != nil {
+		return nil, err
+	}
+	var sink Sink
+	batchSize, flushInterval := 100, 0
+	switch c.Type {
+	case TypeFile:
+		if file == nil {
+			return nil, errors.New("no access log file config")
+		}
+		sink = newFileSink(file)
+	case TypeStdout:
+		sink = newStdoutSink()
+	case TypeSyslog:
+		sink = newSyslogSink(c)
+	case TypeKafka:
+		sink = newKafkaSink(c)
+		batchSize, flushInterval = 500, 1000
+	case TypeHTTP:
+		sink = newHTTPSink(c)
+		batchSize, flushInterval = 500, 1000
+	}
+	return NewWithSink(c, sink, batchSize, flushInterval), nil
+}
+
+//NewWithSink 使用指定的Sink创建输出，配置中未指定批量大小和等待时间时使用传入的默认值
+func NewWithSink(c *config.AccessLogOutput, sink Sink, batchSize, flushInterval int) *Output {
+	if c.BatchSize > 0 {
+		batchSize = c.BatchSize
+	}
+	if c.FlushInterval > 0 {
+		flushInterval = c.FlushInterval
+	}
+	bufferSize := c.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultBufferSize
+	}
+	o := &Output{
+		conf:          c,
+		json:          c.Format == FormatJSON || (c.Format == "" && c.Type != TypeFile),
+		sink:          sink,
+		records:       make(chan []byte, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: time.Duration(flushInterval) * time.Millisecond,
+		done:          make(chan struct{}),
+	}
+	o.wg.Add(1)
+	go o.run()
+	return o
+}
+
+//JSON 是否以json格式输出
+func (o *Output) JSON() bool {
+	return o.json
+}
+
+//Write 写入一条已格式化的日志，缓冲已满时丢弃
+func (o *Output) Write(record []byte) {
+	select {
+	case o.records <- record:
+	default:
+		atomic.AddUint64(&o.dropped, 1)
+	}
+}
+
+//Close 写入缓冲中剩余的日志后关闭
+func (o *Output) Close() {
+	o.closeOnce.Do(func() {
+		close(o.done)
+	})
+	o.wg.Wait()
+}
+
+//Stat 获取统计
+func (o *Output) Stat() *Stat {
+	s := &Stat{
+		Type:     o.conf.Type,
+		Address:  o.conf.Address,
+		Buffered: len(o.records),
+		Written:  atomic.LoadUint64(&o.written),
+		Dropped:  atomic.LoadUint64(&o.dropped),
+		Failed:   atomic.LoadUint64(&o.failed),
+	}
+	s.LastError, _ = o.lastError.Load().(string)
+	return s
+}
+
+func (o *Output) run() {
+	defer o.wg.Done()
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	var dropped, failed uint64
+	batch := make([][]byte, 0, o.batchSize)
+	for {
+		select {
+		case record := <-o.records:
+			batch = o.collect(append(batch[:0], record))
+			o.flush(batch)
+		case <-ticker.C:
+			dropped, failed = o.report(dropped, failed)
+		case <-o.done:
+			for {
+				batch = o.drain(batch[:0])
+				if len(batch) == 0 {
+					break
+				}
+				o.flush(batch)
+			}
+			o.report(dropped, failed)
+			if err := o.sink.Close(); err != nil {
+				log.Warn("close access log output ", o.conf.Type, " error:", err)
+			}
+			return
+		}
+	}
+}
+
+// collect 凑满一批或等待超时后返回，未设置等待时间时只取缓冲中已有的日志
+func (o *Output) collect(batch [][]byte) [][]byte {
+	if o.flushInterval <= 0 {
+		return o.drain(batch)
+	}
+	timer := time.NewTimer(o.flushInterval)
+	defer timer.Stop()
+	for len(batch) < o.batchSize {
+		select {
+		case record := <-o.records:
+			batch = append(batch, record)
+		case <-timer.C:
+			return batch
+		case <-o.done:
+			return batch
+		}
+	}
+	return batch
+}
+
+func (o *Output) drain(batch [][]byte) [][]byte {
+	for len(batch) < o.batchSize {
+		select {
+		case record := <-o.records:
+			batch = append(batch, record)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+func (o *Output) flush(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := o.sink.Write(batch); err != nil {
+		atomic.AddUint64(&o.failed, uint64(len(batch)))
+		o.lastError.Store(err.Error())
+		return
+	}
+	atomic.AddUint64(&o.written, uint64(len(batch)))
+}
+
+// report 输出上次汇总后新增的丢弃及写入失败数
+func (o *Output) report(dropped, failed uint64) (uint64, uint64) {
+	d, f := atomic.LoadUint64(&o.dropped), atomic.LoadUint64(&o.failed)
+	if d > dropped || f > failed {
+		lastError, _ := o.lastError.Load().(string)
+		log.Warn("access log output ", o.conf.Type, " ", o.conf.Address, ": dropped ", d-dropped, ", failed ", f-failed, ", last error: ", lastError)
+	}
+	return d, f
+}
@@ -0,0 +1,248 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+type blockSink struct {
+	release chan struct{}
+	records int
+}
+
+func (s *blockSink) Write(records [][]byte) error {
+	<-s.release
+	s.records += len(records)
+	return nil
+}
+
+func (s *blockSink) Close() error {
+	return nil
+}
+
+func TestOutputDrop(t *testing.T) {
+	sink := &blockSink{release: make(chan struct{})}
+	o := NewWithSink(&config.AccessLogOutput{Type: TypeStdout, BufferSize: 2, BatchSize: 10}, sink, 1, 0)
+	o.Write([]byte("1\n"))
+	// 等待第一条日志被取出并阻塞在写入上
+	for i := 0; i < 100 && len(o.records) > 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	for i := 0; i < 5; i++ {
+		o.Write([]byte("n\n"))
+	}
+	if s := o.Stat(); s.Dropped != 3 || s.Buffered != 2 {
+		t.Fatalf("dropped %d buffered %d", s.Dropped, s.Buffered)
+	}
+	close(sink.release)
+	o.Close()
+	if s := o.Stat(); s.Written != 3 || sink.records != 3 {
+		t.Errorf("written %d, sink got %d", s.Written, sink.records)
+	}
+	if !o.JSON() {
+		t.Error("stdout output should default to json")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	valid := []*config.AccessLogOutput{
+		{Type: TypeFile},
+		{Type: TypeStdout, Format: FormatText},
+		{Type: TypeSyslog, Address: "10.0.0.1:514"},
+		{Type: TypeSyslog, Address: "unixgram:///dev/log"},
+		{Type: TypeKafka, Address: "k1:9092, k2:9092", Topic: "access"},
+		{Type: TypeHTTP, Address: "https://log.example.com/bulk"},
+	}
+	for _, c := range valid {
+		if err := Check(c); err != nil {
+			t.Errorf("%+v: %s", c, err)
+		}
+	}
+	invalid := []*config.AccessLogOutput{
+		nil,
+		{Type: "mongo"},
+		{Type: TypeStdout, Format: "xml"},
+		{Type: TypeStdout, BufferSize: -1},
+		{Type: TypeSyslog},
+		{Type: TypeSyslog, Address: "sctp://h:1"},
+		{Type: TypeSyslog, Address: "h:514", Facility: 24},
+		{Type: TypeKafka, Address: "k1:9092"},
+		{Type: TypeHTTP, Address: "log.example.com"},
+	}
+	for _, c := range invalid {
+		if err := Check(c); err == nil {
+			t.Errorf("%+v: no error", c)
+		}
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	s := newSyslogSink(&config.AccessLogOutput{Type: TypeSyslog, Address: "udp://" + pc.LocalAddr().String(), Tag: "gw"})
+	defer s.Close()
+	if err := s.Write([][]byte{[]byte("{\"status\":200}\n")}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(time.Second * 3))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<134>1 ") || !strings.HasSuffix(msg, " gw "+strconv.Itoa(s.pid)+" access - {\"status\":200}") {
+		t.Errorf("message %q", msg)
+	}
+
+	s.network = "tcp"
+	frame := strings.SplitN(string(s.format([]byte("x"), time.Now())), " ", 2)
+	if n, _ := strconv.Atoi(frame[0]); len(frame) != 2 || n != len(frame[1]) || !strings.HasPrefix(frame[1], "<134>1 ") {
+		t.Errorf("tcp frame %q", frame)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- r.Header.Get("X-Token") + "|" + string(body)
+	}))
+	defer server.Close()
+	s := newHTTPSink(&config.AccessLogOutput{Type: TypeHTTP, Address: server.URL, Headers: map[string]string{"X-Token": "t"}})
+	if err := s.Write([][]byte{[]byte("{\"a\":1}\n"), []byte("{\"a\":2}\n")}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-bodies; got != "t|{\"a\":1}\n{\"a\":2}\n" {
+		t.Errorf("body %q", got)
+	}
+}
+
+// fakeBroker 只处理Metadata和Produce请求的kafka broker，返回收到的记录
+func fakeBroker(t *testing.T, l net.Listener, values chan<- []string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	for {
+		var size int32
+		if binary.Read(conn, binary.BigEndian, &size) != nil {
+			return
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+		d := &kafkaDecoder{data: data}
+		apiKey, _, id := d.int16(), d.int16(), d.int32()
+		d.string()
+		resp := &kafkaEncoder{}
+		resp.int32(id)
+		switch apiKey {
+		case kafkaAPIMetadata:
+			resp.int32(1)
+			resp.int32(7)
+			resp.string(host)
+			resp.int32(int32(portNum))
+			resp.int16(-1)
+			resp.int32(7)
+			resp.int32(1)
+			resp.int16(0)
+			resp.string("access")
+			resp.buf.WriteByte(0)
+			resp.int32(1)
+			resp.int16(0)
+			resp.int32(0)
+			resp.int32(7)
+			resp.int32(0)
+			resp.int32(0)
+		case kafkaAPIProduce:
+			d.int16()
+			d.int16()
+			d.int32()
+			d.int32()
+			d.string()
+			d.int32()
+			d.int32()
+			batch := d.next(int(d.int32()))
+			values <- decodeBatch(t, batch)
+			resp.int32(1)
+			resp.string("access")
+			resp.int32(1)
+			resp.int32(0)
+			resp.int16(0)
+			resp.int64(0)
+			resp.int64(-1)
+			resp.int32(0)
+		}
+		if _, err := conn.Write(resp.sized()); err != nil {
+			return
+		}
+	}
+}
+
+func decodeBatch(t *testing.T, batch []byte) []string {
+	if len(batch) < 61 || batch[16] != 2 {
+		t.Errorf("invalid record batch")
+		return nil
+	}
+	if crc := binary.BigEndian.Uint32(batch[17:21]); crc != crc32.Checksum(batch[21:], crc32c) {
+		t.Errorf("crc mismatch")
+	}
+	if l := int(binary.BigEndian.Uint32(batch[8:12])); l != len(batch)-12 {
+		t.Errorf("batch length %d, want %d", l, len(batch)-12)
+	}
+	r := bytes.NewReader(batch[61:])
+	values := make([]string, 0)
+	for r.Len() > 0 {
+		binary.ReadVarint(r) // length
+		r.ReadByte()         // attributes
+		binary.ReadVarint(r) // timestamp_delta
+		binary.ReadVarint(r) // offset_delta
+		binary.ReadVarint(r) // key
+		n, _ := binary.ReadVarint(r)
+		v := make([]byte, n)
+		io.ReadFull(r, v)
+		binary.ReadVarint(r) // headers
+		values = append(values, string(v))
+	}
+	return values
+}
+
+func TestKafka(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer l.Close()
+	values := make(chan []string, 1)
+	go fakeBroker(t, l, values)
+
+	s := newKafkaSink(&config.AccessLogOutput{Type: TypeKafka, Address: l.Addr().String(), Topic: "access"})
+	defer s.Close()
+	if err := s.Write([][]byte{[]byte("{\"a\":1}\n"), []byte("{\"a\":2}\n")}); err != nil {
+		t.Fatal(err)
+	}
+	got := <-values
+	if len(got) != 2 || got[0] != "{\"a\":1}" || got[1] != "{\"a\":2}" {
+		t.Errorf("records %q", got)
+	}
+}
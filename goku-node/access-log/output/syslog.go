@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	defaultSyslogFacility = 16
+	syslogSeverityInfo    = 6
+	syslogTimeFormat      = "2006-01-02T15:04:05.000000Z07:00"
+	dialTimeout           = time.Second * 5
+	writeTimeout          = time.Second * 10
+)
+
+// parseSyslogAddress 解析network://address，支持udp、tcp、unix、unixgram，未指定network时使用udp
+func parseSyslogAddress(address string) (string, string, error) {
+	if address == "" {
+		return "", "", fmt.Errorf("no syslog address")
+	}
+	u, err := url.Parse(address)
+	if err != nil || u.Scheme == "" {
+		if _, _, e := net.SplitHostPort(address); e != nil {
+			return "", "", fmt.Errorf("invalid syslog address %q", address)
+		}
+		return "udp", address, nil
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid syslog address %q", address)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix", "unixgram":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("invalid syslog address %q", address)
+		}
+		return u.Scheme, u.Path, nil
+	}
+	return "", "", fmt.Errorf("unknown syslog network %q", u.Scheme)
+}
+
+// syslogSink 按RFC5424发送日志，流式连接使用RFC6587的octet-counting分帧
+type syslogSink struct {
+	network  string
+	address  string
+	priority int
+	hostname string
+	tag      string
+	pid      int
+	conn     net.Conn
+}
+
+func newSyslogSink(c *config.AccessLogOutput) *syslogSink {
+	network, address, _ := parseSyslogAddress(c.Address)
+	facility := c.Facility
+	if facility == 0 {
+		facility = defaultSyslogFacility
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	tag := c.Tag
+	if tag == "" {
+		tag = "goku"
+	}
+	return &syslogSink{
+		network:  network,
+		address:  address,
+		priority: facility*8 + syslogSeverityInfo,
+		hostname: hostname,
+		tag:      tag,
+		pid:      os.Getpid(),
+	}
+}
+
+func (s *syslogSink) stream() bool {
+	return s.network == "tcp" || s.network == "unix"
+}
+
+func (s *syslogSink) format(record []byte, t time.Time) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d access - %s", s.priority, t.Format(syslogTimeFormat), s.hostname, s.tag, s.pid, bytes.TrimRight(record, "\n"))
+	if s.stream() {
+		return []byte(strconv.Itoa(len(msg)) + " " + msg)
+	}
+	return []byte(msg)
+}
+
+func (s *syslogSink) Write(records [][]byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, dialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	now := time.Now()
+	_ = s.conn.SetWriteDeadline(now.Add(writeTimeout))
+	for _, r := range records {
+		if _, err := s.conn.Write(s.format(r, now)); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -1,6 +1,9 @@
 package common
 
 import (
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 	"strconv"
 
@@ -28,6 +31,7 @@ type Context struct {
 	requestID            string
 	finalTargetServer    string
 	retryTargetServers   string
+	hijacked             bool
 
 	RestfulParam map[string]string
 	LogFields    log.Fields
@@ -53,8 +57,30 @@ func (ctx *Context) SetRetryTargetServers(retryTargetServers string) {
 	ctx.retryTargetServers = retryTargetServers
 }
 
+//Hijack 接管客户端连接，接管后由调用方负责写响应及关闭连接
+func (ctx *Context) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := ctx.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx.hijacked = true
+	return conn, rw, nil
+}
+
+//Hijacked 客户端连接是否已被接管
+func (ctx *Context) Hijacked() bool {
+	return ctx.hijacked
+}
+
 //Finish finish
 func (ctx *Context) Finish() (n int, statusCode int) {
+	if ctx.hijacked {
+		return 0, ctx.StatusHandler.code
+	}
 
 	header := ctx.PriorityHeader.header
 
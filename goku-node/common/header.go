@@ -77,7 +77,7 @@ func (h *PriorityHeader) Append() goku_plugin.Header {
 	if h.appendHeader == nil {
 		h.appendHeader = NewHeader(nil)
 	}
-	return h.setHeader
+	return h.appendHeader
 }
 
 //NewPriorityHeader 创建PriorityHeader
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http2"
+)
+
+const (
+	//ProtoGRPC 明文grpc（h2c）
+	ProtoGRPC = "grpc"
+	//ProtoGRPCS 基于tls的grpc
+	ProtoGRPCS = "grpcs"
+)
+
+var (
+	h2cTransport = &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}
+	h2Transport         = &http2.Transport{}
+	h2InsecureTransport = &http2.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+)
+
+//IsGRPC 判断转发协议是否为grpc
+func IsGRPC(proto string) bool {
+	proto = strings.ToLower(proto)
+	return proto == ProtoGRPC || proto == ProtoGRPCS
+}
+
+// grpcTransport 根据grpc协议返回实际的url scheme及http2 transport
+func grpcTransport(scheme string) (string, http.RoundTripper) {
+	if strings.ToLower(scheme) == ProtoGRPCS {
+		if skipCertificate == 1 {
+			return "https", h2InsecureTransport
+		}
+		return "https", h2Transport
+	}
+	return "http", h2cTransport
+}
+
+// removeHopHeaders 移除http2不允许的逐跳请求头
+func removeHopHeaders(header http.Header) {
+	for _, h := range []string{"Connection", "Keep-Alive", "Proxy-Connection", "Transfer-Encoding", "Upgrade", "Accept-Encoding"} {
+		header.Del(h)
+	}
+	header.Set("Te", "trailers")
+}
@@ -41,6 +41,7 @@ type Request struct {
 	queryParams map[string][]string
 
 	timeout time.Duration
+	grpc    bool
 }
 
 //NewRequest 创建新请求
@@ -66,19 +67,24 @@ func newRequest(method string, URL *url.URL) (*Request, error) {
 	for key, values := range URL.Query() {
 		queryParams[key] = values
 	}
-	urlPath = URL.Scheme + "://" + URL.Host + URL.Path
-	tp := http.DefaultTransport
-	if skipCertificate == 1 {
+	var tp http.RoundTripper = http.DefaultTransport
+	scheme := URL.Scheme
+	isGRPC := IsGRPC(scheme)
+	if isGRPC {
+		scheme, tp = grpcTransport(scheme)
+	} else if skipCertificate == 1 {
 		tp = &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
 	}
+	urlPath = scheme + "://" + URL.Host + URL.Path
 	r := &Request{
 		client:      &http.Client{Transport: tp},
 		method:      method,
 		URL:         urlPath,
 		headers:     make(map[string][]string),
 		queryParams: queryParams,
+		grpc:        isGRPC,
 	}
 	return r, nil
 }
@@ -144,6 +150,9 @@ func (r *Request) Send(ctx goku_plugin.ContextAccess) (*http.Response, error) {
 	}()
 	req.Header.Set("Accept-Encoding", "gzip")
 	req.Header = parseHeaders(r.headers)
+	if r.grpc {
+		removeHopHeaders(req.Header)
+	}
 
 	r.client.Timeout = r.timeout
 
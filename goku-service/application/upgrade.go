@@ -0,0 +1,158 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+const defaultUpgradeTimeout = 30 * time.Second
+
+//IUpgradeApplication 支持协议升级（websocket等）的后端应用
+type IUpgradeApplication interface {
+	Upgrade(proto string, path string, querys url.Values, header http.Header, timeout time.Duration, retry int) (net.Conn, *http.Response, string, []string, error)
+}
+
+//IsUpgrade 判断是否为协议升级请求
+func IsUpgrade(header http.Header) bool {
+	if header.Get("Upgrade") == "" {
+		return false
+	}
+	for _, v := range header["Connection"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//Upgrade 选择服务实例并发起协议升级请求
+func (app *Application) Upgrade(proto string, path string, querys url.Values, header http.Header, timeout time.Duration, retry int) (net.Conn, *http.Response, string, []string, error) {
+	FinalTargetServer := ""
+	RetryTargetServers := make([]string, 0, retry+1)
+
+	var err error
+	lastIndex := -1
+	for doTrice := retry + 1; doTrice > 0; doTrice-- {
+		instance, index, has := app.service.Next(lastIndex)
+		lastIndex = index
+		if !has {
+			return nil, nil, FinalTargetServer, RetryTargetServers, fmt.Errorf("not found instance for app:%s", app.service.Name)
+		}
+
+		FinalTargetServer = instance.IP
+		if instance.Port != 0 {
+			FinalTargetServer = fmt.Sprintf("%s:%d", instance.IP, instance.Port)
+		}
+		RetryTargetServers = append(RetryTargetServers, FinalTargetServer)
+
+		var conn net.Conn
+		var response *http.Response
+		conn, response, err = dialUpgrade(proto, FinalTargetServer, path, querys, header, timeout)
+		if err != nil {
+			if app.healthCheckHandler.IsNeedCheck() {
+				app.healthCheckHandler.Check(instance)
+			}
+			continue
+		}
+		return conn, response, FinalTargetServer, RetryTargetServers, nil
+	}
+	return nil, nil, FinalTargetServer, RetryTargetServers, err
+}
+
+//Upgrade 发起协议升级请求，忽略重试
+func (app *Org) Upgrade(proto string, path string, querys url.Values, header http.Header, timeout time.Duration, retry int) (net.Conn, *http.Response, string, []string, error) {
+	conn, response, err := dialUpgrade(proto, app.server, path, querys, header, timeout)
+	return conn, response, app.server, []string{app.server}, err
+}
+
+// dialUpgrade 与后端建立连接并完成握手，后端返回101时返回已升级的连接，否则返回普通响应
+func dialUpgrade(proto string, server string, path string, querys url.Values, header http.Header, timeout time.Duration) (net.Conn, *http.Response, error) {
+	if timeout <= 0 {
+		timeout = defaultUpgradeTimeout
+	}
+	secure := false
+	switch strings.ToLower(proto) {
+	case "https", "wss":
+		secure = true
+	}
+
+	u := &url.URL{
+		Scheme:   "http",
+		Host:     server,
+		Path:     "/" + utils.TrimPrefixAll(path, "/"),
+		RawQuery: querys.Encode(),
+	}
+	if secure {
+		u.Scheme = "https"
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if secure {
+		conn, err = tls.DialWithDialer(dialer, "tcp", server, &tls.Config{InsecureSkipVerify: skipCertificate == 1})
+	} else {
+		conn, err = dialer.Dial("tcp", server)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header, len(header)),
+		Host:       server,
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Del("Content-Length")
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		response.Body = ioutil.NopCloser(bytes.NewReader(body))
+		conn.Close()
+		return nil, response, nil
+	}
+	conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, reader: reader}, response, nil
+}
+
+// bufferedConn 握手时已读入缓冲区的数据需要先于连接中的数据读出
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
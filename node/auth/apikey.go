@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const apiKeyName = "Apikey"
+
+type apiKeyConf struct {
+	APIKey         string `json:"Apikey"`
+	TokenPlace     string `json:"tokenPlace"` // header | query | body
+	HideCredential bool   `json:"hideCredential"`
+}
+
+type apiKey struct {
+	// tokenPlace -> apikey哈希 -> 配置
+	keys map[string]map[string]*apiKeyConf
+}
+
+func newAPIKey(config string) (Authenticator, error) {
+	confs := make([]*apiKeyConf, 0)
+	if strings.TrimSpace(config) != "" {
+		if err := json.Unmarshal([]byte(config), &confs); err != nil {
+			return nil, err
+		}
+	}
+	a := &apiKey{keys: make(map[string]map[string]*apiKeyConf)}
+	for _, c := range confs {
+		if c.APIKey == "" {
+			continue
+		}
+		place := strings.ToLower(c.TokenPlace)
+		if place != "query" && place != "body" {
+			place = "header"
+		}
+		if _, has := a.keys[place]; !has {
+			a.keys[place] = make(map[string]*apiKeyConf)
+		}
+		// 控制台下发的apikey已哈希，兼容旧版本的明文配置
+		key := c.APIKey
+		if !secret.IsKeyHash(key) {
+			key = secret.HashKey(key)
+		}
+		a.keys[place][key] = c
+	}
+	return a, nil
+}
+
+// token 按位置读取apikey
+func (a *apiKey) token(ctx *common.Context, place string) string {
+	r := ctx.Request()
+	switch place {
+	case "query":
+		return r.URL().Query().Get(apiKeyName)
+	case "body":
+		return r.GetForm(apiKeyName)
+	}
+	if v := r.GetHeader(apiKeyName); v != "" {
+		return v
+	}
+	if v := r.GetHeader("X-Api-Key"); v != "" {
+		return v
+	}
+	// 旧版本通过 Authorization-Type 指定鉴权方式，apikey 放在 Authorization 中
+	if v := r.GetHeader("Authorization"); v != "" && (r.GetHeader(TypeHeader) == apiKeyName || !strings.Contains(v, " ")) {
+		return v
+	}
+	return ""
+}
+
+func (a *apiKey) Detect(ctx *common.Context) bool {
+	for place := range a.keys {
+		if a.token(ctx, place) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *apiKey) Authenticate(ctx *common.Context) (string, error) {
+	found := false
+	for _, place := range []string{"header", "query", "body"} {
+		keys, has := a.keys[place]
+		if !has {
+			continue
+		}
+		token := a.token(ctx, place)
+		if token == "" {
+			continue
+		}
+		found = true
+		c, has := keys[secret.HashKey(token)]
+		if !has {
+			continue
+		}
+		if c.HideCredential {
+			a.hide(ctx, place)
+		}
+		return "apikey:" + fingerprint(token), nil
+	}
+	if !found {
+		return "", ErrorMissingCredential
+	}
+	return "", ErrorInvalidCredential
+}
+
+// hide 转发时移除鉴权信息
+func (a *apiKey) hide(ctx *common.Context, place string) {
+	switch place {
+	case "query":
+		ctx.ProxyRequest.Querys().Del(apiKeyName)
+	case "body":
+		if form, err := ctx.ProxyRequest.BodyForm(); err == nil {
+			form.Del(apiKeyName)
+			ctx.ProxyRequest.SetForm(form)
+		}
+	default:
+		ctx.ProxyRequest.DelHeader(apiKeyName)
+		ctx.ProxyRequest.DelHeader("X-Api-Key")
+		ctx.ProxyRequest.DelHeader("Authorization")
+	}
+}
+
+// fingerprint 调用方标识，避免凭证明文出现在日志及限流key中
+func fingerprint(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:8])
+}
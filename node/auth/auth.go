@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const (
+	//PolicyAny 任一鉴权方式通过即可
+	PolicyAny = "any"
+	//PolicyAll 所有鉴权方式都需要通过
+	PolicyAll = "all"
+
+	//TypeHeader 指定鉴权方式的请求头，兼容旧版本客户端
+	TypeHeader = "Authorization-Type"
+)
+
+var (
+	//ErrorMissingCredential 请求中没有可用的鉴权信息
+	ErrorMissingCredential = errors.New("missing credential")
+	//ErrorInvalidCredential 鉴权信息校验不通过
+	ErrorInvalidCredential = errors.New("invalid credential")
+)
+
+//Authenticator 鉴权器
+type Authenticator interface {
+	//Detect 请求中是否携带该方式的鉴权信息
+	Detect(ctx *common.Context) bool
+	//Authenticate 校验鉴权信息，返回调用方标识
+	Authenticate(ctx *common.Context) (string, error)
+}
+
+//Factory 根据配置创建鉴权器，配置为策略中该鉴权方式的配置
+type Factory func(config string) (Authenticator, error)
+
+var (
+	factories = make(map[string]Factory)
+	locker    sync.RWMutex
+)
+
+//Register 注册鉴权方式，name与策略中的鉴权类型（Apikey、Basic等）一致
+func Register(name string, factory Factory) {
+	locker.Lock()
+	defer locker.Unlock()
+	factories[name] = factory
+}
+
+//Has 是否存在内置的鉴权方式
+func Has(name string) bool {
+	locker.RLock()
+	defer locker.RUnlock()
+	_, has := factories[name]
+	return has
+}
+
+//Create 创建鉴权器
+func Create(name, config string) (Authenticator, error) {
+	locker.RLock()
+	factory, has := factories[name]
+	locker.RUnlock()
+	if !has {
+		return nil, fmt.Errorf("auth type %s is not supported", name)
+	}
+	return factory(config)
+}
+
+func init() {
+	Register("Apikey", newAPIKey)
+	Register("Basic", newBasic)
+	Register("Jwt", newJWT)
+	Register("Hmac", newHMAC)
+}
+
+//Reject 创建拒绝所有请求的鉴权器，鉴权方式创建失败时使用，避免跳过该鉴权方式
+func Reject(err error) Authenticator {
+	return &rejecter{err: err}
+}
+
+type rejecter struct {
+	err error
+}
+
+func (r *rejecter) Detect(ctx *common.Context) bool {
+	return true
+}
+
+func (r *rejecter) Authenticate(ctx *common.Context) (string, error) {
+	return "", r.err
+}
+
+//Chain 鉴权链，按策略对多个鉴权方式进行校验
+type Chain struct {
+	policy         string
+	names          []string
+	authenticators map[string]Authenticator
+}
+
+//NewChain 创建鉴权链
+func NewChain(policy string, authenticators map[string]Authenticator) *Chain {
+	names := make([]string, 0, len(authenticators))
+	for name := range authenticators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if policy != PolicyAll {
+		policy = PolicyAny
+	}
+	return &Chain{
+		policy:         policy,
+		names:          names,
+		authenticators: authenticators,
+	}
+}
+
+//Authenticate 校验请求，返回通过校验的鉴权方式及调用方标识
+func (c *Chain) Authenticate(ctx *common.Context) (string, string, error) {
+	if c.policy == PolicyAll {
+		return c.all(ctx)
+	}
+	return c.any(ctx)
+}
+
+func (c *Chain) any(ctx *common.Context) (string, string, error) {
+	// 指定了鉴权方式时只使用该方式校验
+	if authType := ctx.Request().GetHeader(TypeHeader); authType != "" {
+		a, has := c.authenticators[authType]
+		if !has {
+			return authType, "", errors.New("Illegal authorization type:" + authType)
+		}
+		id, err := a.Authenticate(ctx)
+		return authType, id, err
+	}
+
+	var lastErr error
+	for _, name := range c.names {
+		a := c.authenticators[name]
+		if !a.Detect(ctx) {
+			continue
+		}
+		id, err := a.Authenticate(ctx)
+		if err == nil {
+			return name, id, nil
+		}
+		lastErr = fmt.Errorf("%s:%s", name, err.Error())
+	}
+	if lastErr == nil {
+		lastErr = ErrorMissingCredential
+	}
+	return "", "", lastErr
+}
+
+func (c *Chain) all(ctx *common.Context) (string, string, error) {
+	if len(c.names) == 0 {
+		return "", "", ErrorMissingCredential
+	}
+	ids := make([]string, 0, len(c.names))
+	for _, name := range c.names {
+		id, err := c.authenticators[name].Authenticate(ctx)
+		if err != nil {
+			return name, "", fmt.Errorf("%s:%s", name, err.Error())
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(c.names, ","), strings.Join(ids, ","), nil
+}
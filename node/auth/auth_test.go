@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func newContext(method, target string, header map[string]string) *common.Context {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return common.NewContext(req, "test", httptest.NewRecorder())
+}
+
+func segment(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestBasicAndAPIKey(t *testing.T) {
+	basic, _ := Create("Basic", `[{"userName":"goku","password":"secret","hideCredential":true}]`)
+	apikey, _ := Create("Apikey", `[{"Apikey":"key1","tokenPlace":"query"}]`)
+	chain := NewChain(PolicyAny, map[string]Authenticator{"Basic": basic, "Apikey": apikey})
+
+	ctx := newContext("GET", "/", map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("goku:secret"))})
+	if name, id, err := chain.Authenticate(ctx); err != nil || name != "Basic" || id != "basic:goku" {
+		t.Fatalf("basic: %s %s %v", name, id, err)
+	}
+	if ctx.ProxyRequest.GetHeader("Authorization") != "" {
+		t.Error("expected credential hidden")
+	}
+	if _, _, err := chain.Authenticate(newContext("GET", "/?Apikey=key1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := chain.Authenticate(newContext("GET", "/?Apikey=key2", nil)); err == nil {
+		t.Fatal("expected invalid apikey")
+	}
+	if _, _, err := chain.Authenticate(newContext("GET", "/", nil)); err != ErrorMissingCredential {
+		t.Fatalf("expected missing credential, got %v", err)
+	}
+
+	all := NewChain(PolicyAll, map[string]Authenticator{"Basic": basic, "Apikey": apikey})
+	if _, _, err := all.Authenticate(newContext("GET", "/?Apikey=key1", nil)); err == nil {
+		t.Fatal("expected all policy to require basic")
+	}
+}
+
+func TestFailClosed(t *testing.T) {
+	if _, _, err := NewChain(PolicyAll, map[string]Authenticator{}).Authenticate(newContext("GET", "/", nil)); err != ErrorMissingCredential {
+		t.Fatalf("expected missing credential, got %v", err)
+	}
+	apikey, _ := Create("Apikey", `[{"Apikey":"key1","tokenPlace":"query"}]`)
+	broken := Reject(fmt.Errorf("broken"))
+	all := NewChain(PolicyAll, map[string]Authenticator{"Apikey": apikey, "Jwt": broken})
+	if _, _, err := all.Authenticate(newContext("GET", "/?Apikey=key1", nil)); err == nil {
+		t.Fatal("expected broken method to reject")
+	}
+	chain := NewChain(PolicyAny, map[string]Authenticator{"Jwt": broken})
+	if _, _, err := chain.Authenticate(newContext("GET", "/", nil)); err == nil {
+		t.Fatal("expected broken method to reject")
+	}
+}
+
+func TestHashedCredentials(t *testing.T) {
+	password, _ := secret.HashPassword("secret")
+	basic, _ := Create("Basic", fmt.Sprintf(`[{"userName":"goku","password":%q}]`, password))
+	for i := 0; i < 2; i++ {
+		ctx := newContext("GET", "/", map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("goku:secret"))})
+		if _, err := basic.Authenticate(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := newContext("GET", "/", map[string]string{"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("goku:"+password))})
+	if _, err := basic.Authenticate(ctx); err == nil {
+		t.Fatal("expected hash as password rejected")
+	}
+
+	hashed := secret.HashKey("key1")
+	apikey, _ := Create("Apikey", fmt.Sprintf(`[{"Apikey":%q,"tokenPlace":"header"}]`, hashed))
+	if _, err := apikey.Authenticate(newContext("GET", "/", map[string]string{"Apikey": "key1"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apikey.Authenticate(newContext("GET", "/", map[string]string{"Apikey": hashed})); err == nil {
+		t.Fatal("expected hash as apikey rejected")
+	}
+}
+
+func TestJWT(t *testing.T) {
+	a, err := Create("Jwt", `{"jwtCredentials":[{"iss":"goku","secret":"s3cret","algorithm":"HS256"}],"claimsToVerify":["exp"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(claims map[string]interface{}) string {
+		unsigned := segment(map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + segment(claims)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(unsigned))
+		return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	ctx := newContext("GET", "/", map[string]string{"Authorization": "Bearer " + sign(map[string]interface{}{"iss": "goku", "sub": "u1", "exp": exp})})
+	if id, err := a.Authenticate(ctx); err != nil || id != "jwt:goku:u1" {
+		t.Fatalf("hs256: %s %v", id, err)
+	}
+	ctx = newContext("GET", "/", map[string]string{"Authorization": "Bearer " + sign(map[string]interface{}{"iss": "goku", "exp": time.Now().Add(-time.Hour).Unix()})})
+	if _, err := a.Authenticate(ctx); err != ErrorTokenExpired {
+		t.Fatalf("expected expired, got %v", err)
+	}
+	ctx = newContext("GET", "/", map[string]string{"Authorization": "Bearer " + sign(map[string]interface{}{"iss": "goku"})})
+	if _, err := a.Authenticate(ctx); err == nil {
+		t.Fatal("expected exp claim required")
+	}
+}
+
+func TestJWKSFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := ioutil.TempDir("", "jwks")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "jwks.json")
+	jwks := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, _ := json.Marshal(jwks)
+	ioutil.WriteFile(file, data, 0644)
+
+	a, err := Create("Jwt", fmt.Sprintf(`{"jwks":%q}`, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := segment(map[string]string{"alg": "RS256", "kid": "k1"}) + "." + segment(map[string]interface{}{"iss": "idp", "sub": "u2"})
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	token := unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	if id, err := a.Authenticate(newContext("GET", "/?jwt="+token, nil)); err != nil || id != "jwt:idp:u2" {
+		t.Fatalf("rs256: %s %v", id, err)
+	}
+	// 使用公钥作为HS256密钥伪造的token不能通过
+	forged := segment(map[string]string{"alg": "HS256", "kid": "k1"}) + "." + segment(map[string]interface{}{"iss": "idp"})
+	if _, err := a.Authenticate(newContext("GET", "/?jwt="+forged+".AAAA", nil)); err == nil {
+		t.Fatal("expected forged token rejected")
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	a, _ := Create("Hmac", `[{"userName":"alice","secret":"hmac-secret"}]`)
+	date := time.Now().UTC().Format(http.TimeFormat)
+	signingString := "date: " + date + "\nGET /orders?id=1 HTTP/1.1"
+	mac := hmac.New(sha256.New, []byte("hmac-secret"))
+	mac.Write([]byte(signingString))
+	authorization := fmt.Sprintf(`hmac username="alice", algorithm="hmac-sha256", headers="date request-line", signature="%s"`, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	ctx := newContext("GET", "/orders?id=1", map[string]string{"Date": date, "Authorization": authorization})
+	if !a.Detect(ctx) {
+		t.Fatal("expected hmac detected")
+	}
+	if id, err := a.Authenticate(ctx); err != nil || id != "hmac:alice" {
+		t.Fatalf("hmac: %s %v", id, err)
+	}
+	ctx = newContext("GET", "/orders?id=2", map[string]string{"Date": date, "Authorization": authorization})
+	if _, err := a.Authenticate(ctx); err != ErrorSignature {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+	old := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	ctx = newContext("GET", "/orders?id=1", map[string]string{"Date": old, "Authorization": authorization})
+	if _, err := a.Authenticate(ctx); err == nil {
+		t.Fatal("expected clock skew error")
+	}
+}
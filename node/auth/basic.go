@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+// maxVerifiedCache bcrypt校验较慢，缓存校验通过的凭证摘要
+const maxVerifiedCache = 1024
+
+type basicConf struct {
+	UserName       string `json:"userName"`
+	Password       string `json:"password"`
+	HideCredential bool   `json:"hideCredential"`
+}
+
+type basic struct {
+	users map[string]*basicConf
+
+	verified map[[sha256.Size]byte]bool
+	locker   sync.RWMutex
+}
+
+func newBasic(config string) (Authenticator, error) {
+	confs := make([]*basicConf, 0)
+	if strings.TrimSpace(config) != "" {
+		if err := json.Unmarshal([]byte(config), &confs); err != nil {
+			return nil, err
+		}
+	}
+	b := &basic{
+		users:    make(map[string]*basicConf, len(confs)),
+		verified: make(map[[sha256.Size]byte]bool),
+	}
+	for _, c := range confs {
+		if c.UserName == "" {
+			continue
+		}
+		b.users[c.UserName] = c
+	}
+	return b, nil
+}
+
+func (b *basic) Detect(ctx *common.Context) bool {
+	_, ok := schemeValue(ctx.Request().GetHeader("Authorization"), "Basic")
+	return ok
+}
+
+func (b *basic) Authenticate(ctx *common.Context) (string, error) {
+	v, ok := schemeValue(ctx.Request().GetHeader("Authorization"), "Basic")
+	if !ok {
+		return "", ErrorMissingCredential
+	}
+	data, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", ErrorInvalidCredential
+	}
+	i := strings.IndexByte(string(data), ':')
+	if i < 0 {
+		return "", ErrorInvalidCredential
+	}
+	userName, password := string(data[:i]), string(data[i+1:])
+	c, has := b.users[userName]
+	if !has || !b.verify(c, password) {
+		return "", ErrorInvalidCredential
+	}
+	if c.HideCredential {
+		ctx.ProxyRequest.DelHeader("Authorization")
+	}
+	return "basic:" + userName, nil
+}
+
+// verify 校验密码，配置中的密码为bcrypt哈希时缓存校验通过的结果
+func (b *basic) verify(c *basicConf, password string) bool {
+	if !secret.IsPasswordHash(c.Password) {
+		return secret.VerifyPassword(c.Password, password)
+	}
+	key := sha256.Sum256([]byte(c.UserName + ":" + password + ":" + c.Password))
+	b.locker.RLock()
+	ok := b.verified[key]
+	b.locker.RUnlock()
+	if ok {
+		return true
+	}
+	if !secret.VerifyPassword(c.Password, password) {
+		return false
+	}
+	b.locker.Lock()
+	if len(b.verified) >= maxVerifiedCache {
+		b.verified = make(map[[sha256.Size]byte]bool)
+	}
+	b.verified[key] = true
+	b.locker.Unlock()
+	return true
+}
+
+// schemeValue 获取 Authorization 中指定scheme的值
+func schemeValue(authorization, scheme string) (string, bool) {
+	if len(authorization) <= len(scheme) || !strings.EqualFold(authorization[:len(scheme)], scheme) || authorization[len(scheme)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(authorization[len(scheme)+1:]), true
+}
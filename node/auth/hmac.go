@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/sha1" // 注册 SHA1
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+// 请求时间与网关时间允许的最大偏差
+const hmacClockSkew = 300 * time.Second
+
+var hmacAlgorithms = map[string]crypto.Hash{
+	"hmac-sha1":   crypto.SHA1,
+	"hmac-sha256": crypto.SHA256,
+	"hmac-sha384": crypto.SHA384,
+	"hmac-sha512": crypto.SHA512,
+}
+
+type hmacConf struct {
+	UserName       string `json:"userName"`
+	Secret         string `json:"secret"`
+	HideCredential bool   `json:"hideCredential"`
+}
+
+// hmacAuth HTTP签名鉴权，格式为
+// Authorization: hmac username="", algorithm="hmac-sha256", headers="date request-line", signature=""
+type hmacAuth struct {
+	users map[string]*hmacConf
+}
+
+func newHMAC(config string) (Authenticator, error) {
+	confs := make([]*hmacConf, 0)
+	if strings.TrimSpace(config) != "" {
+		if err := json.Unmarshal([]byte(config), &confs); err != nil {
+			return nil, err
+		}
+	}
+	a := &hmacAuth{users: make(map[string]*hmacConf, len(confs))}
+	for _, c := range confs {
+		if c.UserName == "" {
+			continue
+		}
+		a.users[c.UserName] = c
+	}
+	return a, nil
+}
+
+func (a *hmacAuth) Detect(ctx *common.Context) bool {
+	_, ok := schemeValue(ctx.Request().GetHeader("Authorization"), "hmac")
+	return ok
+}
+
+func (a *hmacAuth) Authenticate(ctx *common.Context) (string, error) {
+	v, ok := schemeValue(ctx.Request().GetHeader("Authorization"), "hmac")
+	if !ok {
+		return "", ErrorMissingCredential
+	}
+	params := parseSignatureParams(v)
+	c, has := a.users[params["username"]]
+	if !has {
+		return "", ErrorInvalidCredential
+	}
+	hash, has := hmacAlgorithms[strings.ToLower(params["algorithm"])]
+	if !has {
+		return "", fmt.Errorf("unsupported algorithm %s", params["algorithm"])
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return "", ErrorSignature
+	}
+
+	r := ctx.Request()
+	headers := strings.Fields(strings.ToLower(params["headers"]))
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	if err := checkDate(r.Headers(), headers, time.Now()); err != nil {
+		return "", err
+	}
+	signingString, err := buildSigningString(r.Method(), r.RequestURI(), r.Proto(), r.Headers(), headers)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(hash.New, []byte(c.Secret))
+	mac.Write([]byte(signingString))
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return "", ErrorSignature
+	}
+	if err := checkDigest(ctx, headers); err != nil {
+		return "", err
+	}
+	if c.HideCredential {
+		ctx.ProxyRequest.DelHeader("Authorization")
+	}
+	return "hmac:" + c.UserName, nil
+}
+
+// parseSignatureParams 解析 key="value" 形式的参数
+func parseSignatureParams(v string) map[string]string {
+	params := make(map[string]string)
+	for _, item := range strings.Split(v, ",") {
+		i := strings.IndexByte(item, '=')
+		if i < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(item[:i]))
+		params[key] = strings.Trim(strings.TrimSpace(item[i+1:]), `"`)
+	}
+	return params
+}
+
+// checkDate 签名必须包含 date 或 x-date，且时间在允许的偏差内
+func checkDate(header http.Header, signed []string, now time.Time) error {
+	for _, name := range signed {
+		if name != "date" && name != "x-date" {
+			continue
+		}
+		t, err := http.ParseTime(header.Get(name))
+		if err != nil {
+			return fmt.Errorf("invalid %s header", name)
+		}
+		if d := now.Sub(t); d > hmacClockSkew || d < -hmacClockSkew {
+			return errors.New("clock skew exceeded")
+		}
+		return nil
+	}
+	return errors.New("date or x-date must be signed")
+}
+
+func buildSigningString(method, requestURI, proto string, header http.Header, signed []string) (string, error) {
+	lines := make([]string, 0, len(signed))
+	for _, name := range signed {
+		switch name {
+		case "request-line":
+			lines = append(lines, fmt.Sprintf("%s %s %s", method, requestURI, proto))
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(method), requestURI))
+		default:
+			values, has := header[http.CanonicalHeaderKey(name)]
+			if !has {
+				return "", fmt.Errorf("signed header %s is missing", name)
+			}
+			lines = append(lines, name+": "+strings.Join(values, ", "))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// checkDigest 签名包含 digest 时校验请求体摘要
+func checkDigest(ctx *common.Context, signed []string) error {
+	for _, name := range signed {
+		if name != "digest" {
+			continue
+		}
+		digest := ctx.Request().GetHeader("Digest")
+		i := strings.IndexByte(digest, '=')
+		if i < 0 {
+			return errors.New("invalid digest header")
+		}
+		var hash crypto.Hash
+		switch strings.ToUpper(digest[:i]) {
+		case "SHA-256":
+			hash = crypto.SHA256
+		case "SHA-512":
+			hash = crypto.SHA512
+		default:
+			return fmt.Errorf("unsupported digest %s", digest[:i])
+		}
+		body, err := ctx.Request().RawBody()
+		if err != nil {
+			return err
+		}
+		h := hash.New()
+		h.Write(body)
+		if base64.StdEncoding.EncodeToString(h.Sum(nil)) != digest[i+1:] {
+			return errors.New("digest does not match body")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+const (
+	defaultJWKSRefresh = 5 * time.Minute
+	// 遇到未知kid时强制刷新的最小间隔
+	minJWKSReload = 10 * time.Second
+)
+
+var (
+	jwksCache  = make(map[string]*jwks)
+	jwksLocker sync.Mutex
+	jwksClient = &http.Client{Timeout: 5 * time.Second}
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// jwks 从地址或本地文件加载的公钥集合，定期刷新
+type jwks struct {
+	source  string
+	refresh time.Duration
+
+	locker   sync.Mutex
+	keys     map[string]*jwtKey
+	list     []*jwtKey
+	loadedAt time.Time
+}
+
+// getJWKS 相同来源的jwks在配置刷新后复用，避免重复加载
+func getJWKS(source string, refresh time.Duration) *jwks {
+	if refresh <= 0 {
+		refresh = defaultJWKSRefresh
+	}
+	jwksLocker.Lock()
+	defer jwksLocker.Unlock()
+	s, has := jwksCache[source]
+	if !has {
+		s = &jwks{source: source}
+		jwksCache[source] = s
+	}
+	s.locker.Lock()
+	s.refresh = refresh
+	s.locker.Unlock()
+	return s
+}
+
+//Key 按kid获取公钥，不存在时尝试重新加载
+func (s *jwks) Key(kid string) (*jwtKey, bool) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.load(false)
+	k, has := s.keys[kid]
+	if !has && s.load(true) {
+		k, has = s.keys[kid]
+	}
+	return k, has
+}
+
+//Keys 获取所有公钥
+func (s *jwks) Keys() []*jwtKey {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.load(false)
+	return s.list
+}
+
+// load 按刷新间隔加载，force为true时在最小间隔后强制加载，返回是否进行了加载
+func (s *jwks) load(force bool) bool {
+	since := time.Since(s.loadedAt)
+	if !s.loadedAt.IsZero() && since < s.refresh && (!force || since < minJWKSReload) {
+		return false
+	}
+	s.loadedAt = time.Now()
+	data, err := s.read()
+	if err != nil {
+		log.Warn("load jwks from ", s.source, " error:", err)
+		return false
+	}
+	keys, list, err := parseJWKS(data)
+	if err != nil {
+		log.Warn("parse jwks from ", s.source, " error:", err)
+		return false
+	}
+	s.keys, s.list = keys, list
+	return true
+}
+
+func (s *jwks) read() ([]byte, error) {
+	if strings.HasPrefix(s.source, "http://") || strings.HasPrefix(s.source, "https://") {
+		resp, err := jwksClient.Get(s.source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+	return ioutil.ReadFile(strings.TrimPrefix(s.source, "file://"))
+}
+
+func parseJWKS(data []byte) (map[string]*jwtKey, []*jwtKey, error) {
+	set := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, nil, err
+	}
+	keys := make(map[string]*jwtKey, len(set.Keys))
+	list := make([]*jwtKey, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn("invalid jwk ", k.Kid, ":", err)
+			continue
+		}
+		jk := &jwtKey{algorithm: k.Alg, key: key}
+		if k.Kid != "" {
+			keys[k.Kid] = jk
+		}
+		list = append(list, jk)
+	}
+	return keys, list, nil
+}
+
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(k.K, "="))
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func decodeBigInt(v string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(v, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // 注册 SHA256
+	_ "crypto/sha512" // 注册 SHA384、SHA512
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+var (
+	//ErrorTokenExpired token已过期
+	ErrorTokenExpired = errors.New("token is expired")
+	//ErrorTokenNotValidYet token未生效
+	ErrorTokenNotValidYet = errors.New("token is not valid yet")
+	//ErrorSignature 签名校验失败
+	ErrorSignature = errors.New("signature is invalid")
+)
+
+// 允许的时钟偏差
+const jwtLeeway = 30 * time.Second
+
+type jwtCredential struct {
+	ISS          string `json:"iss"`
+	Secret       string `json:"secret"`
+	RsaPublicKey string `json:"rsaPublicKey"`
+	Algorithm    string `json:"algorithm"`
+}
+
+type jwtConf struct {
+	SignatureIsBase64 bool            `json:"signatureIsBase64"`
+	ClaimsToVerify    []string        `json:"claimsToVerify"`
+	JwtCredentials    []jwtCredential `json:"jwtCredentials"`
+	HideCredentials   bool            `json:"hideCredentials"`
+	//JWKS jwks地址，支持 http(s) 地址及本地文件路径
+	JWKS string `json:"jwks,omitempty"`
+	//JWKSRefresh jwks刷新间隔，单位秒
+	JWKSRefresh int `json:"jwksRefresh,omitempty"`
+}
+
+type jwtKey struct {
+	algorithm string
+	key       interface{} // []byte | *rsa.PublicKey | *ecdsa.PublicKey
+}
+
+type jwtAuth struct {
+	claims []string
+	hide   bool
+	issuer map[string]*jwtKey
+	jwks   *jwks
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func newJWT(config string) (Authenticator, error) {
+	conf := &jwtConf{}
+	if strings.TrimSpace(config) != "" {
+		if err := json.Unmarshal([]byte(config), conf); err != nil {
+			return nil, err
+		}
+	}
+	a := &jwtAuth{
+		claims: conf.ClaimsToVerify,
+		hide:   conf.HideCredentials,
+		issuer: make(map[string]*jwtKey, len(conf.JwtCredentials)),
+	}
+	for _, c := range conf.JwtCredentials {
+		k := &jwtKey{algorithm: strings.ToUpper(c.Algorithm)}
+		if k.algorithm == "" {
+			k.algorithm = "HS256"
+		}
+		if strings.HasPrefix(k.algorithm, "HS") {
+			secret := []byte(c.Secret)
+			if conf.SignatureIsBase64 {
+				data, err := base64.StdEncoding.DecodeString(c.Secret)
+				if err != nil {
+					return nil, fmt.Errorf("invalid base64 secret of iss %s:%s", c.ISS, err.Error())
+				}
+				secret = data
+			}
+			k.key = secret
+		} else {
+			key, err := parsePublicKey(c.RsaPublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key of iss %s:%s", c.ISS, err.Error())
+			}
+			k.key = key
+		}
+		a.issuer[c.ISS] = k
+	}
+	if conf.JWKS != "" {
+		a.jwks = getJWKS(conf.JWKS, time.Duration(conf.JWKSRefresh)*time.Second)
+	}
+	return a, nil
+}
+
+func (a *jwtAuth) token(ctx *common.Context) string {
+	r := ctx.Request()
+	authorization := r.GetHeader("Authorization")
+	if v, ok := schemeValue(authorization, "Bearer"); ok {
+		return v
+	}
+	if authorization != "" && r.GetHeader(TypeHeader) == "Jwt" {
+		return authorization
+	}
+	return r.URL().Query().Get("jwt")
+}
+
+func (a *jwtAuth) Detect(ctx *common.Context) bool {
+	return strings.Count(a.token(ctx), ".") == 2
+}
+
+func (a *jwtAuth) Authenticate(ctx *common.Context) (string, error) {
+	token := a.token(ctx)
+	if token == "" {
+		return "", ErrorMissingCredential
+	}
+	claims, err := a.verify(token, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if a.hide {
+		ctx.ProxyRequest.DelHeader("Authorization")
+		ctx.ProxyRequest.Querys().Del("jwt")
+	}
+	return fmt.Sprintf("jwt:%v:%v", claims["iss"], claims["sub"]), nil
+}
+
+// verify 校验签名及声明，返回payload
+func (a *jwtAuth) verify(token string, now time.Time) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrorInvalidCredential
+	}
+	header := &jwtHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, err
+	}
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return nil, ErrorSignature
+	}
+
+	keys, err := a.keys(header, claims)
+	if err != nil {
+		return nil, err
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, k := range keys {
+		if k.algorithm != "" && k.algorithm != header.Alg {
+			continue
+		}
+		if verifySignature(header.Alg, k.key, signed, signature) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrorSignature
+	}
+	if err := verifyClaims(claims, a.claims, now); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// keys 获取候选密钥：优先按 kid 从jwks中查找，其次按 iss 查找
+func (a *jwtAuth) keys(header *jwtHeader, claims map[string]interface{}) ([]*jwtKey, error) {
+	if a.jwks != nil && header.Kid != "" {
+		if k, has := a.jwks.Key(header.Kid); has {
+			return []*jwtKey{k}, nil
+		}
+	}
+	if iss, ok := claims["iss"].(string); ok {
+		if k, has := a.issuer[iss]; has {
+			return []*jwtKey{k}, nil
+		}
+	}
+	if a.jwks != nil && header.Kid == "" {
+		return a.jwks.Keys(), nil
+	}
+	return nil, errors.New("no key found for token")
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+	if err != nil {
+		return ErrorInvalidCredential
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return ErrorInvalidCredential
+	}
+	return nil
+}
+
+func verifyClaims(claims map[string]interface{}, required []string, now time.Time) error {
+	for _, name := range required {
+		if _, has := claims[name]; !has {
+			return fmt.Errorf("claim %s is required", name)
+		}
+	}
+	if exp, has := numericClaim(claims, "exp"); has && now.Add(-jwtLeeway).Unix() >= exp {
+		return ErrorTokenExpired
+	}
+	if nbf, has := numericClaim(claims, "nbf"); has && now.Add(jwtLeeway).Unix() < nbf {
+		return ErrorTokenNotValidYet
+	}
+	return nil
+}
+
+func numericClaim(claims map[string]interface{}, name string) (int64, bool) {
+	v, has := claims[name]
+	if !has {
+		return 0, false
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	if i, err := n.Int64(); err == nil {
+		return i, true
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func hashOf(alg string) (crypto.Hash, error) {
+	if len(alg) != 5 {
+		return 0, fmt.Errorf("unsupported algorithm %s", alg)
+	}
+	switch alg[2:] {
+	case "256":
+		return crypto.SHA256, nil
+	case "384":
+		return crypto.SHA384, nil
+	case "512":
+		return crypto.SHA512, nil
+	}
+	return 0, fmt.Errorf("unsupported algorithm %s", alg)
+}
+
+func verifySignature(alg string, key interface{}, signed, signature []byte) error {
+	hash, err := hashOf(alg)
+	if err != nil {
+		return err
+	}
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrorSignature
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrorSignature
+		}
+		return nil
+	case "RS", "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrorSignature
+		}
+		h := hash.New()
+		h.Write(signed)
+		if alg[0] == 'R' {
+			return rsa.VerifyPKCS1v15(pub, hash, h.Sum(nil), signature)
+		}
+		return rsa.VerifyPSS(pub, hash, h.Sum(nil), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrorSignature
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrorSignature
+		}
+		h := hash.New()
+		h.Write(signed)
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, h.Sum(nil), r, s) {
+			return ErrorSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %s", alg)
+}
+
+// parsePublicKey 解析PEM格式的公钥，支持 PKIX、PKCS1 公钥及证书
+func parsePublicKey(data string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid pem")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return cert.PublicKey, nil
+}
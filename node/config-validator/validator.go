@@ -0,0 +1,309 @@
+package config_validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/access-log/output"
+	"github.com/eolinker/goku-api-gateway/module/httprouter"
+	"github.com/eolinker/goku-api-gateway/node/auth"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/rewrite"
+)
+
+//Problem 配置问题，Location为问题在配置中的位置，如strategy[s1].api[12].step[0].path
+type Problem struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (p *Problem) String() string {
+	return p.Location + ": " + p.Message
+}
+
+//Options 校验选项
+type Options struct {
+	//Drivers 可用的服务发现驱动，为nil时不校验驱动
+	Drivers []string
+	//LoadPlugin 加载插件，为nil时不校验插件能否加载
+	LoadPlugin func(name string) error
+}
+
+type validator struct {
+	cfg      *config.GokuConfig
+	opts     *Options
+	apis     map[int]*config.APIContent
+	problems []*Problem
+}
+
+//Validate 校验节点配置，返回发现的全部问题
+func Validate(cfg *config.GokuConfig, opts *Options) []*Problem {
+	if opts == nil {
+		opts = &Options{}
+	}
+	v := &validator{
+		cfg:      cfg,
+		opts:     opts,
+		apis:     make(map[int]*config.APIContent),
+		problems: make([]*Problem, 0),
+	}
+	if cfg == nil {
+		return v.problems
+	}
+	v.checkDiscovery()
+	v.checkBalances()
+	v.checkAccessLog()
+	v.checkPlugins("plugin.before", cfg.Plugins.BeforePlugins)
+	v.checkPlugins("plugin.global", cfg.Plugins.GlobalPlugins)
+	for _, api := range cfg.APIS {
+		if api == nil {
+			continue
+		}
+		v.apis[api.ID] = api
+		v.checkAPI(api)
+	}
+	for _, s := range cfg.Strategy {
+		if s != nil {
+			v.checkStrategy(s)
+		}
+	}
+	return v.problems
+}
+
+func (v *validator) add(location, format string, args ...interface{}) {
+	v.problems = append(v.problems, &Problem{Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *validator) checkDiscovery() {
+	if v.opts.Drivers == nil {
+		return
+	}
+	drivers := make(map[string]bool, len(v.opts.Drivers))
+	for _, d := range v.opts.Drivers {
+		drivers[d] = true
+	}
+	names := make(map[string]bool, len(v.cfg.DiscoverConfig))
+	for name := range v.cfg.DiscoverConfig {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		d := v.cfg.DiscoverConfig[name]
+		if d != nil && !drivers[d.Driver] {
+			v.add("discovery["+name+"].driver", "unknown discovery driver %q", d.Driver)
+		}
+	}
+}
+
+func (v *validator) checkBalances() {
+	names := make(map[string]bool, len(v.cfg.Balance))
+	for name := range v.cfg.Balance {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		b := v.cfg.Balance[name]
+		if b == nil || b.DiscoverName == "" {
+			continue
+		}
+		if _, has := v.cfg.DiscoverConfig[b.DiscoverName]; !has {
+			v.add("balance["+name+"].discover", "unknown discovery %q", b.DiscoverName)
+		}
+	}
+}
+
+func (v *validator) checkAccessLog() {
+	if v.cfg.AccessLog == nil {
+		return
+	}
+	for i, o := range v.cfg.AccessLog.Outputs {
+		if err := output.Check(o); err != nil {
+			v.add("accessLog.output["+strconv.Itoa(i)+"]", "%s", err)
+		}
+	}
+}
+
+// isAddress 未配置为负载的目标按后端地址直接转发
+func isAddress(target string) bool {
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return true
+	}
+	return target == "localhost" || net.ParseIP(target) != nil || strings.Contains(target, ".")
+}
+
+func (v *validator) checkBalance(location, name string) {
+	if name == "" {
+		v.add(location, "balance is empty")
+		return
+	}
+	if _, has := v.cfg.Balance[name]; has {
+		return
+	}
+	if n, err := url.QueryUnescape(name); err == nil {
+		if _, has := v.cfg.Balance[n]; has {
+			return
+		}
+	}
+	if !isAddress(name) {
+		v.add(location, "unknown balance %q", name)
+	}
+}
+
+func (v *validator) checkAPI(api *config.APIContent) {
+	location := "api[" + strconv.Itoa(api.ID) + "]"
+	if !strings.HasPrefix(api.RequestURL, "/") {
+		v.add(location+".requestUrl", "path %q must begin with '/'", api.RequestURL)
+	}
+	if len(api.Methods) == 0 {
+		v.add(location+".methods", "no method")
+	}
+	for i, step := range api.Steps {
+		if step == nil {
+			continue
+		}
+		stepLocation := location + ".step[" + strconv.Itoa(i) + "]"
+		if _, err := interpreter.ParsePath(step.Path); err != nil {
+			v.add(stepLocation+".path", "invalid path template %q: %s", step.Path, err)
+		}
+		if step.Encode != "origin" && strings.TrimSpace(step.Body) != "" {
+			if _, err := interpreter.Parse(strings.TrimSpace(step.Body)); err != nil {
+				v.add(stepLocation+".body", "invalid body template: %s", err)
+			}
+		}
+		if _, err := rewrite.New(step.Rewrite); err != nil {
+			v.add(stepLocation+".rewrite", "invalid rewrite: %s", err)
+		}
+	}
+}
+
+// checkRoute 按节点的路由规则注册接口，检查非法路径及重复、冲突的路由
+func (v *validator) checkRoute(r *httprouter.Router, location, method, path string) {
+	defer func() {
+		if e := recover(); e != nil {
+			v.add(location, "route %s %s: %v", method, path, e)
+		}
+	}()
+	r.Handle(method, path, func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+}
+
+func (v *validator) checkStrategy(s *config.StrategyConfig) {
+	location := "strategy[" + s.ID + "]"
+	v.checkPlugins(location+".plugin", s.Plugins)
+	if s.AuthPolicy != "" && s.AuthPolicy != auth.PolicyAny && s.AuthPolicy != auth.PolicyAll {
+		v.add(location+".authPolicy", "unknown auth policy %q", s.AuthPolicy)
+	}
+	names := make(map[string]bool, len(s.AUTH))
+	for name := range s.AUTH {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		authLocation := location + ".auth[" + name + "]"
+		if auth.Has(name) {
+			if _, err := auth.Create(name, s.AUTH[name]); err != nil {
+				v.add(authLocation, "invalid auth config: %s", err)
+			}
+			continue
+		}
+		pluginName, has := v.cfg.AuthPlugin[name]
+		if !has {
+			v.add(authLocation, "no auth plugin for %q", name)
+			continue
+		}
+		v.loadPlugin(authLocation, pluginName)
+	}
+	if !s.Enable {
+		return
+	}
+	r := httprouter.New()
+	for _, a := range s.APIS {
+		if a == nil {
+			continue
+		}
+		apiLocation := location + ".api[" + strconv.Itoa(a.ID) + "]"
+		api, has := v.apis[a.ID]
+		if !has {
+			v.add(apiLocation, "api does not exist")
+			continue
+		}
+		v.checkPlugins(apiLocation+".plugin", a.Plugins)
+		if len(api.Steps) == 1 {
+			balance := api.Steps[0].Balance
+			if a.Balance != "" {
+				balance = a.Balance
+			}
+			v.checkBalance(apiLocation+".balance", balance)
+		} else {
+			for i, step := range api.Steps {
+				if step != nil {
+					v.checkBalance(apiLocation+".step["+strconv.Itoa(i)+"].balance", step.Balance)
+				}
+			}
+		}
+		if !strings.HasPrefix(api.RequestURL, "/") {
+			continue
+		}
+		for _, method := range api.Methods {
+			v.checkRoute(r, apiLocation+".route", strings.ToUpper(method), api.RequestURL)
+		}
+	}
+}
+
+func (v *validator) loadPlugin(location, name string) {
+	if v.opts.LoadPlugin == nil {
+		return
+	}
+	if err := v.opts.LoadPlugin(name); err != nil {
+		v.add(location, "load plugin %s: %s", name, err)
+	}
+}
+
+func (v *validator) checkPlugins(location string, plugins []*config.PluginConfig) {
+	for _, p := range plugins {
+		if p == nil {
+			continue
+		}
+		pluginLocation := location + "[" + p.Name + "]"
+		if !config.IsPluginErrorPolicy(p.ErrorPolicy) {
+			v.add(pluginLocation+".errorPolicy", "unknown error policy %q", p.ErrorPolicy)
+		}
+		if strings.TrimSpace(p.Config) != "" && !json.Valid([]byte(p.Config)) {
+			v.add(pluginLocation+".config", "config is not valid json")
+			continue
+		}
+		if config.IsScriptPlugin(p.Name) {
+			v.checkScripts(pluginLocation, p)
+			continue
+		}
+		v.loadPlugin(pluginLocation, p.Name)
+	}
+}
+
+func (v *validator) checkScripts(location string, p *config.PluginConfig) {
+	if strings.TrimSpace(p.Config) == "" {
+		return
+	}
+	sc := new(config.ScriptPluginConfig)
+	if err := json.Unmarshal([]byte(p.Config), sc); err != nil {
+		v.add(location+".config", "invalid script plugin config: %s", err)
+		return
+	}
+	for _, name := range sc.Scripts {
+		if _, has := v.cfg.Scripts[name]; !has {
+			v.add(location+".scripts", "unknown script %q", name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config_validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := &config.GokuConfig{
+		APIS: []*config.APIContent{
+			{ID: 1, RequestURL: "/a", Methods: []string{"GET"}, Steps: []*config.APIStepConfig{{Balance: "b1", Path: "/a"}}},
+			{ID: 2, RequestURL: "/a", Methods: []string{"get"}, Steps: []*config.APIStepConfig{{Balance: "missing", Path: "/b"}}},
+			{ID: 3, RequestURL: "c", Methods: []string{"GET"}, Steps: []*config.APIStepConfig{{Balance: "10.0.0.1:80", Path: "/c", Rewrite: &config.RewriteConfig{Regex: "("}}}},
+		},
+		Strategy: []*config.StrategyConfig{{
+			ID:      "s1",
+			Enable:  true,
+			APIS:    []*config.APIOfStrategy{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+			AUTH:    map[string]string{"Oauth2": "{}"},
+			Plugins: []*config.PluginConfig{{Name: "p1", Config: "{bad"}, {Name: config.ScriptPluginStrategy, Config: `{"scripts":["nope"]}`}},
+		}},
+		Balance: map[string]*config.BalanceConfig{
+			"b1": {Name: "b1", DiscoverName: "d1"},
+			"b2": {Name: "b2", DiscoverName: "d2"},
+		},
+		DiscoverConfig: map[string]*config.DiscoverConfig{"d1": {Name: "d1", Driver: "kubernetes"}},
+		AccessLog:      &config.AccessLogConfig{Outputs: []*config.AccessLogOutput{{Type: "stdout"}, {Type: "kafka", Address: "k1:9092"}}},
+	}
+	problems := Validate(cfg, &Options{Drivers: []string{"static"}})
+	got := make([]string, 0, len(problems))
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"discovery[d1].driver",
+		"balance[b2].discover",
+		"accessLog.output[1]",
+		"api[3].requestUrl",
+		"api[3].step[0].rewrite",
+		"strategy[s1].plugin[p1].config",
+		"strategy[s1].plugin[goku-script].scripts",
+		"strategy[s1].auth[Oauth2]",
+		"strategy[s1].api[2].balance",
+		"strategy[s1].api[2].route",
+		"strategy[s1].api[4]",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d problems:\n%s", len(got), strings.Join(got, "\n"))
+	}
+	for i, location := range want {
+		if problems[i].Location != location {
+			t.Errorf("problem %d: %s, want location %s", i, got[i], location)
+		}
+	}
+	if p := Validate(&config.GokuConfig{}, nil); len(p) != 0 {
+		t.Errorf("empty config: %v", p)
+	}
+}
@@ -15,3 +15,13 @@ type ConfigConsole interface {
 
 //ConfigCallbackFunc configCallbackFunc
 type ConfigCallbackFunc func(conf *config.GokuConfig)
+
+//PluginErrorReporter 向控制台上报插件加载错误
+type PluginErrorReporter interface {
+	SendPluginErrors(errs map[string]string) error
+}
+
+//TrafficReporter 向控制台上报流量统计
+type TrafficReporter interface {
+	SendTraffic(report *config.TrafficReport) error
+}
@@ -0,0 +1,153 @@
+package file_console
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/listener"
+	"github.com/eolinker/goku-api-gateway/common/manager"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+	config_validator "github.com/eolinker/goku-api-gateway/node/config-validator"
+	"github.com/eolinker/goku-api-gateway/node/console"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+)
+
+// watchInterval 检查配置文件变化的间隔
+const watchInterval = time.Second * 3
+
+//Console 从本地配置文件加载配置，文件变化或收到SIGHUP时重新加载
+type Console struct {
+	path       string
+	listener   *listener.Listener
+	lastConfig *manager.Value
+	signature  string
+
+	lock       sync.Mutex
+	done       chan struct{}
+	closeOnce  sync.Once
+	listenOnce sync.Once
+}
+
+//NewConsole 创建文件配置源，path为配置文件或配置片段目录
+func NewConsole(path string) *Console {
+	return &Console{
+		path:       path,
+		listener:   listener.New(),
+		lastConfig: manager.NewValue(),
+		done:       make(chan struct{}),
+	}
+}
+
+//Close 停止监听
+func (c *Console) Close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+//AddListen 添加配置变化回调
+func (c *Console) AddListen(callback console.ConfigCallbackFunc) {
+	c.listener.Listen(func(event interface{}) {
+		callback(event.(*config.GokuConfig))
+	})
+}
+
+//GetConfig 获取当前生效的配置
+func (c *Console) GetConfig() (*config.GokuConfig, error) {
+	conf, has := c.lastConfig.Get()
+	if has {
+		return conf.(*config.GokuConfig), nil
+	}
+	return nil, errors.New("config not loaded")
+}
+
+//RegisterToConsole 读取初始配置，与重新加载使用相同的校验，校验不通过时节点不启动
+func (c *Console) RegisterToConsole() (*config.GokuConfig, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.signature, _ = signature(c.path)
+	conf, err := Load(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(conf); err != nil {
+		return nil, err
+	}
+	c.lastConfig.Set(conf)
+	return conf, nil
+}
+
+//Listen 定时检查配置文件变化，并在收到SIGHUP时重新加载
+func (c *Console) Listen() {
+	c.listenOnce.Do(func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			defer signal.Stop(hup)
+			ticker := time.NewTicker(watchInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-c.done:
+					return
+				case <-hup:
+					log.Info("received SIGHUP, reload config from ", c.path)
+					c.reload(true)
+				case <-ticker.C:
+					c.reload(false)
+				}
+			}
+		}()
+	})
+}
+
+// reload 重新加载配置，读取或校验失败时保留原配置；force为false时仅在文件变化后加载
+func (c *Console) reload(force bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	sign, err := signature(c.path)
+	if err != nil {
+		log.Warn("check config ", c.path, " error:", err)
+		return
+	}
+	if !force && sign == c.signature {
+		return
+	}
+	c.signature = sign
+	conf, err := Load(c.path)
+	if err != nil {
+		log.Error("reload config error, keep the current config:", err)
+		return
+	}
+	if err := validate(conf); err != nil {
+		log.Error("reload config rejected: ", err, ", keep the current config")
+		return
+	}
+	c.lastConfig.Set(conf)
+	c.listener.Call(conf)
+	log.Info("config reloaded from ", c.path)
+}
+
+// validate 校验配置并记录全部问题，存在问题时返回错误
+func validate(conf *config.GokuConfig) error {
+	problems := config_validator.Validate(conf, &config_validator.Options{Drivers: discovery.AllDrivers(), LoadPlugin: loadPlugin})
+	if len(problems) == 0 {
+		return nil
+	}
+	for _, p := range problems {
+		log.Error("config problem ", p.String())
+	}
+	return fmt.Errorf("config has %d problems", len(problems))
+}
+
+func loadPlugin(name string) error {
+	_, err := plugin_loader.LoadPlugin(name)
+	return err
+}
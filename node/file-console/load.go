@@ -0,0 +1,144 @@
+package file_console
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"gopkg.in/yaml.v2"
+)
+
+var extensions = map[string]bool{".json": true, ".yaml": true, ".yml": true}
+
+// files 返回配置文件列表，path为目录时按文件名顺序返回目录下的json、yaml文件
+func files(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, len(infos))
+	for _, f := range infos {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || !extensions[strings.ToLower(filepath.Ext(f.Name()))] {
+			continue
+		}
+		list = append(list, filepath.Join(path, f.Name()))
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// signature 记录配置文件的名称、大小和修改时间，用于判断文件是否变化
+func signature(path string) (string, error) {
+	list, err := files(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range list {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", f, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+//Load 读取配置，path可为单个json、yaml文件，或包含多个配置片段的目录；
+//片段按文件名顺序合并：对象逐个字段合并，数组依次追加，其余值以后读取的为准
+func Load(path string) (*config.GokuConfig, error) {
+	list, err := files(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no config file in %s", path)
+	}
+	var merged interface{}
+	for _, f := range list {
+		v, err := readFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f, err)
+		}
+		merged = merge(merged, v)
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	c := &config.GokuConfig{}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func readFile(file string) (interface{}, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		err = json.Unmarshal(data, &v)
+		return v, err
+	}
+	if err = yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return normalize(v), nil
+}
+
+func merge(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return s
+		}
+		for k, v := range s {
+			d[k] = merge(d[k], v)
+		}
+		return d
+	case []interface{}:
+		if d, ok := dst.([]interface{}); ok {
+			return append(d, s...)
+		}
+		return s
+	case nil:
+		return dst
+	default:
+		return s
+	}
+}
+
+// normalize 将yaml解析得到的map[interface{}]interface{}转换为map[string]interface{}
+func normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalize(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,71 @@
+package file_console
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFragments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fragments := map[string]string{
+		"00-base.json": `{"cluster":"default","bind":":6689","apis":[{"id":1,"requestUrl":"/a"}]}`,
+		"10-apis.yaml": "bind: \":7000\"\napis:\n  - id: 2\n    requestUrl: /b\nbalance:\n  b1:\n    name: b1\n",
+		"README.md":    "not a config",
+	}
+	for name, content := range fragments {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Cluster != "default" || c.BindAddress != ":7000" {
+		t.Errorf("cluster %q bind %q", c.Cluster, c.BindAddress)
+	}
+	if len(c.APIS) != 2 || c.APIS[0].ID != 1 || c.APIS[1].RequestURL != "/b" {
+		t.Errorf("apis not merged: %+v", c.APIS)
+	}
+	if b, has := c.Balance["b1"]; !has || b.Name != "b1" {
+		t.Errorf("balance not loaded: %+v", c.Balance)
+	}
+
+	before, _ := signature(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "20-bad.yml"), []byte("apis: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := signature(dir); after == before {
+		t.Error("signature not changed after adding a fragment")
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("invalid fragment loaded")
+	}
+}
+
+func TestRegisterToConsole(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goku-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "goku.json")
+	if err := ioutil.WriteFile(file, []byte(`{"cluster":"default","apis":[{"id":1,"requestUrl":"a","methods":["GET"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewConsole(file).RegisterToConsole(); err == nil {
+		t.Error("invalid config should be rejected at startup")
+	}
+	if err := ioutil.WriteFile(file, []byte(`{"cluster":"default","apis":[{"id":1,"requestUrl":"/a","methods":["GET"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewConsole(file).RegisterToConsole(); err != nil {
+		t.Error(err)
+	}
+}
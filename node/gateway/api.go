@@ -4,8 +4,13 @@ import (
 	"fmt"
 
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	goku_application "github.com/eolinker/goku-api-gateway/goku-service/application"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
+	rate_limit "github.com/eolinker/goku-api-gateway/node/rate-limit"
+	request_validator "github.com/eolinker/goku-api-gateway/node/request-validator"
+	response_cache "github.com/eolinker/goku-api-gateway/node/response-cache"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 )
 
@@ -13,15 +18,17 @@ import (
 type API struct {
 	strategyID string
 	//api *config.APIContent
-	app                application.Application
-	pluginAccess       []plugin_executor.Executor
-	pluginAccessGlobal []plugin_executor.Executor
-
-	pluginProxies       []plugin_executor.Executor
-	pluginProxiesGlobal []plugin_executor.Executor
+	app           application.Application
+	pluginAccess  plugin_executor.Pipeline
+	pluginProxies plugin_executor.Pipeline
 
 	apiID   int
 	apiName string
+
+	sizeLimit *size_limit.Limit
+	limiter   *rate_limit.Limiter
+	validator *request_validator.Validator
+	cache     *response_cache.Cache
 }
 
 //Router router
@@ -30,61 +37,29 @@ func (h *API) Router(ctx *common.Context) {
 	ctx.SetAPIID(h.apiID)
 	ctx.LogFields[access_field.API] = fmt.Sprintf("\"%d %s\"", h.apiID, h.apiName)
 
-	isAccess := h.accessFlow(ctx)
-	h.accessGlobalFlow(ctx)
-	if !isAccess {
+	if !h.sizeLimit.Check(ctx) {
 		return
 	}
 
-	h.app.Execute(ctx)
-
-	isproxy := h.proxyFlow(ctx)
-	h.proxyGlobalFlow(ctx)
-
-	if !isproxy {
+	if !h.limiter.Check(ctx) {
 		return
 	}
 
-	return
-}
-
-func (h *API) accessFlow(ctx *common.Context) bool {
-	for _, handler := range h.pluginAccess {
-		flag, err := handler.Execute(ctx)
-		if err != nil {
-			fmt.Println(err)
-		}
-		if flag == false && handler.IsStop() {
-
-			return false
-		}
+	if !h.validator.Check(ctx) {
+		return
 	}
-	return true
-}
 
-func (h *API) accessGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
-	for _, handler := range h.pluginAccessGlobal {
-		_, _ = handler.Execute(ctx)
+	if !h.pluginAccess.Execute(ctx) {
+		return
 	}
-}
-
-func (h *API) proxyFlow(ctx *common.Context) bool {
-	for _, handler := range h.pluginProxies {
-		flag, _ := handler.Execute(ctx)
 
-		if flag == false && handler.IsStop() {
-
-			return false
-		}
+	if upgradeApp, ok := h.app.(application.UpgradeApplication); ok && goku_application.IsUpgrade(ctx.ProxyRequest.Headers()) {
+		// 协议升级后连接由后端接管，不再执行转发后插件
+		upgradeApp.Upgrade(ctx)
+		return
 	}
-	return true
-}
 
-func (h *API) proxyGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
-	for _, handler := range h.pluginProxiesGlobal {
-		_, _ = handler.Execute(ctx)
+	h.cache.Execute(ctx, h.app)
 
-	}
+	h.pluginProxies.Execute(ctx)
 }
@@ -1,9 +1,12 @@
 package action
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/expression"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
 )
 
@@ -18,6 +21,16 @@ const (
 	Black = "black"
 	//White white
 	White = "white"
+	//Set set
+	Set = "set"
+	//Default default
+	Default = "default"
+	//Convert convert
+	Convert = "convert"
+	//Map map
+	Map = "map"
+	//ArrayFilterType filter
+	ArrayFilterType = "filter"
 )
 
 //Filter 过滤器
@@ -37,21 +50,95 @@ func (f Filters) Do(value *response.Response) {
 
 }
 
-//GenByconfig 通过配置生成Filter
+//GenByconfig 通过配置生成Filter，配置无效时返回nil
 func GenByconfig(ac *config.ActionConfig) Filter {
+	f, err := Gen(ac)
+	if err != nil {
+		log.Warn("invalid action config:", err)
+		return nil
+	}
+	return f
+}
+
+//Gen 通过配置生成Filter，未知的action类型返回nil
+func Gen(ac *config.ActionConfig) (Filter, error) {
+	f, err := gen(ac)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	if ac.Condition == "" || strings.ToLower(ac.ActionType) == ArrayFilterType {
+		return f, nil
+	}
+	condition, err := expression.Compile(ac.Condition)
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionFilter{
+		condition: condition,
+		filter:    f,
+	}, nil
+}
+
+func gen(ac *config.ActionConfig) (Filter, error) {
 	switch strings.ToLower(ac.ActionType) {
 	case Delete:
-		return DeleteFilter(ac.Original)
+		return DeleteFilter(ac.Original), nil
 	case Rename:
 		return &RenameFilter{
 			pattern: ac.Original,
 			name:    ac.Target,
-		}
+		}, nil
 	case Move:
 		return &MoveFilter{
 			target: ac.Target,
 			source: ac.Original,
+		}, nil
+	case Set:
+		expr, err := expression.Compile(ac.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return &SetFilter{
+			target: ac.Target,
+			expr:   expr,
+		}, nil
+	case Default:
+		expr, err := expression.Compile(ac.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return &DefaultFilter{
+			target: ac.Original,
+			expr:   expr,
+		}, nil
+	case Convert:
+		switch strings.ToLower(ac.ValueType) {
+		case expression.TypeString, expression.TypeNumber, expression.TypeInteger, expression.TypeBoolean, expression.TypeArray, expression.TypeObject, "int", "bool":
+		default:
+			return nil, fmt.Errorf("invalid value type [%s] for convert", ac.ValueType)
+		}
+		return &ConvertFilter{
+			pattern:   ac.Original,
+			valueType: ac.ValueType,
+		}, nil
+	case Map:
+		expr, err := expression.Compile(ac.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return &MapFilter{
+			pattern: ac.Original,
+			expr:    expr,
+		}, nil
+	case ArrayFilterType:
+		condition, err := expression.Compile(ac.Condition)
+		if err != nil {
+			return nil, err
 		}
+		return &ArrayFilter{
+			pattern:   ac.Original,
+			condition: condition,
+		}, nil
 	}
-	return nil
+	return nil, nil
 }
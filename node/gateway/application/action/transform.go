@@ -0,0 +1,105 @@
+package action
+
+import (
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/expression"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+)
+
+//SetFilter 将表达式的计算结果写入目标字段
+type SetFilter struct {
+	target string
+	expr   *expression.Expression
+}
+
+//Do do
+func (f *SetFilter) Do(value *response.Response) {
+	v := f.expr.Search(value.Data)
+	value.SetValue(f.target, v)
+}
+
+//DefaultFilter 字段不存在或为null时写入默认值
+type DefaultFilter struct {
+	target string
+	expr   *expression.Expression
+}
+
+//Do do
+func (f *DefaultFilter) Do(value *response.Response) {
+	v := f.expr.Search(value.Data)
+	if v == nil {
+		return
+	}
+	value.SetDefault(f.target, v)
+}
+
+//ConvertFilter 类型转换
+type ConvertFilter struct {
+	pattern   string
+	valueType string
+}
+
+//Do do
+func (f *ConvertFilter) Do(value *response.Response) {
+	value.Transform(f.pattern, func(v interface{}) interface{} {
+		return expression.Convert(v, f.valueType)
+	})
+}
+
+//MapFilter 对数组的每一项求值，并以结果替换该项，表达式中 @ 为当前项，$ 为根节点
+type MapFilter struct {
+	pattern string
+	expr    *expression.Expression
+}
+
+//Do do
+func (f *MapFilter) Do(value *response.Response) {
+	root := value.Data
+	value.Transform(f.pattern, func(v interface{}) interface{} {
+		list, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		result := make([]interface{}, len(list))
+		for i, item := range list {
+			result[i] = f.expr.SearchIn(root, item)
+		}
+		return result
+	})
+}
+
+//ArrayFilter 仅保留数组中条件为真的项
+type ArrayFilter struct {
+	pattern   string
+	condition *expression.Expression
+}
+
+//Do do
+func (f *ArrayFilter) Do(value *response.Response) {
+	root := value.Data
+	value.Transform(f.pattern, func(v interface{}) interface{} {
+		list, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		result := make([]interface{}, 0, len(list))
+		for _, item := range list {
+			if expression.Truthy(f.condition.SearchIn(root, item)) {
+				result = append(result, item)
+			}
+		}
+		return result
+	})
+}
+
+//ConditionFilter 条件为真时才执行内部的Filter
+type ConditionFilter struct {
+	condition *expression.Expression
+	filter    Filter
+}
+
+//Do do
+func (f *ConditionFilter) Do(value *response.Response) {
+	if expression.Truthy(f.condition.Search(value.Data)) {
+		f.filter.Do(value)
+	}
+}
@@ -0,0 +1,66 @@
+package action
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+)
+
+func TestTransform(t *testing.T) {
+	payload := `{
+		"user": {"first": "Li", "last": "Lei", "age": "18", "vip": "true"},
+		"orders": [
+			{"id": 1, "price": 12.5, "count": 2, "status": "paid"},
+			{"id": 2, "price": 3, "count": 1, "status": "cancel"}
+		]
+	}`
+	configs := []*config.ActionConfig{
+		{ActionType: Set, Target: "user.name", Expression: "concat(user.first, ' ', user.last)"},
+		{ActionType: Convert, Original: "user.age", ValueType: "integer"},
+		{ActionType: Convert, Original: "user.vip", ValueType: "boolean"},
+		{ActionType: Default, Original: "user.level", Expression: "`1`"},
+		{ActionType: Default, Original: "user.first", Expression: "'ignored'"},
+		{ActionType: ArrayFilterType, Original: "orders", Condition: "status != 'cancel'"},
+		{ActionType: Map, Original: "orders", Expression: "{id: id, total: price * count}"},
+		{ActionType: Set, Target: "total", Expression: "sum(orders[*].total)"},
+		{ActionType: Delete, Original: "user.first", Condition: "user.vip"},
+		{ActionType: Delete, Original: "user.last", Condition: "!user.vip"},
+	}
+
+	filters := make(Filters, 0, len(configs))
+	for _, c := range configs {
+		f, err := Gen(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filters = append(filters, f)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		t.Fatal(err)
+	}
+	r := &response.Response{Data: data}
+	filters.Do(r)
+
+	got, _ := json.Marshal(r.Data)
+	want := `{"orders":[{"id":1,"total":25}],"total":25,"user":{"age":18,"last":"Lei","level":1,"name":"Li Lei","vip":true}}`
+	if string(got) != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestGenInvalid(t *testing.T) {
+	invalid := []*config.ActionConfig{
+		{ActionType: Set, Target: "a", Expression: "a["},
+		{ActionType: Convert, Original: "a", ValueType: "date"},
+		{ActionType: Delete, Original: "a", Condition: "=="},
+	}
+	for _, c := range invalid {
+		if _, err := Gen(c); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
@@ -8,3 +8,8 @@ import (
 type Application interface {
 	Execute(ctx *common.Context)
 }
+
+//UpgradeApplication 支持协议升级（websocket等）的应用
+type UpgradeApplication interface {
+	Upgrade(ctx *common.Context)
+}
@@ -0,0 +1,252 @@
+package backend
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	proto_codec "github.com/eolinker/goku-api-gateway/common/proto-codec"
+	"github.com/eolinker/goku-api-gateway/goku-service/application"
+)
+
+//GRPCTranscoder http/json 与 grpc 方法之间的转换
+type GRPCTranscoder struct {
+	Method string
+}
+
+//GRPCStatusError grpc调用返回的非0状态
+type GRPCStatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *GRPCStatusError) Error() string {
+	return fmt.Sprintf("grpc status %d:%s", e.Code, e.Message)
+}
+
+func newGRPCTranscoder(proto, method string) *GRPCTranscoder {
+	if !application.IsGRPC(proto) || method == "" {
+		return nil
+	}
+	if !strings.HasPrefix(method, "/") {
+		method = "/" + method
+	}
+	return &GRPCTranscoder{
+		Method: method,
+	}
+}
+
+func (t *GRPCTranscoder) method() (*proto_codec.Registry, *proto_codec.Method, error) {
+	registry := proto_codec.Default()
+	m, has := registry.Method(t.Method)
+	if !has {
+		return nil, nil, fmt.Errorf("grpc method %s not found in proto descriptors", t.Method)
+	}
+	if m.ClientStreaming {
+		return nil, nil, fmt.Errorf("grpc method %s is client streaming, which can not be transcoded", t.Method)
+	}
+	return registry, m, nil
+}
+
+//Request 将json请求体、query参数及restful参数合并为grpc请求消息，返回请求路径、请求体及请求头
+func (t *GRPCTranscoder) Request(body []byte, query url.Values, restful map[string]string, header http.Header) (string, []byte, http.Header, error) {
+	registry, m, err := t.method()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	values := make(map[string]interface{})
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &values); err != nil {
+			return "", nil, nil, fmt.Errorf("invalid json body:%s", err.Error())
+		}
+	}
+	for k, vs := range query {
+		if len(vs) == 1 {
+			setField(values, k, vs[0], false)
+			continue
+		}
+		list := make([]interface{}, 0, len(vs))
+		for _, v := range vs {
+			list = append(list, v)
+		}
+		setField(values, k, list, false)
+	}
+	for k, v := range restful {
+		setField(values, k, v, true)
+	}
+
+	data, err := registry.Encode(m.Input, values)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	h := make(http.Header, len(header))
+	for k, vs := range header {
+		h[k] = vs
+	}
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/grpc")
+
+	return m.FullPath(), proto_codec.EncodeFrame(data), h, nil
+}
+
+//Response 将grpc响应转换为通用结构，server streaming方法返回消息数组
+func (t *GRPCTranscoder) Response(header, trailer http.Header, body []byte) (http.Header, interface{}, error) {
+	h := make(http.Header, len(header))
+	for k, vs := range header {
+		if strings.HasPrefix(k, "Grpc-") || k == "Trailer" || k == "Content-Type" || k == "Content-Length" {
+			continue
+		}
+		h[k] = vs
+	}
+	h.Set("Content-Type", "application/json")
+
+	if code, msg, has := grpcStatus(header, trailer); has && code != 0 {
+		return h, nil, &GRPCStatusError{Code: code, Message: msg}
+	}
+
+	registry, m, err := t.method()
+	if err != nil {
+		return h, nil, err
+	}
+	frames, err := proto_codec.DecodeFrames(body)
+	if err != nil {
+		return h, nil, err
+	}
+	messages := make([]interface{}, 0, len(frames))
+	for _, frame := range frames {
+		msg, err := registry.Decode(m.Output, frame)
+		if err != nil {
+			return h, nil, err
+		}
+		messages = append(messages, msg)
+	}
+	if m.ServerStreaming {
+		return h, messages, nil
+	}
+	if len(messages) == 0 {
+		return h, map[string]interface{}{}, nil
+	}
+	return h, messages[0], nil
+}
+
+// grpcStatus 获取grpc状态，Trailers-Only响应的状态在响应头中
+func grpcStatus(header, trailer http.Header) (int, string, bool) {
+	status := trailer.Get("Grpc-Status")
+	msg := trailer.Get("Grpc-Message")
+	if status == "" {
+		status = header.Get("Grpc-Status")
+		msg = header.Get("Grpc-Message")
+	}
+	if status == "" {
+		return 0, "", false
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return 2, status, true
+	}
+	if m, err := url.PathUnescape(msg); err == nil {
+		msg = m
+	}
+	return code, msg, true
+}
+
+// setField 按 a.b.c 形式设置字段，override为false时不覆盖json请求体中已存在的字段
+func setField(values map[string]interface{}, key string, value interface{}, override bool) {
+	keys := strings.Split(key, ".")
+	node := values
+	for _, k := range keys[:len(keys)-1] {
+		child, ok := node[k].(map[string]interface{})
+		if !ok {
+			if _, has := node[k]; has && !override {
+				return
+			}
+			child = make(map[string]interface{})
+			node[k] = child
+		}
+		node = child
+	}
+	last := keys[len(keys)-1]
+	if _, has := node[last]; !has || override {
+		node[last] = value
+	}
+}
+
+// appendTrailer 将原生grpc响应的trailer写入响应头，由http服务在响应结束时以trailer发送
+func appendTrailer(header, trailer http.Header) {
+	for k, vs := range trailer {
+		for _, v := range vs {
+			header.Add(http.TrailerPrefix+k, v)
+		}
+	}
+}
+
+// grpcUnimplemented grpc状态码UNIMPLEMENTED
+const grpcUnimplemented = 12
+
+// checkUnary 直连grpc时请求及响应均完整缓存后转发，只支持一元调用：
+// proto描述中声明为流式的方法，以及请求中包含多个消息的调用直接拒绝，避免流式调用一直等待
+func checkUnary(path string, body []byte) error {
+	if m, has := proto_codec.Default().Method(path); has && (m.ClientStreaming || m.ServerStreaming) {
+		return fmt.Errorf("grpc method %s is streaming, only unary calls are supported by the gateway", path)
+	}
+	if n := grpcMessageCount(body); n > 1 {
+		return fmt.Errorf("grpc request of %s contains %d messages, only unary calls are supported by the gateway", path, n)
+	}
+	return nil
+}
+
+// grpcMessageCount 统计grpc消息体中长度前缀消息的数量，不解压消息
+func grpcMessageCount(data []byte) int {
+	count := 0
+	for len(data) >= 5 {
+		size := int(binary.BigEndian.Uint32(data[1:5]))
+		if len(data)-5 < size {
+			break
+		}
+		count++
+		data = data[5+size:]
+	}
+	return count
+}
+
+// grpcStatusResponse 直连grpc时以Trailers-Only形式返回grpc错误状态
+func grpcStatusResponse(code int, message string) *BackendResponse {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/grpc")
+	header.Set("Grpc-Status", strconv.Itoa(code))
+	header.Set("Grpc-Message", message)
+	return &BackendResponse{
+		Header:     header,
+		StatusCode: http.StatusOK,
+		Status:     strconv.Itoa(http.StatusOK),
+		BodyOrg:    []byte{},
+	}
+}
+
+// grpcErrorResponse 生成转码失败或grpc返回错误状态时的json响应
+func grpcErrorResponse(statusCode int, err error) *BackendResponse {
+	data := map[string]interface{}{
+		"message": err.Error(),
+	}
+	if e, ok := err.(*GRPCStatusError); ok {
+		statusCode = proto_codec.HTTPStatus(e.Code)
+		data["code"] = e.Code
+		data["message"] = e.Message
+	}
+	body, _ := json.Marshal(data)
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return &BackendResponse{
+		Header:     header,
+		StatusCode: statusCode,
+		Status:     strconv.Itoa(statusCode),
+		Body:       data,
+		BodyOrg:    body,
+	}
+}
@@ -3,17 +3,20 @@ package backend
 import (
 	"compress/gzip"
 	"context"
-	"io/ioutil"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/action"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/rewrite"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 )
 
 //Layer layer
@@ -23,10 +26,12 @@ type Layer struct {
 	HasBalance  bool
 	Protocol    string
 
-	Filter action.Filter
-	Method string
-	Path   interpreter.Interpreter
-	Decode response.DecodeHandle
+	Filter  action.Filter
+	Method  string
+	Path    interpreter.Interpreter
+	Rewrite *rewrite.Rewriter
+	Decode  response.DecodeHandle
+	GRPC    *GRPCTranscoder
 
 	Body    interpreter.Interpreter
 	Encode  string
@@ -38,11 +43,20 @@ type Layer struct {
 
 //Send send
 func (b *Layer) Send(deadline context.Context, ctx *common.Context, variables *interpreter.Variables) (*BackendResponse, error) {
-	path := b.Path.Execution(variables)
-	body := b.Body.Execution(variables)
+	path := b.Rewrite.Path(b.Path.Execution(variables))
+	query := b.Rewrite.Query(ctx.ProxyRequest.Querys())
+	body := []byte(b.Body.Execution(variables))
 	method := b.Method
+	header := ctx.ProxyRequest.Headers()
+	if b.GRPC != nil {
+		grpcPath, grpcBody, grpcHeader, err := b.GRPC.Request(body, query, variables.Restful, header)
+		if err != nil {
+			return nil, err
+		}
+		method, path, query, header, body = http.MethodPost, grpcPath, nil, grpcHeader, grpcBody
+	}
 
-	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, ctx.ProxyRequest.Querys(), ctx.ProxyRequest.Headers(), []byte(body), b.TimeOut, b.Retry)
+	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, query, header, body, b.TimeOut, b.Retry)
 
 	if err != nil {
 		return nil, err
@@ -64,15 +78,29 @@ func (b *Layer) Send(deadline context.Context, ctx *common.Context, variables *i
 		r.Header.Del("Content-Encoding")
 	}
 
-	backendResponse.BodyOrg, err = ioutil.ReadAll(bd)
+	backendResponse.BodyOrg, err = size_limit.ReadResponse(ctx, bd)
+	if err == size_limit.ErrorResponseTooLarge {
+		return nil, err
+	}
 	if err != nil {
 		return backendResponse, nil
 	}
 
-	rp, e := response.Decode(backendResponse.BodyOrg, b.Decode)
-	if e != nil {
-		backendResponse.Body = nil
-		return nil, e
+	var rp *response.Response
+	if b.GRPC != nil {
+		h, data, e := b.GRPC.Response(r.Header, r.Trailer, backendResponse.BodyOrg)
+		if e != nil {
+			return nil, e
+		}
+		backendResponse.Header = h
+		rp = &response.Response{Data: data}
+	} else {
+		var e error
+		rp, e = response.Decode(backendResponse.BodyOrg, b.Decode)
+		if e != nil {
+			backendResponse.Body = nil
+			return nil, e
+		}
 	}
 
 	b.Filter.Do(rp)
@@ -105,6 +133,7 @@ func NewLayer(step *config.APIStepConfig) *Layer {
 		TimeOut:     time.Duration(step.TimeOut) * time.Millisecond,
 		Body:        interpreter.Gen(step.Body, step.Encode),
 		Retry:       step.Retry,
+		GRPC:        newGRPCTranscoder(step.Proto, step.GRPCMethod),
 	}
 	if step.Group != "" {
 		b.Group = strings.Split(step.Group, ".")
@@ -112,5 +141,11 @@ func NewLayer(step *config.APIStepConfig) *Layer {
 
 	b.Balance, b.HasBalance = balance.GetByName(b.BalanceName)
 
+	rw, err := rewrite.New(step.Rewrite)
+	if err != nil {
+		log.Warn("invalid rewrite config:", err)
+	}
+	b.Rewrite = rw
+
 	return b
 }
@@ -2,11 +2,15 @@ package backend
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
 	"github.com/eolinker/goku-api-gateway/goku-service/application"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
@@ -14,7 +18,9 @@ import (
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/rewrite"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 )
 
 //Proxy proxy
@@ -31,6 +37,8 @@ type Proxy struct {
 	Decode  response.DecodeHandle
 
 	RequestPath string
+	Rewrite     *rewrite.Rewriter
+	GRPC        *GRPCTranscoder
 
 	Retry   int
 	TimeOut time.Duration
@@ -49,21 +57,22 @@ func NewProxyBackendTarget(step *config.APIStepConfig, requestPath string, balan
 
 		TimeOut: time.Duration(step.TimeOut) * time.Millisecond,
 		Retry:   step.Retry,
+		GRPC:    newGRPCTranscoder(step.Proto, step.GRPCMethod),
 	}
 
 	b.Balance, b.HasBalance = balance.GetByName(balanceTarget)
 
+	rw, err := rewrite.New(step.Rewrite)
+	if err != nil {
+		log.Warn("invalid rewrite config:", err)
+	}
+	b.Rewrite = rw
+
 	return b
 }
 
-//Send send
-func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*BackendResponse, error) {
-
-	if !b.HasBalance {
-		err := fmt.Errorf("get balance error:%s", b.BalanceName)
-		return nil, err
-	}
-
+// target 计算转发路径及query参数
+func (b *Proxy) target(ctx *common.Context, variables *interpreter.Variables) (string, url.Values) {
 	path := b.Path.Execution(variables)
 
 	// 不是restful时，将匹配路由之后对url拼接到path之后
@@ -76,12 +85,41 @@ func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*Ba
 			path = fmt.Sprint(path, "/", lessPath)
 		}
 	}
+	return b.Rewrite.Path(path), b.Rewrite.Query(ctx.ProxyRequest.Querys())
+}
+
+//Send send
+func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*BackendResponse, error) {
+
+	if !b.HasBalance {
+		err := fmt.Errorf("get balance error:%s", b.BalanceName)
+		return nil, err
+	}
+
+	path, query := b.target(ctx, variables)
 
 	method := b.Method
 	if method == "FOLLOW" {
 		method = ctx.ProxyRequest.Method
 	}
-	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, ctx.ProxyRequest.Querys(), ctx.ProxyRequest.Headers(), variables.Org, b.TimeOut, b.Retry)
+	header := ctx.ProxyRequest.Headers()
+	body := variables.Org
+	if b.GRPC != nil {
+		grpcPath, grpcBody, grpcHeader, err := b.GRPC.Request(body, query, variables.Restful, header)
+		if err != nil {
+			res := grpcErrorResponse(http.StatusBadRequest, err)
+			res.Method, res.Protocol, res.TargetURL = method, b.Protocol, path
+			return res, nil
+		}
+		method, path, query, header, body = http.MethodPost, grpcPath, nil, grpcHeader, grpcBody
+	} else if application.IsGRPC(b.Protocol) {
+		if err := checkUnary(path, body); err != nil {
+			res := grpcStatusResponse(grpcUnimplemented, err.Error())
+			res.Method, res.Protocol, res.TargetURL = method, b.Protocol, path
+			return res, nil
+		}
+	}
+	r, finalTargetServer, retryTargetServers, err := b.Balance.Send(ctx, b.Protocol, method, path, query, header, body, b.TimeOut, b.Retry)
 
 	backendResponse := &BackendResponse{
 		Method:     method,
@@ -106,11 +144,32 @@ func (b *Proxy) Send(ctx *common.Context, variables *interpreter.Variables) (*Ba
 		bd, _ = gzip.NewReader(r.Body)
 		r.Header.Del("Content-Encoding")
 	}
-	backendResponse.BodyOrg, err = ioutil.ReadAll(bd)
+	backendResponse.BodyOrg, err = size_limit.ReadResponse(ctx, bd)
+	if err == size_limit.ErrorResponseTooLarge {
+		return nil, err
+	}
 	if err != nil {
 		return backendResponse, nil
 	}
 
+	if b.GRPC != nil {
+		h, data, e := b.GRPC.Response(r.Header, r.Trailer, backendResponse.BodyOrg)
+		if e != nil {
+			res := grpcErrorResponse(http.StatusBadGateway, e)
+			backendResponse.Header, backendResponse.StatusCode, backendResponse.Status = h, res.StatusCode, res.Status
+			backendResponse.Body, backendResponse.BodyOrg = res.Body, res.BodyOrg
+			return backendResponse, nil
+		}
+		backendResponse.Header, backendResponse.Body = h, data
+		backendResponse.BodyOrg, _ = json.Marshal(data)
+		return backendResponse, nil
+	}
+	if application.IsGRPC(b.Protocol) {
+		appendTrailer(backendResponse.Header, r.Trailer)
+		backendResponse.StatusCode, backendResponse.Status = r.StatusCode, strconv.Itoa(r.StatusCode)
+		return backendResponse, nil
+	}
+
 	if b.Decode != nil {
 		rp, e := response.Decode(backendResponse.BodyOrg, b.Decode)
 		if e != nil {
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/goku-service/application"
+	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+var (
+	upgradeConnections = make(map[string]int)
+	upgradeLocker      sync.Mutex
+)
+
+//Upgrade 转发协议升级（websocket等）请求，后端返回101后接管客户端连接并双向转发数据，直到任意一端关闭
+func (b *Proxy) Upgrade(ctx *common.Context, variables *interpreter.Variables) (*BackendResponse, error) {
+	if !b.HasBalance {
+		return nil, fmt.Errorf("get balance error:%s", b.BalanceName)
+	}
+	upgrader, ok := b.Balance.(application.IUpgradeApplication)
+	if !ok {
+		return nil, fmt.Errorf("balance %s does not support upgrade", b.BalanceName)
+	}
+
+	path, query := b.target(ctx, variables)
+	conn, r, finalTargetServer, retryTargetServers, err := upgrader.Upgrade(b.Protocol, path, query, ctx.ProxyRequest.Headers(), b.TimeOut, b.Retry)
+
+	backendResponse := &BackendResponse{
+		Method:             http.MethodGet,
+		Protocol:           b.Protocol,
+		StatusCode:         200,
+		Status:             "200",
+		TargetURL:          path,
+		FinalTargetServer:  finalTargetServer,
+		RetryTargetServers: retryTargetServers,
+	}
+	if err != nil {
+		backendResponse.StatusCode, backendResponse.Status = 503, "503"
+		return backendResponse, err
+	}
+	backendResponse.Header = r.Header
+	backendResponse.StatusCode, backendResponse.Status = r.StatusCode, strconv.Itoa(r.StatusCode)
+	if conn == nil {
+		// 后端拒绝升级，按普通响应返回
+		backendResponse.BodyOrg, _ = ioutil.ReadAll(r.Body)
+		return backendResponse, nil
+	}
+	defer conn.Close()
+
+	clientConn, rw, err := ctx.Hijack()
+	if err != nil {
+		backendResponse.StatusCode, backendResponse.Status = 502, "502"
+		return backendResponse, err
+	}
+	defer clientConn.Close()
+
+	if _, err := fmt.Fprintf(clientConn, "HTTP/1.1 %d %s\r\n", r.StatusCode, http.StatusText(r.StatusCode)); err != nil {
+		return backendResponse, err
+	}
+	if err := r.Header.Write(clientConn); err != nil {
+		return backendResponse, err
+	}
+	if _, err := io.WriteString(clientConn, "\r\n"); err != nil {
+		return backendResponse, err
+	}
+
+	pipe(ctx, strings.ToLower(r.Header.Get("Upgrade")), finalTargetServer, clientConn, rw.Reader, conn)
+	return backendResponse, nil
+}
+
+// pipe 双向转发数据，并记录连接数及连接时长
+func pipe(ctx *common.Context, protocol, host string, client net.Conn, clientReader io.Reader, backend net.Conn) {
+	labels := make(diting.Labels)
+	labels[goku_labels.API] = strconv.Itoa(ctx.ApiID())
+	labels[goku_labels.Strategy] = ctx.StrategyId()
+	labels[goku_labels.Upgrade] = protocol
+
+	key := strings.Join([]string{labels[goku_labels.API], labels[goku_labels.Strategy], protocol}, ":")
+	setUpgradeConnections(key, 1, labels)
+	start := time.Now()
+	defer func() {
+		setUpgradeConnections(key, -1, labels)
+		if monitor.UpgradeMonitor != nil {
+			durationLabels := make(diting.Labels)
+			for k, v := range labels {
+				durationLabels[k] = v
+			}
+			durationLabels[goku_labels.Host] = host
+			monitor.UpgradeMonitor.Observe(time.Since(start).Seconds(), durationLabels)
+		}
+	}()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(backend, clientReader)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, backend)
+		done <- struct{}{}
+	}()
+	<-done
+	// 任意一端关闭后关闭两端连接，使另一个方向的转发结束
+	client.Close()
+	backend.Close()
+	<-done
+}
+
+func setUpgradeConnections(key string, delta int, labels diting.Labels) {
+	upgradeLocker.Lock()
+	defer upgradeLocker.Unlock()
+	count := upgradeConnections[key] + delta
+	if count <= 0 {
+		delete(upgradeConnections, key)
+		count = 0
+	} else {
+		upgradeConnections[key] = count
+	}
+
+	if monitor.UpgradeConnections != nil {
+		gaugeLabels := make(diting.Labels)
+		for k, v := range labels {
+			gaugeLabels[k] = v
+		}
+		monitor.UpgradeConnections.Set(float64(count), gaugeLabels)
+	}
+}
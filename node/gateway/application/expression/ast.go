@@ -0,0 +1,400 @@
+package expression
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+type scope struct {
+	root interface{}
+}
+
+type node interface {
+	eval(s *scope, current interface{}) interface{}
+}
+
+type currentNode struct{}
+
+func (n *currentNode) eval(s *scope, current interface{}) interface{} {
+	return current
+}
+
+type rootNode struct{}
+
+func (n *rootNode) eval(s *scope, current interface{}) interface{} {
+	return s.root
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(s *scope, current interface{}) interface{} {
+	return n.value
+}
+
+type fieldNode struct {
+	name string
+}
+
+func (n *fieldNode) eval(s *scope, current interface{}) interface{} {
+	if m, ok := current.(map[string]interface{}); ok {
+		return m[n.name]
+	}
+	return nil
+}
+
+type subNode struct {
+	left  node
+	right node
+}
+
+func (n *subNode) eval(s *scope, current interface{}) interface{} {
+	v := n.left.eval(s, current)
+	if v == nil {
+		return nil
+	}
+	return n.right.eval(s, v)
+}
+
+type pipeNode struct {
+	left  node
+	right node
+}
+
+func (n *pipeNode) eval(s *scope, current interface{}) interface{} {
+	return n.right.eval(s, n.left.eval(s, current))
+}
+
+type indexNode struct {
+	index int
+}
+
+func (n *indexNode) eval(s *scope, current interface{}) interface{} {
+	list, ok := current.([]interface{})
+	if !ok {
+		return nil
+	}
+	i := n.index
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil
+	}
+	return list[i]
+}
+
+type sliceNode struct {
+	start, stop, step *int
+}
+
+func (n *sliceNode) eval(s *scope, current interface{}) interface{} {
+	list, ok := current.([]interface{})
+	if !ok {
+		return nil
+	}
+	step := 1
+	if n.step != nil {
+		step = *n.step
+	}
+	if step == 0 {
+		return nil
+	}
+	length := len(list)
+	start, stop := 0, length
+	if step < 0 {
+		start, stop = length-1, -1
+	}
+	if n.start != nil {
+		start = adjustSliceIndex(*n.start, length, step)
+	}
+	if n.stop != nil {
+		stop = adjustSliceIndex(*n.stop, length, step)
+	}
+	result := make([]interface{}, 0, length)
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		result = append(result, list[i])
+	}
+	return result
+}
+
+func adjustSliceIndex(i, length, step int) int {
+	if i < 0 {
+		i += length
+		if i < 0 {
+			if step < 0 {
+				return -1
+			}
+			return 0
+		}
+	} else if i >= length {
+		if step < 0 {
+			return length - 1
+		}
+		return length
+	}
+	return i
+}
+
+type projectionNode struct {
+	left  node
+	right node
+}
+
+func (n *projectionNode) eval(s *scope, current interface{}) interface{} {
+	list, ok := n.left.eval(s, current).([]interface{})
+	if !ok {
+		return nil
+	}
+	return project(s, list, n.right)
+}
+
+type valueProjectionNode struct {
+	left  node
+	right node
+}
+
+func (n *valueProjectionNode) eval(s *scope, current interface{}) interface{} {
+	m, ok := n.left.eval(s, current).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	list := make([]interface{}, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		list = append(list, m[k])
+	}
+	return project(s, list, n.right)
+}
+
+type filterNode struct {
+	left      node
+	condition node
+	right     node
+}
+
+func (n *filterNode) eval(s *scope, current interface{}) interface{} {
+	list, ok := n.left.eval(s, current).([]interface{})
+	if !ok {
+		return nil
+	}
+	matched := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if Truthy(n.condition.eval(s, item)) {
+			matched = append(matched, item)
+		}
+	}
+	return project(s, matched, n.right)
+}
+
+func project(s *scope, list []interface{}, right node) []interface{} {
+	result := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if v := right.eval(s, item); v != nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+type flattenNode struct {
+	node node
+}
+
+func (n *flattenNode) eval(s *scope, current interface{}) interface{} {
+	list, ok := n.node.eval(s, current).([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if sub, ok := item.([]interface{}); ok {
+			result = append(result, sub...)
+		} else {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+type multiSelectListNode struct {
+	items []node
+}
+
+func (n *multiSelectListNode) eval(s *scope, current interface{}) interface{} {
+	if current == nil {
+		return nil
+	}
+	result := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		result[i] = item.eval(s, current)
+	}
+	return result
+}
+
+type multiSelectHashNode struct {
+	keys   []string
+	values []node
+}
+
+func (n *multiSelectHashNode) eval(s *scope, current interface{}) interface{} {
+	if current == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(n.keys))
+	for i, k := range n.keys {
+		result[k] = n.values[i].eval(s, current)
+	}
+	return result
+}
+
+type notNode struct {
+	node node
+}
+
+func (n *notNode) eval(s *scope, current interface{}) interface{} {
+	return !Truthy(n.node.eval(s, current))
+}
+
+type orNode struct {
+	left  node
+	right node
+}
+
+func (n *orNode) eval(s *scope, current interface{}) interface{} {
+	v := n.left.eval(s, current)
+	if Truthy(v) {
+		return v
+	}
+	return n.right.eval(s, current)
+}
+
+type andNode struct {
+	left  node
+	right node
+}
+
+func (n *andNode) eval(s *scope, current interface{}) interface{} {
+	v := n.left.eval(s, current)
+	if !Truthy(v) {
+		return v
+	}
+	return n.right.eval(s, current)
+}
+
+type compareNode struct {
+	op    tokenType
+	left  node
+	right node
+}
+
+func (n *compareNode) eval(s *scope, current interface{}) interface{} {
+	l := n.left.eval(s, current)
+	r := n.right.eval(s, current)
+	switch n.op {
+	case tEQ:
+		return equal(l, r)
+	case tNE:
+		return !equal(l, r)
+	}
+	if ln, ok := toFloat(l); ok {
+		rn, ok := toFloat(r)
+		if !ok {
+			return nil
+		}
+		return compareOrder(n.op, ln-rn)
+	}
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		if !ok {
+			return nil
+		}
+		return compareOrder(n.op, float64(strings.Compare(ls, rs)))
+	}
+	return nil
+}
+
+func compareOrder(op tokenType, diff float64) bool {
+	switch op {
+	case tLT:
+		return diff < 0
+	case tLTE:
+		return diff <= 0
+	case tGT:
+		return diff > 0
+	case tGTE:
+		return diff >= 0
+	}
+	return false
+}
+
+type arithmeticNode struct {
+	op    tokenType
+	left  node
+	right node
+}
+
+func (n *arithmeticNode) eval(s *scope, current interface{}) interface{} {
+	l := n.left.eval(s, current)
+	r := n.right.eval(s, current)
+	if n.op == tPlus {
+		if ls, ok := l.(string); ok {
+			return ls + toString(r)
+		}
+		if rs, ok := r.(string); ok && l != nil {
+			return toString(l) + rs
+		}
+	}
+	ln, ok := toFloat(l)
+	if !ok {
+		return nil
+	}
+	rn, ok := toFloat(r)
+	if !ok {
+		return nil
+	}
+	switch n.op {
+	case tPlus:
+		return ln + rn
+	case tMinus:
+		return ln - rn
+	case tStar:
+		return ln * rn
+	case tDiv:
+		if rn == 0 {
+			return nil
+		}
+		return ln / rn
+	case tMod:
+		if rn == 0 {
+			return nil
+		}
+		return math.Mod(ln, rn)
+	}
+	return nil
+}
+
+type functionNode struct {
+	name string
+	fn   *function
+	args []node
+}
+
+func (n *functionNode) eval(s *scope, current interface{}) interface{} {
+	args := make([]interface{}, len(n.args))
+	for i, a := range n.args {
+		args[i] = a.eval(s, current)
+	}
+	return n.fn.call(args)
+}
+
+func equal(l, r interface{}) bool {
+	if ln, ok := toFloat(l); ok {
+		if rn, ok := toFloat(r); ok {
+			return ln == rn
+		}
+		return false
+	}
+	return reflect.DeepEqual(l, r)
+}
@@ -0,0 +1,236 @@
+package expression
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	//TypeString string
+	TypeString = "string"
+	//TypeNumber number
+	TypeNumber = "number"
+	//TypeInteger integer
+	TypeInteger = "integer"
+	//TypeBoolean boolean
+	TypeBoolean = "boolean"
+	//TypeArray array
+	TypeArray = "array"
+	//TypeObject object
+	TypeObject = "object"
+	//TypeNull null
+	TypeNull = "null"
+)
+
+//SyntaxError 表达式语法错误
+type SyntaxError struct {
+	Expression string
+	Offset     int
+	Msg        string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid expression %q at offset %d: %s", e.Expression, e.Offset, e.Msg)
+}
+
+func newSyntaxError(src string, offset int, msg string) *SyntaxError {
+	return &SyntaxError{Expression: src, Offset: offset, Msg: msg}
+}
+
+//Expression 已编译的表达式
+//语法兼容jmespath的常用子集：字段访问(a.b)、索引与切片(a[0]、a[1:3])、投影(a[*].b、a.*)、过滤(a[?b > `1`])、
+//扁平化(a[])、管道(|)、多选(["a", b]、{k: v})、逻辑运算(&&、||、!)、比较运算及函数调用，
+//并在此基础上补充了算术运算(+ - * / %)、裸数字字面量，以及通过 $ 引用根节点
+type Expression struct {
+	src  string
+	root node
+}
+
+//Compile 编译表达式
+func Compile(src string) (*Expression, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return nil, newSyntaxError(src, 0, "empty expression")
+	}
+	n, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{src: src, root: n}, nil
+}
+
+//MustCompile 编译表达式，失败时panic
+func MustCompile(src string) *Expression {
+	e, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+//Search 以data作为根节点及当前节点求值
+func (e *Expression) Search(data interface{}) interface{} {
+	return e.SearchIn(data, data)
+}
+
+//SearchIn 以root作为根节点($)、current作为当前节点(@)求值
+func (e *Expression) SearchIn(root, current interface{}) interface{} {
+	return e.root.eval(&scope{root: root}, current)
+}
+
+func (e *Expression) String() string {
+	return e.src
+}
+
+//Truthy 判断值是否为真，null、false、空字符串、空数组、空对象为假
+func Truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []interface{}:
+		return len(t) > 0
+	case map[string]interface{}:
+		return len(t) > 0
+	}
+	return true
+}
+
+//TypeOf 返回值的类型名称
+func TypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return TypeNull
+	case bool:
+		return TypeBoolean
+	case string:
+		return TypeString
+	case []interface{}:
+		return TypeArray
+	case map[string]interface{}:
+		return TypeObject
+	}
+	if _, ok := toFloat(v); ok {
+		return TypeNumber
+	}
+	return TypeNull
+}
+
+//Convert 类型转换，无法转换时返回nil
+func Convert(v interface{}, typ string) interface{} {
+	switch strings.ToLower(typ) {
+	case TypeString:
+		if v == nil {
+			return nil
+		}
+		return toString(v)
+	case TypeNumber:
+		if n, ok := toFloat(v); ok {
+			return n
+		}
+		if s, ok := v.(string); ok {
+			if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return n
+			}
+		}
+		if b, ok := v.(bool); ok {
+			if b {
+				return float64(1)
+			}
+			return float64(0)
+		}
+	case TypeInteger, "int":
+		if n, ok := Convert(v, TypeNumber).(float64); ok {
+			return int64(n)
+		}
+	case TypeBoolean, "bool":
+		switch t := v.(type) {
+		case bool:
+			return t
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(t))
+			if err != nil {
+				return nil
+			}
+			return b
+		}
+		if n, ok := toFloat(v); ok {
+			return n != 0
+		}
+	case TypeArray:
+		if v == nil {
+			return nil
+		}
+		if l, ok := v.([]interface{}); ok {
+			return l
+		}
+		return []interface{}{v}
+	case TypeObject:
+		if m, ok := v.(map[string]interface{}); ok {
+			return m
+		}
+		if s, ok := v.(string); ok {
+			var m map[string]interface{}
+			if err := json.Unmarshal([]byte(s), &m); err == nil {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case []interface{}, map[string]interface{}:
+		data, _ := json.Marshal(t)
+		return string(data)
+	}
+	if n, ok := toFloat(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int8:
+		return float64(t), true
+	case int16:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint:
+		return float64(t), true
+	case uint8:
+		return float64(t), true
+	case uint16:
+		return float64(t), true
+	case uint32:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case json.Number:
+		n, err := t.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,101 @@
+package expression
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const sample = `{
+	"code": 0,
+	"data": {
+		"user": {"first": "Li", "last": "Lei", "age": "18"},
+		"orders": [
+			{"id": 1, "price": 12.5, "count": 2, "status": "paid"},
+			{"id": 2, "price": 3, "count": 1, "status": "cancel"},
+			{"id": 3, "price": 8, "count": 3, "status": "paid"}
+		],
+		"tags": [["a", "b"], ["c"]]
+	}
+}`
+
+func decodeSample(t *testing.T) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(sample), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestSearch(t *testing.T) {
+	data := decodeSample(t)
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"code", `0`},
+		{"data.user.first", `"Li"`},
+		{"data.orders[0].id", `1`},
+		{"data.orders[-1].id", `3`},
+		{"data.orders[*].id", `[1,2,3]`},
+		{"data.orders[1:].id", `[2,3]`},
+		{"data.orders[?status == 'paid'].id", `[1,3]`},
+		{"data.orders[?price > `5` && count >= `2`].id", `[1,3]`},
+		{"data.orders[?!(status == 'paid')] | [0].id", `2`},
+		{"data.tags[]", `["a","b","c"]`},
+		{"data.orders[*].[id, status]", `[[1,"paid"],[2,"cancel"],[3,"paid"]]`},
+		{"data.user.{name: concat(first, ' ', last), age: to_number(age)}", `{"age":18,"name":"Li Lei"}`},
+		{"sum(data.orders[*].price * 2)", `null`},
+		{"data.orders[0].price * data.orders[0].count", `25`},
+		{"sum(data.orders[?status == 'paid'].count)", `5`},
+		{"length(data.orders)", `3`},
+		{"upper(data.user.first)", `"LI"`},
+		{"data.missing || 'default'", `"default"`},
+		{"data.orders[*].{id: id, total: price * count}", `[{"id":1,"total":25},{"id":2,"total":3},{"id":3,"total":24}]`},
+		{"join(',', data.orders[*].status)", `"paid,cancel,paid"`},
+		{"data.user.*", `["18","Li","Lei"]`},
+		{"data.orders[?id == $.data.orders[2].id].status", `["paid"]`},
+	}
+
+	for _, c := range cases {
+		e, err := Compile(c.expr)
+		if err != nil {
+			t.Errorf("compile %s:%v", c.expr, err)
+			continue
+		}
+		got, _ := json.Marshal(e.Search(data))
+		if string(got) != c.want {
+			t.Errorf("%s = %s, want %s", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, expr := range []string{"", "a.", "a[", "unknown(a)", "length(a, b)", "a ==", "{a b}", "'abc"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		typ   string
+		want  interface{}
+	}{
+		{"12.5", TypeNumber, 12.5},
+		{"12.5", TypeInteger, int64(12)},
+		{float64(3), TypeString, "3"},
+		{"true", TypeBoolean, true},
+		{float64(0), TypeBoolean, false},
+		{"x", TypeNumber, nil},
+		{"a", TypeArray, []interface{}{"a"}},
+	}
+	for _, c := range cases {
+		if got := Convert(c.value, c.typ); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Convert(%v, %s) = %#v, want %#v", c.value, c.typ, got, c.want)
+		}
+	}
+}
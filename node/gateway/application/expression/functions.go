@@ -0,0 +1,284 @@
+package expression
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+type function struct {
+	minArgs int
+	maxArgs int // -1 表示不限制
+	call    func(args []interface{}) interface{}
+}
+
+var functions map[string]*function
+
+func init() {
+	functions = map[string]*function{
+		"length":     {1, 1, fnLength},
+		"upper":      {1, 1, stringFunc(strings.ToUpper)},
+		"lower":      {1, 1, stringFunc(strings.ToLower)},
+		"trim":       {1, 1, stringFunc(strings.TrimSpace)},
+		"to_string":  {1, 1, func(args []interface{}) interface{} { return toString(args[0]) }},
+		"to_number":  {1, 1, func(args []interface{}) interface{} { return Convert(args[0], TypeNumber) }},
+		"to_integer": {1, 1, func(args []interface{}) interface{} { return Convert(args[0], TypeInteger) }},
+		"to_boolean": {1, 1, func(args []interface{}) interface{} { return Convert(args[0], TypeBoolean) }},
+		"type":       {1, 1, func(args []interface{}) interface{} { return TypeOf(args[0]) }},
+		"not_null":   {1, -1, fnNotNull},
+		"concat":     {1, -1, fnConcat},
+		"join":       {2, 2, fnJoin},
+		"split":      {2, 2, fnSplit},
+		"contains":   {2, 2, fnContains},
+		"starts_with": {2, 2, func(args []interface{}) interface{} {
+			s, ok1 := args[0].(string)
+			p, ok2 := args[1].(string)
+			return ok1 && ok2 && strings.HasPrefix(s, p)
+		}},
+		"ends_with": {2, 2, func(args []interface{}) interface{} {
+			s, ok1 := args[0].(string)
+			p, ok2 := args[1].(string)
+			return ok1 && ok2 && strings.HasSuffix(s, p)
+		}},
+		"keys":    {1, 1, fnKeys},
+		"values":  {1, 1, fnValues},
+		"merge":   {1, -1, fnMerge},
+		"sum":     {1, 1, fnSum},
+		"avg":     {1, 1, fnAvg},
+		"min":     {1, 1, numberReduce(math.Min)},
+		"max":     {1, 1, numberReduce(math.Max)},
+		"abs":     {1, 1, numberFunc(math.Abs)},
+		"floor":   {1, 1, numberFunc(math.Floor)},
+		"ceil":    {1, 1, numberFunc(math.Ceil)},
+		"round":   {1, 1, numberFunc(math.Round)},
+		"reverse": {1, 1, fnReverse},
+		"sort":    {1, 1, fnSort},
+	}
+}
+
+func stringFunc(f func(string) string) func(args []interface{}) interface{} {
+	return func(args []interface{}) interface{} {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil
+		}
+		return f(s)
+	}
+}
+
+func numberFunc(f func(float64) float64) func(args []interface{}) interface{} {
+	return func(args []interface{}) interface{} {
+		n, ok := toFloat(args[0])
+		if !ok {
+			return nil
+		}
+		return f(n)
+	}
+}
+
+func numberReduce(f func(a, b float64) float64) func(args []interface{}) interface{} {
+	return func(args []interface{}) interface{} {
+		list, ok := args[0].([]interface{})
+		if !ok || len(list) == 0 {
+			return nil
+		}
+		var result float64
+		for i, item := range list {
+			n, ok := toFloat(item)
+			if !ok {
+				return nil
+			}
+			if i == 0 {
+				result = n
+				continue
+			}
+			result = f(result, n)
+		}
+		return result
+	}
+}
+
+func fnLength(args []interface{}) interface{} {
+	switch v := args[0].(type) {
+	case string:
+		return float64(len([]rune(v)))
+	case []interface{}:
+		return float64(len(v))
+	case map[string]interface{}:
+		return float64(len(v))
+	}
+	return nil
+}
+
+func fnNotNull(args []interface{}) interface{} {
+	for _, a := range args {
+		if a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+func fnConcat(args []interface{}) interface{} {
+	builder := strings.Builder{}
+	for _, a := range args {
+		builder.WriteString(toString(a))
+	}
+	return builder.String()
+}
+
+func fnJoin(args []interface{}) interface{} {
+	sep, ok := args[0].(string)
+	if !ok {
+		return nil
+	}
+	list, ok := args[1].([]interface{})
+	if !ok {
+		return nil
+	}
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		items = append(items, toString(item))
+	}
+	return strings.Join(items, sep)
+}
+
+func fnSplit(args []interface{}) interface{} {
+	s, ok1 := args[0].(string)
+	sep, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	parts := strings.Split(s, sep)
+	result := make([]interface{}, len(parts))
+	for i, p := range parts {
+		result[i] = p
+	}
+	return result
+}
+
+func fnContains(args []interface{}) interface{} {
+	switch v := args[0].(type) {
+	case string:
+		s, ok := args[1].(string)
+		return ok && strings.Contains(v, s)
+	case []interface{}:
+		for _, item := range v {
+			if equal(item, args[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func fnKeys(args []interface{}) interface{} {
+	m, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := sortedKeys(m)
+	result := make([]interface{}, len(keys))
+	for i, k := range keys {
+		result[i] = k
+	}
+	return result
+}
+
+func fnValues(args []interface{}) interface{} {
+	m, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]interface{}, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		result = append(result, m[k])
+	}
+	return result
+}
+
+func fnMerge(args []interface{}) interface{} {
+	result := make(map[string]interface{})
+	for _, a := range args {
+		m, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func fnSum(args []interface{}) interface{} {
+	list, ok := args[0].([]interface{})
+	if !ok {
+		return nil
+	}
+	var sum float64
+	for _, item := range list {
+		n, ok := toFloat(item)
+		if !ok {
+			return nil
+		}
+		sum += n
+	}
+	return sum
+}
+
+func fnAvg(args []interface{}) interface{} {
+	list, ok := args[0].([]interface{})
+	if !ok || len(list) == 0 {
+		return nil
+	}
+	sum, ok := fnSum(args).(float64)
+	if !ok {
+		return nil
+	}
+	return sum / float64(len(list))
+}
+
+func fnReverse(args []interface{}) interface{} {
+	switch v := args[0].(type) {
+	case string:
+		r := []rune(v)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[len(v)-1-i] = item
+		}
+		return result
+	}
+	return nil
+}
+
+func fnSort(args []interface{}) interface{} {
+	list, ok := args[0].([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]interface{}, len(list))
+	copy(result, list)
+	sort.SliceStable(result, func(i, j int) bool {
+		if a, ok := toFloat(result[i]); ok {
+			b, _ := toFloat(result[j])
+			return a < b
+		}
+		return toString(result[i]) < toString(result[j])
+	})
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
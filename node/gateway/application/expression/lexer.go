@@ -0,0 +1,263 @@
+package expression
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tEOF tokenType = iota
+	tIdent
+	tQuoted
+	tString
+	tLiteral
+	tNumber
+	tDot
+	tStar
+	tAt
+	tRoot
+	tComma
+	tColon
+	tLBracket
+	tRBracket
+	tFilter
+	tFlatten
+	tLBrace
+	tRBrace
+	tLParen
+	tRParen
+	tPipe
+	tOr
+	tAnd
+	tNot
+	tEQ
+	tNE
+	tLT
+	tLTE
+	tGT
+	tGTE
+	tPlus
+	tMinus
+	tDiv
+	tMod
+)
+
+var tokenNames = map[tokenType]string{
+	tEOF:      "EOF",
+	tIdent:    "identifier",
+	tQuoted:   "quoted identifier",
+	tString:   "string",
+	tLiteral:  "literal",
+	tNumber:   "number",
+	tDot:      ".",
+	tStar:     "*",
+	tAt:       "@",
+	tRoot:     "$",
+	tComma:    ",",
+	tColon:    ":",
+	tLBracket: "[",
+	tRBracket: "]",
+	tFilter:   "[?",
+	tFlatten:  "[]",
+	tLBrace:   "{",
+	tRBrace:   "}",
+	tLParen:   "(",
+	tRParen:   ")",
+	tPipe:     "|",
+	tOr:       "||",
+	tAnd:      "&&",
+	tNot:      "!",
+	tEQ:       "==",
+	tNE:       "!=",
+	tLT:       "<",
+	tLTE:      "<=",
+	tGT:       ">",
+	tGTE:      ">=",
+	tPlus:     "+",
+	tMinus:    "-",
+	tDiv:      "/",
+	tMod:      "%",
+}
+
+func (t tokenType) String() string {
+	return tokenNames[t]
+}
+
+type token struct {
+	typ   tokenType
+	text  string
+	value interface{}
+	pos   int
+}
+
+var singleTokens = map[byte]tokenType{
+	'.': tDot,
+	'*': tStar,
+	'@': tAt,
+	'$': tRoot,
+	',': tComma,
+	':': tColon,
+	']': tRBracket,
+	'{': tLBrace,
+	'}': tRBrace,
+	'(': tLParen,
+	')': tRParen,
+	'+': tPlus,
+	'-': tMinus,
+	'/': tDiv,
+	'%': tMod,
+}
+
+func lex(src string) ([]token, error) {
+	tokens := make([]token, 0, len(src)/2+1)
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentPart(src[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tIdent, text: src[start:i], pos: start})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9') {
+				i++
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, newSyntaxError(src, start, "invalid number")
+			}
+			tokens = append(tokens, token{typ: tNumber, text: src[start:i], value: n, pos: start})
+		case c == '\'':
+			s, end, err := readQuoted(src, i, '\'')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{typ: tString, text: s, value: s, pos: i})
+			i = end
+		case c == '"':
+			s, end, err := readQuoted(src, i, '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{typ: tQuoted, text: s, pos: i})
+			i = end
+		case c == '`':
+			raw, end, err := readQuoted(src, i, '`')
+			if err != nil {
+				return nil, err
+			}
+			var v interface{}
+			if err := json.Unmarshal([]byte(raw), &v); err != nil {
+				// 与jmespath一致，无法解析为json时视为字符串
+				v = raw
+			}
+			tokens = append(tokens, token{typ: tLiteral, text: raw, value: v, pos: i})
+			i = end
+		case c == '[':
+			switch {
+			case strings.HasPrefix(src[i:], "[?"):
+				tokens = append(tokens, token{typ: tFilter, text: "[?", pos: i})
+				i += 2
+			case strings.HasPrefix(src[i:], "[]"):
+				tokens = append(tokens, token{typ: tFlatten, text: "[]", pos: i})
+				i += 2
+			default:
+				tokens = append(tokens, token{typ: tLBracket, text: "[", pos: i})
+				i++
+			}
+		case c == '|':
+			if strings.HasPrefix(src[i:], "||") {
+				tokens = append(tokens, token{typ: tOr, text: "||", pos: i})
+				i += 2
+			} else {
+				tokens = append(tokens, token{typ: tPipe, text: "|", pos: i})
+				i++
+			}
+		case c == '&':
+			if !strings.HasPrefix(src[i:], "&&") {
+				return nil, newSyntaxError(src, i, "unexpected '&'")
+			}
+			tokens = append(tokens, token{typ: tAnd, text: "&&", pos: i})
+			i += 2
+		case c == '!' || c == '=' || c == '<' || c == '>':
+			two := ""
+			if i+1 < len(src) {
+				two = src[i : i+2]
+			}
+			switch two {
+			case "!=":
+				tokens = append(tokens, token{typ: tNE, text: two, pos: i})
+				i += 2
+				continue
+			case "==":
+				tokens = append(tokens, token{typ: tEQ, text: two, pos: i})
+				i += 2
+				continue
+			case "<=":
+				tokens = append(tokens, token{typ: tLTE, text: two, pos: i})
+				i += 2
+				continue
+			case ">=":
+				tokens = append(tokens, token{typ: tGTE, text: two, pos: i})
+				i += 2
+				continue
+			}
+			switch c {
+			case '!':
+				tokens = append(tokens, token{typ: tNot, text: "!", pos: i})
+			case '<':
+				tokens = append(tokens, token{typ: tLT, text: "<", pos: i})
+			case '>':
+				tokens = append(tokens, token{typ: tGT, text: ">", pos: i})
+			default:
+				return nil, newSyntaxError(src, i, "unexpected '='")
+			}
+			i++
+		default:
+			t, has := singleTokens[c]
+			if !has {
+				return nil, newSyntaxError(src, i, fmt.Sprintf("unexpected character %q", c))
+			}
+			tokens = append(tokens, token{typ: t, text: string(c), pos: i})
+			i++
+		}
+	}
+	tokens = append(tokens, token{typ: tEOF, pos: len(src)})
+	return tokens, nil
+}
+
+func readQuoted(src string, start int, quote byte) (string, int, error) {
+	builder := strings.Builder{}
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		if c == '\\' && i+1 < len(src) && (src[i+1] == quote || src[i+1] == '\\') {
+			builder.WriteByte(src[i+1])
+			i += 2
+			continue
+		}
+		if c == quote {
+			return builder.String(), i + 1, nil
+		}
+		builder.WriteByte(c)
+		i++
+	}
+	return "", 0, newSyntaxError(src, start, "unterminated quote")
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
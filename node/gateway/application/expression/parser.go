@@ -0,0 +1,427 @@
+package expression
+
+import "fmt"
+
+// 绑定优先级，与jmespath保持一致，并补充了算术运算
+var bindingPowers = map[tokenType]int{
+	tPipe:     1,
+	tOr:       2,
+	tAnd:      3,
+	tEQ:       5,
+	tNE:       5,
+	tLT:       5,
+	tLTE:      5,
+	tGT:       5,
+	tGTE:      5,
+	tPlus:     6,
+	tMinus:    6,
+	tStar:     7,
+	tDiv:      7,
+	tMod:      7,
+	tFlatten:  9,
+	tFilter:   21,
+	tDot:      40,
+	tNot:      45,
+	tLBrace:   50,
+	tLBracket: 55,
+	tLParen:   60,
+}
+
+// 投影右侧表达式遇到优先级低于该值的token时结束
+const projectionStop = 10
+
+type parser struct {
+	src    string
+	tokens []token
+	index  int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, tokens: tokens}
+	n, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.current().typ != tEOF {
+		return nil, p.unexpected()
+	}
+	return n, nil
+}
+
+func (p *parser) current() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) lookahead(n int) token {
+	if p.index+n < len(p.tokens) {
+		return p.tokens[p.index+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.index]
+	if p.index < len(p.tokens)-1 {
+		p.index++
+	}
+	return t
+}
+
+func (p *parser) match(t tokenType) error {
+	if p.current().typ != t {
+		return newSyntaxError(p.src, p.current().pos, fmt.Sprintf("expected %s, got %s", t, p.current().typ))
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.current()
+	return newSyntaxError(p.src, t.pos, fmt.Sprintf("unexpected %s", t.typ))
+}
+
+func (p *parser) expression(bp int) (node, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return nil, err
+	}
+	for bp < bindingPowers[p.current().typ] {
+		left, err = p.led(p.advance(), left)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) nud(t token) (node, error) {
+	switch t.typ {
+	case tIdent, tQuoted:
+		return &fieldNode{name: t.text}, nil
+	case tString, tLiteral, tNumber:
+		return &literalNode{value: t.value}, nil
+	case tAt:
+		return &currentNode{}, nil
+	case tRoot:
+		return &rootNode{}, nil
+	case tStar:
+		right, err := p.projectionRHS(bindingPowers[tStar])
+		if err != nil {
+			return nil, err
+		}
+		return &valueProjectionNode{left: &currentNode{}, right: right}, nil
+	case tNot:
+		n, err := p.expression(bindingPowers[tNot])
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	case tMinus:
+		n, err := p.expression(bindingPowers[tNot])
+		if err != nil {
+			return nil, err
+		}
+		return &arithmeticNode{op: tMinus, left: &literalNode{value: float64(0)}, right: n}, nil
+	case tLParen:
+		n, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.match(tRParen)
+	case tLBrace:
+		return p.multiSelectHash()
+	case tFlatten:
+		return p.flatten(&currentNode{})
+	case tFilter:
+		return p.filter(&currentNode{})
+	case tLBracket:
+		next := p.current().typ
+		if next == tNumber || next == tColon || next == tMinus {
+			index, err := p.indexExpression(&currentNode{})
+			if err != nil {
+				return nil, err
+			}
+			return index, nil
+		}
+		if next == tStar && p.lookahead(1).typ == tRBracket {
+			p.advance()
+			p.advance()
+			right, err := p.projectionRHS(bindingPowers[tStar])
+			if err != nil {
+				return nil, err
+			}
+			return &projectionNode{left: &currentNode{}, right: right}, nil
+		}
+		return p.multiSelectList()
+	}
+	p.index--
+	return nil, p.unexpected()
+}
+
+func (p *parser) led(t token, left node) (node, error) {
+	switch t.typ {
+	case tDot:
+		if p.current().typ == tStar {
+			p.advance()
+			right, err := p.projectionRHS(bindingPowers[tDot])
+			if err != nil {
+				return nil, err
+			}
+			return &valueProjectionNode{left: left, right: right}, nil
+		}
+		right, err := p.dotRHS(bindingPowers[tDot])
+		if err != nil {
+			return nil, err
+		}
+		return &subNode{left: left, right: right}, nil
+	case tPipe:
+		right, err := p.expression(bindingPowers[tPipe])
+		if err != nil {
+			return nil, err
+		}
+		return &pipeNode{left: left, right: right}, nil
+	case tOr, tAnd:
+		right, err := p.expression(bindingPowers[t.typ])
+		if err != nil {
+			return nil, err
+		}
+		if t.typ == tOr {
+			return &orNode{left: left, right: right}, nil
+		}
+		return &andNode{left: left, right: right}, nil
+	case tEQ, tNE, tLT, tLTE, tGT, tGTE:
+		right, err := p.expression(bindingPowers[t.typ])
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: t.typ, left: left, right: right}, nil
+	case tPlus, tMinus, tStar, tDiv, tMod:
+		right, err := p.expression(bindingPowers[t.typ])
+		if err != nil {
+			return nil, err
+		}
+		return &arithmeticNode{op: t.typ, left: left, right: right}, nil
+	case tFlatten:
+		return p.flatten(left)
+	case tFilter:
+		return p.filter(left)
+	case tLBracket:
+		next := p.current().typ
+		if next == tStar && p.lookahead(1).typ == tRBracket {
+			p.advance()
+			p.advance()
+			right, err := p.projectionRHS(bindingPowers[tStar])
+			if err != nil {
+				return nil, err
+			}
+			return &projectionNode{left: left, right: right}, nil
+		}
+		return p.indexExpression(left)
+	case tLParen:
+		name, ok := left.(*fieldNode)
+		if !ok {
+			p.index--
+			return nil, p.unexpected()
+		}
+		return p.function(name.name, t.pos)
+	}
+	p.index--
+	return nil, p.unexpected()
+}
+
+func (p *parser) dotRHS(bp int) (node, error) {
+	switch p.current().typ {
+	case tIdent, tQuoted:
+		t := p.advance()
+		if p.current().typ == tLParen {
+			p.advance()
+			return p.function(t.text, t.pos)
+		}
+		return &fieldNode{name: t.text}, nil
+	case tLBracket:
+		p.advance()
+		return p.multiSelectList()
+	case tLBrace:
+		p.advance()
+		return p.multiSelectHash()
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) projectionRHS(bp int) (node, error) {
+	t := p.current().typ
+	if bindingPowers[t] < projectionStop {
+		return &currentNode{}, nil
+	}
+	switch t {
+	case tLBracket, tFilter:
+		return p.expression(bp)
+	case tDot:
+		p.advance()
+		if p.current().typ == tStar {
+			p.advance()
+			right, err := p.projectionRHS(bindingPowers[tDot])
+			if err != nil {
+				return nil, err
+			}
+			return &valueProjectionNode{left: &currentNode{}, right: right}, nil
+		}
+		n, err := p.dotRHS(bp)
+		if err != nil {
+			return nil, err
+		}
+		for bp < bindingPowers[p.current().typ] {
+			n, err = p.led(p.advance(), n)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) indexExpression(left node) (node, error) {
+	parts := [3]*int{}
+	pos := 0
+	isSlice := false
+	for p.current().typ != tRBracket {
+		switch p.current().typ {
+		case tColon:
+			isSlice = true
+			pos++
+			if pos > 2 {
+				return nil, p.unexpected()
+			}
+			p.advance()
+		case tNumber, tMinus:
+			sign := 1
+			if p.current().typ == tMinus {
+				sign = -1
+				p.advance()
+				if p.current().typ != tNumber {
+					return nil, p.unexpected()
+				}
+			}
+			n := sign * int(p.advance().value.(float64))
+			parts[pos] = &n
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	p.advance()
+	if !isSlice {
+		if parts[0] == nil {
+			return nil, newSyntaxError(p.src, p.current().pos, "empty index")
+		}
+		return &subNode{left: left, right: &indexNode{index: *parts[0]}}, nil
+	}
+	right, err := p.projectionRHS(bindingPowers[tStar])
+	if err != nil {
+		return nil, err
+	}
+	return &projectionNode{
+		left:  &subNode{left: left, right: &sliceNode{start: parts[0], stop: parts[1], step: parts[2]}},
+		right: right,
+	}, nil
+}
+
+func (p *parser) flatten(left node) (node, error) {
+	right, err := p.projectionRHS(bindingPowers[tFlatten])
+	if err != nil {
+		return nil, err
+	}
+	return &projectionNode{left: &flattenNode{node: left}, right: right}, nil
+}
+
+func (p *parser) filter(left node) (node, error) {
+	condition, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.match(tRBracket); err != nil {
+		return nil, err
+	}
+	right, err := p.projectionRHS(bindingPowers[tFilter])
+	if err != nil {
+		return nil, err
+	}
+	return &filterNode{left: left, condition: condition, right: right}, nil
+}
+
+func (p *parser) multiSelectList() (node, error) {
+	items := make([]node, 0, 4)
+	for {
+		n, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, n)
+		if p.current().typ == tRBracket {
+			p.advance()
+			return &multiSelectListNode{items: items}, nil
+		}
+		if err := p.match(tComma); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) multiSelectHash() (node, error) {
+	keys := make([]string, 0, 4)
+	values := make([]node, 0, 4)
+	for {
+		k := p.current()
+		if k.typ != tIdent && k.typ != tQuoted && k.typ != tString {
+			return nil, p.unexpected()
+		}
+		p.advance()
+		if err := p.match(tColon); err != nil {
+			return nil, err
+		}
+		v, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k.text)
+		values = append(values, v)
+		if p.current().typ == tRBrace {
+			p.advance()
+			return &multiSelectHashNode{keys: keys, values: values}, nil
+		}
+		if err := p.match(tComma); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) function(name string, pos int) (node, error) {
+	fn, has := functions[name]
+	if !has {
+		return nil, newSyntaxError(p.src, pos, fmt.Sprintf("unknown function %s()", name))
+	}
+	args := make([]node, 0, 2)
+	for p.current().typ != tRParen {
+		n, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, n)
+		if p.current().typ == tComma {
+			p.advance()
+			continue
+		}
+		if p.current().typ != tRParen {
+			return nil, p.unexpected()
+		}
+	}
+	p.advance()
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, newSyntaxError(p.src, pos, fmt.Sprintf("invalid number of arguments for %s()", name))
+	}
+	return &functionNode{name: name, fn: fn, args: args}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/backend"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 )
 
 //LayerApplication layer application
@@ -58,6 +59,11 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 	case e := <-errC:
 		fmt.Println(e)
 		cancelFunc()
+		if e == size_limit.ErrorResponseTooLarge {
+			ctx.SetStatus(502, "502")
+			ctx.SetBody([]byte("[ERROR]Response body too large!"))
+			return
+		}
 		ctx.SetStatus(504, "504")
 		ctx.SetBody([]byte("[ERROR]Fail to get response after proxy!"))
 		//error
@@ -75,6 +81,9 @@ func (app *LayerApplication) Execute(ctx *common.Context) {
 		log.Warn("encode response error:", e)
 		return
 	}
+	if contentType := app.output.ContentType(); contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
 	//if headers.Get("Content-Encoding") == "gzip" {
 	//	var b bytes.Buffer
 	//	wb := gzip.NewWriter(&b)
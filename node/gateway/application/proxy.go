@@ -13,6 +13,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/node/gateway/application/interpreter"
 
 	"github.com/eolinker/goku-api-gateway/node/gateway/response"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 )
 
@@ -69,6 +70,11 @@ func (app *DefaultApplication) Execute(ctx *common.Context) {
 			ctx.LogFields[access_field.Proxy] = fmt.Sprintf("\"%s %s %s\"", r.Method, application.URLPath(r.TargetURL, ctx.ProxyRequest.Querys()), r.Protocol)
 
 		}
+		if err == size_limit.ErrorResponseTooLarge {
+			ctx.SetStatus(502, "502")
+			ctx.SetBody([]byte("[ERROR]Response body too large!"))
+			return
+		}
 		if err != nil {
 			ctx.SetStatus(504, "504")
 			ctx.SetBody([]byte("[ERROR]Fail to get response after proxy!"))
@@ -81,6 +87,8 @@ func (app *DefaultApplication) Execute(ctx *common.Context) {
 		body, err := app.output.Encode(r.Body, r.BodyOrg)
 		if err != nil {
 			body = r.BodyOrg
+		} else if contentType := app.output.ContentType(); contentType != "" && r.Body != nil && r.Header != nil {
+			r.Header.Set("Content-Type", contentType)
 		}
 		ctx.SetProxyResponseHandler(common.NewResponseReader(r.Header, r.StatusCode, r.Status, body))
 
@@ -96,3 +104,47 @@ func (app *DefaultApplication) Execute(ctx *common.Context) {
 	ctx.SetBody([]byte("[ERROR]Fail to get response after proxy!"))
 
 }
+
+//Upgrade 转发websocket等协议升级请求
+func (app *DefaultApplication) Upgrade(ctx *common.Context) {
+
+	ctx.LogFields[access_field.Balance] = app.balanceTarget
+
+	if app.backend == nil {
+		ctx.SetStatus(502, "502")
+		ctx.SetBody([]byte("[ERROR]Upgrade is not supported by this api!"))
+		return
+	}
+
+	variables := interpreter.NewVariables(nil, nil, ctx.ProxyRequest.Headers(), ctx.ProxyRequest.Cookies(), ctx.RestfulParam, ctx.ProxyRequest.Querys(), 1)
+
+	// 接管连接前设置状态码，供访问日志及监控使用
+	ctx.SetStatus(101, "101")
+	r, err := app.backend.Upgrade(ctx, variables)
+	if r != nil {
+		ctx.ProxyRequest.Method = r.Method
+		ctx.ProxyRequest.SetTargetURL(r.TargetURL)
+
+		ctx.SetRetryTargetServers(strings.Join(r.RetryTargetServers, ","))
+		ctx.SetFinalTargetServer(r.FinalTargetServer)
+
+		ctx.LogFields[access_field.FinallyServer] = ctx.FinalTargetServer()
+		ctx.LogFields[access_field.Retry] = ctx.RetryTargetServers()
+		ctx.LogFields[access_field.Proxy] = fmt.Sprintf("\"%s %s %s\"", r.Method, application.URLPath(r.TargetURL, ctx.ProxyRequest.Querys()), r.Protocol)
+		ctx.LogFields[access_field.ProxyStatusCode] = r.StatusCode
+	}
+	if ctx.Hijacked() {
+		if err != nil {
+			log.Warn(err)
+		}
+		return
+	}
+	if err != nil {
+		ctx.SetStatus(504, "504")
+		ctx.SetBody([]byte("[ERROR]Fail to get response after proxy!"))
+		log.Warn(err)
+		return
+	}
+	ctx.SetStatus(r.StatusCode, r.Status)
+	ctx.SetProxyResponseHandler(common.NewResponseReader(r.Header, r.StatusCode, r.Status, r.BodyOrg))
+}
@@ -0,0 +1,98 @@
+package rewrite
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//Rewriter 转发路径及query重写器
+type Rewriter struct {
+	stripPrefix string
+	regex       *regexp.Regexp
+	replacement string
+	addPrefix   string
+
+	queryAdd    map[string]string
+	queryRemove []string
+	queryRename map[string]string
+}
+
+//New 通过配置创建重写器，未配置任何规则时返回nil
+func New(cfg *config.RewriteConfig) (*Rewriter, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	r := &Rewriter{
+		stripPrefix: cfg.StripPrefix,
+		replacement: cfg.Replacement,
+		addPrefix:   cfg.AddPrefix,
+		queryAdd:    cfg.QueryAdd,
+		queryRemove: cfg.QueryRemove,
+		queryRename: cfg.QueryRename,
+	}
+	if cfg.Regex != "" {
+		reg, err := regexp.Compile(cfg.Regex)
+		if err != nil {
+			return nil, err
+		}
+		r.regex = reg
+	}
+	if r.isEmpty() {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func (r *Rewriter) isEmpty() bool {
+	return r.stripPrefix == "" && r.regex == nil && r.addPrefix == "" &&
+		len(r.queryAdd) == 0 && len(r.queryRemove) == 0 && len(r.queryRename) == 0
+}
+
+//Path 依次执行去除前缀、正则替换、添加前缀
+func (r *Rewriter) Path(path string) string {
+	if r == nil {
+		return path
+	}
+	if r.stripPrefix != "" && strings.HasPrefix(path, r.stripPrefix) {
+		path = strings.TrimPrefix(path, r.stripPrefix)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+	}
+	if r.regex != nil {
+		path = r.regex.ReplaceAllString(path, r.replacement)
+	}
+	if r.addPrefix != "" {
+		path = strings.TrimSuffix(r.addPrefix, "/") + "/" + strings.TrimPrefix(path, "/")
+	}
+	return path
+}
+
+//Query 依次执行删除、重命名、添加，返回新的query，不修改原始值
+func (r *Rewriter) Query(query url.Values) url.Values {
+	if r == nil || (len(r.queryAdd) == 0 && len(r.queryRemove) == 0 && len(r.queryRename) == 0) {
+		return query
+	}
+	values := make(url.Values, len(query)+len(r.queryAdd))
+	for k, v := range query {
+		values[k] = append([]string(nil), v...)
+	}
+	for _, k := range r.queryRemove {
+		values.Del(k)
+	}
+	for origin, target := range r.queryRename {
+		v, has := values[origin]
+		if !has {
+			continue
+		}
+		delete(values, origin)
+		values[target] = v
+	}
+	for k, v := range r.queryAdd {
+		values.Set(k, v)
+	}
+	return values
+}
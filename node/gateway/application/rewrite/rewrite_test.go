@@ -0,0 +1,73 @@
+package rewrite
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+func TestPath(t *testing.T) {
+	r, err := New(&config.RewriteConfig{
+		StripPrefix: "/api",
+		Regex:       `^/user/(\d+)/(?P<action>\w+)$`,
+		Replacement: "/users/$1/${action}",
+		AddPrefix:   "/v2/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"/api/user/12/profile": "/v2/users/12/profile",
+		"/api/order/1":         "/v2/order/1",
+		"/user/3/info":         "/v2/users/3/info",
+	}
+	for in, want := range cases {
+		if got := r.Path(in); got != want {
+			t.Errorf("Path(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	r, err := New(&config.RewriteConfig{
+		QueryAdd:    map[string]string{"source": "gateway"},
+		QueryRemove: []string{"debug"},
+		QueryRename: map[string]string{"uid": "userId"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org := url.Values{"uid": {"1"}, "debug": {"true"}, "page": {"2"}}
+	q := r.Query(org)
+
+	if q.Get("userId") != "1" || q.Get("uid") != "" {
+		t.Errorf("rename failed:%v", q)
+	}
+	if q.Get("debug") != "" {
+		t.Errorf("remove failed:%v", q)
+	}
+	if q.Get("source") != "gateway" || q.Get("page") != "2" {
+		t.Errorf("add failed:%v", q)
+	}
+	if org.Get("uid") != "1" {
+		t.Errorf("origin query modified:%v", org)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	r, err := New(&config.RewriteConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		t.Fatal("empty config should not create rewriter")
+	}
+	if r.Path("/a") != "/a" {
+		t.Fatal("nil rewriter should keep path")
+	}
+	if _, err := New(&config.RewriteConfig{Regex: "("}); err == nil {
+		t.Fatal("invalid regex should return error")
+	}
+}
@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/auth"
+	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
+)
+
+// pluginAuthenticator 没有内置实现的鉴权方式（如Oauth2）仍由鉴权插件处理，需通过 Authorization-Type 指定
+type pluginAuthenticator struct {
+	name     string
+	executor plugin_executor.Executor
+}
+
+func (p *pluginAuthenticator) Detect(ctx *common.Context) bool {
+	return ctx.Request().GetHeader(auth.TypeHeader) == p.name
+}
+
+func (p *pluginAuthenticator) Authenticate(ctx *common.Context) (string, error) {
+	isContinue, err := p.executor.Execute(ctx)
+	if !isContinue {
+		if err == nil {
+			err = auth.ErrorInvalidCredential
+		}
+		return "", err
+	}
+	return strings.ToLower(p.name), nil
+}
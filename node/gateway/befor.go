@@ -12,11 +12,12 @@ import (
 
 //Before before
 type Before struct {
-	pluginBefor       []plugin_executor.Executor
-	pluginGlobalBefor []plugin_executor.Executor
+	pluginBefor plugin_executor.Pipeline
 
 	strategies        map[string]*Strategy
 	anonymousStrategy string
+	// 读取请求体的大小上限，为0时不限制
+	maxRequestBody int64
 }
 
 //Router 路由
@@ -34,29 +35,10 @@ func (r *Before) Router(w http.ResponseWriter, req *http.Request, ctx *common.Co
 //BeforeMatch 插件流程，匹配URI及策略前执行
 func (r *Before) BeforeMatch(ctx *common.Context) bool {
 	requestID := ctx.RequestId()
-	defer func(ctx *common.Context) {
-		log.Debug(requestID, " before plugin default: begin")
-		for _, handler := range r.pluginGlobalBefor {
-
-			_, _ = handler.Execute(ctx)
-
-		}
-		log.Debug(requestID, " before plugin default: end")
-	}(ctx)
 	log.Debug(requestID, " before plugin : begin")
-	for _, handler := range r.pluginBefor {
-
-		flag, _ := handler.Execute(ctx)
-
-		if flag == false {
-			if handler.IsStop() == true {
-				return false
-			}
-		}
-	}
+	isContinue := r.pluginBefor.Execute(ctx)
 	log.Debug(requestID, " before plugin : end")
-	return true
-
+	return isContinue
 }
 
 func (r *Before) rout(w http.ResponseWriter, req *http.Request, ctx *common.Context) {
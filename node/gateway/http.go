@@ -9,6 +9,7 @@ import (
 	"github.com/eolinker/goku-api-gateway/diting"
 	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
 	"github.com/eolinker/goku-api-gateway/node/monitor"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	access_log "github.com/eolinker/goku-api-gateway/goku-node/access-log"
@@ -29,6 +30,11 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// 记录访问次数
 	requestID := utils.GetRandomString(16)
 
+	var body *size_limit.BodyReader
+	if h.router.maxRequestBody > 0 {
+		body = size_limit.LimitRequest(req, h.router.maxRequestBody)
+	}
+
 	ctx := common.NewContext(req, requestID, w)
 
 	log.Debug(requestID, " url: ", ctx.Request().URL().String())
@@ -50,7 +56,11 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		ctx.LogFields[fields.HTTPXForwardedFor] = realIP
 	}
 
-	h.router.Router(w, req, ctx)
+	if body != nil && body.Exceeded() {
+		size_limit.TooLarge(ctx)
+	} else {
+		h.router.Router(w, req, ctx)
+	}
 
 	n, status := ctx.Finish()
 
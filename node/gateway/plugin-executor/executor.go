@@ -1,6 +1,8 @@
 package plugin_executor
 
 import (
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/eolinker/goku-api-gateway/config"
@@ -9,16 +11,36 @@ import (
 	goku_plugin "github.com/eolinker/goku-plugin"
 )
 
+const (
+	//PhaseBefore 路由匹配前
+	PhaseBefore = "before"
+	//PhaseAccess 转发前
+	PhaseAccess = "access"
+	//PhaseProxy 转发后
+	PhaseProxy = "proxy"
+)
+
 //Executor executor
 type Executor interface {
 	Execute(ctx *common.Context) (isContinue bool, e error)
 	IsStop() bool
 	IsAuth() bool
+	PluginName() string
+	Phase() string
+	Priority() int
+	//OnError 插件返回错误时按错误处理策略处理，返回是否继续执行后续插件
+	OnError(ctx *common.Context, isContinue bool, err error) bool
 }
 type executorInfo struct {
-	Name   string
-	isStop bool
-	isAuth bool
+	Name     string
+	phase    string
+	priority int
+	isStop   bool
+	isAuth   bool
+
+	errorPolicy string
+	errorStatus int
+	errorBody   string
 }
 
 func (ex *executorInfo) IsStop() bool {
@@ -29,11 +51,54 @@ func (ex *executorInfo) IsAuth() bool {
 	return ex.isAuth
 }
 
-func genExecutor(cfg *config.PluginConfig) executorInfo {
+func (ex *executorInfo) PluginName() string {
+	return ex.Name
+}
+
+func (ex *executorInfo) Phase() string {
+	return ex.phase
+}
+
+func (ex *executorInfo) Priority() int {
+	return ex.priority
+}
+
+func (ex *executorInfo) OnError(ctx *common.Context, isContinue bool, err error) bool {
+	status := ex.errorStatus
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	switch ex.errorPolicy {
+	case config.PluginErrorContinue:
+		return true
+	case config.PluginErrorResponse:
+		ctx.SetStatus(status, strconv.Itoa(status))
+		ctx.SetBody([]byte(ex.errorBody))
+		return false
+	case config.PluginErrorStop:
+	default:
+		// 未配置错误处理策略时，插件要求中断且isStop为true才中断
+		if isContinue || !ex.isStop {
+			return true
+		}
+	}
+	if ctx.StatusCode() == 0 {
+		ctx.SetStatus(status, strconv.Itoa(status))
+		ctx.SetBody([]byte(err.Error()))
+	}
+	return false
+}
+
+func genExecutor(cfg *config.PluginConfig, phase string) executorInfo {
 	return executorInfo{
-		Name:   cfg.Name,
-		isStop: cfg.IsStop,
-		isAuth: cfg.IsAuth,
+		Name:        cfg.Name,
+		phase:       phase,
+		priority:    cfg.Priority,
+		isStop:      cfg.IsStop,
+		isAuth:      cfg.IsAuth,
+		errorPolicy: cfg.ErrorPolicy,
+		errorStatus: cfg.ErrorStatus,
+		errorBody:   cfg.ErrorBody,
 	}
 }
 
@@ -60,7 +125,7 @@ func (ex *beforeExecutor) Execute(ctx *common.Context) (isContinue bool, e error
 //NewBeforeExecutor 创建before阶段执行器
 func NewBeforeExecutor(cfg *config.PluginConfig, p goku_plugin.PluginBeforeMatch) *beforeExecutor {
 	return &beforeExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, PhaseBefore),
 		plugin:       p,
 	}
 
@@ -89,7 +154,7 @@ func (ex *accessExecutor) Execute(ctx *common.Context) (isContinue bool, e error
 //NewAccessExecutor 创建access阶段执行器
 func NewAccessExecutor(cfg *config.PluginConfig, p goku_plugin.PluginAccess) *accessExecutor {
 	return &accessExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, PhaseAccess),
 		plugin:       p,
 	}
 }
@@ -118,7 +183,7 @@ func (ex *proxyExecutor) Execute(ctx *common.Context) (isContinue bool, e error)
 //NewProxyExecutor 创建proxy阶段执行器
 func NewProxyExecutor(cfg *config.PluginConfig, p goku_plugin.PluginProxy) *proxyExecutor {
 	return &proxyExecutor{
-		executorInfo: genExecutor(cfg),
+		executorInfo: genExecutor(cfg, PhaseProxy),
 		plugin:       p,
 	}
 }
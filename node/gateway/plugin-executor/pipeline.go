@@ -0,0 +1,62 @@
+package plugin_executor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+//Pipeline 同一阶段的插件执行链，按优先级从高到低执行，优先级相同时保持传入顺序
+type Pipeline []Executor
+
+//NewPipeline 合并多组插件并按优先级排序
+func NewPipeline(groups ...[]Executor) Pipeline {
+	size := 0
+	for _, g := range groups {
+		size += len(g)
+	}
+	p := make(Pipeline, 0, size)
+	for _, g := range groups {
+		p = append(p, g...)
+	}
+	sort.SliceStable(p, func(i, j int) bool {
+		return p[i].Priority() > p[j].Priority()
+	})
+	return p
+}
+
+//Execute 依次执行插件，返回是否继续处理请求
+func (p Pipeline) Execute(ctx *common.Context) bool {
+	for _, ex := range p {
+		start := time.Now()
+		isContinue, err := ex.Execute(ctx)
+		observe(ex, time.Since(start), err)
+		if err != nil {
+			if !ex.OnError(ctx, isContinue, err) {
+				return false
+			}
+			continue
+		}
+		if !isContinue && ex.IsStop() {
+			return false
+		}
+	}
+	return true
+}
+
+func observe(ex Executor, delay time.Duration, err error) {
+	if monitor.PluginMonitor == nil {
+		return
+	}
+	labels := make(diting.Labels)
+	labels[goku_labels.Plugin] = ex.PluginName()
+	labels[goku_labels.Phase] = ex.Phase()
+	monitor.PluginMonitor.Observe(float64(delay)/float64(time.Millisecond), labels)
+	if err != nil && monitor.PluginErrors != nil {
+		monitor.PluginErrors.Add(1, labels)
+	}
+}
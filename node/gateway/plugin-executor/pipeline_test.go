@@ -0,0 +1,107 @@
+package plugin_executor
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+type testExecutor struct {
+	executorInfo
+	isContinue bool
+	err        error
+	calls      *[]string
+}
+
+func (ex *testExecutor) Execute(ctx *common.Context) (bool, error) {
+	*ex.calls = append(*ex.calls, ex.Name)
+	return ex.isContinue, ex.err
+}
+
+func newTestExecutor(cfg *config.PluginConfig, isContinue bool, err error, calls *[]string) Executor {
+	return &testExecutor{
+		executorInfo: genExecutor(cfg, PhaseAccess),
+		isContinue:   isContinue,
+		err:          err,
+		calls:        calls,
+	}
+}
+
+func newTestContext() (*common.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	return common.NewContext(httptest.NewRequest("GET", "/", nil), "test", w), w
+}
+
+func TestPipelineOrder(t *testing.T) {
+	calls := make([]string, 0)
+	p := NewPipeline(
+		[]Executor{
+			newTestExecutor(&config.PluginConfig{Name: "a", Priority: 1}, true, nil, &calls),
+			newTestExecutor(&config.PluginConfig{Name: "b", Priority: 3}, true, nil, &calls),
+		},
+		[]Executor{
+			newTestExecutor(&config.PluginConfig{Name: "c", Priority: 2}, true, nil, &calls),
+			newTestExecutor(&config.PluginConfig{Name: "d", Priority: 1}, true, nil, &calls),
+		},
+	)
+	ctx, _ := newTestContext()
+	if !p.Execute(ctx) {
+		t.Fatal("expected continue")
+	}
+	if got := len(calls); got != 4 || calls[0] != "b" || calls[1] != "c" || calls[2] != "a" || calls[3] != "d" {
+		t.Errorf("unexpected order:%v", calls)
+	}
+}
+
+func TestPipelineErrorPolicy(t *testing.T) {
+	e := errors.New("plugin error")
+	cases := []struct {
+		cfg        *config.PluginConfig
+		isContinue bool
+		want       bool
+		status     int
+		body       string
+	}{
+		{&config.PluginConfig{Name: "default"}, false, true, 0, ""},
+		{&config.PluginConfig{Name: "stop", IsStop: true}, false, false, 500, "plugin error"},
+		{&config.PluginConfig{Name: "continue", IsStop: true, ErrorPolicy: config.PluginErrorContinue}, false, true, 0, ""},
+		{&config.PluginConfig{Name: "stopStatus", ErrorPolicy: config.PluginErrorStop, ErrorStatus: 502}, true, false, 502, "plugin error"},
+		{&config.PluginConfig{Name: "response", ErrorPolicy: config.PluginErrorResponse, ErrorStatus: 503, ErrorBody: "busy"}, true, false, 503, "busy"},
+	}
+	for _, c := range cases {
+		calls := make([]string, 0)
+		p := NewPipeline([]Executor{
+			newTestExecutor(c.cfg, c.isContinue, e, &calls),
+			newTestExecutor(&config.PluginConfig{Name: "next"}, true, nil, &calls),
+		})
+		ctx, _ := newTestContext()
+		if got := p.Execute(ctx); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.cfg.Name, got, c.want)
+		}
+		if ctx.StatusCode() != c.status || string(ctx.GetBody()) != c.body {
+			t.Errorf("%s: unexpected response %d %s", c.cfg.Name, ctx.StatusCode(), ctx.GetBody())
+		}
+		if c.want != (len(calls) == 2) {
+			t.Errorf("%s: unexpected calls %v", c.cfg.Name, calls)
+		}
+	}
+}
+
+func TestPipelineStop(t *testing.T) {
+	calls := make([]string, 0)
+	p := NewPipeline([]Executor{
+		newTestExecutor(&config.PluginConfig{Name: "a"}, false, nil, &calls),
+		newTestExecutor(&config.PluginConfig{Name: "b", IsStop: true}, false, nil, &calls),
+		newTestExecutor(&config.PluginConfig{Name: "c"}, true, nil, &calls),
+	})
+	ctx, _ := newTestContext()
+	if p.Execute(ctx) {
+		t.Error("expected stop")
+	}
+	if len(calls) != 2 {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
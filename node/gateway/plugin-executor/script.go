@@ -0,0 +1,44 @@
+package plugin_executor
+
+import (
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/script"
+)
+
+type scriptExecutor struct {
+	executorInfo
+	programs []*script.Program
+}
+
+//Execute execute
+func (ex *scriptExecutor) Execute(ctx *common.Context) (isContinue bool, e error) {
+	requestID := ctx.RequestId()
+	for _, p := range ex.programs {
+		ctx.SetPlugin(ex.Name + ":" + p.Name)
+		log.Debug(requestID, " ", ex.phase, " script :", p.Name, " start")
+		now := time.Now()
+		isContinue, err := p.Execute(ex.phase, ctx)
+		log.Debug(requestID, " ", ex.phase, " script :", p.Name, " Duration:", time.Since(now))
+		log.Debug(requestID, " ", ex.phase, " script :", p.Name, " end")
+		if err != nil {
+			log.Warn(requestID, " ", ex.phase, " script:", p.Name, " error:", err)
+			return false, err
+		}
+		if !isContinue {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+//NewScriptExecutor 创建脚本执行器，按顺序执行脚本中指定阶段的处理函数
+func NewScriptExecutor(cfg *config.PluginConfig, phase string, programs []*script.Program) *scriptExecutor {
+	return &scriptExecutor{
+		executorInfo: genExecutor(cfg, phase),
+		programs:     programs,
+	}
+}
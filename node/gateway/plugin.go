@@ -1,24 +1,35 @@
 package gateway
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
 	plugin "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	"github.com/eolinker/goku-api-gateway/node/script"
 )
 
 func genBeforPlugin(cfgs []*config.PluginConfig, cluster string) []plugin_executor.Executor {
 	ps := make([]plugin_executor.Executor, 0, len(cfgs))
 	for _, cfg := range cfgs {
+		if config.IsScriptPlugin(cfg.Name) {
+			if programs := genScripts(cfg, script.PhaseBefore); len(programs) > 0 {
+				ps = append(ps, plugin_executor.NewScriptExecutor(cfg, script.PhaseBefore, programs))
+			}
+			continue
+		}
 
 		factory, e := plugin.LoadPlugin(cfg.Name)
 		if e != nil {
+			log.Warn("load plugin ", cfg.Name, " error:", e)
 			continue
 		}
 		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, "", 0)
 		if err != nil {
+			log.Warn("create plugin ", cfg.Name, " error:", err)
 			continue
 		}
 
@@ -34,13 +45,27 @@ func genPlugins(cfgs []*config.PluginConfig, cluster string, strategyID string,
 	psAccess := make([]plugin_executor.Executor, 0, len(cfgs))
 	psProxy := make([]plugin_executor.Executor, 0, len(cfgs))
 	for _, cfg := range cfgs {
+		if config.IsScriptPlugin(cfg.Name) {
+			if programs := genScripts(cfg, script.PhaseBefore); len(programs) > 0 {
+				psBefor = append(psBefor, plugin_executor.NewScriptExecutor(cfg, script.PhaseBefore, programs))
+			}
+			if programs := genScripts(cfg, script.PhaseAccess); len(programs) > 0 {
+				psAccess = append(psAccess, plugin_executor.NewScriptExecutor(cfg, script.PhaseAccess, programs))
+			}
+			if programs := genScripts(cfg, script.PhaseProxy); len(programs) > 0 {
+				psProxy = append(psProxy, plugin_executor.NewScriptExecutor(cfg, script.PhaseProxy, programs))
+			}
+			continue
+		}
 
 		factory, e := plugin.LoadPlugin(cfg.Name)
 		if e != nil {
+			log.Warn("load plugin ", cfg.Name, " error:", e)
 			continue
 		}
 		obj, err := factory.Create(cfg.Config, cluster, cfg.UpdateTag, strategyID, apiID)
 		if err != nil {
+			log.Warn("create plugin ", cfg.Name, " of strategy ", strategyID, " error:", err)
 			continue
 		}
 
@@ -60,3 +85,27 @@ func genPlugins(cfgs []*config.PluginConfig, cluster string, strategyID string,
 	return psBefor, psAccess, psProxy
 
 }
+
+// genScripts 获取脚本插件绑定的脚本中定义了该阶段处理函数的脚本
+func genScripts(cfg *config.PluginConfig, phase string) []*script.Program {
+	scriptConfig := new(config.ScriptPluginConfig)
+	if cfg.Config == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(cfg.Config), scriptConfig); err != nil {
+		log.Warn("parse config of plugin ", cfg.Name, " error:", err)
+		return nil
+	}
+	programs := make([]*script.Program, 0, len(scriptConfig.Scripts))
+	for _, name := range scriptConfig.Scripts {
+		p, has := script.Get(name)
+		if !has {
+			log.Warn("script ", name, " of plugin ", cfg.Name, " not found")
+			continue
+		}
+		if p.Has(phase) {
+			programs = append(programs, p)
+		}
+	}
+	return programs
+}
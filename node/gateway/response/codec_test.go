@@ -0,0 +1,68 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestXMLCodec(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><user id="1"><name>goku</name><tag>a</tag><tag>b</tag><note lang="en">hi</note></user>`)
+	var v interface{}
+	if err := GetDecoder("XML")(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(v)
+	want := `{"user":{"@id":"1","name":"goku","note":{"#text":"hi","@lang":"en"},"tag":["a","b"]}}`
+	if string(got) != want {
+		t.Errorf("decode got %s\nwant %s", got, want)
+	}
+
+	out, err := GetEncoder(XML).Encode(v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXML := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<user id="1"><name>goku</name><note lang="en">hi</note><tag>a</tag><tag>b</tag></user>`
+	if string(out) != wantXML {
+		t.Errorf("encode got %s\nwant %s", out, wantXML)
+	}
+
+	out, err = GetEncoder(XML).Encode(map[string]interface{}{"a": 1000000.0, "b c": []interface{}{true, nil}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXML = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><a>1000000</a><b_c>true</b_c><b_c/></response>`
+	if string(out) != wantXML {
+		t.Errorf("encode got %s\nwant %s", out, wantXML)
+	}
+}
+
+func TestFormAndYAMLCodec(t *testing.T) {
+	var v interface{}
+	if err := GetDecoder(Form)([]byte("a=1&b=2&b=3"), &v); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(v)
+	if string(got) != `{"a":"1","b":["2","3"]}` {
+		t.Errorf("form decode got %s", got)
+	}
+	out, _ := GetEncoder(Form).Encode(map[string]interface{}{"a": map[string]interface{}{"b": 1.0}, "c": []interface{}{"x", "y"}}, nil)
+	if string(out) != "a.b=1&c=x&c=y" {
+		t.Errorf("form encode got %s", out)
+	}
+
+	v = nil
+	if err := GetDecoder(YAML)([]byte("a:\n  b: [1, 2]\n  3: c\n"), &v); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = json.Marshal(v)
+	if string(got) != `{"a":{"3":"c","b":[1,2]}}` {
+		t.Errorf("yaml decode got %s", got)
+	}
+
+	if GetDecoder("protobuf") != nil {
+		t.Error("protobuf decoder without message should be nil")
+	}
+	if GetEncoder("protobuf:demo.User").ContentType() != "application/x-protobuf" {
+		t.Error("expected protobuf encoder")
+	}
+}
@@ -2,9 +2,13 @@ package response
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
 	"strings"
 
+	proto_codec "github.com/eolinker/goku-api-gateway/common/proto-codec"
 	"github.com/eolinker/goku-api-gateway/utils"
+	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -16,6 +20,12 @@ const (
 	String = "string"
 	//JSONNoQuote 非标准json（key不带双引号）
 	JSONNoQuote = "json-noquote"
+	//Form application/x-www-form-urlencoded
+	Form = "form"
+	//YAML yaml
+	YAML = "yaml"
+	//Protobuf protobuf，使用 protobuf:包名.消息名 指定消息类型
+	Protobuf = "protobuf"
 )
 
 var (
@@ -31,16 +41,112 @@ var (
 		err = json.Unmarshal(d, v)
 		return err
 	}
+	xmlDecoder = func(data []byte, v interface{}) error {
+		o, err := DecodeXML(data)
+		if err != nil {
+			return err
+		}
+		return setValue(v, o)
+	}
+	formDecoder = func(data []byte, v interface{}) error {
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return err
+		}
+		o := make(map[string]interface{}, len(values))
+		for k, vs := range values {
+			if len(vs) == 1 {
+				o[k] = vs[0]
+				continue
+			}
+			list := make([]interface{}, 0, len(vs))
+			for _, s := range vs {
+				list = append(list, s)
+			}
+			o[k] = list
+		}
+		return setValue(v, o)
+	}
+	yamlDecoder = func(data []byte, v interface{}) error {
+		var o interface{}
+		err := yaml.Unmarshal(data, &o)
+		if err != nil {
+			return err
+		}
+		return setValue(v, normalizeYAML(o))
+	}
 )
 
+func protobufDecoder(message string) DecodeHandle {
+	return func(data []byte, v interface{}) error {
+		o, err := proto_codec.Default().Decode(message, data)
+		if err != nil {
+			return err
+		}
+		return setValue(v, o)
+	}
+}
+
+func setValue(v interface{}, o interface{}) error {
+	p, ok := v.(*interface{})
+	if !ok {
+		d, err := json.Marshal(o)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(d, v)
+	}
+	*p = o
+	return nil
+}
+
+// normalizeYAML 将yaml解析出的 map[interface{}]interface{} 转换为 map[string]interface{}
+func normalizeYAML(v interface{}) interface{} {
+	switch o := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(o))
+		for k, item := range o {
+			m[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range o {
+			o[i] = normalizeYAML(item)
+		}
+		return o
+	}
+	return v
+}
+
+// splitCodec 拆分 protobuf:demo.User 形式的编解码名称
+func splitCodec(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	i := strings.Index(name, ":")
+	if i < 0 {
+		return strings.ToLower(name), ""
+	}
+	return strings.ToLower(name[:i]), strings.TrimSpace(name[i+1:])
+}
+
 //GetDecoder getDecoder
 func GetDecoder(decoder string) DecodeHandle {
 
-	switch strings.ToLower(decoder) {
+	name, message := splitCodec(decoder)
+	switch name {
 	case JSON:
 		return jsonDecoder
 	case JSONNoQuote:
 		return jsonNoQuoteDecoder
+	case XML:
+		return xmlDecoder
+	case Form:
+		return formDecoder
+	case YAML:
+		return yamlDecoder
+	case Protobuf:
+		if message != "" {
+			return protobufDecoder(message)
+		}
 	}
 
 	return nil
@@ -2,9 +2,12 @@ package response
 
 import (
 	"encoding/json"
-	"encoding/xml"
+	"net/url"
+	"sort"
+	"strconv"
 
-	"strings"
+	proto_codec "github.com/eolinker/goku-api-gateway/common/proto-codec"
+	"gopkg.in/yaml.v2"
 )
 
 var (
@@ -17,7 +20,21 @@ var (
 	xmlEncoder = &EncoderH{
 		contentType: "text/xml; charset=utf-8",
 		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
-			return xml.Marshal(v)
+			return EncodeXML(v)
+		},
+	}
+	formEncoder = &EncoderH{
+		contentType: "application/x-www-form-urlencoded",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			values := url.Values{}
+			flattenForm(values, "", v)
+			return []byte(values.Encode()), nil
+		},
+	}
+	yamlEncoder = &EncoderH{
+		contentType: "application/x-yaml",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			return yaml.Marshal(v)
 		},
 	}
 	stringEncoder = &EncoderH{
@@ -35,6 +52,52 @@ var (
 	}
 )
 
+func protobufEncoder(message string) Encoder {
+	return &EncoderH{
+		contentType: "application/x-protobuf",
+		handleFunc: func(v interface{}, org []byte) ([]byte, error) {
+			return proto_codec.Default().Encode(message, v)
+		},
+	}
+}
+
+// flattenForm 将通用结构展开为 a.b.0=value 形式的表单字段
+func flattenForm(values url.Values, prefix string, v interface{}) {
+	switch o := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenForm(values, key, o[k])
+		}
+	case []interface{}:
+		for i, item := range o {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenForm(values, prefix+"."+strconv.Itoa(i), item)
+			default:
+				flattenForm(values, prefix, item)
+			}
+		}
+	case nil:
+		if prefix != "" {
+			values.Add(prefix, "")
+		}
+	default:
+		if prefix == "" {
+			prefix = XMLDefaultRoot
+		}
+		values.Add(prefix, scalarString(o))
+	}
+}
+
 //EncoderH encodeH
 type EncoderH struct {
 	contentType string
@@ -54,13 +117,22 @@ func (e *EncoderH) ContentType() string {
 //GetEncoder 获取编码器
 func GetEncoder(encoder string) Encoder {
 
-	switch strings.ToLower(encoder) {
+	name, message := splitCodec(encoder)
+	switch name {
 	case JSON:
 		return jsonEncoder
 	case XML:
 		return xmlEncoder
 	case String:
 		return stringEncoder
+	case Form:
+		return formEncoder
+	case YAML:
+		return yamlEncoder
+	case Protobuf:
+		if message != "" {
+			return protobufEncoder(message)
+		}
 	}
 	return notEncoder
 }
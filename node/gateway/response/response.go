@@ -185,3 +185,65 @@ func (r *Response) Move(source, target string) {
 	}
 
 }
+
+//Transform 对匹配的节点执行handler，并以返回值替换原值
+func (r *Response) Transform(pattern string, handler func(value interface{}) interface{}) {
+	if pattern == "" {
+		r.Data = handler(r.Data)
+		return
+	}
+	root := _Node{
+		data: r.Data,
+	}
+
+	root.Pattern(pattern, func(node *_Node) bool {
+		if node.parent == nil {
+			return false
+		}
+		parent := node.parent
+		switch parent.data.(type) {
+		case []interface{}:
+			sl := parent.data.([]interface{})
+			sl[node.index] = handler(node.data)
+
+		case map[string]interface{}:
+			mp := parent.data.(map[string]interface{})
+			mp[node.key] = handler(node.data)
+		}
+		return false
+	})
+}
+
+//SetDefault 目标不存在或为null时设置默认值，如果上级路径不存在，会对路径进行创建
+func (r *Response) SetDefault(pattern string, value interface{}) {
+	if pattern == "" {
+		if r.Data == nil {
+			r.Data = value
+		}
+		return
+	}
+	parentPattern, key := "", pattern
+	if index := strings.LastIndex(pattern, "."); index != -1 {
+		parentPattern, key = pattern[:index], pattern[index+1:]
+	}
+
+	if r.Data == nil {
+		r.Data = make(map[string]interface{})
+	}
+	root := _Node{
+		data: r.Data,
+	}
+	if parentPattern != "" {
+		root.Make(strings.Split(parentPattern, "."))
+	}
+	root.Pattern(parentPattern, func(node *_Node) bool {
+		mp, ok := node.data.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if v, has := mp[key]; !has || v == nil {
+			mp[key] = value
+		}
+		return false
+	})
+}
@@ -0,0 +1,212 @@
+package response
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//XMLAttrPrefix xml属性在通用结构中的key前缀
+	XMLAttrPrefix = "@"
+	//XMLTextKey 同时存在属性或子节点时，节点文本在通用结构中的key
+	XMLTextKey = "#text"
+	//XMLDefaultRoot 无法确定根节点名称时使用的默认名称
+	XMLDefaultRoot = "response"
+	//XMLItemName 数组根节点的子节点名称
+	XMLItemName = "item"
+)
+
+var (
+	//ErrorEmptyXML 空xml文档
+	ErrorEmptyXML = errors.New("empty xml document")
+)
+
+//DecodeXML 将xml解码为通用结构，根节点作为唯一的key，属性使用 @ 前缀，重复的节点转换为数组
+func DecodeXML(data []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			return nil, ErrorEmptyXML
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			v, err := decodeXMLElement(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: v}, nil
+		}
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	node := make(map[string]interface{}, len(start.Attr))
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		node[XMLAttrPrefix+attr.Name.Local] = attr.Value
+	}
+	text := strings.Builder{}
+	hasChild := false
+	for {
+		t, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tk := t.(type) {
+		case xml.StartElement:
+			hasChild = true
+			child, err := decodeXMLElement(decoder, tk)
+			if err != nil {
+				return nil, err
+			}
+			name := tk.Name.Local
+			if exist, has := node[name]; has {
+				if list, ok := exist.([]interface{}); ok {
+					node[name] = append(list, child)
+				} else {
+					node[name] = []interface{}{exist, child}
+				}
+			} else {
+				node[name] = child
+			}
+		case xml.CharData:
+			text.Write(tk)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if !hasChild && len(node) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				node[XMLTextKey] = content
+			}
+			return node, nil
+		}
+	}
+}
+
+//EncodeXML 将通用结构编码为xml，只有一个key的对象会使用该key作为根节点
+func EncodeXML(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+
+	root, value := XMLDefaultRoot, v
+	if m, ok := v.(map[string]interface{}); ok && len(m) == 1 {
+		for k, child := range m {
+			if _, isList := child.([]interface{}); !isList && !strings.HasPrefix(k, XMLAttrPrefix) && k != XMLTextKey {
+				root, value = k, child
+			}
+		}
+	}
+	if list, ok := value.([]interface{}); ok {
+		value = map[string]interface{}{XMLItemName: list}
+	}
+	if err := encodeXMLElement(buf, root, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(buf *bytes.Buffer, name string, v interface{}) error {
+	name = xmlName(name)
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if err := encodeXMLElement(buf, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	buf.WriteString("<")
+	buf.WriteString(name)
+
+	m, isMap := v.(map[string]interface{})
+	if !isMap {
+		if v == nil {
+			buf.WriteString("/>")
+			return nil
+		}
+		buf.WriteString(">")
+		if err := xml.EscapeText(buf, []byte(scalarString(v))); err != nil {
+			return err
+		}
+		buf.WriteString("</" + name + ">")
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !strings.HasPrefix(k, XMLAttrPrefix) {
+			continue
+		}
+		buf.WriteString(" " + xmlName(strings.TrimPrefix(k, XMLAttrPrefix)) + `="`)
+		if err := xml.EscapeText(buf, []byte(scalarString(m[k]))); err != nil {
+			return err
+		}
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	if text, has := m[XMLTextKey]; has && text != nil {
+		if err := xml.EscapeText(buf, []byte(scalarString(text))); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		if strings.HasPrefix(k, XMLAttrPrefix) || k == XMLTextKey {
+			continue
+		}
+		if err := encodeXMLElement(buf, k, m[k]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+// xmlName 将key转换为合法的xml名称
+func xmlName(name string) string {
+	if name == "" {
+		return XMLItemName
+	}
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || c == '-' || c == '.' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	if c := b[0]; c >= '0' && c <= '9' || c == '-' || c == '.' {
+		return "_" + string(b)
+	}
+	return string(b)
+}
+
+// scalarString 将基础类型转换为字符串，避免浮点数输出为科学计数法
+func scalarString(v interface{}) string {
+	switch o := v.(type) {
+	case string:
+		return o
+	case float64:
+		return strconv.FormatFloat(o, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(o), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
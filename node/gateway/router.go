@@ -2,30 +2,46 @@ package gateway
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
 
+	"github.com/eolinker/goku-api-gateway/common/openapi"
 	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-service/balance"
 	"github.com/eolinker/goku-api-gateway/goku-service/discovery"
+	"github.com/eolinker/goku-api-gateway/node/auth"
+	config_validator "github.com/eolinker/goku-api-gateway/node/config-validator"
 	"github.com/eolinker/goku-api-gateway/node/gateway/application"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
 	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
+	rate_limit "github.com/eolinker/goku-api-gateway/node/rate-limit"
+	request_validator "github.com/eolinker/goku-api-gateway/node/request-validator"
+	response_cache "github.com/eolinker/goku-api-gateway/node/response-cache"
 	"github.com/eolinker/goku-api-gateway/node/router"
+	size_limit "github.com/eolinker/goku-api-gateway/node/size-limit"
 )
 
 var (
 	errorConfig = errors.New("config is error")
 )
 
+func loadPlugin(name string) error {
+	_, err := plugin_loader.LoadPlugin(name)
+	return err
+}
+
 //Parse 解析
 func Parse(config *config.GokuConfig, factory router.Factory) (http.Handler, error) {
 
 	if config == nil {
 		return nil, errorConfig
 	}
+	// 配置问题只记录告警，不阻止节点加载其余可用的配置
+	for _, p := range config_validator.Validate(config, &config_validator.Options{Drivers: discovery.AllDrivers(), LoadPlugin: loadPlugin}) {
+		log.Warn("config problem ", p.String())
+	}
 
 	f := genFactory(config, factory)
 
@@ -42,17 +58,33 @@ type _RootFactory struct {
 	appFactory    *application.Factory
 	routerFactory router.Factory
 	cluster       string
+	rateLimits    []*rateLimitRule
+	sizeLimits    []*config.SizeLimitConfig
+	openAPIs      map[string]*openapi.Document
 
 	authPlugin map[string]string
 }
 
+type rateLimitRule struct {
+	strategyID string
+	apiID      int
+	rule       *rate_limit.Rule
+}
+
 func (f *_RootFactory) create() *Before {
 	beforeRouter := &Before{
-		pluginBefor:       f.beforePlugin,
-		pluginGlobalBefor: f.gBefores,
+		pluginBefor:       plugin_executor.NewPipeline(f.beforePlugin, f.gBefores),
 		strategies:        f.createStrategy(),
 		anonymousStrategy: f.orgCfg.AnonymousStrategyID,
 	}
+	// 请求体在路由匹配前读取，按各路由中最大的请求体限制读取
+	routes := make(map[string][]int)
+	for _, s := range f.orgCfg.Strategy {
+		for _, api := range s.APIS {
+			routes[s.ID] = append(routes[s.ID], api.ID)
+		}
+	}
+	beforeRouter.maxRequestBody = size_limit.ReadLimit(f.sizeLimits, routes)
 
 	return beforeRouter
 }
@@ -77,9 +109,8 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 		Name:   cfg.Name,
 		Enable: cfg.Enable,
 
-		accessPlugin:       accesses,
-		globalAccessPlugin: f.gAccesses,
-		authPlugin:         make(map[string]plugin_executor.Executor),
+		accessPlugin:       plugin_executor.NewPipeline(withoutAuth(accesses)),
+		globalAccessPlugin: plugin_executor.NewPipeline(f.gAccesses),
 		isNeedAuth:         false,
 	}
 	if !s.Enable {
@@ -87,32 +118,18 @@ func (f *_RootFactory) genStrategy(cfg *config.StrategyConfig) *Strategy {
 	}
 	s.apiRouter = f.routerFactory.New()
 	s.apiRouter.AddNotFound(s.HandlerAPINotFound)
+	authenticators := make(map[string]auth.Authenticator, len(cfg.AUTH))
 	for authKey, authCfg := range cfg.AUTH {
 		s.isNeedAuth = true
-		pluginName, has := f.authPlugin[authKey]
-		if has {
-			pluginFactory, e := plugin_loader.LoadPlugin(pluginName)
-			if e != nil {
-				continue
-			}
-			pluginObj, err := pluginFactory.Create(authCfg, f.cluster, "", s.ID, 0)
-			if err != nil {
-				continue
-			}
-
-			if pluginObj.Access != nil && !reflect.ValueOf(pluginObj.Access).IsNil() {
-				if cfg.ID == "68YAY7" {
-					fmt.Println(authKey, pluginName)
-				}
-				s.authPlugin[authKey] = plugin_executor.NewAccessExecutor(&config.PluginConfig{
-					Name:   pluginName,
-					IsStop: true,
-					Config: authCfg,
-					IsAuth: true,
-				}, pluginObj.Access)
-			}
+		a, err := f.genAuthenticator(s.ID, authKey, authCfg)
+		if err != nil {
+			// 鉴权方式不可用时拒绝请求，避免跳过该鉴权方式
+			log.Warn("create auth ", authKey, " of strategy ", s.ID, " error:", err)
+			a = auth.Reject(errors.New("auth " + authKey + " is not available"))
 		}
+		authenticators[authKey] = a
 	}
+	s.authChain = auth.NewChain(cfg.AuthPolicy, authenticators)
 
 	factory := newAPIFactory(f, s.ID)
 	for _, apiCfg := range cfg.APIS {
@@ -153,21 +170,103 @@ func (f *_ApiFactory) genAPIRouter(cfg *config.APIOfStrategy, proxies []plugin_e
 		return nil, nil
 	}
 	_, pluginAccesses, pluginProxies := genPlugins(cfg.Plugins, f.root.cluster, f.strategyID, cfg.ID)
-	pro := make([]plugin_executor.Executor, 0, len(proxies)+len(pluginProxies))
-	pro = append(pro, proxies...)
-	pro = append(pro, pluginProxies...)
+
+	validator, err := request_validator.New(apiContend.Validation, f.root.openAPIs)
+	if err != nil {
+		log.Warn("create validator of api ", cfg.ID, " error:", err)
+	}
 
 	return &API{
-		strategyID:          f.strategyID,
-		apiID:               cfg.ID,
-		app:                 app,
-		pluginAccess:        pluginAccesses,
-		pluginProxies:       pro,
-		pluginAccessGlobal:  f.root.gAccesses,
-		pluginProxiesGlobal: f.root.gProxies,
+		strategyID:    f.strategyID,
+		apiID:         cfg.ID,
+		app:           app,
+		sizeLimit:     f.genSizeLimit(cfg.ID),
+		limiter:       f.genLimiter(cfg.ID),
+		validator:     validator,
+		cache:         response_cache.NewCache(cfg.ID, apiContend.Cache),
+		pluginAccess:  plugin_executor.NewPipeline(pluginAccesses, f.root.gAccesses),
+		pluginProxies: plugin_executor.NewPipeline(proxies, pluginProxies, f.root.gProxies),
 	}, apiContend
 }
 
+// genAuthenticator 创建内置鉴权方式或鉴权插件的鉴权器
+func (f *_RootFactory) genAuthenticator(strategyID, authKey, authCfg string) (auth.Authenticator, error) {
+	if auth.Has(authKey) {
+		return auth.Create(authKey, authCfg)
+	}
+	pluginName, has := f.authPlugin[authKey]
+	if !has {
+		return nil, errors.New("auth type " + authKey + " is not supported")
+	}
+	pluginFactory, err := plugin_loader.LoadPlugin(pluginName)
+	if err != nil {
+		return nil, err
+	}
+	pluginObj, err := pluginFactory.Create(authCfg, f.cluster, "", strategyID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if pluginObj.Access == nil || reflect.ValueOf(pluginObj.Access).IsNil() {
+		return nil, errors.New("auth plugin " + pluginName + " has no access handler")
+	}
+	return &pluginAuthenticator{
+		name: authKey,
+		executor: plugin_executor.NewAccessExecutor(&config.PluginConfig{
+			Name:   pluginName,
+			IsStop: true,
+			Config: authCfg,
+			IsAuth: true,
+		}, pluginObj.Access),
+	}, nil
+}
+
+// withoutAuth 鉴权插件由策略的鉴权链执行，不参与access插件流程
+func withoutAuth(executors []plugin_executor.Executor) []plugin_executor.Executor {
+	list := make([]plugin_executor.Executor, 0, len(executors))
+	for _, ex := range executors {
+		if ex.IsAuth() {
+			continue
+		}
+		list = append(list, ex)
+	}
+	return list
+}
+
+// genLimiter 获取作用于策略、接口及策略下接口的限流规则
+func (f *_ApiFactory) genLimiter(apiID int) *rate_limit.Limiter {
+	rules := make([]*rate_limit.Rule, 0, len(f.root.rateLimits))
+	for _, r := range f.root.rateLimits {
+		if r.strategyID != "" && r.strategyID != f.strategyID {
+			continue
+		}
+		if r.apiID != 0 && r.apiID != apiID {
+			continue
+		}
+		rules = append(rules, r.rule)
+	}
+	return rate_limit.NewLimiter(rules)
+}
+
+// genSizeLimit 合并作用于全局、策略、接口及策略下接口的大小限制
+func (f *_ApiFactory) genSizeLimit(apiID int) *size_limit.Limit {
+	return size_limit.Match(f.root.sizeLimits, f.strategyID, apiID)
+}
+
+func genRateLimits(cfgs []*config.RateLimitConfig) []*rateLimitRule {
+	rules := make([]*rateLimitRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.StrategyID == "" && cfg.APIID == 0 {
+			continue
+		}
+		rules = append(rules, &rateLimitRule{
+			strategyID: cfg.StrategyID,
+			apiID:      cfg.APIID,
+			rule:       rate_limit.NewRule(cfg),
+		})
+	}
+	return rules
+}
+
 func genFactory(cfg *config.GokuConfig, factory router.Factory) *_RootFactory {
 
 	discovery.ResetAllServiceConfig(cfg.DiscoverConfig)
@@ -186,6 +285,9 @@ func genFactory(cfg *config.GokuConfig, factory router.Factory) *_RootFactory {
 		apis:          apis,
 		routerFactory: factory,
 		cluster:       cfg.Cluster,
+		rateLimits:    genRateLimits(cfg.RateLimits),
+		sizeLimits:    cfg.SizeLimits,
+		openAPIs:      request_validator.ParseDocuments(cfg.OpenAPIs),
 		orgCfg:        cfg,
 		authPlugin:    cfg.AuthPlugin,
 	}
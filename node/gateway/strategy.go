@@ -8,7 +8,9 @@ import (
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/eolinker/goku-api-gateway/node/auth"
 	plugin_executor "github.com/eolinker/goku-api-gateway/node/gateway/plugin-executor"
+	rate_limit "github.com/eolinker/goku-api-gateway/node/rate-limit"
 	"github.com/eolinker/goku-api-gateway/node/router"
 	access_field "github.com/eolinker/goku-api-gateway/server/access-field"
 )
@@ -21,10 +23,10 @@ type Strategy struct {
 
 	apiRouter router.APIRouter
 
-	accessPlugin       []plugin_executor.Executor
-	globalAccessPlugin []plugin_executor.Executor
+	accessPlugin       plugin_executor.Pipeline
+	globalAccessPlugin plugin_executor.Pipeline
 
-	authPlugin map[string]plugin_executor.Executor
+	authChain *auth.Chain
 
 	isNeedAuth bool
 }
@@ -59,83 +61,30 @@ func (r *Strategy) Router(w http.ResponseWriter, req *http.Request, ctx *common.
 			return
 		}
 	}
-	for _, strategyAccess := range r.accessPlugin {
-		if strategyAccess.IsAuth() {
-			continue
-		}
-		fmt.Println(strategyAccess.IsAuth())
-		isContinued, err := strategyAccess.Execute(ctx)
-		if isContinued {
-			continue
-		}
-		if err != nil {
-			ctx.SetStatus(403, "403")
-			ctx.SetBody([]byte(err.Error()))
-			return
-		}
+	if !r.accessPlugin.Execute(ctx) {
+		return
 	}
 	r.apiRouter.ServeHTTP(w, req, ctx)
 }
 
 func (r *Strategy) auth(ctx *common.Context) (bool, error) {
 	requestID := ctx.RequestId()
-	authType := ctx.Request().GetHeader("Authorization-Type")
-	authPlugin, has := r.authPlugin[authType]
-	if !has {
-		errInfo := errors.New(" Illegal authorization type:" + authType)
+	authType, credential, err := r.authChain.Authenticate(ctx)
+	if err != nil {
+		errInfo := errors.New(" access auth:[" + authType + "] error:" + err.Error())
 		log.Warn(requestID, errInfo.Error())
 		return false, errInfo
 	}
-
-	isContinue, err := authPlugin.Execute(ctx)
-	if isContinue == false {
-		pluginName := authNames[authType]
-
-		var errInfo error
-		// 校验失败
-		if err != nil {
-			errInfo = errors.New(" access auth:[" + pluginName + "] error:" + err.Error())
-			log.Warn(requestID, errInfo.Error())
-			return false, errInfo
-		}
-		log.Info(requestID, " auth [", pluginName, "] refuse")
-
-		return false, nil
-	}
+	ctx.SetCache(rate_limit.CredentialCacheKey, credential)
 	log.Debug(requestID, " auth [", authType, "] pass")
 	return true, nil
 }
-func (r *Strategy) accessFlow(ctx *common.Context) bool {
-	for _, handler := range r.accessPlugin {
-
-		flag, _ := handler.Execute(ctx)
-
-		if flag == false && handler.IsStop() {
-
-			return false
-		}
-	}
-	return true
-}
-
-func (r *Strategy) accessGlobalFlow(ctx *common.Context) {
-	// 全局插件不中断
-	for _, handler := range r.globalAccessPlugin {
-		_, _ = handler.Execute(ctx)
-
-	}
-}
-
 //HandlerAPINotFound 当接口不存在时调用
 func (r *Strategy) HandlerAPINotFound(ctx *common.Context) {
-	// 未匹配到api
-	// 执行策略access 插件
-	if !r.accessFlow(ctx) {
-		r.accessGlobalFlow(ctx)
+	// 未匹配到api，策略access插件已在Router中执行，这里只执行全局access插件
+	if !r.globalAccessPlugin.Execute(ctx) {
 		return
 	}
-	// 执行全局access 插件
-	r.accessGlobalFlow(ctx)
 
 	if ctx.StatusCode() == 0 {
 		// 插件可能会设置状态码
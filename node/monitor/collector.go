@@ -10,6 +10,14 @@ var (
 	APIMonitor diting.Histogram
 	//ProxyMonitor diting.Histogram
 	ProxyMonitor diting.Histogram
+	//UpgradeConnections 当前协议升级（websocket等）连接数
+	UpgradeConnections diting.Gauge
+	//UpgradeMonitor 协议升级连接时长统计
+	UpgradeMonitor diting.Histogram
+	//PluginMonitor 插件执行耗时统计
+	PluginMonitor diting.Histogram
+	//PluginErrors 插件错误计数
+	PluginErrors diting.Counter
 )
 
 func initCollector(constLabels diting.Labels) {
@@ -24,9 +32,21 @@ func initCollector(constLabels diting.Labels) {
 	//APICount = diting.NewCounter(apiCounterOpt)
 
 	apiHistogramOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.APIName, "api整体请求统计", constLabels, goku_labels.APIDelayLabelNames, goku_labels.APIBuckets)
-	APIMonitor = diting.NewHistogram(apiHistogramOpt)
+	APIMonitor = &trafficHistogram{Histogram: diting.NewHistogram(apiHistogramOpt), observe: traffic.observeAPI}
 
 	proxyMonitorOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.ProxyName, "转发统计", constLabels, goku_labels.ProxyDelayLabelNames, goku_labels.ProxyBuckets)
-	ProxyMonitor = diting.NewHistogram(proxyMonitorOpt)
+	ProxyMonitor = &trafficHistogram{Histogram: diting.NewHistogram(proxyMonitorOpt), observe: traffic.observeProxy}
+
+	upgradeConnectionsOpt := diting.NewGaugeOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.UpgradeConnectionsName, "协议升级连接数", constLabels, goku_labels.UpgradeConnectionsLabelNames)
+	UpgradeConnections = diting.NewGauge(upgradeConnectionsOpt)
+
+	upgradeDurationOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.UpgradeDurationName, "协议升级连接时长统计", constLabels, goku_labels.UpgradeDurationLabelNames, goku_labels.UpgradeBuckets)
+	UpgradeMonitor = diting.NewHistogram(upgradeDurationOpt)
+
+	pluginMonitorOpt := diting.NewHistogramOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.PluginName, "插件执行耗时统计", constLabels, goku_labels.PluginLabelNames, goku_labels.PluginBuckets)
+	PluginMonitor = diting.NewHistogram(pluginMonitorOpt)
+
+	pluginErrorsOpt := diting.NewCounterOpts(goku_labels.Namespace, goku_labels.Subsystem, goku_labels.PluginErrorName, "插件错误计数", constLabels, goku_labels.PluginLabelNames)
+	PluginErrors = diting.NewCounter(pluginErrorsOpt)
 
 }
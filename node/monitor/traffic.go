@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/diting"
+	goku_labels "github.com/eolinker/goku-api-gateway/goku-labels"
+)
+
+type trafficKey struct {
+	strategyID string
+	apiID      int
+}
+
+// trafficCollector 汇总上报到控制台的流量统计
+type trafficCollector struct {
+	locker sync.Mutex
+	start  time.Time
+	stats  map[trafficKey]*config.TrafficStat
+}
+
+var traffic = &trafficCollector{
+	start: time.Now(),
+	stats: make(map[trafficKey]*config.TrafficStat),
+}
+
+func (c *trafficCollector) get(labels diting.Labels) *config.TrafficStat {
+	apiID, _ := strconv.Atoi(labels[goku_labels.API])
+	key := trafficKey{strategyID: labels[goku_labels.Strategy], apiID: apiID}
+	s, has := c.stats[key]
+	if !has {
+		s = config.NewTrafficStat(key.strategyID, key.apiID)
+		c.stats[key] = s
+	}
+	return s
+}
+
+func (c *trafficCollector) observeAPI(value float64, labels diting.Labels) {
+	status, _ := strconv.Atoi(labels[goku_labels.Status])
+	c.locker.Lock()
+	c.get(labels).ObserveRequest(status, value)
+	c.locker.Unlock()
+}
+
+func (c *trafficCollector) observeProxy(value float64, labels diting.Labels) {
+	status, _ := strconv.Atoi(labels[goku_labels.Status])
+	c.locker.Lock()
+	c.get(labels).ObserveUpstream(status)
+	c.locker.Unlock()
+}
+
+func (c *trafficCollector) take() *config.TrafficReport {
+	now := time.Now()
+	c.locker.Lock()
+	stats, start := c.stats, c.start
+	c.stats = make(map[trafficKey]*config.TrafficStat)
+	c.start = now
+	c.locker.Unlock()
+
+	report := &config.TrafficReport{
+		Time:   now.Unix(),
+		Period: int(now.Sub(start) / time.Second),
+		Stats:  make([]*config.TrafficStat, 0, len(stats)),
+	}
+	for _, s := range stats {
+		report.Stats = append(report.Stats, s)
+	}
+	return report
+}
+
+//TakeTraffic 取出上次调用以来的流量统计并重新开始计数
+func TakeTraffic() *config.TrafficReport {
+	return traffic.take()
+}
+
+// trafficHistogram 在原有监控模块之外同时记录流量统计
+type trafficHistogram struct {
+	diting.Histogram
+	observe func(value float64, labels diting.Labels)
+}
+
+func (h *trafficHistogram) Observe(value float64, labels diting.Labels) {
+	h.Histogram.Observe(value, labels)
+	h.observe(value, labels)
+}
@@ -0,0 +1,53 @@
+package plugin_loader
+
+import (
+	"fmt"
+	"sort"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+var (
+	builtinPlugins = make(map[string]goku_plugin.PluginFactory)
+)
+
+//RegisterBuiltin 注册编译进节点的插件，加载插件时优先于 plugin/<name>.so，需要在init中调用
+func RegisterBuiltin(name string, factory goku_plugin.PluginFactory) {
+	if factory == nil {
+		panic(fmt.Sprintf("builtin plugin %s is nil", name))
+	}
+	if _, has := builtinPlugins[name]; has {
+		panic(fmt.Sprintf("builtin plugin %s is already registered", name))
+	}
+	builtinPlugins[name] = factory
+}
+
+//IsBuiltin 是否为内置插件
+func IsBuiltin(name string) bool {
+	_, has := builtinPlugins[name]
+	return has
+}
+
+//Builtins 内置插件名称列表
+func Builtins() []string {
+	names := make([]string, 0, len(builtinPlugins))
+	for name := range builtinPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//Errors 加载失败的插件及错误信息
+func Errors() map[string]string {
+	return globalPluginManager.loadErrors()
+}
+
+//Check 检查插件能否被当前节点加载，不使用已缓存的加载结果
+func Check(name string) error {
+	if IsBuiltin(name) {
+		return nil
+	}
+	_, _, err := openPlugin(name)
+	return err
+}
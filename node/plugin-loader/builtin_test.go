@@ -0,0 +1,32 @@
+package plugin_loader
+
+import (
+	"testing"
+
+	goku_plugin "github.com/eolinker/goku-plugin"
+)
+
+type testFactory struct{}
+
+func (f *testFactory) Create(config string, clusterName string, updateTag string, strategyID string, apiID int) (*goku_plugin.PluginObj, error) {
+	return &goku_plugin.PluginObj{}, nil
+}
+
+func TestBuiltin(t *testing.T) {
+	RegisterBuiltin("goku-test_builtin", new(testFactory))
+	factory, err := LoadPlugin("goku-test_builtin")
+	if err != nil || factory == nil {
+		t.Fatalf("load builtin:%v", err)
+	}
+	if err := Check("goku-test_builtin"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := LoadPlugin("goku-test_missing"); err == nil {
+		t.Fatal("expected missing plugin error")
+	}
+	errs := Errors()
+	if _, has := errs["goku-test_missing"]; !has || len(errs) != 1 {
+		t.Errorf("unexpected load errors:%v", errs)
+	}
+}
@@ -64,8 +64,23 @@ func LoadPlugin(name string) (goku_plugin.PluginFactory, error) {
 
 }
 
-// 加载动态库
+func (m *_GlodPluginManager) loadErrors() map[string]string {
+	m.gloadPluginLocker.RLock()
+	defer m.gloadPluginLocker.RUnlock()
+	errs := make(map[string]string)
+	for name, code := range m.errorCodes {
+		if code != LoadOk && m.errors[name] != nil {
+			errs[name] = m.errors[name].Error()
+		}
+	}
+	return errs
+}
+
+// 加载插件，内置插件优先于动态库
 func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory, int, error) {
+	if factory, has := builtinPlugins[name]; has {
+		return factory, LoadOk, nil
+	}
 	handle, has := m.getPluginHandle(name)
 	if has {
 		return handle, LoadOk, nil
@@ -73,14 +88,23 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 	m.gloadPluginLocker.Lock()
 	defer m.gloadPluginLocker.Unlock()
 
+	factory, code, err := openPlugin(name)
+	m.errorCodes[name] = code
+	m.errors[name] = err
+	if err != nil {
+		return nil, code, err
+	}
+	m.gloadPlugin[name] = factory
+	return factory, LoadOk, nil
+}
+
+// openPlugin 加载动态库
+func openPlugin(name string) (goku_plugin.PluginFactory, int, error) {
 	path, _ := filepath.Abs(fmt.Sprintf("plugin/%s.so", name))
 
 	pdll, err := plugin.Open(path)
 	if err != nil {
-		e := fmt.Errorf("plugin:%s ", name, err.Error())
-		m.errors[name] = e
-		m.errorCodes[name] = LoadFileError
-		return nil, LoadFileError, e
+		return nil, LoadFileError, fmt.Errorf("plugin:%s %s", name, err.Error())
 	}
 
 	//structName := strings.Replace(name, "-", "_", -1)
@@ -89,31 +113,16 @@ func (m *_GlodPluginManager) loadPlugin(name string) (goku_plugin.PluginFactory,
 	// 在插件中寻找相关的对象，将其方法加载
 	v, err := pdll.Lookup("Builder")
 	if err != nil {
-
-		e := fmt.Errorf("The Builder can not be found in plugin/%s.so ", name)
-		m.errors[name] = e
-		m.errorCodes[name] = LoadLookupError
-		return nil, LoadLookupError, e
+		return nil, LoadLookupError, fmt.Errorf("The Builder can not be found in plugin/%s.so ", name)
 	}
 
 	vp, ok := v.(func() goku_plugin.PluginFactory)
 	if !ok {
-		e := fmt.Errorf("The builder func  can not  implemented interface named goku_plugin.PluginFactory:%s ", name)
-		m.errors[name] = e
-		m.errorCodes[name] = LoadInterFaceError
-		return nil, LoadInterFaceError, e
+		return nil, LoadInterFaceError, fmt.Errorf("The builder func  can not  implemented interface named goku_plugin.PluginFactory:%s ", name)
 	}
 	factory := vp()
 	if factory == nil || reflect.ValueOf(factory).IsNil() {
-		e := fmt.Errorf("The builder result is nil:%s ", name)
-		m.errors[name] = e
-		m.errorCodes[name] = LoadInterFaceError
-		fmt.Println(e)
-		return nil, LoadInterFaceError, e
+		return nil, LoadInterFaceError, fmt.Errorf("The builder result is nil:%s ", name)
 	}
-	m.gloadPlugin[name] = factory
-	m.errorCodes[name] = LoadOk
-	m.errors[name] = nil
 	return factory, LoadOk, nil
-
 }
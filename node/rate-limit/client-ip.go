@@ -0,0 +1,79 @@
+package rate_limit
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	fields "github.com/eolinker/goku-api-gateway/server/access-field"
+)
+
+var (
+	trustedProxies []*net.IPNet
+	proxyLocker    sync.RWMutex
+)
+
+//SetTrustedProxies 设置可信代理的IP或CIDR，只有来自可信代理的请求才使用 X-Forwarded-For 及 X-Real-Ip 作为客户端IP
+func SetTrustedProxies(proxies []string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return fmt.Errorf("illegal trusted proxy %s", p)
+		}
+		nets = append(nets, n)
+	}
+	proxyLocker.Lock()
+	trustedProxies = nets
+	proxyLocker.Unlock()
+	return nil
+}
+
+func isTrusted(addr string) bool {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+	proxyLocker.RLock()
+	defer proxyLocker.RUnlock()
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP 获取客户端IP，直连地址为可信代理时从 X-Forwarded-For 右侧取第一个非可信代理的地址
+func clientIP(ctx *common.Context) string {
+	remote, _ := ctx.LogFields[fields.RemoteAddr].(string)
+	if !isTrusted(remote) {
+		return remote
+	}
+	if forwarded := ctx.RequestOrg.GetHeader("X-Forwarded-For"); forwarded != "" {
+		ips := strings.Split(forwarded, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if !isTrusted(ip) {
+				return ip
+			}
+		}
+	}
+	if realIP := strings.TrimSpace(ctx.RequestOrg.GetHeader("X-Real-Ip")); realIP != "" {
+		return realIP
+	}
+	return remote
+}
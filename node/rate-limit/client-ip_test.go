@@ -0,0 +1,42 @@
+package rate_limit
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	fields "github.com/eolinker/goku-api-gateway/server/access-field"
+)
+
+func newIPContext(remote, forwarded, realIP string) *common.Context {
+	req := httptest.NewRequest("GET", "/", nil)
+	if forwarded != "" {
+		req.Header.Set("X-Forwarded-For", forwarded)
+	}
+	if realIP != "" {
+		req.Header.Set("X-Real-Ip", realIP)
+	}
+	ctx := common.NewContext(req, "test", httptest.NewRecorder())
+	ctx.LogFields[fields.RemoteAddr] = remote
+	return ctx
+}
+
+func TestClientIP(t *testing.T) {
+	if err := SetTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTrustedProxies(nil)
+
+	if ip := clientIP(newIPContext("1.2.3.4", "5.6.7.8", "5.6.7.8")); ip != "1.2.3.4" {
+		t.Errorf("headers from untrusted address should be ignored: %s", ip)
+	}
+	if ip := clientIP(newIPContext("10.0.0.1", "9.9.9.9, 5.6.7.8, 192.168.1.1", "")); ip != "5.6.7.8" {
+		t.Errorf("expected the last untrusted forwarded address: %s", ip)
+	}
+	if ip := clientIP(newIPContext("192.168.1.1", "", "5.6.7.8")); ip != "5.6.7.8" {
+		t.Errorf("expected X-Real-Ip from trusted proxy: %s", ip)
+	}
+	if err := SetTrustedProxies([]string{"bad"}); err == nil {
+		t.Error("expected illegal proxy error")
+	}
+}
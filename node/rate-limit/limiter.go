@@ -0,0 +1,242 @@
+package rate_limit
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	redis_manager "github.com/eolinker/goku-api-gateway/common/redis-manager"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const (
+	//CredentialCacheKey 鉴权通过后写入上下文缓存的调用方凭证，用于按调用方限流
+	CredentialCacheKey = "credential"
+
+	keyPrefix = "goku:rate-limit:"
+)
+
+var (
+	// 内存计数在配置刷新后继续保留
+	localStore = NewLocalStore()
+)
+
+//Rule 限流规则
+type Rule struct {
+	id        int
+	algorithm string
+	mode      string
+	key       func(ctx *common.Context) string
+
+	limit  int64
+	burst  int64
+	period time.Duration
+
+	dailyQuota   int64
+	monthlyQuota int64
+}
+
+//NewRule 根据配置创建限流规则
+func NewRule(cfg *config.RateLimitConfig) *Rule {
+	period := time.Duration(cfg.Period) * time.Second
+	if period <= 0 {
+		period = time.Second
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = cfg.Limit
+	}
+	return &Rule{
+		id:           cfg.ID,
+		algorithm:    cfg.Algorithm,
+		mode:         cfg.Mode,
+		key:          keyFunc(cfg.Key),
+		limit:        cfg.Limit,
+		burst:        burst,
+		period:       period,
+		dailyQuota:   cfg.DailyQuota,
+		monthlyQuota: cfg.MonthlyQuota,
+	}
+}
+
+//Limiter 限流器，依次检查所有规则
+type Limiter struct {
+	rules []*Rule
+}
+
+//NewLimiter 创建限流器，没有规则时返回nil
+func NewLimiter(rules []*Rule) *Limiter {
+	if len(rules) == 0 {
+		return nil
+	}
+	return &Limiter{rules: rules}
+}
+
+//Check 检查请求是否允许通过，并设置 X-RateLimit-* 及 X-Quota-* 响应头，拒绝时返回429
+func (l *Limiter) Check(ctx *common.Context) bool {
+	if l == nil {
+		return true
+	}
+	now := time.Now()
+	var rate, quota *Result
+	for _, rule := range l.rules {
+		identity := rule.key(ctx)
+		prefix := keyPrefix + strconv.Itoa(rule.id) + ":"
+		if rule.limit > 0 {
+			r := rule.rate(prefix+identity, now)
+			rate = restrictive(rate, r)
+			if !r.Allowed {
+				setHeaders(ctx, rate, quota)
+				reject(ctx, r, "[ERROR]Too many requests!")
+				return false
+			}
+		}
+		for _, q := range rule.quotas(prefix+"quota:", identity, now) {
+			quota = restrictive(quota, q)
+			if !q.Allowed {
+				setHeaders(ctx, rate, quota)
+				reject(ctx, q, "[ERROR]Quota exceeded!")
+				return false
+			}
+		}
+	}
+	setHeaders(ctx, rate, quota)
+	return true
+}
+
+func (r *Rule) rate(key string, now time.Time) *Result {
+	var result *Result
+	err := r.do(func(store Store) (err error) {
+		if r.algorithm == config.RateLimitSlidingWindow {
+			result, err = store.SlidingWindow(key, r.limit, r.period, now)
+		} else {
+			result, err = store.TokenBucket(key, r.burst, r.limit, r.period, now)
+		}
+		return err
+	})
+	if err != nil {
+		return &Result{Allowed: true, Limit: r.limit, Remaining: r.limit}
+	}
+	return result
+}
+
+func (r *Rule) quotas(prefix, identity string, now time.Time) []*Result {
+	results := make([]*Result, 0, 2)
+	if r.dailyQuota > 0 {
+		y, m, d := now.Date()
+		expireAt := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+		results = append(results, r.quota(prefix+identity+":"+now.Format("20060102"), r.dailyQuota, expireAt, now))
+	}
+	if r.monthlyQuota > 0 {
+		y, m, _ := now.Date()
+		expireAt := time.Date(y, m+1, 1, 0, 0, 0, 0, now.Location())
+		results = append(results, r.quota(prefix+identity+":"+now.Format("200601"), r.monthlyQuota, expireAt, now))
+	}
+	return results
+}
+
+func (r *Rule) quota(key string, limit int64, expireAt, now time.Time) *Result {
+	var result *Result
+	err := r.do(func(store Store) (err error) {
+		result, err = store.Quota(key, limit, expireAt, now)
+		return err
+	})
+	if err != nil {
+		return &Result{Allowed: true, Limit: limit, Remaining: limit, Reset: expireAt.Sub(now)}
+	}
+	return result
+}
+
+// do 使用规则指定的存储执行计数，redis不可用时降级为内存计数
+func (r *Rule) do(fn func(store Store) error) error {
+	if r.mode == config.RateLimitModeRedis {
+		if rds, has := redis_manager.Default(); has {
+			err := fn(NewRedisStore(rds))
+			if err == nil {
+				return nil
+			}
+			log.Warn("rate limit use redis error, fallback to local:", err)
+		}
+	}
+	return fn(localStore)
+}
+
+// keyFunc 获取限流维度
+func keyFunc(key string) func(ctx *common.Context) string {
+	switch {
+	case key == "ip":
+		return func(ctx *common.Context) string {
+			return "ip:" + clientIP(ctx)
+		}
+	case key == "credential":
+		return credential
+	case strings.HasPrefix(key, "header:"):
+		name := strings.TrimSpace(strings.TrimPrefix(key, "header:"))
+		return func(ctx *common.Context) string {
+			return "header:" + digest(ctx.ProxyRequest.GetHeader(name))
+		}
+	}
+	return func(ctx *common.Context) string {
+		return "strategy:" + ctx.StrategyId()
+	}
+}
+
+// credential 优先使用鉴权插件写入的调用方凭证，否则使用鉴权请求头
+func credential(ctx *common.Context) string {
+	if v, has := ctx.GetCache(CredentialCacheKey); has {
+		if s := fmt.Sprint(v); s != "" {
+			return "credential:" + s
+		}
+	}
+	for _, name := range []string{"Authorization", "Apikey", "X-Api-Key"} {
+		if v := ctx.ProxyRequest.GetHeader(name); v != "" {
+			return "credential:" + digest(v)
+		}
+	}
+	return "credential:anonymous"
+}
+
+func digest(v string) string {
+	sum := md5.Sum([]byte(v))
+	return hex.EncodeToString(sum[:])
+}
+
+// restrictive 返回剩余次数更少的结果
+func restrictive(current, r *Result) *Result {
+	if current == nil || r.Remaining < current.Remaining || !r.Allowed {
+		return r
+	}
+	return current
+}
+
+func setHeaders(ctx *common.Context, rate, quota *Result) {
+	if rate != nil {
+		ctx.Set().SetHeader("X-RateLimit-Limit", strconv.FormatInt(rate.Limit, 10))
+		ctx.Set().SetHeader("X-RateLimit-Remaining", strconv.FormatInt(rate.Remaining, 10))
+		ctx.Set().SetHeader("X-RateLimit-Reset", seconds(rate.Reset))
+	}
+	if quota != nil {
+		ctx.Set().SetHeader("X-Quota-Limit", strconv.FormatInt(quota.Limit, 10))
+		ctx.Set().SetHeader("X-Quota-Remaining", strconv.FormatInt(quota.Remaining, 10))
+		ctx.Set().SetHeader("X-Quota-Reset", seconds(quota.Reset))
+	}
+}
+
+func reject(ctx *common.Context, r *Result, msg string) {
+	ctx.Set().SetHeader("Retry-After", seconds(r.Reset))
+	ctx.SetStatus(429, "429")
+	ctx.SetBody([]byte(msg))
+}
+
+// seconds 向上取整的秒数
+func seconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
@@ -0,0 +1,149 @@
+package rate_limit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	expire time.Time
+}
+
+type window struct {
+	start  time.Time
+	prev   int64
+	curr   int64
+	expire time.Time
+}
+
+type counter struct {
+	count  int64
+	expire time.Time
+}
+
+//LocalStore 单节点内存计数
+type LocalStore struct {
+	locker   sync.Mutex
+	buckets  map[string]*bucket
+	windows  map[string]*window
+	counters map[string]*counter
+	ops      int
+}
+
+//NewLocalStore 创建内存计数
+func NewLocalStore() *LocalStore {
+	return &LocalStore{
+		buckets:  make(map[string]*bucket),
+		windows:  make(map[string]*window),
+		counters: make(map[string]*counter),
+	}
+}
+
+//TokenBucket 令牌桶
+func (s *LocalStore) TokenBucket(key string, capacity, rate int64, period time.Duration, now time.Time) (*Result, error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.gc(now)
+
+	b, has := s.buckets[key]
+	if !has {
+		b = &bucket{tokens: float64(capacity), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, b.last, now, capacity, rate, period)
+	b.last = now
+
+	r := &Result{Limit: capacity}
+	if b.tokens >= 1 {
+		b.tokens--
+		r.Allowed = true
+	}
+	r.Remaining = int64(b.tokens)
+	r.Reset = tokenReset(b.tokens, capacity, rate, period)
+	b.expire = now.Add(r.Reset + period)
+	return r, nil
+}
+
+//SlidingWindow 滑动窗口
+func (s *LocalStore) SlidingWindow(key string, limit int64, size time.Duration, now time.Time) (*Result, error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.gc(now)
+
+	start := now.Truncate(size)
+	w, has := s.windows[key]
+	if !has {
+		w = &window{start: start}
+		s.windows[key] = w
+	}
+	if !w.start.Equal(start) {
+		if w.start.Add(size).Equal(start) {
+			w.prev = w.curr
+		} else {
+			w.prev = 0
+		}
+		w.curr = 0
+		w.start = start
+	}
+	w.expire = start.Add(2 * size)
+
+	elapsed := now.Sub(start)
+	count := slidingCount(w.prev, w.curr, elapsed, size)
+	r := &Result{Limit: limit, Reset: size - elapsed}
+	if count < limit {
+		w.curr++
+		count++
+		r.Allowed = true
+	}
+	r.Remaining = limit - count
+	if r.Remaining < 0 {
+		r.Remaining = 0
+	}
+	return r, nil
+}
+
+//Quota 配额计数
+func (s *LocalStore) Quota(key string, limit int64, expireAt time.Time, now time.Time) (*Result, error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	s.gc(now)
+
+	c, has := s.counters[key]
+	if !has {
+		c = &counter{expire: expireAt}
+		s.counters[key] = c
+	}
+	r := &Result{Limit: limit, Reset: expireAt.Sub(now)}
+	if c.count < limit {
+		c.count++
+		r.Allowed = true
+	}
+	r.Remaining = limit - c.count
+	return r, nil
+}
+
+// gc 定期清理过期的计数
+func (s *LocalStore) gc(now time.Time) {
+	s.ops++
+	if s.ops < 10000 {
+		return
+	}
+	s.ops = 0
+	for k, b := range s.buckets {
+		if now.After(b.expire) {
+			delete(s.buckets, k)
+		}
+	}
+	for k, w := range s.windows {
+		if now.After(w.expire) {
+			delete(s.windows, k)
+		}
+	}
+	for k, c := range s.counters {
+		if now.After(c.expire) {
+			delete(s.counters, k)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package rate_limit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	s := NewLocalStore()
+	now := time.Unix(1000, 0)
+	for i := 0; i < 3; i++ {
+		r, _ := s.TokenBucket("k", 3, 1, time.Second, now)
+		if !r.Allowed || r.Remaining != int64(2-i) {
+			t.Fatalf("request %d: %+v", i, r)
+		}
+	}
+	if r, _ := s.TokenBucket("k", 3, 1, time.Second, now); r.Allowed {
+		t.Fatalf("expected rejected: %+v", r)
+	}
+	if r, _ := s.TokenBucket("k", 3, 1, time.Second, now.Add(time.Second)); !r.Allowed || r.Remaining != 0 {
+		t.Fatalf("expected refilled: %+v", r)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	s := NewLocalStore()
+	start := time.Unix(960, 0)
+	for i := 0; i < 4; i++ {
+		if r, _ := s.SlidingWindow("k", 4, time.Minute, start.Add(50*time.Second)); !r.Allowed {
+			t.Fatalf("request %d rejected", i)
+		}
+	}
+	if r, _ := s.SlidingWindow("k", 4, time.Minute, start.Add(55*time.Second)); r.Allowed {
+		t.Fatal("expected rejected")
+	}
+	// 下一窗口过去一半时，上一窗口的4个请求按一半计入
+	next := start.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		if r, _ := s.SlidingWindow("k", 4, time.Minute, next); !r.Allowed {
+			t.Fatalf("request %d rejected", i)
+		}
+	}
+	if r, _ := s.SlidingWindow("k", 4, time.Minute, next); r.Allowed || r.Reset != 30*time.Second {
+		t.Fatalf("expected rejected: %+v", r)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	s := NewLocalStore()
+	now := time.Unix(1000, 0)
+	expire := now.Add(time.Hour)
+	s.Quota("k", 1, expire, now)
+	if r, _ := s.Quota("k", 1, expire, now); r.Allowed || r.Remaining != 0 || r.Reset != time.Hour {
+		t.Fatalf("expected rejected: %+v", r)
+	}
+}
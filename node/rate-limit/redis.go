@@ -0,0 +1,156 @@
+package rate_limit
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+var (
+	tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(data[1])
+local last = tonumber(data[2])
+if tokens == nil or last == nil then
+	tokens = capacity
+	last = now
+end
+if now > last then
+	tokens = math.min(capacity, tokens + (now - last) * rate / period)
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) * period / rate) + period)
+return {allowed, tostring(tokens)}
+`)
+
+	slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local size = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local curr = tonumber(redis.call('GET', KEYS[1]) or '0')
+local prev = tonumber(redis.call('GET', KEYS[2]) or '0')
+local count = math.floor(prev * (size - elapsed) / size) + curr
+local allowed = 0
+if count < limit then
+	redis.call('INCR', KEYS[1])
+	redis.call('PEXPIRE', KEYS[1], size * 2)
+	count = count + 1
+	allowed = 1
+end
+return {allowed, count}
+`)
+
+	quotaScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+end
+if count > tonumber(ARGV[1]) then
+	redis.call('DECR', KEYS[1])
+	return {0, count - 1}
+end
+return {1, count}
+`)
+)
+
+//RedisStore 基于redis的集群计数
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+//NewRedisStore 创建redis计数
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+//TokenBucket 令牌桶
+func (s *RedisStore) TokenBucket(key string, capacity, rate int64, period time.Duration, now time.Time) (*Result, error) {
+	v, err := tokenBucketScript.Run(s.client, []string{key}, capacity, rate, toMillisecond(period), toMillisecond(now.Sub(time.Unix(0, 0)))).Result()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := v.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("invalid token bucket result:%v", v)
+	}
+	tokens, _ := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	return &Result{
+		Allowed:   toInt64(values[0]) == 1,
+		Limit:     capacity,
+		Remaining: int64(tokens),
+		Reset:     tokenReset(tokens, capacity, rate, period),
+	}, nil
+}
+
+//SlidingWindow 滑动窗口，当前窗口与上一窗口的key使用相同的hash tag以兼容集群模式
+func (s *RedisStore) SlidingWindow(key string, limit int64, size time.Duration, now time.Time) (*Result, error) {
+	start := now.Truncate(size)
+	index := start.UnixNano() / int64(size)
+	keys := []string{
+		fmt.Sprintf("{%s}:%d", key, index),
+		fmt.Sprintf("{%s}:%d", key, index-1),
+	}
+	elapsed := now.Sub(start)
+	v, err := slidingWindowScript.Run(s.client, keys, limit, toMillisecond(size), toMillisecond(elapsed)).Result()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := v.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("invalid sliding window result:%v", v)
+	}
+	remaining := limit - toInt64(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &Result{
+		Allowed:   toInt64(values[0]) == 1,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     size - elapsed,
+	}, nil
+}
+
+//Quota 配额计数
+func (s *RedisStore) Quota(key string, limit int64, expireAt time.Time, now time.Time) (*Result, error) {
+	v, err := quotaScript.Run(s.client, []string{key}, limit, toMillisecond(expireAt.Sub(time.Unix(0, 0)))).Result()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := v.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("invalid quota result:%v", v)
+	}
+	return &Result{
+		Allowed:   toInt64(values[0]) == 1,
+		Limit:     limit,
+		Remaining: limit - toInt64(values[1]),
+		Reset:     expireAt.Sub(now),
+	}, nil
+}
+
+func toMillisecond(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
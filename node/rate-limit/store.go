@@ -0,0 +1,49 @@
+package rate_limit
+
+import (
+	"time"
+)
+
+//Result 限流检查结果
+type Result struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	Reset     time.Duration
+}
+
+//Store 计数存储
+type Store interface {
+	//TokenBucket 令牌桶，capacity为桶容量，每period补充rate个令牌
+	TokenBucket(key string, capacity, rate int64, period time.Duration, now time.Time) (*Result, error)
+	//SlidingWindow 滑动窗口，window内最多允许limit个请求
+	SlidingWindow(key string, limit int64, window time.Duration, now time.Time) (*Result, error)
+	//Quota 配额计数，expireAt时重置
+	Quota(key string, limit int64, expireAt time.Time, now time.Time) (*Result, error)
+}
+
+// slidingCount 滑动窗口计数：上一窗口按剩余时间比例计入
+func slidingCount(prev, curr int64, elapsed, window time.Duration) int64 {
+	weight := float64(window-elapsed) / float64(window)
+	return int64(float64(prev)*weight) + curr
+}
+
+// refill 计算令牌桶补充后的令牌数
+func refill(tokens float64, last, now time.Time, capacity, rate int64, period time.Duration) float64 {
+	if now.After(last) {
+		tokens += float64(now.Sub(last)) * float64(rate) / float64(period)
+	}
+	if tokens > float64(capacity) {
+		tokens = float64(capacity)
+	}
+	return tokens
+}
+
+// tokenReset 令牌桶补满所需时间
+func tokenReset(tokens float64, capacity, rate int64, period time.Duration) time.Duration {
+	lack := float64(capacity) - tokens
+	if lack <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(lack * float64(period) / float64(rate))
+}
@@ -0,0 +1,237 @@
+package request_validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	json_schema "github.com/eolinker/goku-api-gateway/common/json-schema"
+	"github.com/eolinker/goku-api-gateway/common/openapi"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+//Error 校验错误，In为body、query、header、path或cookie
+type Error struct {
+	In      string `json:"in"`
+	Name    string `json:"name,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+//Validator 请求校验器
+type Validator struct {
+	body    *json_schema.Schema
+	query   *json_schema.Schema
+	headers *json_schema.Schema
+
+	doc *openapi.Document
+	op  *openapi.Operation
+}
+
+//ParseDocuments 解析OpenAPI文档，解析失败的文档不参与校验
+func ParseDocuments(docs map[string]string) map[string]*openapi.Document {
+	documents := make(map[string]*openapi.Document, len(docs))
+	for name, content := range docs {
+		d, err := openapi.Parse([]byte(content))
+		if err != nil {
+			log.Warn("parse openapi document ", name, " error:", err)
+			continue
+		}
+		documents[name] = d
+	}
+	return documents
+}
+
+//New 根据接口配置创建校验器，未开启时返回nil
+func New(cfg *config.ValidationConfig, docs map[string]*openapi.Document) (*Validator, error) {
+	if cfg == nil || !cfg.Enable {
+		return nil, nil
+	}
+	v := new(Validator)
+	if cfg.OpenAPI != "" {
+		d, has := docs[cfg.OpenAPI]
+		if !has {
+			return nil, fmt.Errorf("openapi document %s does not exist", cfg.OpenAPI)
+		}
+		v.doc = d
+		if cfg.Operation != "" {
+			op, has := d.Operation(cfg.Operation)
+			if !has {
+				return nil, fmt.Errorf("operation %s does not exist in %s", cfg.Operation, cfg.OpenAPI)
+			}
+			v.op = op
+		}
+	}
+	var err error
+	for _, item := range []struct {
+		name   string
+		raw    string
+		target **json_schema.Schema
+	}{
+		{"body", cfg.Body, &v.body},
+		{"query", cfg.Query, &v.query},
+		{"headers", cfg.Headers, &v.headers},
+	} {
+		if item.raw == "" {
+			continue
+		}
+		if *item.target, err = json_schema.Compile([]byte(item.raw)); err != nil {
+			return nil, fmt.Errorf("%s schema: %s", item.name, err)
+		}
+	}
+	return v, nil
+}
+
+//Check 校验请求，不通过时返回400及详细错误，v为nil时不校验
+func (v *Validator) Check(ctx *common.Context) bool {
+	if v == nil {
+		return true
+	}
+	errs := v.Validate(ctx)
+	if len(errs) == 0 {
+		return true
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"code":    http.StatusBadRequest,
+		"message": "request validation failed",
+		"errors":  errs,
+	})
+	ctx.SetStatus(http.StatusBadRequest, "400")
+	ctx.Set().SetHeader("Content-Type", "application/json; charset=utf-8")
+	ctx.SetBody(data)
+	return false
+}
+
+//Validate 校验请求，返回全部错误
+func (v *Validator) Validate(ctx *common.Context) []*Error {
+	errs := make([]*Error, 0)
+	req := ctx.RequestOrg
+	query := req.URL().Query()
+
+	if v.query != nil {
+		errs = appendErrors(errs, "query", v.query.Validate(paramObject(v.query, func(name string) []string {
+			return query[name]
+		}, query)))
+	}
+	if v.headers != nil {
+		header := req.Headers()
+		errs = appendErrors(errs, "header", v.headers.Validate(paramObject(v.headers, func(name string) []string {
+			return header[http.CanonicalHeaderKey(name)]
+		}, nil)))
+	}
+	if v.body != nil {
+		errs = append(errs, validateBody(ctx, v.body, true)...)
+	}
+	if v.doc != nil {
+		errs = append(errs, v.validateOperation(ctx, query)...)
+	}
+	return errs
+}
+
+func (v *Validator) validateOperation(ctx *common.Context, query url.Values) []*Error {
+	req := ctx.RequestOrg
+	op := v.op
+	var pathParams map[string]string
+	if op == nil {
+		var has bool
+		op, pathParams, has = v.doc.Find(req.Method(), req.URL().Path)
+		if !has {
+			log.Debug(ctx.RequestId(), " no openapi operation matches ", req.Method(), " ", req.URL().Path)
+			return nil
+		}
+	} else if params, has := v.doc.Match(op, req.URL().Path); has {
+		pathParams = params
+	} else {
+		pathParams = ctx.RestfulParam
+	}
+
+	errs := make([]*Error, 0)
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if value, has := pathParams[p.Name]; has {
+				values = []string{value}
+			}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = req.Headers()[http.CanonicalHeaderKey(p.Name)]
+		case "cookie":
+			if c, err := req.Cookie(p.Name); err == nil {
+				values = []string{c.Value}
+			}
+		default:
+			continue
+		}
+		if len(values) == 0 {
+			if p.Required {
+				errs = append(errs, &Error{In: p.In, Name: p.Name, Message: "missing required parameter"})
+			}
+			continue
+		}
+		for _, e := range p.Schema.Validate(p.Schema.Coerce(strings.Join(values, ","))) {
+			errs = append(errs, &Error{In: p.In, Name: p.Name, Path: e.Path, Message: e.Message})
+		}
+	}
+	if op.Body != nil {
+		errs = append(errs, validateBody(ctx, op.Body, op.BodyRequired)...)
+	}
+	return errs
+}
+
+// validateBody 校验请求体，JSON按原样校验，表单按Schema声明的类型转换后校验
+func validateBody(ctx *common.Context, schema *json_schema.Schema, required bool) []*Error {
+	raw, _ := ctx.RequestOrg.RawBody()
+	if len(raw) == 0 {
+		if required {
+			return []*Error{{In: "body", Message: "request body is required"}}
+		}
+		return nil
+	}
+	var body interface{}
+	mediaType, _, _ := mime.ParseMediaType(ctx.RequestOrg.ContentType())
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		form, err := ctx.RequestOrg.BodyForm()
+		if err != nil {
+			return []*Error{{In: "body", Message: "invalid form body: " + err.Error()}}
+		}
+		body = paramObject(schema, func(name string) []string {
+			return form[name]
+		}, form)
+	default:
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return []*Error{{In: "body", Message: "invalid JSON body: " + err.Error()}}
+		}
+	}
+	return appendErrors(nil, "body", schema.Validate(body))
+}
+
+// paramObject 将query参数、请求头等字符串参数转换为对象，all不为nil时包含未在Schema中声明的参数
+func paramObject(schema *json_schema.Schema, get func(name string) []string, all url.Values) map[string]interface{} {
+	object := make(map[string]interface{})
+	for name, values := range all {
+		object[name] = strings.Join(values, ",")
+	}
+	for _, name := range schema.Properties() {
+		values := get(name)
+		if len(values) == 0 {
+			continue
+		}
+		object[name] = schema.Property(name).Coerce(strings.Join(values, ","))
+	}
+	return object
+}
+
+func appendErrors(errs []*Error, in string, list []*json_schema.Error) []*Error {
+	for _, e := range list {
+		errs = append(errs, &Error{In: in, Path: e.Path, Message: e.Message})
+	}
+	return errs
+}
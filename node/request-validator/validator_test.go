@@ -0,0 +1,88 @@
+package request_validator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const document = `{
+  "openapi": "3.0.0",
+  "info": {"title": "users", "version": "1"},
+  "paths": {
+    "/users/{id}": {
+      "put": {
+        "operationId": "updateUser",
+        "parameters": [
+          {"name": "id", "in": "path", "schema": {"type": "integer"}},
+          {"name": "X-Trace", "in": "header", "required": true, "schema": {"type": "string"}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object", "required": ["name"]}}}
+        }
+      }
+    }
+  }
+}`
+
+func newContext(method, target, contentType, body string, header map[string]string) *common.Context {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return common.NewContext(r, "test", httptest.NewRecorder())
+}
+
+func TestSchemaValidation(t *testing.T) {
+	v, err := New(&config.ValidationConfig{
+		Enable:  true,
+		Query:   `{"type":"object","required":["page"],"properties":{"page":{"type":"integer","minimum":1}}}`,
+		Headers: `{"type":"object","properties":{"X-Version":{"enum":["1","2"]}}}`,
+		Body:    `{"type":"object","properties":{"age":{"type":"integer"}}}`,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newContext(http.MethodPost, "/users?page=2", "application/x-www-form-urlencoded", "age=3", map[string]string{"X-Version": "2"})
+	if errs := v.Validate(ctx); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %+v", errs[0])
+	}
+	ctx = newContext(http.MethodPost, "/users?page=0", "application/json", `{"age":"x"}`, map[string]string{"X-Version": "3"})
+	if v.Check(ctx) || ctx.StatusCode() != http.StatusBadRequest {
+		t.Fatal("expected rejected")
+	}
+	if errs := v.Validate(ctx); len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d", len(errs))
+	}
+}
+
+func TestOpenAPIValidation(t *testing.T) {
+	docs := ParseDocuments(map[string]string{"users": document})
+	if _, err := New(&config.ValidationConfig{Enable: true, OpenAPI: "users", Operation: "none"}, docs); err == nil {
+		t.Fatal("expected missing operation error")
+	}
+	v, err := New(&config.ValidationConfig{Enable: true, OpenAPI: "users"}, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newContext(http.MethodPut, "/users/1", "application/json", `{"name":"tom"}`, map[string]string{"X-Trace": "t"})
+	if errs := v.Validate(ctx); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %+v", errs[0])
+	}
+	ctx = newContext(http.MethodPut, "/users/x", "application/json", "", nil)
+	errs := v.Validate(ctx)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d", len(errs))
+	}
+	if errs[0].In != "path" || errs[1].In != "header" || errs[2].In != "body" {
+		t.Fatalf("unexpected errors: %+v %+v %+v", errs[0], errs[1], errs[2])
+	}
+}
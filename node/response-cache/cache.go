@@ -0,0 +1,256 @@
+package response_cache
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	redis_manager "github.com/eolinker/goku-api-gateway/common/redis-manager"
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+const (
+	//HeaderCache 缓存状态响应头
+	HeaderCache = "X-Goku-Cache"
+	//HeaderCacheKey 缓存key响应头，可用于按key清除缓存
+	HeaderCacheKey = "X-Goku-Cache-Key"
+
+	//StateHit 命中缓存
+	StateHit = "HIT"
+	//StateMiss 未命中缓存，已回源
+	StateMiss = "MISS"
+	//StateStale 返回了过期的缓存
+	StateStale = "STALE"
+	//StateRevalidated 缓存经后端确认未修改
+	StateRevalidated = "REVALIDATED"
+	//StateBypass 请求要求不使用缓存
+	StateBypass = "BYPASS"
+)
+
+var (
+	// 内存缓存在配置刷新后继续保留
+	localStore = NewLocalStore(0, 0)
+	calls      = newGroup()
+)
+
+//Executor 回源执行器
+type Executor interface {
+	Execute(ctx *common.Context)
+}
+
+//Cache 接口响应缓存
+type Cache struct {
+	apiID       int
+	ttl         time.Duration
+	staleTTL    time.Duration
+	queryParams []string
+	headers     []string
+	consumer    bool
+	statusCodes map[int]bool
+	shared      bool
+}
+
+//NewCache 根据接口配置创建响应缓存，未开启时返回nil
+func NewCache(apiID int, cfg *config.ResponseCacheConfig) *Cache {
+	if cfg == nil || !cfg.Enable || cfg.TTL <= 0 {
+		return nil
+	}
+	staleTTL := cfg.StaleTTL
+	if staleTTL < 0 {
+		staleTTL = 0
+	}
+	c := &Cache{
+		apiID:       apiID,
+		ttl:         time.Duration(cfg.TTL) * time.Second,
+		staleTTL:    time.Duration(staleTTL) * time.Second,
+		queryParams: sortedNames(cfg.QueryParams, false),
+		headers:     sortedNames(cfg.Headers, true),
+		consumer:    cfg.Consumer,
+		statusCodes: make(map[int]bool),
+		shared:      cfg.Shared,
+	}
+	for _, code := range cfg.StatusCodes {
+		c.statusCodes[code] = true
+	}
+	if len(c.statusCodes) == 0 {
+		c.statusCodes[http.StatusOK] = true
+	}
+	return c
+}
+
+//Execute 优先使用缓存响应，未命中时由app回源，c为nil时直接回源
+func (c *Cache) Execute(ctx *common.Context, app Executor) {
+	if c == nil {
+		app.Execute(ctx)
+		return
+	}
+	method := ctx.RequestOrg.Method()
+	if method != http.MethodGet && method != http.MethodHead {
+		app.Execute(ctx)
+		return
+	}
+	reqCC := ParseCacheControl(ctx.RequestOrg.GetHeader("Cache-Control"))
+	if reqCC.Has("no-store") {
+		app.Execute(ctx)
+		ctx.Set().SetHeader(HeaderCache, StateBypass)
+		return
+	}
+	refresh := reqCC.Has("no-cache") || ctx.RequestOrg.GetHeader("Pragma") == "no-cache"
+
+	key := c.key(ctx)
+	now := time.Now()
+	entry, has := c.get(key)
+	if has && !entry.Usable(now) {
+		entry, has = nil, false
+	}
+	if has && !refresh && entry.Fresh(now) {
+		c.serve(ctx, key, entry, StateHit, now)
+		return
+	}
+
+	cl, leader := calls.begin(key)
+	if !leader {
+		// 已有请求在刷新缓存，直接返回旧响应
+		if has && !refresh {
+			c.serve(ctx, key, entry, StateStale, now)
+			return
+		}
+		if e := cl.wait(); e != nil {
+			c.serve(ctx, key, e, StateHit, time.Now())
+			return
+		}
+		// 回源结果不可缓存，各自回源
+		app.Execute(ctx)
+		c.mark(ctx, key, StateMiss)
+		return
+	}
+
+	var result *Entry
+	defer func() {
+		calls.end(key, cl, result)
+	}()
+	result = c.fetch(ctx, app, key, entry, method)
+}
+
+// fetch 回源并写入缓存，old不为nil时向后端发起条件请求，返回可供等待中的请求使用的缓存
+func (c *Cache) fetch(ctx *common.Context, app Executor, key string, old *Entry, method string) *Entry {
+	// 客户端的条件请求由缓存应答，回源时需要完整响应
+	ctx.ProxyRequest.DelHeader("If-None-Match")
+	ctx.ProxyRequest.DelHeader("If-Modified-Since")
+	if old != nil {
+		if old.ETag != "" {
+			ctx.ProxyRequest.SetHeader("If-None-Match", old.ETag)
+		}
+		if old.LastModified != "" {
+			ctx.ProxyRequest.SetHeader("If-Modified-Since", old.LastModified)
+		}
+	}
+
+	app.Execute(ctx)
+
+	now := time.Now()
+	statusCode := ctx.StatusCode()
+	header := ctx.Headers()
+
+	if old != nil && statusCode == http.StatusNotModified {
+		renewed := *old
+		renewed.Header = cloneHeader(old.Header)
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if v := header.Get(name); v != "" {
+				renewed.Header.Set(name, v)
+			}
+		}
+		renewed.ETag = renewed.Header.Get("ETag")
+		renewed.LastModified = renewed.Header.Get("Last-Modified")
+		ttl, ok := storable(renewed.Header, c.ttl)
+		if !ok {
+			ttl = c.ttl
+		}
+		renewed.renew(now, ttl, c.staleTTL)
+		c.set(key, &renewed)
+		c.serve(ctx, key, &renewed, StateRevalidated, now)
+		return &renewed
+	}
+
+	// 后端异常时在旧响应可用期内返回旧响应
+	if old != nil && (statusCode == 0 || statusCode >= http.StatusInternalServerError) && old.Usable(now) {
+		c.serve(ctx, key, old, StateStale, now)
+		return old
+	}
+
+	if method != http.MethodGet || !c.statusCodes[statusCode] {
+		c.mark(ctx, key, StateMiss)
+		return nil
+	}
+	// 携带凭证的请求只有按调用方区分缓存或后端明确允许共享缓存时才缓存
+	if !c.consumer && consumer(ctx) != "" && !sharable(header) {
+		c.mark(ctx, key, StateMiss)
+		return nil
+	}
+	ttl, ok := storable(header, c.ttl)
+	if !ok {
+		c.mark(ctx, key, StateMiss)
+		return nil
+	}
+	e := newEntry(statusCode, ctx.Status(), header, ctx.Body, now, ttl, c.staleTTL)
+	c.set(key, e)
+	c.serve(ctx, key, e, StateMiss, now)
+	return e
+}
+
+// serve 使用缓存响应，客户端条件请求匹配时返回304
+func (c *Cache) serve(ctx *common.Context, key string, e *Entry, state string, now time.Time) {
+	header := cloneHeader(e.Header)
+	if e.notModified(ctx.RequestOrg.Headers()) {
+		ctx.SetProxyResponseHandler(common.NewResponseReader(header, http.StatusNotModified, "304 Not Modified", nil))
+	} else {
+		ctx.SetProxyResponseHandler(common.NewResponseReader(header, e.StatusCode, e.Status, e.Body))
+	}
+	ctx.Set().SetHeader("Age", strconv.Itoa(e.Age(now)))
+	c.mark(ctx, key, state)
+}
+
+func (c *Cache) mark(ctx *common.Context, key, state string) {
+	ctx.Set().SetHeader(HeaderCache, state)
+	ctx.Set().SetHeader(HeaderCacheKey, key)
+}
+
+// store 开启共享且设置了redis时使用redis，否则使用内存缓存
+func (c *Cache) store() Store {
+	if c.shared {
+		if rds, has := redis_manager.Default(); has {
+			return NewRedisStore(rds)
+		}
+	}
+	return localStore
+}
+
+func (c *Cache) get(key string) (*Entry, bool) {
+	store := c.store()
+	e, has, err := store.Get(key)
+	if err != nil && store != Store(localStore) {
+		log.Warn("response cache use redis error, fallback to local:", err)
+		e, has, _ = localStore.Get(key)
+	}
+	return e, has
+}
+
+func (c *Cache) set(key string, e *Entry) {
+	store := c.store()
+	err := store.Set(c.apiID, key, e)
+	if err != nil && store != Store(localStore) {
+		log.Warn("response cache use redis error, fallback to local:", err)
+		_ = localStore.Set(c.apiID, key, e)
+	}
+}
+
+//Purge 清除接口缓存，key为空时清除接口下的全部缓存
+func Purge(apiID int, key string) error {
+	_ = localStore.Purge(apiID, key)
+	if rds, has := redis_manager.Default(); has {
+		return NewRedisStore(rds).Purge(apiID, key)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package response_cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+type backend struct {
+	calls  int
+	header http.Header
+	status int
+}
+
+func (b *backend) Execute(ctx *common.Context) {
+	b.calls++
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if ctx.ProxyRequest.GetHeader("If-None-Match") == "\"v1\"" {
+		status = http.StatusNotModified
+	}
+	header := cloneHeader(b.header)
+	header.Set("ETag", "\"v1\"")
+	ctx.SetProxyResponseHandler(common.NewResponseReader(header, status, http.StatusText(status), []byte("body")))
+}
+
+func newContext(target string, header map[string]string) *common.Context {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return common.NewContext(r, "test", httptest.NewRecorder())
+}
+
+func state(ctx *common.Context) string {
+	h := ctx.Set().(*common.Header)
+	return h.GetHeader(HeaderCache)
+}
+
+func TestCacheHit(t *testing.T) {
+	localStore = NewLocalStore(0, 0)
+	c := NewCache(1, &config.ResponseCacheConfig{Enable: true, TTL: 60, QueryParams: []string{"page"}})
+	b := &backend{}
+
+	ctx := newContext("/users?page=1&ts=1", nil)
+	c.Execute(ctx, b)
+	if state(ctx) != StateMiss {
+		t.Fatalf("first request: %s", state(ctx))
+	}
+	ctx = newContext("/users?ts=2&page=1", nil)
+	c.Execute(ctx, b)
+	if state(ctx) != StateHit || string(ctx.Body) != "body" || b.calls != 1 {
+		t.Fatalf("second request: %s %q calls=%d", state(ctx), ctx.Body, b.calls)
+	}
+	ctx = newContext("/users?page=2", nil)
+	c.Execute(ctx, b)
+	if b.calls != 2 {
+		t.Fatalf("page 2 should miss, calls=%d", b.calls)
+	}
+	ctx = newContext("/users?page=1", map[string]string{"If-None-Match": "\"v1\""})
+	c.Execute(ctx, b)
+	if ctx.StatusCode() != http.StatusNotModified {
+		t.Fatalf("conditional request: %d", ctx.StatusCode())
+	}
+}
+
+func TestCacheNotStorable(t *testing.T) {
+	localStore = NewLocalStore(0, 0)
+	c := NewCache(2, &config.ResponseCacheConfig{Enable: true, TTL: 60})
+	b := &backend{header: http.Header{"Cache-Control": {"private"}}}
+	for i := 0; i < 2; i++ {
+		c.Execute(newContext("/private", nil), b)
+	}
+	if b.calls != 2 {
+		t.Fatalf("private response cached, calls=%d", b.calls)
+	}
+	b = &backend{status: http.StatusInternalServerError}
+	for i := 0; i < 2; i++ {
+		c.Execute(newContext("/error", nil), b)
+	}
+	if b.calls != 2 {
+		t.Fatalf("error response cached, calls=%d", b.calls)
+	}
+}
+
+func TestCacheAuthorized(t *testing.T) {
+	localStore = NewLocalStore(0, 0)
+	c := NewCache(4, &config.ResponseCacheConfig{Enable: true, TTL: 60})
+	b := &backend{}
+	c.Execute(newContext("/me", map[string]string{"Authorization": "Bearer a"}), b)
+	ctx := newContext("/me", map[string]string{"Authorization": "Bearer b"})
+	c.Execute(ctx, b)
+	if b.calls != 2 || state(ctx) == StateHit {
+		t.Fatalf("authorized response shared, calls=%d", b.calls)
+	}
+
+	b = &backend{header: http.Header{"Cache-Control": {"public, max-age=60"}}}
+	c.Execute(newContext("/public", map[string]string{"Authorization": "Bearer a"}), b)
+	c.Execute(newContext("/public", map[string]string{"Authorization": "Bearer b"}), b)
+	if b.calls != 1 {
+		t.Fatalf("public response not cached, calls=%d", b.calls)
+	}
+
+	c = NewCache(5, &config.ResponseCacheConfig{Enable: true, TTL: 60, Consumer: true})
+	b = &backend{}
+	for i := 0; i < 2; i++ {
+		c.Execute(newContext("/me", map[string]string{"Authorization": "Bearer a"}), b)
+	}
+	if b.calls != 1 {
+		t.Fatalf("response per consumer not cached, calls=%d", b.calls)
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	localStore = NewLocalStore(0, 0)
+	c := NewCache(3, &config.ResponseCacheConfig{Enable: true, TTL: 60, StaleTTL: 60})
+	b := &backend{}
+	ctx := newContext("/items", nil)
+	c.Execute(ctx, b)
+	key := ctx.Set().(*common.Header).GetHeader(HeaderCacheKey)
+
+	e, _, _ := localStore.Get(key)
+	e.Expires = time.Now().Add(-time.Second)
+
+	ctx = newContext("/items", nil)
+	c.Execute(ctx, b)
+	if state(ctx) != StateRevalidated || ctx.StatusCode() != http.StatusOK || string(ctx.Body) != "body" {
+		t.Fatalf("revalidate: %s %d %q", state(ctx), ctx.StatusCode(), ctx.Body)
+	}
+
+	if err := Purge(3, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, has, _ := localStore.Get(key); has {
+		t.Fatal("purged entry still exists")
+	}
+}
+
+func TestLocalStoreEvict(t *testing.T) {
+	s := NewLocalStore(2, 0)
+	now := time.Now()
+	for _, key := range []string{"a", "b", "c"} {
+		s.Set(1, key, newEntry(200, "200", http.Header{}, nil, now, time.Minute, 0))
+	}
+	if _, has, _ := s.Get("a"); has {
+		t.Fatal("a should be evicted")
+	}
+	if s.Len() != 2 {
+		t.Fatalf("len=%d", s.Len())
+	}
+}
+
+func TestStorable(t *testing.T) {
+	cases := []struct {
+		cc  string
+		ttl time.Duration
+		ok  bool
+	}{
+		{"", time.Minute, true},
+		{"max-age=10", 10 * time.Second, true},
+		{"max-age=10, s-maxage=20", 20 * time.Second, true},
+		{"max-age=600", time.Minute, true},
+		{"max-age=0", 0, false},
+		{"no-store", 0, false},
+	}
+	for _, item := range cases {
+		header := http.Header{}
+		if item.cc != "" {
+			header.Set("Cache-Control", item.cc)
+		}
+		ttl, ok := storable(header, time.Minute)
+		if ttl != item.ttl || ok != item.ok {
+			t.Fatalf("%q: ttl=%s ok=%v", item.cc, ttl, ok)
+		}
+	}
+}
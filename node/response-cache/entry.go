@@ -0,0 +1,179 @@
+package response_cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Entry 缓存的响应
+type Entry struct {
+	StatusCode   int         `json:"statusCode"`
+	Status       string      `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+
+	StoredAt   time.Time `json:"storedAt"`
+	Expires    time.Time `json:"expires"`
+	StaleUntil time.Time `json:"staleUntil"`
+}
+
+func newEntry(statusCode int, status string, header http.Header, body []byte, now time.Time, ttl, staleTTL time.Duration) *Entry {
+	e := &Entry{
+		StatusCode:   statusCode,
+		Status:       status,
+		Header:       cloneHeader(header),
+		Body:         body,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+	e.renew(now, ttl, staleTTL)
+	return e
+}
+
+// renew 刷新缓存时间，用于写入及后端返回304时
+func (e *Entry) renew(now time.Time, ttl, staleTTL time.Duration) {
+	e.StoredAt = now
+	e.Expires = now.Add(ttl)
+	e.StaleUntil = e.Expires.Add(staleTTL)
+}
+
+//Fresh 是否仍在有效期内
+func (e *Entry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+//Usable 是否可以作为旧响应返回
+func (e *Entry) Usable(now time.Time) bool {
+	return now.Before(e.StaleUntil)
+}
+
+//Age 缓存时长，单位秒
+func (e *Entry) Age(now time.Time) int {
+	age := int(now.Sub(e.StoredAt) / time.Second)
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+func (e *Entry) size() int {
+	n := len(e.Body)
+	for k, vs := range e.Header {
+		n += len(k)
+		for _, v := range vs {
+			n += len(v)
+		}
+	}
+	return n
+}
+
+// notModified 客户端的条件请求是否与缓存一致
+func (e *Entry) notModified(header http.Header) bool {
+	if inm := header.Get("If-None-Match"); inm != "" {
+		if e.ETag == "" {
+			return false
+		}
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || weakTag(tag) == weakTag(e.ETag) {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := header.Get("If-Modified-Since"); ims != "" && e.LastModified != "" {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		modified, err := http.ParseTime(e.LastModified)
+		if err != nil {
+			return false
+		}
+		return !modified.After(since)
+	}
+	return false
+}
+
+func weakTag(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
+
+func cloneHeader(header http.Header) http.Header {
+	h := make(http.Header, len(header))
+	for k, vs := range header {
+		h[k] = append([]string(nil), vs...)
+	}
+	return h
+}
+
+//CacheControl 解析后的Cache-Control指令
+type CacheControl map[string]string
+
+//ParseCacheControl 解析Cache-Control头，指令名统一为小写
+func ParseCacheControl(value string) CacheControl {
+	cc := make(CacheControl)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			name, arg = strings.TrimSpace(part[:i]), strings.Trim(strings.TrimSpace(part[i+1:]), "\"")
+		}
+		cc[strings.ToLower(name)] = arg
+	}
+	return cc
+}
+
+//Has 是否包含指令
+func (cc CacheControl) Has(name string) bool {
+	_, has := cc[name]
+	return has
+}
+
+//Seconds 读取秒数参数，不存在或非法时返回false
+func (cc CacheControl) Seconds(name string) (time.Duration, bool) {
+	v, has := cc[name]
+	if !has {
+		return 0, false
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 0 {
+		return 0, false
+	}
+	return time.Duration(i) * time.Second, true
+}
+
+// sharable 携带鉴权信息的请求，其响应是否允许共享缓存（RFC 7234 3.2）
+func sharable(header http.Header) bool {
+	cc := ParseCacheControl(strings.Join(header["Cache-Control"], ","))
+	return cc.Has("public") || cc.Has("s-maxage") || cc.Has("must-revalidate")
+}
+
+// storable 根据后端响应头判断是否可以缓存，返回缓存有效期，有效期不超过maxTTL
+func storable(header http.Header, maxTTL time.Duration) (time.Duration, bool) {
+	if header.Get("Set-Cookie") != "" {
+		return 0, false
+	}
+	cc := ParseCacheControl(strings.Join(header["Cache-Control"], ","))
+	if cc.Has("no-store") || cc.Has("private") || cc.Has("no-cache") {
+		return 0, false
+	}
+	ttl, has := cc.Seconds("s-maxage")
+	if !has {
+		ttl, has = cc.Seconds("max-age")
+	}
+	if !has || ttl > maxTTL {
+		ttl = maxTTL
+	}
+	if ttl <= 0 {
+		return 0, false
+	}
+	return ttl, true
+}
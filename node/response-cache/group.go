@@ -0,0 +1,47 @@
+package response_cache
+
+import "sync"
+
+// call 正在进行的回源请求
+type call struct {
+	wg    sync.WaitGroup
+	entry *Entry
+}
+
+// group 合并相同缓存key的回源请求，同一时刻只有一个请求回源
+type group struct {
+	lock  sync.Mutex
+	calls map[string]*call
+}
+
+func newGroup() *group {
+	return &group{calls: make(map[string]*call)}
+}
+
+// begin 开始回源，返回的leader为true时由调用方回源并在结束后调用end
+func (g *group) begin(key string) (*call, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if c, has := g.calls[key]; has {
+		return c, false
+	}
+	c := new(call)
+	c.wg.Add(1)
+	g.calls[key] = c
+	return c, true
+}
+
+// end 结束回源，entry为nil表示响应不可缓存
+func (g *group) end(key string, c *call, entry *Entry) {
+	c.entry = entry
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+	c.wg.Done()
+}
+
+// wait 等待回源结束
+func (c *call) wait() *Entry {
+	c.wg.Wait()
+	return c.entry
+}
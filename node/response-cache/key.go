@@ -0,0 +1,84 @@
+package response_cache
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	rate_limit "github.com/eolinker/goku-api-gateway/node/rate-limit"
+)
+
+func indexKey(apiID int) string {
+	return fmt.Sprintf("goku:cache-index:%d", apiID)
+}
+
+// key 生成缓存key，由策略、路径、指定的query参数、请求头及调用方组成，HEAD请求与GET请求共用缓存
+func (c *Cache) key(ctx *common.Context) string {
+	var b strings.Builder
+	b.WriteString(ctx.StrategyId())
+	b.WriteByte('\n')
+	b.WriteString(ctx.RequestOrg.URL().Path)
+	b.WriteByte('\n')
+	b.WriteString(c.query(ctx.RequestOrg.URL().Query()))
+	for _, name := range c.headers {
+		b.WriteByte('\n')
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(ctx.RequestOrg.GetHeader(name))
+	}
+	if c.consumer {
+		b.WriteByte('\n')
+		b.WriteString(consumer(ctx))
+	}
+	sum := md5.Sum([]byte(b.String()))
+	return config.ResponseCacheKeyPrefix(c.apiID) + hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) query(values url.Values) string {
+	if len(c.queryParams) == 0 {
+		return values.Encode()
+	}
+	selected := make(url.Values, len(c.queryParams))
+	for _, name := range c.queryParams {
+		if vs, has := values[name]; has {
+			selected[name] = vs
+		}
+	}
+	return selected.Encode()
+}
+
+// consumer 调用方标识，优先使用鉴权通过后的凭证
+func consumer(ctx *common.Context) string {
+	if v, has := ctx.GetCache(rate_limit.CredentialCacheKey); has {
+		if s := fmt.Sprint(v); s != "" {
+			return s
+		}
+	}
+	for _, name := range []string{"Authorization", "Apikey", "X-Api-Key"} {
+		if v := ctx.RequestOrg.GetHeader(name); v != "" {
+			return name + ":" + v
+		}
+	}
+	return ""
+}
+
+func sortedNames(names []string, canonical bool) []string {
+	list := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if canonical {
+			name = strings.ToLower(name)
+		}
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
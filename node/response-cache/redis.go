@@ -0,0 +1,78 @@
+package response_cache
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+//RedisStore 基于redis的共享缓存，每个接口使用一个集合记录缓存key，用于按接口清除
+type RedisStore struct {
+	client redis.Cmdable
+}
+
+//NewRedisStore 创建redis缓存
+func NewRedisStore(client redis.Cmdable) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+//Get 获取缓存
+func (s *RedisStore) Get(key string) (*Entry, bool, error) {
+	data, err := s.client.Get(key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	e := new(Entry)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, false, err
+	}
+	return e, true, nil
+}
+
+//Set 写入缓存，缓存在旧响应可用期结束后由redis清除
+func (s *RedisStore) Set(apiID int, key string, e *Entry) error {
+	ttl := time.Until(e.StaleUntil)
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(key, data, ttl).Err(); err != nil {
+		return err
+	}
+	index := indexKey(apiID)
+	if err := s.client.SAdd(index, key).Err(); err != nil {
+		return err
+	}
+	if current, err := s.client.TTL(index).Result(); err == nil && current < ttl {
+		s.client.Expire(index, ttl)
+	}
+	return nil
+}
+
+//Purge 清除缓存，集群模式下key可能分布在不同节点，逐个删除
+func (s *RedisStore) Purge(apiID int, key string) error {
+	index := indexKey(apiID)
+	if key != "" {
+		if err := s.client.Del(key).Err(); err != nil {
+			return err
+		}
+		return s.client.SRem(index, key).Err()
+	}
+	keys, err := s.client.SMembers(index).Result()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := s.client.Del(k).Err(); err != nil {
+			return err
+		}
+	}
+	return s.client.Del(index).Err()
+}
@@ -0,0 +1,123 @@
+package response_cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+const (
+	defaultMaxEntries = 10000
+	defaultMaxBytes   = 64 << 20
+)
+
+//Store 缓存存储
+type Store interface {
+	Get(key string) (*Entry, bool, error)
+	Set(apiID int, key string, e *Entry) error
+	//Purge 清除缓存，key为空时清除接口下的全部缓存
+	Purge(apiID int, key string) error
+}
+
+type lruItem struct {
+	key   string
+	entry *Entry
+	size  int
+}
+
+//LocalStore 节点内存缓存，按条数及字节数淘汰最久未使用的缓存
+type LocalStore struct {
+	lock       sync.Mutex
+	items      map[string]*list.Element
+	ll         *list.List
+	bytes      int
+	maxEntries int
+	maxBytes   int
+}
+
+//NewLocalStore 创建内存缓存，参数小于等于0时使用默认值
+func NewLocalStore(maxEntries, maxBytes int) *LocalStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+	return &LocalStore{
+		items:      make(map[string]*list.Element),
+		ll:         list.New(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+//Get 获取缓存，已超过旧响应可用期的缓存视为不存在
+func (s *LocalStore) Get(key string) (*Entry, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	el, has := s.items[key]
+	if !has {
+		return nil, false, nil
+	}
+	item := el.Value.(*lruItem)
+	if !item.entry.Usable(time.Now()) {
+		s.remove(el)
+		return nil, false, nil
+	}
+	s.ll.MoveToFront(el)
+	return item.entry, true, nil
+}
+
+//Set 写入缓存
+func (s *LocalStore) Set(apiID int, key string, e *Entry) error {
+	size := e.size() + len(key)
+	if size > s.maxBytes {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if el, has := s.items[key]; has {
+		s.remove(el)
+	}
+	s.items[key] = s.ll.PushFront(&lruItem{key: key, entry: e, size: size})
+	s.bytes += size
+	for s.ll.Len() > s.maxEntries || s.bytes > s.maxBytes {
+		s.remove(s.ll.Back())
+	}
+	return nil
+}
+
+//Purge 清除缓存
+func (s *LocalStore) Purge(apiID int, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if key != "" {
+		if el, has := s.items[key]; has {
+			s.remove(el)
+		}
+		return nil
+	}
+	prefix := config.ResponseCacheKeyPrefix(apiID)
+	for k, el := range s.items {
+		if strings.HasPrefix(k, prefix) {
+			s.remove(el)
+		}
+	}
+	return nil
+}
+
+//Len 缓存条数
+func (s *LocalStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.ll.Len()
+}
+
+func (s *LocalStore) remove(el *list.Element) {
+	item := s.ll.Remove(el).(*lruItem)
+	delete(s.items, item.key)
+	s.bytes -= item.size
+}
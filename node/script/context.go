@@ -0,0 +1,280 @@
+package script
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/robertkrimen/otto"
+)
+
+// jsFunc 暴露给脚本的函数
+type jsFunc = func(call otto.FunctionCall) otto.Value
+
+// context 脚本中的ctx对象，只暴露当前阶段可用的能力
+type context struct {
+	vm          *otto.Otto
+	phase       string
+	ctx         *common.Context
+	maxDataSize int
+	used        int
+	err         error
+}
+
+func newContext(vm *otto.Otto, phase string, ctx *common.Context, maxDataSize int) *context {
+	return &context{
+		vm:          vm,
+		phase:       phase,
+		ctx:         ctx,
+		maxDataSize: maxDataSize,
+	}
+}
+
+// account 统计脚本读写的数据量，超过限制时中断脚本
+func (c *context) account(size int) {
+	c.used += size
+	if c.used > c.maxDataSize {
+		c.err = ErrorDataSize
+		panic(ErrorDataSize)
+	}
+}
+
+func (c *context) value(v interface{}) otto.Value {
+	value, err := c.vm.ToValue(v)
+	if err != nil {
+		return otto.UndefinedValue()
+	}
+	return value
+}
+
+func (c *context) str(v string) otto.Value {
+	c.account(len(v))
+	return c.value(v)
+}
+
+func arg(call otto.FunctionCall, i int) string {
+	v := call.Argument(i)
+	if v.IsUndefined() || v.IsNull() {
+		return ""
+	}
+	s, _ := v.ToString()
+	return s
+}
+
+func (c *context) headers(h http.Header) otto.Value {
+	m := make(map[string]interface{}, len(h))
+	for k, vs := range h {
+		v := strings.Join(vs, ",")
+		c.account(len(k) + len(v))
+		m[k] = v
+	}
+	return c.value(m)
+}
+
+func (c *context) object() otto.Value {
+	ctx := c.ctx
+	o := map[string]interface{}{
+		"phase":     c.phase,
+		"requestId": ctx.RequestId(),
+		"request":   c.request(),
+		"response":  c.response(),
+		"store": map[string]interface{}{
+			"get": jsFunc(func(call otto.FunctionCall) otto.Value {
+				return c.value(ctx.Store().Get())
+			}),
+			"set": jsFunc(func(call otto.FunctionCall) otto.Value {
+				v, _ := call.Argument(0).Export()
+				ctx.Store().Set(v)
+				return otto.UndefinedValue()
+			}),
+		},
+		"cache": map[string]interface{}{
+			"get": jsFunc(func(call otto.FunctionCall) otto.Value {
+				v, has := ctx.GetCache(arg(call, 0))
+				if !has {
+					return otto.UndefinedValue()
+				}
+				return c.value(v)
+			}),
+			"set": jsFunc(func(call otto.FunctionCall) otto.Value {
+				v, _ := call.Argument(1).Export()
+				ctx.SetCache(arg(call, 0), v)
+				return otto.UndefinedValue()
+			}),
+		},
+	}
+	if c.phase != PhaseBefore {
+		o["strategyId"] = ctx.StrategyId()
+		o["strategyName"] = ctx.StrategyName()
+		o["apiId"] = ctx.ApiID()
+	}
+	if c.phase != PhaseProxy {
+		o["proxy"] = c.proxy()
+	} else if ctx.ProxyResponseHandler != nil {
+		o["proxyResponse"] = c.proxyResponse()
+	}
+	return c.value(o)
+}
+
+// request 原始请求，只读
+func (c *context) request() map[string]interface{} {
+	r := c.ctx.Request()
+	return map[string]interface{}{
+		"method":     r.Method(),
+		"url":        r.URL().String(),
+		"path":       r.URL().Path,
+		"host":       r.Host(),
+		"remoteAddr": r.RemoteAddr(),
+		"header": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(r.GetHeader(arg(call, 0)))
+		}),
+		"headers": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.headers(r.Headers())
+		}),
+		"query": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(r.URL().Query().Get(arg(call, 0)))
+		}),
+		"cookie": jsFunc(func(call otto.FunctionCall) otto.Value {
+			cookie, err := r.Cookie(arg(call, 0))
+			if err != nil {
+				return otto.UndefinedValue()
+			}
+			return c.str(cookie.Value)
+		}),
+		"form": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(r.GetForm(arg(call, 0)))
+		}),
+		"body": jsFunc(func(call otto.FunctionCall) otto.Value {
+			body, _ := r.RawBody()
+			return c.str(string(body))
+		}),
+	}
+}
+
+// proxy 转发给后端的请求
+func (c *context) proxy() map[string]interface{} {
+	p := c.ctx.ProxyRequest
+	return map[string]interface{}{
+		"header": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(p.GetHeader(arg(call, 0)))
+		}),
+		"headers": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.headers(p.Headers())
+		}),
+		"setHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			c.account(len(arg(call, 0)) + len(arg(call, 1)))
+			p.SetHeader(arg(call, 0), arg(call, 1))
+			return otto.UndefinedValue()
+		}),
+		"addHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			c.account(len(arg(call, 0)) + len(arg(call, 1)))
+			p.AddHeader(arg(call, 0), arg(call, 1))
+			return otto.UndefinedValue()
+		}),
+		"delHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			p.DelHeader(arg(call, 0))
+			return otto.UndefinedValue()
+		}),
+		"query": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(p.Querys().Get(arg(call, 0)))
+		}),
+		"setQuery": jsFunc(func(call otto.FunctionCall) otto.Value {
+			c.account(len(arg(call, 0)) + len(arg(call, 1)))
+			p.Querys().Set(arg(call, 0), arg(call, 1))
+			return otto.UndefinedValue()
+		}),
+		"delQuery": jsFunc(func(call otto.FunctionCall) otto.Value {
+			p.Querys().Del(arg(call, 0))
+			return otto.UndefinedValue()
+		}),
+		"body": jsFunc(func(call otto.FunctionCall) otto.Value {
+			body, _ := p.RawBody()
+			return c.str(string(body))
+		}),
+		"setBody": jsFunc(func(call otto.FunctionCall) otto.Value {
+			body := arg(call, 1)
+			c.account(len(body))
+			p.SetRaw(arg(call, 0), []byte(body))
+			return otto.UndefinedValue()
+		}),
+	}
+}
+
+// proxyResponse 后端返回的响应，只读
+func (c *context) proxyResponse() map[string]interface{} {
+	r := c.ctx.ProxyResponse()
+	return map[string]interface{}{
+		"statusCode": r.StatusCode(),
+		"header": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(r.GetHeader(arg(call, 0)))
+		}),
+		"headers": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.headers(r.Headers())
+		}),
+		"body": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(string(r.GetBody()))
+		}),
+	}
+}
+
+// response 返回给客户端的响应
+func (c *context) response() map[string]interface{} {
+	ctx := c.ctx
+	return map[string]interface{}{
+		"header": jsFunc(func(call otto.FunctionCall) otto.Value {
+			if v := ctx.Set().GetHeader(arg(call, 0)); v != "" {
+				return c.str(v)
+			}
+			return c.str(ctx.GetHeader(arg(call, 0)))
+		}),
+		"setHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			c.account(len(arg(call, 0)) + len(arg(call, 1)))
+			ctx.Set().SetHeader(arg(call, 0), arg(call, 1))
+			return otto.UndefinedValue()
+		}),
+		"addHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			c.account(len(arg(call, 0)) + len(arg(call, 1)))
+			ctx.Append().AddHeader(arg(call, 0), arg(call, 1))
+			return otto.UndefinedValue()
+		}),
+		"delHeader": jsFunc(func(call otto.FunctionCall) otto.Value {
+			ctx.DelHeader(arg(call, 0))
+			ctx.Set().DelHeader(arg(call, 0))
+			ctx.Append().DelHeader(arg(call, 0))
+			return otto.UndefinedValue()
+		}),
+		"statusCode": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.value(ctx.StatusCode())
+		}),
+		"setStatus": jsFunc(func(call otto.FunctionCall) otto.Value {
+			code, _ := call.Argument(0).ToInteger()
+			status := arg(call, 1)
+			if status == "" {
+				status = fmt.Sprintf("%d %s", code, http.StatusText(int(code)))
+			}
+			ctx.SetStatus(int(code), status)
+			return otto.UndefinedValue()
+		}),
+		"body": jsFunc(func(call otto.FunctionCall) otto.Value {
+			return c.str(string(ctx.GetBody()))
+		}),
+		"setBody": jsFunc(func(call otto.FunctionCall) otto.Value {
+			body := arg(call, 0)
+			c.account(len(body))
+			ctx.SetBody([]byte(body))
+			return otto.UndefinedValue()
+		}),
+	}
+}
+
+func scriptLog(name string, call otto.FunctionCall) {
+	args := make([]interface{}, 0, len(call.ArgumentList)+1)
+	args = append(args, "[script] ", name, ":")
+	for _, v := range call.ArgumentList {
+		s, _ := v.ToString()
+		args = append(args, s, " ")
+	}
+	log.Info(args...)
+}
@@ -0,0 +1,52 @@
+package script
+
+import (
+	"sync"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+)
+
+var (
+	programs = make(map[string]*Program)
+	locker   sync.RWMutex
+)
+
+//Reset 重置脚本，名称及版本未变化的脚本不重新编译
+func Reset(scripts map[string]*config.ScriptConfig) {
+	locker.RLock()
+	old := programs
+	locker.RUnlock()
+
+	ps := make(map[string]*Program, len(scripts))
+	for name, cfg := range scripts {
+		if cfg == nil {
+			continue
+		}
+		if cfg.Name == "" {
+			cfg.Name = name
+		}
+		if p, has := old[name]; has && p.Version == cfg.Version {
+			ps[name] = p
+			continue
+		}
+		p, err := Compile(cfg)
+		if err != nil {
+			log.Warn("compile script ", name, " error:", err)
+			continue
+		}
+		ps[name] = p
+	}
+
+	locker.Lock()
+	programs = ps
+	locker.Unlock()
+}
+
+//Get 获取脚本
+func Get(name string) (*Program, bool) {
+	locker.RLock()
+	defer locker.RUnlock()
+	p, has := programs[name]
+	return p, has
+}
@@ -0,0 +1,354 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/ast"
+	"github.com/robertkrimen/otto/file"
+	"github.com/robertkrimen/otto/parser"
+)
+
+const (
+	//PhaseBefore 路由匹配前
+	PhaseBefore = "before"
+	//PhaseAccess 转发前
+	PhaseAccess = "access"
+	//PhaseProxy 转发后
+	PhaseProxy = "proxy"
+
+	defaultTimeout     = 50 * time.Millisecond
+	defaultMaxDataSize = 1 << 20
+	maxContentSize     = 64 << 10
+	// 沙箱中可达数据的估算大小上限，执行期间定时检查
+	maxMemory           = 16 << 20
+	memoryCheckInterval = 2 * time.Millisecond
+
+	//MaxTimeout 单次执行超时时间上限
+	MaxTimeout = time.Second
+
+	disableFunctionConstructor = `(function() {
+	var disabled = function() { throw new Error("Function constructor is not allowed"); };
+	Function.prototype.constructor = disabled;
+	Function = disabled;
+})();`
+)
+
+var (
+	//ErrorTimeout 脚本执行超时
+	ErrorTimeout = errors.New("script execution timeout")
+	//ErrorDataSize 脚本读写的数据超过限制
+	ErrorDataSize = errors.New("script data size exceeds limit")
+	//ErrorMemory 脚本占用的内存超过限制
+	ErrorMemory = errors.New("script memory exceeds limit")
+	//ErrorUnsupportedLanguage 不支持的脚本语言
+	ErrorUnsupportedLanguage = errors.New("unsupported script language")
+)
+
+//Program 编译后的脚本，沙箱在请求间复用，脚本不应在全局变量中保存请求相关的状态；
+//全局变量引用的数据同样计入内存限制，超过限制的沙箱将被丢弃
+type Program struct {
+	Name    string
+	Version int
+
+	program     *ast.Program
+	phases      map[string]bool
+	timeout     time.Duration
+	maxDataSize int
+	pool        sync.Pool
+}
+
+//Compile 编译脚本，并检查脚本定义了哪些阶段的处理函数
+func Compile(cfg *config.ScriptConfig) (*Program, error) {
+	if cfg.Language != "" && cfg.Language != config.ScriptLanguageJavaScript {
+		return nil, ErrorUnsupportedLanguage
+	}
+	if len(cfg.Content) > maxContentSize {
+		return nil, fmt.Errorf("script %s is larger than %d bytes", cfg.Name, maxContentSize)
+	}
+	p := &Program{
+		Name:        cfg.Name,
+		Version:     cfg.Version,
+		phases:      make(map[string]bool),
+		timeout:     time.Duration(cfg.Timeout) * time.Millisecond,
+		maxDataSize: cfg.MaxDataSize,
+	}
+	if p.timeout <= 0 {
+		p.timeout = defaultTimeout
+	}
+	if p.timeout > MaxTimeout {
+		p.timeout = MaxTimeout
+	}
+	if p.maxDataSize <= 0 {
+		p.maxDataSize = defaultMaxDataSize
+	}
+
+	program, err := parser.ParseFile(nil, cfg.Name, cfg.Content, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLoops(program); err != nil {
+		return nil, err
+	}
+	injectLoops(program)
+	p.program = program
+	sb, err := p.newSandbox()
+	if err != nil {
+		return nil, err
+	}
+	for _, phase := range []string{PhaseBefore, PhaseAccess, PhaseProxy} {
+		if v, err := sb.vm.Get(phase); err == nil && v.IsFunction() {
+			p.phases[phase] = true
+		}
+	}
+	if len(p.phases) == 0 {
+		return nil, fmt.Errorf("script %s does not define any of before, access or proxy function", cfg.Name)
+	}
+	p.pool.Put(sb)
+	return p, nil
+}
+
+// loopChecker 沙箱在执行语句及表达式时检查超时，没有条件、更新表达式且循环体为空的for循环无法被中断
+type loopChecker struct {
+	err error
+}
+
+func (v *loopChecker) Enter(n ast.Node) ast.Visitor {
+	if f, ok := n.(*ast.ForStatement); ok && f.Test == nil && f.Update == nil {
+		if b, ok := f.Body.(*ast.BlockStatement); ok && len(b.List) == 0 {
+			v.err = fmt.Errorf("empty infinite loop is not allowed at %d", f.For)
+			return nil
+		}
+	}
+	return v
+}
+
+func (v *loopChecker) Exit(n ast.Node) {}
+
+func checkLoops(program *ast.Program) error {
+	v := &loopChecker{}
+	ast.Walk(v, program)
+	return v.err
+}
+
+// loopInjector 在循环体末尾插入空语句，沙箱在执行语句时检查中断，使循环体为空的循环也能被中断
+type loopInjector struct{}
+
+func (v loopInjector) Enter(n ast.Node) ast.Visitor {
+	switch n := n.(type) {
+	case *ast.ForStatement:
+		n.Body = interruptible(n.Body, n.For)
+	case *ast.ForInStatement:
+		n.Body = interruptible(n.Body, n.For)
+	case *ast.WhileStatement:
+		n.Body = interruptible(n.Body, n.While)
+	case *ast.DoWhileStatement:
+		n.Body = interruptible(n.Body, n.Do)
+	}
+	return v
+}
+
+func (v loopInjector) Exit(n ast.Node) {}
+
+func interruptible(body ast.Statement, idx file.Idx) ast.Statement {
+	noop := &ast.ExpressionStatement{Expression: &ast.NumberLiteral{Idx: idx, Literal: "0", Value: int64(0)}}
+	if b, ok := body.(*ast.BlockStatement); ok {
+		b.List = append(b.List, noop)
+		return b
+	}
+	return &ast.BlockStatement{LeftBrace: idx, List: []ast.Statement{body, noop}, RightBrace: idx}
+}
+
+func injectLoops(program *ast.Program) {
+	ast.Walk(loopInjector{}, program)
+}
+
+//Has 脚本是否定义了该阶段的处理函数
+func (p *Program) Has(phase string) bool {
+	return p.phases[phase]
+}
+
+// sandbox 脚本沙箱
+type sandbox struct {
+	vm     *otto.Otto
+	global *otto.Object
+}
+
+// newSandbox 创建沙箱并执行脚本的顶层代码
+func (p *Program) newSandbox() (*sandbox, error) {
+	vm := otto.New()
+	// 移除沙箱中与执行环境相关的对象，Function构造函数可在运行时生成未经检查的代码，一并禁用
+	for _, name := range []string{"console", "eval"} {
+		vm.Set(name, otto.UndefinedValue())
+	}
+	if _, err := vm.Run(disableFunctionConstructor); err != nil {
+		return nil, err
+	}
+	vm.Set("log", func(call otto.FunctionCall) otto.Value {
+		scriptLog(p.Name, call)
+		return otto.UndefinedValue()
+	})
+	global, err := vm.Object("this")
+	if err != nil {
+		return nil, err
+	}
+	sb := &sandbox{vm: vm, global: global}
+	_, err = p.run(sb, func() (otto.Value, error) {
+		return vm.Run(p.program)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sb, nil
+}
+
+// run 在超时及内存限制内执行
+func (p *Program) run(sb *sandbox, f func() (otto.Value, error)) (v otto.Value, err error) {
+	// 使用本次执行的中断通道，避免延迟的中断影响沙箱的下一次执行
+	interrupt := make(chan func(), 1)
+	sb.vm.Interrupt = interrupt
+	done := make(chan struct{})
+	go p.watch(sb, interrupt, done)
+	defer func() {
+		close(done)
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return f()
+}
+
+// watch 定时检查沙箱的内存占用，超时后中断执行
+func (p *Program) watch(sb *sandbox, interrupt chan func(), done chan struct{}) {
+	timeout := time.NewTimer(p.timeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	check := func() {
+		if memorySize(sb, maxMemory) > maxMemory {
+			panic(ErrorMemory)
+		}
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case <-timeout.C:
+			select {
+			case interrupt <- func() { panic(ErrorTimeout) }:
+			case <-done:
+			}
+			return
+		case <-ticker.C:
+			select {
+			case interrupt <- check:
+			default:
+			}
+		}
+	}
+}
+
+// memorySize 估算全局变量及当前调用栈中变量可达数据的大小，超过max后不再继续统计
+func memorySize(sb *sandbox, max int) int {
+	c := &sizeCounter{seen: make(map[uintptr]bool), max: max}
+	c.object(sb.global)
+	for _, v := range sb.vm.Context().Symbols {
+		c.value(v)
+	}
+	return c.size
+}
+
+type sizeCounter struct {
+	seen map[uintptr]bool
+	size int
+	max  int
+}
+
+func (c *sizeCounter) value(v otto.Value) {
+	if c.size > c.max {
+		return
+	}
+	switch {
+	case v.IsString():
+		s, _ := v.ToString()
+		c.size += len(s)
+	case v.IsObject():
+		// otto每次返回新的Object，使用内部对象的地址判断是否已统计
+		id := reflect.ValueOf(v).FieldByName("value").Elem().Pointer()
+		if c.seen[id] {
+			return
+		}
+		c.seen[id] = true
+		c.object(v.Object())
+	}
+}
+
+func (c *sizeCounter) object(o *otto.Object) {
+	c.size += 64
+	if o.Class() == "Array" {
+		// 数组按长度逐个统计，避免获取全部下标
+		l, _ := o.Get("length")
+		n, _ := l.ToInteger()
+		for i := int64(0); i < n && c.size <= c.max; i++ {
+			c.size += 16
+			e, _ := o.Get(strconv.FormatInt(i, 10))
+			c.value(e)
+		}
+		return
+	}
+	for _, k := range o.Keys() {
+		if c.size > c.max {
+			return
+		}
+		c.size += 16 + len(k)
+		e, _ := o.Get(k)
+		c.value(e)
+	}
+}
+
+//Execute 执行指定阶段的处理函数，函数返回false时中断后续处理
+func (p *Program) Execute(phase string, ctx *common.Context) (bool, error) {
+	if !p.phases[phase] {
+		return true, nil
+	}
+	var sb *sandbox
+	if v := p.pool.Get(); v != nil {
+		sb = v.(*sandbox)
+	} else {
+		var err error
+		sb, err = p.newSandbox()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	binding := newContext(sb.vm, phase, ctx, p.maxDataSize)
+	result, err := p.run(sb, func() (otto.Value, error) {
+		return sb.vm.Call(phase, nil, binding.object())
+	})
+	if err == nil && binding.err != nil {
+		err = binding.err
+	}
+	if err != nil {
+		// 超时或出错的沙箱状态不可预期，不再复用
+		return false, fmt.Errorf("script %s:%s", p.Name, err.Error())
+	}
+	p.pool.Put(sb)
+
+	if result.IsBoolean() {
+		isContinue, _ := result.ToBoolean()
+		return isContinue, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,139 @@
+package script
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func newRequestContext(target string) *common.Context {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("hello"))
+	req.Header.Set("X-User", "goku")
+	return common.NewContext(req, "test", httptest.NewRecorder())
+}
+
+func TestExecute(t *testing.T) {
+	p, err := Compile(&config.ScriptConfig{Name: "demo", Content: `
+var count = 0;
+function access(ctx) {
+	count++;
+	ctx.proxy.setHeader("X-From", ctx.request.header("X-User") + ":" + ctx.request.query("id"));
+	ctx.proxy.delQuery("id");
+	if (ctx.request.body() != "hello") {
+		ctx.response.setStatus(400);
+		return false;
+	}
+	ctx.store.set(count);
+}
+function proxy(ctx) {
+	ctx.response.setHeader("X-Status", String(ctx.proxyResponse.statusCode));
+}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Has(PhaseBefore) || !p.Has(PhaseAccess) || !p.Has(PhaseProxy) {
+		t.Fatalf("unexpected phases:%v", p.phases)
+	}
+	ctx := newRequestContext("/a?id=1")
+	isContinue, err := p.Execute(PhaseAccess, ctx)
+	if err != nil || !isContinue {
+		t.Fatalf("access:%v %v", isContinue, err)
+	}
+	if v := ctx.ProxyRequest.GetHeader("X-From"); v != "goku:1" {
+		t.Errorf("unexpected proxy header:%s", v)
+	}
+	if ctx.ProxyRequest.Querys().Get("id") != "" {
+		t.Error("expected query removed")
+	}
+
+	ctx.SetProxyResponse(&http.Response{StatusCode: 201, Status: "201 Created", Header: make(http.Header), Body: http.NoBody})
+	if _, err := p.Execute(PhaseProxy, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if v := ctx.Set().GetHeader("X-Status"); v != "201" {
+		t.Errorf("unexpected response header:%s", v)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	if _, err := Compile(&config.ScriptConfig{Name: "none", Content: `var a = 1;`}); err == nil {
+		t.Error("expected missing phase error")
+	}
+	if _, err := Compile(&config.ScriptConfig{Name: "lua", Language: "lua", Content: `function access(ctx) {}`}); err != ErrorUnsupportedLanguage {
+		t.Errorf("expected unsupported language, got %v", err)
+	}
+
+	if _, err := Compile(&config.ScriptConfig{Name: "empty", Content: `function access(ctx) { for (;;) {} }`}); err == nil {
+		t.Error("expected empty loop error")
+	}
+	loop, err := Compile(&config.ScriptConfig{Name: "loop", Timeout: 20, Content: `function access(ctx) { while (true) {} }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loop.Execute(PhaseAccess, newRequestContext("/")); err == nil || !strings.Contains(err.Error(), ErrorTimeout.Error()) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+
+	big, err := Compile(&config.ScriptConfig{Name: "big", MaxDataSize: 16, Content: `function access(ctx) { ctx.response.setBody(new Array(100).join("x")); }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := big.Execute(PhaseAccess, newRequestContext("/")); err == nil || !strings.Contains(err.Error(), ErrorDataSize.Error()) {
+		t.Errorf("expected data size error, got %v", err)
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	for name, content := range map[string]string{
+		"string": `function access(ctx) { var s = "x"; while (true) { s += s; } }`,
+		"global": `var s = new Array(16385).join("x"); var list = []; function access(ctx) { while (true) { list.push(s); } }`,
+	} {
+		p, err := Compile(&config.ScriptConfig{Name: name, Timeout: 1000, Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Execute(PhaseAccess, newRequestContext("/")); err == nil || !strings.Contains(err.Error(), ErrorMemory.Error()) {
+			t.Errorf("%s: expected memory error, got %v", name, err)
+		}
+	}
+}
+
+func TestFunctionConstructor(t *testing.T) {
+	for name, content := range map[string]string{
+		"function":    `function access(ctx) { Function("for(;;){}")(); }`,
+		"constructor": `function access(ctx) { (function(){}).constructor("for(;;){}")(); }`,
+	} {
+		p, err := Compile(&config.ScriptConfig{Name: name, Timeout: 20, Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		done := make(chan error, 1)
+		go func() {
+			_, err := p.Execute(PhaseAccess, newRequestContext("/"))
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: script not interrupted", name)
+		}
+	}
+}
+
+func TestInterruptEmptyLoop(t *testing.T) {
+	p, err := Compile(&config.ScriptConfig{Name: "loops", Timeout: 20, Content: `function access(ctx) { for (var k in {a: 1}) {} for (;; ) ; }`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Execute(PhaseAccess, newRequestContext("/")); err == nil || !strings.Contains(err.Error(), ErrorTimeout.Error()) {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
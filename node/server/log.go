@@ -57,7 +57,7 @@ func SetAccessLog(c *config.AccessLogConfig) {
 		access_log.SetFields(fields)
 	}
 
-	access_log.SetOutput(enable, c.Dir, c.File, period, c.Expire)
+	access_log.SetOutput(enable, c.Dir, c.File, period, c.Expire, c.Outputs)
 }
 
 func defaultAccessLogConfig() *config.AccessLogConfig {
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/eolinker/goku-api-gateway/node/routerRule"
 
+	proto_codec "github.com/eolinker/goku-api-gateway/common/proto-codec"
 	redis_manager "github.com/eolinker/goku-api-gateway/common/redis-manager"
 	"github.com/eolinker/goku-api-gateway/node"
 
@@ -25,7 +27,11 @@ import (
 	log "github.com/eolinker/goku-api-gateway/goku-log"
 	"github.com/eolinker/goku-api-gateway/node/console"
 	"github.com/eolinker/goku-api-gateway/node/gateway"
+	plugin_loader "github.com/eolinker/goku-api-gateway/node/plugin-loader"
 	"github.com/eolinker/goku-api-gateway/node/router/httprouter"
+	"github.com/eolinker/goku-api-gateway/node/script"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 //Server server
@@ -33,6 +39,8 @@ type Server struct {
 	//port    int
 	//console *console.Console
 	router http.Handler
+
+	pluginErrorReporter console.PluginErrorReporter
 }
 
 //NewServer newServer
@@ -62,7 +70,10 @@ func (s *Server) ServerWidthConsole(console console.ConfigConsole) error {
 		if err != nil {
 			return err
 		}
+		s.pluginErrorReporter = toPluginErrorReporter(console)
 
+		console.AddListen(s.FlushProtoDescriptors)
+		console.AddListen(s.FlushScripts)
 		console.AddListen(s.FlushRouter)
 		console.AddListen(s.FlushModule)
 		console.AddListen(s.FlushRedisConfig)
@@ -70,6 +81,7 @@ func (s *Server) ServerWidthConsole(console console.ConfigConsole) error {
 		console.AddListen(s.FlushGatewayBasicConfig)
 
 		console.Listen()
+		startTrafficReport(toTrafficReporter(console))
 
 		return s.ServerWidthConfig(conf)
 	}
@@ -83,6 +95,8 @@ func (s *Server) ServerWidthConfig(conf *config.GokuConfig) error {
 		return errors.New("can not start server width out config")
 	}
 	s.FlushRedisConfig(conf)
+	s.FlushProtoDescriptors(conf)
+	s.FlushScripts(conf)
 
 	r, err := gateway.Parse(conf, httprouter.Factory())
 	if err != nil {
@@ -95,6 +109,7 @@ func (s *Server) ServerWidthConfig(conf *config.GokuConfig) error {
 	if e != nil {
 		return e
 	}
+	s.reportPluginErrors()
 	routerRule.Load(conf.Routers)
 
 	// 初始化监控模块
@@ -112,7 +127,8 @@ func (s *Server) ServerWidthConfig(conf *config.GokuConfig) error {
 	}
 
 	//return endless.ListenAndServe(conf.BindAddress, s)
-	return http.ListenAndServe(conf.BindAddress, s)
+	// 使用h2c以支持客户端通过明文http2转发grpc请求
+	return http.ListenAndServe(conf.BindAddress, h2c.NewHandler(s, &http2.Server{}))
 }
 
 //FlushRouter flushConfig
@@ -123,6 +139,22 @@ func (s *Server) FlushRouter(config *config.GokuConfig) {
 		return
 	}
 	_ = s.SetRouter(r)
+	s.reportPluginErrors()
+}
+
+func toPluginErrorReporter(c console.ConfigConsole) console.PluginErrorReporter {
+	reporter, _ := c.(console.PluginErrorReporter)
+	return reporter
+}
+
+// reportPluginErrors 生成路由后向控制台上报插件加载错误
+func (s *Server) reportPluginErrors() {
+	if s.pluginErrorReporter == nil {
+		return
+	}
+	if err := s.pluginErrorReporter.SendPluginErrors(plugin_loader.Errors()); err != nil {
+		log.Warn("report plugin errors error:", err)
+	}
 }
 
 //FlushRouterRule flushConfig
@@ -158,6 +190,27 @@ func (s *Server) FlushRedisConfig(config *config.GokuConfig) {
 	}
 }
 
+//FlushProtoDescriptors 刷新protobuf描述文件
+func (s *Server) FlushProtoDescriptors(config *config.GokuConfig) {
+	sets := make(map[string][]byte, len(config.ProtoDescriptors))
+	for name, content := range config.ProtoDescriptors {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			log.Warn("invalid proto descriptor ", name, ":", err)
+			continue
+		}
+		sets[name] = data
+	}
+	if err := proto_codec.Reset(sets); err != nil {
+		log.Warn("load proto descriptor error:", err)
+	}
+}
+
+//FlushScripts 刷新脚本，需在生成路由前执行
+func (s *Server) FlushScripts(config *config.GokuConfig) {
+	script.Reset(config.Scripts)
+}
+
 //FlushModule 刷新模块配置
 func (s *Server) FlushModule(conf *config.GokuConfig) {
 	SetLog(conf.Log)
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"time"
+
+	log "github.com/eolinker/goku-api-gateway/goku-log"
+	"github.com/eolinker/goku-api-gateway/node/console"
+	"github.com/eolinker/goku-api-gateway/node/monitor"
+)
+
+// trafficReportInterval 流量统计上报周期
+const trafficReportInterval = time.Second * 10
+
+func toTrafficReporter(c console.ConfigConsole) console.TrafficReporter {
+	reporter, _ := c.(console.TrafficReporter)
+	return reporter
+}
+
+// startTrafficReport 定期向控制台上报流量统计，无请求的周期不上报
+func startTrafficReport(reporter console.TrafficReporter) {
+	if reporter == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(trafficReportInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report := monitor.TakeTraffic()
+			if len(report.Stats) == 0 {
+				continue
+			}
+			if err := reporter.SendTraffic(report); err != nil {
+				log.Warn("report traffic error:", err)
+			}
+		}
+	}()
+}
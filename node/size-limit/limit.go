@@ -0,0 +1,202 @@
+package size_limit
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+//ResponseLimitKey 写入上下文缓存的响应体大小限制，由转发读取响应时使用
+const ResponseLimitKey = "max_response_body"
+
+var (
+	//ErrorResponseTooLarge 响应体超过限制
+	ErrorResponseTooLarge = errors.New("response body too large")
+)
+
+//Limit 生效的大小限制，单位字节，为0时不限制
+type Limit struct {
+	MaxRequestBody  int64
+	MaxResponseBody int64
+	MaxHeader       int
+}
+
+//Merge 合并多条限制规则，每项取非0的最小值，均不限制时返回nil
+func Merge(cfgs ...*config.SizeLimitConfig) *Limit {
+	l := new(Limit)
+	for _, cfg := range cfgs {
+		if cfg == nil {
+			continue
+		}
+		l.MaxRequestBody = min64(l.MaxRequestBody, cfg.MaxRequestBody)
+		l.MaxResponseBody = min64(l.MaxResponseBody, cfg.MaxResponseBody)
+		l.MaxHeader = int(min64(int64(l.MaxHeader), int64(cfg.MaxHeader)))
+	}
+	if l.MaxRequestBody == 0 && l.MaxResponseBody == 0 && l.MaxHeader == 0 {
+		return nil
+	}
+	return l
+}
+
+//Match 合并作用于全局、策略、接口及策略下接口的大小限制
+func Match(cfgs []*config.SizeLimitConfig, strategyID string, apiID int) *Limit {
+	matched := make([]*config.SizeLimitConfig, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.StrategyID != "" && cfg.StrategyID != strategyID {
+			continue
+		}
+		if cfg.APIID != 0 && cfg.APIID != apiID {
+			continue
+		}
+		matched = append(matched, cfg)
+	}
+	return Merge(matched...)
+}
+
+//ReadLimit 读取请求体的上限，取各路由（策略ID对应的接口ID）请求体限制中的最大值，存在不限制的路由时返回0；
+//在路由匹配前限制读取，超过任一路由限制的请求体不会被完整读取
+func ReadLimit(cfgs []*config.SizeLimitConfig, routes map[string][]int) int64 {
+	max, matched := int64(0), false
+	for strategyID, apiIDs := range routes {
+		for _, apiID := range apiIDs {
+			v := Match(cfgs, strategyID, apiID).requestBody()
+			if v == 0 {
+				return 0
+			}
+			if v > max {
+				max = v
+			}
+			matched = true
+		}
+	}
+	if !matched {
+		return Match(cfgs, "", 0).requestBody()
+	}
+	return max
+}
+
+func (l *Limit) requestBody() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.MaxRequestBody
+}
+
+func min64(current, v int64) int64 {
+	if v <= 0 {
+		return current
+	}
+	if current == 0 || v < current {
+		return v
+	}
+	return current
+}
+
+//Check 检查请求头及请求体大小，超过限制时设置响应并返回false，l为nil时不限制
+func (l *Limit) Check(ctx *common.Context) bool {
+	if l == nil {
+		return true
+	}
+	if l.MaxHeader > 0 && HeaderSize(ctx.RequestOrg.Headers()) > l.MaxHeader {
+		ctx.SetStatus(http.StatusRequestHeaderFieldsTooLarge, "431")
+		ctx.SetBody([]byte("[ERROR]Request header fields too large!"))
+		return false
+	}
+	if l.MaxRequestBody > 0 {
+		body, _ := ctx.RequestOrg.RawBody()
+		if int64(len(body)) > l.MaxRequestBody {
+			TooLarge(ctx)
+			return false
+		}
+	}
+	if l.MaxResponseBody > 0 {
+		ctx.SetCache(ResponseLimitKey, l.MaxResponseBody)
+	}
+	return true
+}
+
+//TooLarge 设置请求体过大的响应
+func TooLarge(ctx *common.Context) {
+	ctx.SetStatus(http.StatusRequestEntityTooLarge, "413")
+	ctx.SetBody([]byte("[ERROR]Request entity too large!"))
+}
+
+//HeaderSize 按HTTP/1.1格式计算请求头大小
+func HeaderSize(header http.Header) int {
+	n := 0
+	for k, vs := range header {
+		for _, v := range vs {
+			// "k: v\r\n"
+			n += len(k) + len(v) + 4
+		}
+	}
+	return n
+}
+
+//ReadResponse 读取后端响应体，超过上下文中的响应大小限制时返回ErrorResponseTooLarge
+func ReadResponse(ctx *common.Context, r io.Reader) ([]byte, error) {
+	max := int64(0)
+	if v, has := ctx.GetCache(ResponseLimitKey); has {
+		max, _ = v.(int64)
+	}
+	if max <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > max {
+		return nil, ErrorResponseTooLarge
+	}
+	return data, nil
+}
+
+//BodyReader 限制读取的请求体，超过限制时不再读取
+type BodyReader struct {
+	body     io.ReadCloser
+	remain   int64
+	exceeded bool
+}
+
+//LimitRequest 限制请求体大小，Content-Length已超过限制时不读取请求体
+func LimitRequest(req *http.Request, max int64) *BodyReader {
+	r := &BodyReader{body: req.Body, remain: max}
+	if req.ContentLength > max {
+		r.exceeded = true
+		r.remain = 0
+	}
+	req.Body = r
+	return r
+}
+
+//Read 读取请求体，超过限制后返回io.EOF
+func (r *BodyReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remain+1 {
+		p = p[:r.remain+1]
+	}
+	n, err := r.body.Read(p)
+	if int64(n) > r.remain {
+		r.exceeded = true
+		return 0, io.EOF
+	}
+	r.remain -= int64(n)
+	return n, err
+}
+
+//Close 关闭请求体
+func (r *BodyReader) Close() error {
+	return r.body.Close()
+}
+
+//Exceeded 请求体是否超过限制
+func (r *BodyReader) Exceeded() bool {
+	return r.exceeded
+}
@@ -0,0 +1,102 @@
+package size_limit
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/goku-node/common"
+)
+
+func TestMerge(t *testing.T) {
+	if l := Merge(&config.SizeLimitConfig{}, nil); l != nil {
+		t.Fatalf("expected nil: %+v", l)
+	}
+	l := Merge(
+		&config.SizeLimitConfig{MaxRequestBody: 100, MaxHeader: 50},
+		&config.SizeLimitConfig{MaxRequestBody: 10, MaxResponseBody: 200},
+	)
+	if l.MaxRequestBody != 10 || l.MaxResponseBody != 200 || l.MaxHeader != 50 {
+		t.Fatalf("unexpected limit: %+v", l)
+	}
+}
+
+func TestLimitRequest(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader("0123456789"))
+	req.ContentLength = -1
+	body := LimitRequest(req, 10)
+	data, _ := ioutil.ReadAll(req.Body)
+	if body.Exceeded() || string(data) != "0123456789" {
+		t.Fatalf("expected full body: %q", data)
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("0123456789a"))
+	req.ContentLength = -1
+	body = LimitRequest(req, 10)
+	ioutil.ReadAll(req.Body)
+	if !body.Exceeded() {
+		t.Fatal("expected exceeded")
+	}
+
+	req, _ = http.NewRequest("POST", "/", strings.NewReader("0123456789a"))
+	if body = LimitRequest(req, 10); !body.Exceeded() {
+		t.Fatal("expected exceeded by Content-Length")
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	cfgs := []*config.SizeLimitConfig{
+		{MaxRequestBody: 100},
+		{StrategyID: "s1", MaxRequestBody: 50},
+		{APIID: 2, MaxRequestBody: 10},
+	}
+	if max := ReadLimit(cfgs, nil); max != 100 {
+		t.Errorf("expected global limit: %d", max)
+	}
+	if max := ReadLimit(cfgs, map[string][]int{"s1": {1, 2}}); max != 50 {
+		t.Errorf("expected strategy limit: %d", max)
+	}
+	if max := ReadLimit(cfgs[1:], map[string][]int{"s1": {2}, "s2": {1}}); max != 0 {
+		t.Errorf("expected no limit: %d", max)
+	}
+}
+
+type countReader struct {
+	size int
+	read int
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	if r.read+len(p) > r.size {
+		p = p[:r.size-r.read]
+	}
+	for i := range p {
+		p[i] = 'a'
+	}
+	r.read += len(p)
+	if r.read == r.size {
+		return len(p), io.EOF
+	}
+	return len(p), nil
+}
+
+func TestLimitBeforeContext(t *testing.T) {
+	cfgs := []*config.SizeLimitConfig{{StrategyID: "s1", APIID: 1, MaxRequestBody: 10}}
+	body := &countReader{size: 1 << 20}
+	req, _ := http.NewRequest("POST", "/", body)
+	req.ContentLength = -1
+	reader := LimitRequest(req, ReadLimit(cfgs, map[string][]int{"s1": {1}}))
+
+	ctx := common.NewContext(req, "test", httptest.NewRecorder())
+	ctx.RequestOrg.RawBody()
+	if !reader.Exceeded() {
+		t.Fatal("expected exceeded")
+	}
+	if body.read > 1024 {
+		t.Errorf("body should not be fully read: %d bytes", body.read)
+	}
+}
@@ -0,0 +1,54 @@
+package goku311
+
+import SQL "database/sql"
+
+var gokuTableVersionSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_table_version` (" +
+		"`tableID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`tableName` VARCHAR(64) NOT NULL," +
+		"`version` VARCHAR(32) NOT NULL," +
+		"UNIQUE INDEX `tableName` (`tableName`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+}
+
+var gokuMonitorModuleSQL = []string{
+	"DROP TABLE IF EXISTS `goku_monitor_module`;",
+	"CREATE TABLE `goku_monitor_module` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`name` VARCHAR(255) NOT NULL," +
+		"`config` TEXT NOT NULL," +
+		"`moduleStatus` INT NOT NULL DEFAULT 0," +
+		"UNIQUE INDEX `moduleName` (`name`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+}
+
+var gokuNodeInfoSQL = []string{
+	"DROP TABLE IF EXISTS `goku_node_info_new`;",
+	"CREATE TABLE `goku_node_info_new` (" +
+		"`nodeID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`createTime` TEXT," +
+		"`updateTime` TEXT," +
+		"`groupID` INT NOT NULL DEFAULT 0," +
+		"`nodeName` VARCHAR(255) NOT NULL," +
+		"`nodeStatus` INT NOT NULL," +
+		"`version` VARCHAR(255)," +
+		"`sshAddress` VARCHAR(255) DEFAULT '22'," +
+		"`sshUserName` VARCHAR(255)," +
+		"`sshPassword` VARCHAR(255)," +
+		"`gatewayPath` VARCHAR(255)," +
+		"`sshKey` TEXT," +
+		"`authMethod` INT NOT NULL DEFAULT 0," +
+		"`clusterID` INT NOT NULL DEFAULT 0," +
+		"`listenAddress` VARCHAR(22) NOT NULL DEFAULT ''," +
+		"`adminAddress` VARCHAR(22) NOT NULL DEFAULT ''," +
+		"`nodeKey` VARCHAR(32) NOT NULL DEFAULT ''," +
+		"UNIQUE INDEX `nodeKey_new` (`nodeKey`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+	"INSERT INTO goku_node_info_new (`nodeID`,`createTime`,`updateTime`,`groupID`,`nodeName`,`nodeStatus`,`version`,`sshAddress`,`sshUserName`,`sshPassword`,`gatewayPath`,`sshKey`,`authMethod`,`clusterID`,`listenAddress`,`adminAddress`,`nodeKey`) SELECT `nodeID`,`createTime`,`updateTime`,`groupID`,`nodeName`,`nodeStatus`,`version`,`sshPort`,`userName`,`password`,`gatewayPath`,`key`,`authMethod`,`clusterID`,CONCAT(`nodeIP`,':',IFNULL(`nodePort`,'')),CONCAT(`nodeIP`,':',IFNULL(`nodePort`,'')),CONCAT(`nodeID`,`nodeIP`,':',IFNULL(`nodePort`,'')) FROM goku_node_info;",
+	"DROP TABLE IF EXISTS `goku_node_info`;",
+	"ALTER TABLE `goku_node_info_new` RENAME TO `goku_node_info`;",
+}
+
+func createGokuNodeInfo(db *SQL.DB) error {
+	return execSQL(db, gokuNodeInfoSQL)
+}
@@ -0,0 +1,67 @@
+package goku311
+
+import (
+	"database/sql"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//Version 版本号
+const Version = "3.1.1"
+
+//DBDriver dbDriver
+const DBDriver = "mysql"
+
+//RegisterUpdate RegisterUpdate
+func RegisterUpdate() {
+	pdao.RegisterDBBuilder(DBDriver, new(factory))
+}
+
+type factory struct {
+}
+
+func (f *factory) Build(db *sql.DB) error {
+	return Exec(db)
+}
+
+//Exec 执行3.1.1的表结构变更
+func Exec(db *sql.DB) error {
+
+	updaterDao := updater.NewUpdaterDaoWidthDB(db)
+
+	existed := updaterDao.IsTableExist("goku_table_version")
+	if !existed {
+		err := execSQL(db, gokuTableVersionSQL)
+		if err != nil {
+			return err
+		}
+	}
+	if version := updaterDao.GetTableVersion("goku_node_info"); version != Version {
+		err := createGokuNodeInfo(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_node_info", Version)
+	}
+	if version := updaterDao.GetTableVersion("goku_monitor_module"); version != Version {
+		err := execSQL(db, gokuMonitorModuleSQL)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_monitor_module", Version)
+	}
+
+	updaterDao.SetGokuVersion(Version)
+
+	return nil
+}
+
+func execSQL(db *sql.DB, sqls []string) error {
+	for _, s := range sqls {
+		if _, err := db.Exec(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
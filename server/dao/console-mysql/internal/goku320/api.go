@@ -0,0 +1,10 @@
+package goku320
+
+import (
+	SQL "database/sql"
+)
+
+// addAPIRewrite 接口新增转发路径及query重写配置
+func addAPIRewrite(db *SQL.DB) error {
+	return addColumn(db, "goku_gateway_api", "rewrite", "TEXT")
+}
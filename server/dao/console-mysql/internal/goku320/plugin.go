@@ -0,0 +1,102 @@
+package goku320
+
+import (
+	SQL "database/sql"
+)
+
+// nativeAuthPlugins 网关内置实现的鉴权方式，无需插件文件即可使用
+var nativeAuthPlugins = []struct {
+	name        string
+	chineseName string
+	desc        string
+}{
+	{"goku-apikey_auth", "Apikey鉴权", "网关内置的Apikey鉴权"},
+	{"goku-basic_auth", "Basic鉴权", "网关内置的Basic鉴权"},
+	{"goku-jwt_auth", "Jwt鉴权", "网关内置的Jwt鉴权，支持JWKS"},
+	{"goku-hmac_auth", "Hmac鉴权", "网关内置的HTTP签名鉴权"},
+}
+
+// scriptPlugins 脚本插件，分别用于全局、策略及接口，插件配置为 {"scripts":["脚本名称"]}
+var scriptPlugins = []struct {
+	name        string
+	chineseName string
+	pluginType  int
+	status      int
+}{
+	{"goku-global_script", "全局脚本", 0, 0},
+	{"goku-script", "策略脚本", 1, 1},
+	{"goku-api_script", "接口脚本", 2, 1},
+}
+
+// addStrategyAuthPolicy 策略新增多个鉴权方式的校验策略，并登记内置的鉴权插件
+func addStrategyAuthPolicy(db *SQL.DB) error {
+	err := addColumn(db, "goku_gateway_strategy", "authPolicy", "VARCHAR(32) NOT NULL DEFAULT 'any'")
+	if err != nil {
+		return err
+	}
+	for _, p := range nativeAuthPlugins {
+		err = addPlugin(db, p.name, p.chineseName, p.desc, "", 1, 1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPluginErrorPolicy 插件新增错误处理策略
+func addPluginErrorPolicy(db *SQL.DB) error {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"errorPolicy", "VARCHAR(32) NOT NULL DEFAULT ''"},
+		{"errorStatus", "INT NOT NULL DEFAULT 0"},
+		{"errorBody", "VARCHAR(4000) NOT NULL DEFAULT ''"},
+	}
+	for _, c := range columns {
+		if err := addColumn(db, "goku_plugin", c.name, c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createGokuScript(db *SQL.DB) error {
+	err := createTables(gokuScriptSQL)(db)
+	if err != nil {
+		return err
+	}
+	for _, p := range scriptPlugins {
+		err = addPlugin(db, p.name, p.chineseName, "网关内置的JavaScript脚本插件", "{\"scripts\":[]}", p.status, p.pluginType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPlugin 登记内置插件，优先级排在已有插件之后；mysql不支持在插入语句中查询同一张表，需先查询优先级
+func addPlugin(db *SQL.DB, name, chineseName, desc, config string, status, pluginType int) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM goku_plugin WHERE pluginName = ?;", name).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	var priority int
+	err = db.QueryRow("SELECT IFNULL(MAX(pluginPriority),0)+1 FROM goku_plugin;").Scan(&priority)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("INSERT INTO goku_plugin (pluginName,chineseName,pluginStatus,pluginPriority,pluginConfig,isStop,pluginType,official,pluginDesc,version,isCheck) VALUES (?,?,?,?,?,1,?,'true',?,'1.0',1);", name, chineseName, status, priority, config, pluginType, desc)
+	return err
+}
+
+func addColumn(db *SQL.DB, table, column, definition string) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?;", table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.Exec("ALTER TABLE `" + table + "` ADD COLUMN `" + column + "` " + definition + ";")
+	return err
+}
@@ -0,0 +1,267 @@
+package goku320
+
+const tableOptions = ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;"
+
+var gokuProtoDescriptorSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_proto_descriptor` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`name` VARCHAR(255) NOT NULL," +
+		"`remark` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`content` MEDIUMTEXT NOT NULL," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL," +
+		"UNIQUE INDEX `protoDescriptorName` (`name`)" +
+		tableOptions,
+}
+
+var gokuRateLimitSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_rate_limit` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`strategyID` VARCHAR(32) NOT NULL DEFAULT ''," +
+		"`apiID` INT NOT NULL DEFAULT 0," +
+		"`algorithm` VARCHAR(32) NOT NULL DEFAULT 'token-bucket'," +
+		"`mode` VARCHAR(32) NOT NULL DEFAULT 'local'," +
+		"`limitKey` VARCHAR(255) NOT NULL DEFAULT 'ip'," +
+		"`limitCount` INT NOT NULL DEFAULT 0," +
+		"`period` INT NOT NULL DEFAULT 1," +
+		"`burst` INT NOT NULL DEFAULT 0," +
+		"`dailyQuota` INT NOT NULL DEFAULT 0," +
+		"`monthlyQuota` INT NOT NULL DEFAULT 0," +
+		"`enable` INT NOT NULL DEFAULT 1," +
+		"`remark` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`updateTime` VARCHAR(32) NOT NULL," +
+		"INDEX `rateLimitTarget` (`strategyID`,`apiID`)" +
+		tableOptions,
+}
+
+var gokuScriptSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_script` (" +
+		"`scriptID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`scriptName` VARCHAR(255) NOT NULL," +
+		"`scriptDesc` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`language` VARCHAR(32) NOT NULL DEFAULT 'javascript'," +
+		"`content` MEDIUMTEXT NOT NULL," +
+		"`version` INT NOT NULL DEFAULT 1," +
+		"`timeout` INT NOT NULL DEFAULT 0," +
+		"`maxDataSize` INT NOT NULL DEFAULT 0," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL," +
+		"UNIQUE INDEX `scriptName` (`scriptName`)" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_script_history` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`scriptName` VARCHAR(255) NOT NULL," +
+		"`version` INT NOT NULL," +
+		"`content` MEDIUMTEXT NOT NULL," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"INDEX `scriptHistoryName` (`scriptName`)" +
+		tableOptions,
+}
+
+var gokuAPICacheSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_api_cache` (" +
+		"`apiID` INT NOT NULL PRIMARY KEY," +
+		"`config` TEXT NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuSizeLimitSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_size_limit` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`strategyID` VARCHAR(32) NOT NULL DEFAULT ''," +
+		"`apiID` INT NOT NULL DEFAULT 0," +
+		"`maxRequestBody` BIGINT NOT NULL DEFAULT 0," +
+		"`maxResponseBody` BIGINT NOT NULL DEFAULT 0," +
+		"`maxHeader` BIGINT NOT NULL DEFAULT 0," +
+		"`remark` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuAPIValidationSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_api_validation` (" +
+		"`apiID` INT NOT NULL PRIMARY KEY," +
+		"`config` MEDIUMTEXT NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuOpenAPISQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_openapi` (" +
+		"`name` VARCHAR(255) NOT NULL PRIMARY KEY," +
+		"`remark` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`title` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`version` VARCHAR(64) NOT NULL DEFAULT ''," +
+		"`content` MEDIUMTEXT NOT NULL," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuReplicaSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_node_session` (" +
+		"`nodeKey` VARCHAR(255) NOT NULL PRIMARY KEY," +
+		"`consoleID` VARCHAR(64) NOT NULL," +
+		"`pluginErrors` TEXT NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_console_event` (" +
+		"`id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`consoleID` VARCHAR(64) NOT NULL," +
+		"`eventType` VARCHAR(32) NOT NULL," +
+		"`data` TEXT NOT NULL," +
+		"`createTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuTrafficStatSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_traffic_stat` (" +
+		"`id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`cluster` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`nodeKey` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`reportTime` BIGINT NOT NULL," +
+		"`period` INT NOT NULL DEFAULT 0," +
+		"`strategyID` VARCHAR(32) NOT NULL DEFAULT ''," +
+		"`apiID` INT NOT NULL DEFAULT 0," +
+		"`requests` BIGINT NOT NULL DEFAULT 0," +
+		"`status2xx` BIGINT NOT NULL DEFAULT 0," +
+		"`status3xx` BIGINT NOT NULL DEFAULT 0," +
+		"`status4xx` BIGINT NOT NULL DEFAULT 0," +
+		"`status5xx` BIGINT NOT NULL DEFAULT 0," +
+		"`latencySum` DOUBLE NOT NULL DEFAULT 0," +
+		"`latencyMax` DOUBLE NOT NULL DEFAULT 0," +
+		"`latencyBuckets` VARCHAR(1024) NOT NULL DEFAULT ''," +
+		"`upstreamRequests` BIGINT NOT NULL DEFAULT 0," +
+		"`upstreamErrors` BIGINT NOT NULL DEFAULT 0," +
+		"INDEX `trafficReportTime` (`reportTime`)" +
+		tableOptions,
+}
+
+var gokuConsoleLeaseSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_console_lease` (" +
+		"`name` VARCHAR(64) NOT NULL PRIMARY KEY," +
+		"`consoleID` VARCHAR(64) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuAlertSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_alert_rule` (" +
+		"`ruleID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`name` VARCHAR(255) NOT NULL," +
+		"`metric` VARCHAR(32) NOT NULL," +
+		"`cluster` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`strategyID` VARCHAR(32) NOT NULL DEFAULT ''," +
+		"`apiID` INT NOT NULL DEFAULT 0," +
+		"`threshold` DOUBLE NOT NULL DEFAULT 0," +
+		"`duration` INT NOT NULL DEFAULT 60," +
+		"`minRequests` INT NOT NULL DEFAULT 0," +
+		"`channels` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`enable` INT NOT NULL DEFAULT 1," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_alert_channel` (" +
+		"`channelID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`name` VARCHAR(255) NOT NULL," +
+		"`type` VARCHAR(32) NOT NULL," +
+		"`config` TEXT NOT NULL," +
+		"`isDefault` INT NOT NULL DEFAULT 0," +
+		"`enable` INT NOT NULL DEFAULT 1," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_alert_silence` (" +
+		"`silenceID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`ruleID` INT NOT NULL DEFAULT 0," +
+		"`target` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`startTime` BIGINT NOT NULL," +
+		"`endTime` BIGINT NOT NULL," +
+		"`comment` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`createUserID` INT NOT NULL DEFAULT 0," +
+		"`createTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_alert_history` (" +
+		"`alertID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`ruleID` INT NOT NULL DEFAULT 0," +
+		"`ruleName` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`metric` VARCHAR(32) NOT NULL," +
+		"`target` VARCHAR(255) NOT NULL," +
+		"`status` VARCHAR(16) NOT NULL," +
+		"`value` DOUBLE NOT NULL DEFAULT 0," +
+		"`threshold` DOUBLE NOT NULL DEFAULT 0," +
+		"`message` VARCHAR(1024) NOT NULL DEFAULT ''," +
+		"`silenced` INT NOT NULL DEFAULT 0," +
+		"`fireTime` BIGINT NOT NULL," +
+		"`resolveTime` BIGINT NOT NULL DEFAULT 0," +
+		"INDEX `alertHistoryStatus` (`status`)" +
+		tableOptions,
+}
+
+var gokuAuditLogSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_audit_log` (" +
+		"`logID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`userID` INT NOT NULL DEFAULT 0," +
+		"`operation` VARCHAR(255) NOT NULL," +
+		"`entityType` VARCHAR(64) NOT NULL DEFAULT ''," +
+		"`entityID` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`params` MEDIUMTEXT NOT NULL," +
+		"`before` MEDIUMTEXT NOT NULL," +
+		"`after` MEDIUMTEXT NOT NULL," +
+		"`diff` MEDIUMTEXT NOT NULL," +
+		"`statusCode` VARCHAR(16) NOT NULL DEFAULT ''," +
+		"`versionID` INT NOT NULL DEFAULT 0," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"INDEX `auditLogCreateTime` (`createTime`)," +
+		"INDEX `auditLogEntity` (`entityType`, `entityID`)" +
+		tableOptions,
+}
+
+var gokuVersionPublishSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_version_publish` (" +
+		"`requestID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`versionID` INT NOT NULL," +
+		"`status` VARCHAR(16) NOT NULL," +
+		"`isRollback` INT NOT NULL DEFAULT 0," +
+		"`requestUserID` INT NOT NULL DEFAULT 0," +
+		"`approveUserID` INT NOT NULL DEFAULT 0," +
+		"`publishTime` BIGINT NOT NULL DEFAULT 0," +
+		"`remark` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL," +
+		"INDEX `versionPublishStatus` (`status`)" +
+		tableOptions,
+	"CREATE TABLE IF NOT EXISTS `goku_node_config` (" +
+		"`nodeKey` VARCHAR(255) NOT NULL PRIMARY KEY," +
+		"`versionID` INT NOT NULL DEFAULT 0," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
+
+var gokuVersionCanarySQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_version_canary` (" +
+		"`canaryID` INT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`versionID` INT NOT NULL," +
+		"`baseVersionID` INT NOT NULL DEFAULT 0," +
+		"`groupID` INT NOT NULL," +
+		"`status` VARCHAR(16) NOT NULL," +
+		"`autoPromote` INT NOT NULL DEFAULT 0," +
+		"`observeTime` INT NOT NULL DEFAULT 0," +
+		"`errorThreshold` DOUBLE NOT NULL DEFAULT 0," +
+		"`minRequests` INT NOT NULL DEFAULT 0," +
+		"`userID` INT NOT NULL DEFAULT 0," +
+		"`reason` VARCHAR(255) NOT NULL DEFAULT ''," +
+		"`startTime` BIGINT NOT NULL DEFAULT 0," +
+		"`endTime` BIGINT NOT NULL DEFAULT 0," +
+		"`createTime` VARCHAR(32) NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL," +
+		"INDEX `versionCanaryStatus` (`status`)" +
+		tableOptions,
+}
+
+var gokuConfigAccessOutputSQL = []string{
+	"CREATE TABLE IF NOT EXISTS `goku_config_access_output` (" +
+		"`cluster` VARCHAR(255) NOT NULL PRIMARY KEY," +
+		"`outputs` TEXT NOT NULL," +
+		"`updateTime` VARCHAR(32) NOT NULL" +
+		tableOptions,
+}
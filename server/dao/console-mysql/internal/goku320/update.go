@@ -0,0 +1,87 @@
+package goku320
+
+import (
+	"database/sql"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//Version 版本号
+const Version = "3.2.0"
+
+//DBDriver dbDriver
+const DBDriver = "mysql"
+
+//RegisterUpdate RegisterUpdate
+func RegisterUpdate() {
+	pdao.RegisterDBBuilder(DBDriver, new(factory))
+}
+
+type factory struct {
+}
+
+func (f *factory) Build(db *sql.DB) error {
+	return Exec(db)
+}
+
+// tableUpdates 3.2.0的表结构变更，顺序与console-sqlite3保持一致
+var tableUpdates = []struct {
+	table  string
+	update func(db *sql.DB) error
+}{
+	{"goku_proto_descriptor", createTables(gokuProtoDescriptorSQL)},
+	{"goku_rate_limit", createTables(gokuRateLimitSQL)},
+	{"goku_gateway_strategy", addStrategyAuthPolicy},
+	{"goku_conn_plugin_strategy", sealAuthCredentials},
+	{"goku_plugin", addPluginErrorPolicy},
+	{"goku_script", createGokuScript},
+	{"goku_api_cache", createTables(gokuAPICacheSQL)},
+	{"goku_size_limit", createTables(gokuSizeLimitSQL)},
+	{"goku_api_validation", createTables(gokuAPIValidationSQL)},
+	{"goku_openapi", createTables(gokuOpenAPISQL)},
+	{"goku_node_session", createTables(gokuReplicaSQL)},
+	{"goku_traffic_stat", createTables(gokuTrafficStatSQL)},
+	{"goku_console_lease", createTables(gokuConsoleLeaseSQL)},
+	{"goku_alert_rule", createTables(gokuAlertSQL)},
+	{"goku_audit_log", createTables(gokuAuditLogSQL)},
+	{"goku_version_publish", createTables(gokuVersionPublishSQL)},
+	{"goku_version_canary", createTables(gokuVersionCanarySQL)},
+	{"goku_config_access_output", createTables(gokuConfigAccessOutputSQL)},
+	{"goku_gateway_api", addAPIRewrite},
+}
+
+//Exec 执行3.2.0新增的表
+func Exec(db *sql.DB) error {
+
+	updaterDao := updater.NewUpdaterDaoWidthDB(db)
+
+	for _, u := range tableUpdates {
+		if version := updaterDao.GetTableVersion(u.table); version == Version {
+			continue
+		}
+		if err := u.update(db); err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion(u.table, Version)
+	}
+
+	updaterDao.SetGokuVersion(Version)
+
+	return nil
+}
+
+func createTables(sqls []string) func(db *sql.DB) error {
+	return func(db *sql.DB) error {
+		for _, s := range sqls {
+			if _, err := db.Exec(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func sealAuthCredentials(db *sql.DB) error {
+	return updater.NewUpdaterDaoWidthDB(db).SealAuthCredentials()
+}
@@ -0,0 +1,55 @@
+package console_mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// migrationLockName 多个控制台实例共享同一数据库时，建表及升级需串行执行
+const migrationLockName = "goku_console_migration"
+
+// migrationLockTimeout 等待其他实例完成升级的秒数
+const migrationLockTimeout = 120
+
+// migrationLock 获取mysql命名锁，锁与连接绑定，需保持连接直至释放
+type migrationLock struct {
+	conn *sql.Conn
+}
+
+//Build 获取升级锁
+func (l *migrationLock) Build(db *sql.DB) error {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	var got sql.NullInt64
+	err = conn.QueryRowContext(context.Background(), "SELECT GET_LOCK(?,?);", migrationLockName, migrationLockTimeout).Scan(&got)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !got.Valid || got.Int64 != 1 {
+		conn.Close()
+		return errors.New("timeout waiting for database migration lock")
+	}
+	l.conn = conn
+	return nil
+}
+
+// migrationUnlock 释放升级锁
+type migrationUnlock struct {
+	lock *migrationLock
+}
+
+//Build 释放升级锁
+func (u *migrationUnlock) Build(db *sql.DB) error {
+	conn := u.lock.conn
+	if conn == nil {
+		return nil
+	}
+	u.lock.conn = nil
+	defer conn.Close()
+	var released sql.NullInt64
+	return conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?);", migrationLockName).Scan(&released)
+}
@@ -0,0 +1,69 @@
+package console_mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-mysql/internal/goku311"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-mysql/internal/goku320"
+	console_sqlite3 "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//DBDriver db驱动类型
+const DBDriver = "mysql"
+
+//DoRegister 注册数据库，建表及升级在数据库锁内执行，dao实现复用console-sqlite3
+func DoRegister() {
+	lock := new(migrationLock)
+	pdao.RegisterDBBuilder(DBDriver, lock, new(TableBuilder))
+	goku311.RegisterUpdate()
+	goku320.RegisterUpdate()
+	pdao.RegisterDBBuilder(DBDriver, &migrationUnlock{lock: lock})
+
+	console_sqlite3.RegisterDaos(DBDriver)
+}
+
+//TableBuilder tableBuilder
+type TableBuilder struct {
+}
+
+//Build 数据库中不存在控制台表时执行建表语句
+func (t *TableBuilder) Build(db *sql.DB) error {
+	if updater.NewUpdaterDaoWidthDB(db).IsTableExist("goku_admin") {
+		return nil
+	}
+	content, err := ioutil.ReadFile("sql/goku_ce_mysql.sql")
+	if err != nil {
+		return err
+	}
+	// mysql的DDL语句会隐式提交，逐条执行
+	for _, s := range splitSQL(string(content)) {
+		if _, err := db.Exec(s); err != nil {
+			return fmt.Errorf("InitTable error: %s\t sql: %s", err, s)
+		}
+	}
+	return nil
+}
+
+// splitSQL 按分号拆分语句，并去除注释及空语句
+func splitSQL(content string) []string {
+	sqls := make([]string, 0)
+	for _, s := range strings.Split(content, ";") {
+		lines := make([]string, 0)
+		for _, line := range strings.Split(s, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "--") {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		s = strings.TrimSpace(strings.Join(lines, "\n"))
+		if s != "" {
+			sqls = append(sqls, s)
+		}
+	}
+	return sqls
+}
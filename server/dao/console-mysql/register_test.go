@@ -0,0 +1,31 @@
+package console_mysql
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSplitSQL(t *testing.T) {
+	content, err := ioutil.ReadFile("../../../build/console/resources/sql/goku_ce_mysql.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqls := splitSQL(string(content))
+	if len(sqls) == 0 {
+		t.Fatal("no statements")
+	}
+	tables := 0
+	for _, s := range sqls {
+		if strings.HasPrefix(s, "CREATE TABLE") {
+			tables++
+			continue
+		}
+		if !strings.HasPrefix(s, "INSERT INTO") {
+			t.Errorf("unexpected statement: %s", s)
+		}
+	}
+	if tables == 0 {
+		t.Error("no tables")
+	}
+}
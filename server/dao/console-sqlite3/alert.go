@@ -0,0 +1,296 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+	"github.com/eolinker/goku-api-gateway/utils"
+)
+
+//AlertDao AlertDao
+type AlertDao struct {
+	db *SQL.DB
+}
+
+//NewAlertDao new AlertDao
+func NewAlertDao() *AlertDao {
+	return &AlertDao{}
+}
+
+//Create create
+func (d *AlertDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.AlertDao = d
+	return &i, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// deleteByIDs 按主键批量删除
+func deleteByIDs(db *SQL.DB, table, column string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	sql := "DELETE FROM " + table + " WHERE `" + column + "` IN (" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ");"
+	_, err := db.Exec(sql, args...)
+	return err
+}
+
+const alertRuleColumns = "`ruleID`,`name`,`metric`,`cluster`,`strategyID`,`apiID`,`threshold`,`duration`,`minRequests`,`channels`,`enable`,`updateTime`"
+
+func scanAlertRule(row rowScanner) (*entity.AlertRule, error) {
+	var r entity.AlertRule
+	var channels string
+	var enable int
+	err := row.Scan(&r.RuleID, &r.Name, &r.Metric, &r.Cluster, &r.StrategyID, &r.APIID, &r.Threshold, &r.Duration, &r.MinRequests, &channels, &enable, &r.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	r.Channels = make([]int, 0)
+	if channels != "" {
+		_, r.Channels = utils.ConvertArray(strings.Split(channels, ","))
+	}
+	r.Enable = enable == 1
+	return &r, nil
+}
+
+//GetAlertRules 获取告警规则列表
+func (d *AlertDao) GetAlertRules() ([]*entity.AlertRule, error) {
+	rows, err := d.db.Query("SELECT " + alertRuleColumns + " FROM goku_alert_rule ORDER BY `ruleID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rules := make([]*entity.AlertRule, 0)
+	for rows.Next() {
+		r, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+//GetAlertRule 获取告警规则
+func (d *AlertDao) GetAlertRule(ruleID int) (*entity.AlertRule, error) {
+	return scanAlertRule(d.db.QueryRow("SELECT "+alertRuleColumns+" FROM goku_alert_rule WHERE `ruleID` = ?;", ruleID))
+}
+
+//AddAlertRule 新增告警规则
+func (d *AlertDao) AddAlertRule(r *entity.AlertRule) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_alert_rule (`name`,`metric`,`cluster`,`strategyID`,`apiID`,`threshold`,`duration`,`minRequests`,`channels`,`enable`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?);", r.Name, r.Metric, r.Cluster, r.StrategyID, r.APIID, r.Threshold, r.Duration, r.MinRequests, utils.ConvertIntArrayToString(r.Channels), boolToInt(r.Enable), r.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//EditAlertRule 修改告警规则
+func (d *AlertDao) EditAlertRule(r *entity.AlertRule) error {
+	_, err := d.db.Exec("UPDATE goku_alert_rule SET `name` = ?,`metric` = ?,`cluster` = ?,`strategyID` = ?,`apiID` = ?,`threshold` = ?,`duration` = ?,`minRequests` = ?,`channels` = ?,`enable` = ?,`updateTime` = ? WHERE `ruleID` = ?;", r.Name, r.Metric, r.Cluster, r.StrategyID, r.APIID, r.Threshold, r.Duration, r.MinRequests, utils.ConvertIntArrayToString(r.Channels), boolToInt(r.Enable), r.UpdateTime, r.RuleID)
+	return err
+}
+
+//DeleteAlertRules 批量删除告警规则
+func (d *AlertDao) DeleteAlertRules(ruleIDs []int) error {
+	return deleteByIDs(d.db, "goku_alert_rule", "ruleID", ruleIDs)
+}
+
+const alertChannelColumns = "`channelID`,`name`,`type`,`config`,`isDefault`,`enable`,`updateTime`"
+
+func scanAlertChannel(row rowScanner) (*entity.AlertChannel, error) {
+	var c entity.AlertChannel
+	var isDefault, enable int
+	err := row.Scan(&c.ChannelID, &c.Name, &c.Type, &c.Config, &isDefault, &enable, &c.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	c.IsDefault = isDefault == 1
+	c.Enable = enable == 1
+	return &c, nil
+}
+
+//GetAlertChannels 获取通知渠道列表
+func (d *AlertDao) GetAlertChannels() ([]*entity.AlertChannel, error) {
+	rows, err := d.db.Query("SELECT " + alertChannelColumns + " FROM goku_alert_channel ORDER BY `channelID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	channels := make([]*entity.AlertChannel, 0)
+	for rows.Next() {
+		c, err := scanAlertChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, c)
+	}
+	return channels, nil
+}
+
+//GetAlertChannel 获取通知渠道
+func (d *AlertDao) GetAlertChannel(channelID int) (*entity.AlertChannel, error) {
+	return scanAlertChannel(d.db.QueryRow("SELECT "+alertChannelColumns+" FROM goku_alert_channel WHERE `channelID` = ?;", channelID))
+}
+
+//AddAlertChannel 新增通知渠道
+func (d *AlertDao) AddAlertChannel(c *entity.AlertChannel) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_alert_channel (`name`,`type`,`config`,`isDefault`,`enable`,`updateTime`) VALUES (?,?,?,?,?,?);", c.Name, c.Type, c.Config, boolToInt(c.IsDefault), boolToInt(c.Enable), c.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//EditAlertChannel 修改通知渠道
+func (d *AlertDao) EditAlertChannel(c *entity.AlertChannel) error {
+	_, err := d.db.Exec("UPDATE goku_alert_channel SET `name` = ?,`type` = ?,`config` = ?,`isDefault` = ?,`enable` = ?,`updateTime` = ? WHERE `channelID` = ?;", c.Name, c.Type, c.Config, boolToInt(c.IsDefault), boolToInt(c.Enable), c.UpdateTime, c.ChannelID)
+	return err
+}
+
+//DeleteAlertChannels 批量删除通知渠道
+func (d *AlertDao) DeleteAlertChannels(channelIDs []int) error {
+	return deleteByIDs(d.db, "goku_alert_channel", "channelID", channelIDs)
+}
+
+//GetAlertSilences 获取结束时间在指定时间之后的静默列表
+func (d *AlertDao) GetAlertSilences(after int64) ([]*entity.AlertSilence, error) {
+	rows, err := d.db.Query("SELECT `silenceID`,`ruleID`,`target`,`startTime`,`endTime`,`comment`,`createUserID`,`createTime` FROM goku_alert_silence WHERE `endTime` > ? ORDER BY `startTime` ASC;", after)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	silences := make([]*entity.AlertSilence, 0)
+	for rows.Next() {
+		var s entity.AlertSilence
+		err = rows.Scan(&s.SilenceID, &s.RuleID, &s.Target, &s.StartTime, &s.EndTime, &s.Comment, &s.CreateUserID, &s.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		silences = append(silences, &s)
+	}
+	return silences, nil
+}
+
+//AddAlertSilence 新增静默
+func (d *AlertDao) AddAlertSilence(s *entity.AlertSilence) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_alert_silence (`ruleID`,`target`,`startTime`,`endTime`,`comment`,`createUserID`,`createTime`) VALUES (?,?,?,?,?,?,?);", s.RuleID, s.Target, s.StartTime, s.EndTime, s.Comment, s.CreateUserID, s.CreateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//DeleteAlertSilences 批量删除静默
+func (d *AlertDao) DeleteAlertSilences(silenceIDs []int) error {
+	return deleteByIDs(d.db, "goku_alert_silence", "silenceID", silenceIDs)
+}
+
+const alertHistoryColumns = "`alertID`,`ruleID`,`ruleName`,`metric`,`target`,`status`,`value`,`threshold`,`message`,`silenced`,`fireTime`,`resolveTime`"
+
+func scanAlertHistory(row rowScanner) (*entity.AlertHistory, error) {
+	var h entity.AlertHistory
+	var silenced int
+	err := row.Scan(&h.AlertID, &h.RuleID, &h.RuleName, &h.Metric, &h.Target, &h.Status, &h.Value, &h.Threshold, &h.Message, &silenced, &h.FireTime, &h.ResolveTime)
+	if err != nil {
+		return nil, err
+	}
+	h.Silenced = silenced == 1
+	return &h, nil
+}
+
+func scanAlertHistoryRows(rows *SQL.Rows) ([]*entity.AlertHistory, error) {
+	defer rows.Close()
+	list := make([]*entity.AlertHistory, 0)
+	for rows.Next() {
+		h, err := scanAlertHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, h)
+	}
+	return list, nil
+}
+
+//GetFiringAlerts 获取未恢复的告警
+func (d *AlertDao) GetFiringAlerts() ([]*entity.AlertHistory, error) {
+	rows, err := d.db.Query("SELECT "+alertHistoryColumns+" FROM goku_alert_history WHERE `status` = ?;", "firing")
+	if err != nil {
+		return nil, err
+	}
+	return scanAlertHistoryRows(rows)
+}
+
+//AddAlertHistory 新增告警记录
+func (d *AlertDao) AddAlertHistory(h *entity.AlertHistory) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_alert_history (`ruleID`,`ruleName`,`metric`,`target`,`status`,`value`,`threshold`,`message`,`silenced`,`fireTime`,`resolveTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?);", h.RuleID, h.RuleName, h.Metric, h.Target, h.Status, h.Value, h.Threshold, h.Message, boolToInt(h.Silenced), h.FireTime, h.ResolveTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//ResolveAlert 告警恢复
+func (d *AlertDao) ResolveAlert(alertID int, value float64, resolveTime int64) error {
+	_, err := d.db.Exec("UPDATE goku_alert_history SET `status` = ?,`value` = ?,`resolveTime` = ? WHERE `alertID` = ?;", "resolved", value, resolveTime, alertID)
+	return err
+}
+
+//GetAlertHistoryList 分页获取告警记录，ruleID为0及status为空时不过滤
+func (d *AlertDao) GetAlertHistoryList(ruleID int, status string, page, pageSize int) ([]*entity.AlertHistory, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 2)
+	if ruleID != 0 {
+		conditions = append(conditions, "`ruleID` = ?")
+		args = append(args, ruleID)
+	}
+	if status != "" {
+		conditions = append(conditions, "`status` = ?")
+		args = append(args, status)
+	}
+	sql := "SELECT " + alertHistoryColumns + " FROM goku_alert_history"
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	count := getCountSQL(d.db, sql, args...)
+	rows, err := getPageSQL(d.db, sql, "`alertID`", "DESC", page, pageSize, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := scanAlertHistoryRows(rows)
+	return list, count, err
+}
+
+//GetAPIAlertValves 获取设置了告警阈值的接口，key为apiID
+func (d *AlertDao) GetAPIAlertValves() (map[int]int, error) {
+	rows, err := d.db.Query("SELECT `apiID`,`alertValve` FROM goku_gateway_api WHERE `alertValve` > 0;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	valves := make(map[int]int)
+	for rows.Next() {
+		var apiID, valve int
+		if err = rows.Scan(&apiID, &valve); err != nil {
+			return nil, err
+		}
+		valves[apiID] = valve
+	}
+	return valves, nil
+}
@@ -0,0 +1,102 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//AuditDao AuditDao
+type AuditDao struct {
+	db *SQL.DB
+}
+
+//NewAuditDao new AuditDao
+func NewAuditDao() *AuditDao {
+	return &AuditDao{}
+}
+
+//Create create
+func (d *AuditDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.AuditDao = d
+	return &i, nil
+}
+
+//AddAuditLog 新增审计日志
+func (d *AuditDao) AddAuditLog(l *entity.AuditLog) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_audit_log (`userID`,`operation`,`entityType`,`entityID`,`params`,`before`,`after`,`diff`,`statusCode`,`versionID`,`createTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?);", l.UserID, l.Operation, l.EntityType, l.EntityID, l.Params, l.Before, l.After, l.Diff, l.StatusCode, l.VersionID, l.CreateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//GetAuditLogList 分页获取审计日志，pageSize不大于0时返回全部
+func (d *AuditDao) GetAuditLogList(filter *entity.AuditFilter, page, pageSize int) ([]*entity.AuditLog, int, error) {
+	conditions := make([]string, 0, 6)
+	args := make([]interface{}, 0, 6)
+	if filter.UserID != 0 {
+		conditions = append(conditions, "L.`userID` = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "L.`entityType` = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "L.`entityID` = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.VersionID != 0 {
+		conditions = append(conditions, "L.`versionID` = ?")
+		args = append(args, filter.VersionID)
+	}
+	if filter.Start != "" {
+		conditions = append(conditions, "L.`createTime` >= ?")
+		args = append(args, filter.Start)
+	}
+	if filter.End != "" {
+		conditions = append(conditions, "L.`createTime` <= ?")
+		args = append(args, filter.End)
+	}
+	sql := "SELECT L.`logID`,L.`userID`,IFNULL(A.`loginCall`,''),L.`operation`,L.`entityType`,L.`entityID`,L.`params`,L.`before`,L.`after`,L.`diff`,L.`statusCode`,L.`versionID`,L.`createTime` FROM goku_audit_log L LEFT JOIN goku_admin A ON L.`userID` = A.`userID`"
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	var rows *SQL.Rows
+	var err error
+	count := 0
+	if pageSize > 0 {
+		count = getCountSQL(d.db, sql, args...)
+		rows, err = getPageSQL(d.db, sql, "L.`logID`", "DESC", page, pageSize, args...)
+	} else {
+		rows, err = d.db.Query(sql+" ORDER BY L.`logID` DESC;", args...)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	logs := make([]*entity.AuditLog, 0)
+	for rows.Next() {
+		var l entity.AuditLog
+		err = rows.Scan(&l.LogID, &l.UserID, &l.LoginCall, &l.Operation, &l.EntityType, &l.EntityID, &l.Params, &l.Before, &l.After, &l.Diff, &l.StatusCode, &l.VersionID, &l.CreateTime)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, &l)
+	}
+	if pageSize <= 0 {
+		count = len(logs)
+	}
+	return logs, count, nil
+}
+
+//LinkAuditLogs 将尚未关联版本的成功修改关联到版本，excludeEntityType类型的日志不关联
+func (d *AuditDao) LinkAuditLogs(versionID int, excludeEntityType string) error {
+	_, err := d.db.Exec("UPDATE goku_audit_log SET `versionID` = ? WHERE `versionID` = 0 AND `statusCode` = ? AND `entityType` <> ?;", versionID, "000000", excludeEntityType)
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/common/secret"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 
 	log "github.com/eolinker/goku-api-gateway/goku-log"
@@ -57,6 +58,15 @@ type jwtConf struct {
 	RunOnPreflight    bool            `json:"runOnPreflight"`
 	JwtCredentials    []jwtCredential `json:"jwtCredentials"`
 	HideCredentials   bool            `json:"hideCredentials"`
+	JWKS              string          `json:"jwks,omitempty"`
+	JWKSRefresh       int             `json:"jwksRefresh,omitempty"`
+}
+
+type hmacCredential struct {
+	UserName       string `json:"userName"`
+	Secret         string `json:"secret"`
+	HideCredential bool   `json:"hideCredential"`
+	Remark         string `json:"remark"`
 }
 
 type oauth2Credential struct {
@@ -90,16 +100,21 @@ func (d *AuthDao) Create(db *sql.DB) (interface{}, error) {
 //GetAuthStatus 获取认证状态
 func (d *AuthDao) GetAuthStatus(strategyID string) (bool, map[string]interface{}, error) {
 	db := d.db
-	var basicStatus, apikeyStatus int
+	var basicStatus, apikeyStatus, jwtStatus, hmacStatus int
 	sql := `SELECT CASE WHEN goku_plugin.pluginStatus = 0 THEN 0 ELSE goku_conn_plugin_strategy.pluginStatus END AS pluginStatus FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_plugin.pluginName = goku_conn_plugin_strategy.pluginName WHERE goku_conn_plugin_strategy.pluginName = ? AND goku_conn_plugin_strategy.strategyID = ?;`
 	db.QueryRow(sql, "goku-basic_auth", strategyID).Scan(&basicStatus)
 
 	db.QueryRow(sql, "goku-apikey_auth", strategyID).Scan(&apikeyStatus)
 
+	db.QueryRow(sql, "goku-jwt_auth", strategyID).Scan(&jwtStatus)
+
+	db.QueryRow(sql, "goku-hmac_auth", strategyID).Scan(&hmacStatus)
+
 	authInfo := map[string]interface{}{
 		"basicAuthStatus": basicStatus,
 		"apiKeyStatus":    apikeyStatus,
-		"jwtStatus":       0,
+		"jwtStatus":       jwtStatus,
+		"hmacStatus":      hmacStatus,
 		"oAuthStatus":     0,
 	}
 	return true, authInfo, nil
@@ -108,9 +123,9 @@ func (d *AuthDao) GetAuthStatus(strategyID string) (bool, map[string]interface{}
 //GetAuthInfo 获取认证信息
 func (d *AuthDao) GetAuthInfo(strategyID string) (bool, map[string]interface{}, error) {
 	db := d.db
-	var strategyName, auth string
-	sql := "SELECT IFNULL(auth,''),strategyName FROM goku_gateway_strategy WHERE strategyID = ?;"
-	err := db.QueryRow(sql, strategyID).Scan(&auth, &strategyName)
+	var strategyName, auth, authPolicy string
+	sql := "SELECT IFNULL(auth,''),strategyName,authPolicy FROM goku_gateway_strategy WHERE strategyID = ?;"
+	err := db.QueryRow(sql, strategyID).Scan(&auth, &strategyName, &authPolicy)
 	if err != nil {
 		return false, make(map[string]interface{}), err
 	}
@@ -140,29 +155,75 @@ func (d *AuthDao) GetAuthInfo(strategyID string) (bool, map[string]interface{},
 		}
 	}
 
+	jwt := jwtConf{JwtCredentials: make([]jwtCredential, 0)}
+	var jwtConfig string
+	err = db.QueryRow(sql, "goku-jwt_auth", strategyID).Scan(&jwtConfig)
+	if err == nil && jwtConfig != "" {
+		jwtConfig, err = secret.OpenAuthConfig("goku-jwt_auth", jwtConfig)
+		if err != nil {
+			return false, make(map[string]interface{}), err
+		}
+		err = json.Unmarshal([]byte(jwtConfig), &jwt)
+		if err != nil {
+			return false, make(map[string]interface{}), err
+		}
+		if jwt.JwtCredentials == nil {
+			jwt.JwtCredentials = make([]jwtCredential, 0)
+		}
+	}
+	hmacList := make([]hmacCredential, 0)
+	var hmacConfig string
+	err = db.QueryRow(sql, "goku-hmac_auth", strategyID).Scan(&hmacConfig)
+	if err == nil && hmacConfig != "" {
+		hmacConfig, err = secret.OpenAuthConfig("goku-hmac_auth", hmacConfig)
+		if err != nil {
+			return false, make(map[string]interface{}), err
+		}
+		err = json.Unmarshal([]byte(hmacConfig), &hmacList)
+		if err != nil {
+			return false, make(map[string]interface{}), err
+		}
+	}
+
 	authInfo := map[string]interface{}{
 		"strategyID":           strategyID,
 		"strategyName":         strategyName,
 		"auth":                 auth,
+		"authPolicy":           authPolicy,
 		"basicAuthList":        basicAuthList,
 		"apiKeyList":           apiKeyList,
-		"jwtCredentialList":    make([]interface{}, 0),
+		"jwtCredentialList":    jwt.JwtCredentials,
+		"jwksURL":              jwt.JWKS,
+		"hmacCredentialList":   hmacList,
 		"oauth2CredentialList": make([]interface{}, 0),
 	}
 	return true, authInfo, nil
 }
 
 //EditAuthInfo 编辑认证信息
-func (d *AuthDao) EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyList, jwtCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error) {
+func (d *AuthDao) EditAuthInfo(strategyID, strategyName, authPolicy, basicAuthList, apikeyList, jwtCredentialList, hmacCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error) {
 	db := d.db
 	now := time.Now().Format("2006-01-02 15:04:05")
-	sql := "UPDATE goku_gateway_strategy SET strategyName = ? WHERE strategyID = ?;"
+	// 密码及apikey只保存哈希，其他需要原值校验的凭证使用主密钥加密保存
+	basicAuthList, err := secret.SealAuthConfig("goku-basic_auth", basicAuthList)
+	if err != nil {
+		return false, err
+	}
+	apikeyList, err = secret.SealAuthConfig("goku-apikey_auth", apikeyList)
+	if err != nil {
+		return false, err
+	}
+	hmacCredentialList, err = secret.SealAuthConfig("goku-hmac_auth", hmacCredentialList)
+	if err != nil {
+		return false, err
+	}
+	sql := "UPDATE goku_gateway_strategy SET strategyName = ?,authPolicy = IFNULL(NULLIF(?,''),authPolicy) WHERE strategyID = ?;"
 	stmt, err := db.Prepare(sql)
 	if err != nil {
 		return false, err
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec(strategyName, strategyID)
+	_, err = stmt.Exec(strategyName, authPolicy, strategyID)
 	if err != nil {
 		return false, err
 	}
@@ -178,6 +239,21 @@ func (d *AuthDao) EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyLi
 		Tx.Rollback()
 		return false, err
 	}
+	if hmacCredentialList != "" {
+		_, err = Tx.Exec("UPDATE goku_conn_plugin_strategy SET pluginConfig = ?,updateTime = ? WHERE strategyID = ? AND pluginName = ? AND pluginStatus = 1;", hmacCredentialList, now, strategyID, "goku-hmac_auth")
+		if err != nil {
+			Tx.Rollback()
+			return false, err
+		}
+	}
+	if jwtCredentialList != "" {
+		// jwt插件配置中包含其他选项，只替换凭证列表
+		err = editJwtCredentials(Tx, strategyID, jwtCredentialList, now)
+		if err != nil {
+			Tx.Rollback()
+			return false, err
+		}
+	}
 
 	sql = "UPDATE goku_gateway_strategy SET updateTime = ? WHERE strategyID = ?;"
 	_, err = Tx.Exec(sql, now, strategyID)
@@ -192,3 +268,37 @@ func (d *AuthDao) EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyLi
 	}
 	return true, nil
 }
+
+func editJwtCredentials(Tx *sql.Tx, strategyID, jwtCredentialList, now string) error {
+	credentials := make([]jwtCredential, 0)
+	err := json.Unmarshal([]byte(jwtCredentialList), &credentials)
+	if err != nil {
+		return err
+	}
+	var jwtConfig string
+	err = Tx.QueryRow("SELECT IFNULL(pluginConfig,'') FROM goku_conn_plugin_strategy WHERE strategyID = ? AND pluginName = ? AND pluginStatus = 1;", strategyID, "goku-jwt_auth").Scan(&jwtConfig)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	conf := make(map[string]interface{})
+	if jwtConfig != "" {
+		err = json.Unmarshal([]byte(jwtConfig), &conf)
+		if err != nil {
+			return err
+		}
+	}
+	conf["jwtCredentials"] = credentials
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	jwtConfig, err = secret.SealAuthConfig("goku-jwt_auth", string(data))
+	if err != nil {
+		return err
+	}
+	_, err = Tx.Exec("UPDATE goku_conn_plugin_strategy SET pluginConfig = ?,updateTime = ? WHERE strategyID = ? AND pluginName = ? AND pluginStatus = 1;", jwtConfig, now, strategyID, "goku-jwt_auth")
+	return err
+}
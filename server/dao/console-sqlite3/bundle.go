@@ -0,0 +1,680 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//BundleDao BundleDao
+type BundleDao struct {
+	db *SQL.DB
+}
+
+//NewBundleDao new BundleDao
+func NewBundleDao() *BundleDao {
+	return &BundleDao{}
+}
+
+//Create create
+func (d *BundleDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.BundleDao = d
+	return &i, nil
+}
+
+//ExportBundle 导出控制台配置，includeSecrets为false时加密保存的凭证以占位值导出
+func (d *BundleDao) ExportBundle(includeSecrets bool) (*entity.Bundle, error) {
+	b := &entity.Bundle{Version: entity.BundleVersion}
+	var err error
+	if b.Clusters, err = d.exportClusters(); err != nil {
+		return nil, err
+	}
+	if b.Discovery, err = d.exportDiscovery(); err != nil {
+		return nil, err
+	}
+	if b.Balances, err = d.exportBalances(); err != nil {
+		return nil, err
+	}
+	if b.LogConfigs, err = d.exportLogConfigs(); err != nil {
+		return nil, err
+	}
+	if b.Plugins, err = d.exportPlugins(); err != nil {
+		return nil, err
+	}
+	if b.Projects, err = d.exportProjects(); err != nil {
+		return nil, err
+	}
+	if b.Strategies, err = d.exportStrategies(includeSecrets); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (d *BundleDao) exportClusters() ([]*entity.BundleCluster, error) {
+	rows, err := d.db.Query("SELECT `name`,`title`,IFNULL(`note`,'') FROM goku_cluster ORDER BY `id` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.BundleCluster, 0)
+	for rows.Next() {
+		c := new(entity.BundleCluster)
+		if err = rows.Scan(&c.Name, &c.Title, &c.Note); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (d *BundleDao) exportDiscovery() ([]*entity.BundleDiscovery, error) {
+	rows, err := d.db.Query("SELECT `name`,`driver`,IFNULL(`default`,0),`desc`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut` FROM goku_service_config ORDER BY `id` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.BundleDiscovery, 0)
+	for rows.Next() {
+		s := new(entity.BundleDiscovery)
+		if err = rows.Scan(&s.Name, &s.Driver, &s.Default, &s.Desc, &s.Config, &s.ClusterConfig, &s.HealthCheck, &s.HealthCheckPath, &s.HealthCheckPeriod, &s.HealthCheckCode, &s.HealthCheckTimeout); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func (d *BundleDao) exportBalances() ([]*entity.BundleBalance, error) {
+	rows, err := d.db.Query("SELECT `balanceName`,`serviceName`,`appName`,IFNULL(`static`,''),IFNULL(`staticCluster`,''),IFNULL(`balanceDesc`,'') FROM goku_balance ORDER BY `balanceID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.BundleBalance, 0)
+	for rows.Next() {
+		b := new(entity.BundleBalance)
+		if err = rows.Scan(&b.Name, &b.ServiceName, &b.AppName, &b.Static, &b.StaticCluster, &b.Desc); err != nil {
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+func (d *BundleDao) exportLogConfigs() ([]*entity.BundleLogConfig, error) {
+	rows, err := d.db.Query("SELECT `name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields` FROM goku_config_log ORDER BY `id` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.BundleLogConfig, 0)
+	for rows.Next() {
+		l := new(entity.BundleLogConfig)
+		if err = rows.Scan(&l.Name, &l.Enable, &l.Dir, &l.File, &l.Level, &l.Period, &l.Expire, &l.Fields); err != nil {
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	return list, rows.Err()
+}
+
+func (d *BundleDao) exportPlugins() ([]*entity.BundlePlugin, error) {
+	rows, err := d.db.Query("SELECT `pluginName`,IFNULL(`chineseName`,''),`pluginStatus`,`pluginPriority`,IFNULL(`pluginConfig`,''),`isStop`,`pluginType`,`official`,IFNULL(`pluginDesc`,''),`version`,`isCheck`,`errorPolicy`,`errorStatus`,`errorBody` FROM goku_plugin ORDER BY `pluginID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.BundlePlugin, 0)
+	for rows.Next() {
+		p := new(entity.BundlePlugin)
+		if err = rows.Scan(&p.Name, &p.ChineseName, &p.Status, &p.Priority, &p.Config, &p.IsStop, &p.Type, &p.Official, &p.Desc, &p.Version, &p.IsCheck, &p.ErrorPolicy, &p.ErrorStatus, &p.ErrorBody); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+type bundleGroup struct {
+	name     string
+	parentID int
+}
+
+func (d *BundleDao) exportProjects() ([]*entity.BundleProject, error) {
+	rows, err := d.db.Query("SELECT `projectID`,`projectName` FROM goku_gateway_project ORDER BY `projectID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[int]*entity.BundleProject)
+	list := make([]*entity.BundleProject, 0)
+	for rows.Next() {
+		var projectID int
+		p := &entity.BundleProject{Groups: make([]string, 0), APIs: make([]*entity.BundleAPI, 0)}
+		if err = rows.Scan(&projectID, &p.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		projects[projectID] = p
+		list = append(list, p)
+	}
+	rows.Close()
+
+	rows, err = d.db.Query("SELECT `groupID`,`projectID`,`groupName`,`parentGroupID` FROM goku_gateway_api_group ORDER BY `groupDepth` ASC,`groupID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[int]*bundleGroup)
+	groupProjects := make([][2]int, 0)
+	for rows.Next() {
+		var groupID, projectID int
+		g := new(bundleGroup)
+		if err = rows.Scan(&groupID, &projectID, &g.name, &g.parentID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		groups[groupID] = g
+		groupProjects = append(groupProjects, [2]int{groupID, projectID})
+	}
+	rows.Close()
+	for _, gp := range groupProjects {
+		if p, has := projects[gp[1]]; has {
+			p.Groups = append(p.Groups, groupNamePath(groups, gp[0]))
+		}
+	}
+
+	rows, err = d.db.Query("SELECT `projectID`,`groupID`,`apiName`,`requestURL`,`requestMethod`,IFNULL(`protocol`,''),IFNULL(`balanceName`,''),IFNULL(`targetURL`,''),IFNULL(`targetMethod`,''),`isFollow`,IFNULL(`stripPrefix`,''),IFNULL(`stripSlash`,''),IFNULL(`timeout`,0),IFNULL(`retryCount`,0),`alertValve`,`apiType`,`responseDataType`,IFNULL(`linkApis`,''),IFNULL(`staticResponse`,''),IFNULL(`rewrite`,'') FROM goku_gateway_api ORDER BY `apiID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var projectID, groupID int
+		a := new(entity.BundleAPI)
+		if err = rows.Scan(&projectID, &groupID, &a.Name, &a.RequestURL, &a.RequestMethod, &a.Protocol, &a.BalanceName, &a.TargetURL, &a.TargetMethod, &a.IsFollow, &a.StripPrefix, &a.StripSlash, &a.Timeout, &a.RetryCount, &a.AlertValve, &a.APIType, &a.ResponseDataType, &a.LinkAPIs, &a.StaticResponse, &a.Rewrite); err != nil {
+			return nil, err
+		}
+		if groupID != 0 {
+			a.Group = groupNamePath(groups, groupID)
+		}
+		if p, has := projects[projectID]; has {
+			p.APIs = append(p.APIs, a)
+		}
+	}
+	return list, rows.Err()
+}
+
+// groupNamePath 由分组ID得到以“/”分隔的分组名称路径
+func groupNamePath(groups map[int]*bundleGroup, groupID int) string {
+	names := make([]string, 0, 5)
+	for id, depth := groupID, 0; id != 0 && depth < 10; depth++ {
+		g, has := groups[id]
+		if !has {
+			break
+		}
+		names = append([]string{g.name}, names...)
+		id = g.parentID
+	}
+	return strings.Join(names, "/")
+}
+
+func (d *BundleDao) exportStrategies(includeSecrets bool) ([]*entity.BundleStrategy, error) {
+	rows, err := d.db.Query("SELECT S.`strategyID`,S.`strategyName`,IFNULL(G.`groupName`,''),S.`strategyType`,S.`enableStatus`,IFNULL(S.`auth`,''),S.`authPolicy` FROM goku_gateway_strategy S LEFT JOIN goku_gateway_strategy_group G ON S.`groupID` = G.`groupID` ORDER BY S.`strategyType` DESC,S.`createTime` ASC,S.`strategyID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	strategies := make(map[string]*entity.BundleStrategy)
+	list := make([]*entity.BundleStrategy, 0)
+	for rows.Next() {
+		s := &entity.BundleStrategy{Plugins: make([]*entity.BundleStrategyPlugin, 0), APIs: make([]*entity.BundleStrategyAPI, 0)}
+		if err = rows.Scan(&s.ID, &s.Name, &s.Group, &s.Type, &s.EnableStatus, &s.Auth, &s.AuthPolicy); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		strategies[s.ID] = s
+		list = append(list, s)
+	}
+	rows.Close()
+
+	rows, err = d.db.Query("SELECT `strategyID`,`pluginName`,IFNULL(`pluginStatus`,0),IFNULL(`pluginConfig`,'') FROM goku_conn_plugin_strategy ORDER BY `connID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var strategyID string
+		p := new(entity.BundleStrategyPlugin)
+		if err = rows.Scan(&strategyID, &p.Name, &p.Status, &p.Config); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if p.Config, err = exportBundleAuthConfig(strategyID, p.Name, p.Config, includeSecrets); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if s, has := strategies[strategyID]; has {
+			s.Plugins = append(s.Plugins, p)
+		}
+	}
+	rows.Close()
+
+	rows, err = d.db.Query("SELECT C.`strategyID`,C.`apiID`,P.`projectName`,A.`requestURL`,A.`requestMethod`,IFNULL(C.`target`,'') FROM goku_conn_strategy_api C INNER JOIN goku_gateway_api A ON C.`apiID` = A.`apiID` INNER JOIN goku_gateway_project P ON A.`projectID` = P.`projectID` ORDER BY C.`connID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	apis := make(map[string]*entity.BundleStrategyAPI)
+	for rows.Next() {
+		var strategyID string
+		var apiID int
+		a := &entity.BundleStrategyAPI{Plugins: make([]*entity.BundleStrategyPlugin, 0)}
+		if err = rows.Scan(&strategyID, &apiID, &a.Project, &a.RequestURL, &a.RequestMethod, &a.Target); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if s, has := strategies[strategyID]; has {
+			s.APIs = append(s.APIs, a)
+			apis[strategyID+":"+strconv.Itoa(apiID)] = a
+		}
+	}
+	rows.Close()
+
+	rows, err = d.db.Query("SELECT `strategyID`,`apiID`,`pluginName`,IFNULL(`pluginStatus`,0),IFNULL(`pluginConfig`,'') FROM goku_conn_plugin_api ORDER BY `connID` ASC;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var strategyID string
+		var apiID int
+		p := new(entity.BundleStrategyPlugin)
+		if err = rows.Scan(&strategyID, &apiID, &p.Name, &p.Status, &p.Config); err != nil {
+			return nil, err
+		}
+		if p.Config, err = exportBundleAuthConfig(strategyID, p.Name, p.Config, includeSecrets); err != nil {
+			return nil, err
+		}
+		if a, has := apis[strategyID+":"+strconv.Itoa(apiID)]; has {
+			a.Plugins = append(a.Plugins, p)
+		}
+	}
+	return list, rows.Err()
+}
+
+//ApplyBundle 在同一事务中按自然键新增或更新配置包中的资源，配置包中未出现的资源保持不变；
+//策略所绑定的插件及接口以配置包为准整体替换
+func (d *BundleDao) ApplyBundle(b *entity.Bundle, userID int) error {
+	Tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	steps := []func(*SQL.Tx, *entity.Bundle, string, int) error{
+		applyBundleClusters,
+		applyBundleDiscovery,
+		applyBundleBalances,
+		applyBundleLogConfigs,
+		applyBundlePlugins,
+		applyBundleProjects,
+		applyBundleStrategies,
+	}
+	for _, step := range steps {
+		if err = step(Tx, b, now, userID); err != nil {
+			Tx.Rollback()
+			return err
+		}
+	}
+	return Tx.Commit()
+}
+
+// lookupID 查询自然键对应的ID，不存在时返回false
+func lookupID(Tx *SQL.Tx, sql string, args ...interface{}) (int, bool, error) {
+	var id int
+	err := Tx.QueryRow(sql, args...).Scan(&id)
+	if err == SQL.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+func applyBundleClusters(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, c := range b.Clusters {
+		id, has, err := lookupID(Tx, "SELECT `id` FROM goku_cluster WHERE `name` = ?;", c.Name)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_cluster SET `title` = ?,`note` = ? WHERE `id` = ?;", c.Title, c.Note, id)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_cluster (`name`,`title`,`note`) VALUES (?,?,?);", c.Name, c.Title, c.Note)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBundleDiscovery(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, s := range b.Discovery {
+		id, has, err := lookupID(Tx, "SELECT `id` FROM goku_service_config WHERE `name` = ?;", s.Name)
+		if err != nil {
+			return err
+		}
+		if s.Default == 1 {
+			if _, err = Tx.Exec("UPDATE goku_service_config SET `default` = 0;"); err != nil {
+				return err
+			}
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_service_config SET `driver` = ?,`default` = ?,`desc` = ?,`config` = ?,`clusterConfig` = ?,`healthCheck` = ?,`healthCheckPath` = ?,`healthCheckPeriod` = ?,`healthCheckCode` = ?,`healthCheckTimeOut` = ?,`updateTime` = ? WHERE `id` = ?;", s.Driver, s.Default, s.Desc, s.Config, s.ClusterConfig, s.HealthCheck, s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeout, now, id)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_service_config (`name`,`driver`,`default`,`desc`,`config`,`clusterConfig`,`healthCheck`,`healthCheckPath`,`healthCheckPeriod`,`healthCheckCode`,`healthCheckTimeOut`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?);", s.Name, s.Driver, s.Default, s.Desc, s.Config, s.ClusterConfig, s.HealthCheck, s.HealthCheckPath, s.HealthCheckPeriod, s.HealthCheckCode, s.HealthCheckTimeout, now, now)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBundleBalances(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, balance := range b.Balances {
+		id, has, err := lookupID(Tx, "SELECT `balanceID` FROM goku_balance WHERE `balanceName` = ?;", balance.Name)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_balance SET `serviceName` = ?,`appName` = ?,`static` = ?,`staticCluster` = ?,`balanceDesc` = ?,`updateTime` = ? WHERE `balanceID` = ?;", balance.ServiceName, balance.AppName, balance.Static, balance.StaticCluster, balance.Desc, now, id)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_balance (`balanceName`,`serviceName`,`appName`,`static`,`staticCluster`,`balanceDesc`,`createTime`,`updateTime`,`defaultConfig`,`clusterConfig`,`balanceConfig`) VALUES (?,?,?,?,?,?,?,?,'','','');", balance.Name, balance.ServiceName, balance.AppName, balance.Static, balance.StaticCluster, balance.Desc, now, now)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBundleLogConfigs(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, l := range b.LogConfigs {
+		id, has, err := lookupID(Tx, "SELECT `id` FROM goku_config_log WHERE `name` = ?;", l.Name)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_config_log SET `enable` = ?,`dir` = ?,`file` = ?,`level` = ?,`period` = ?,`expire` = ?,`fields` = ? WHERE `id` = ?;", l.Enable, l.Dir, l.File, l.Level, l.Period, l.Expire, l.Fields, id)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_config_log (`name`,`enable`,`dir`,`file`,`level`,`period`,`expire`,`fields`) VALUES (?,?,?,?,?,?,?,?);", l.Name, l.Enable, l.Dir, l.File, l.Level, l.Period, l.Expire, l.Fields)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBundlePlugins(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, p := range b.Plugins {
+		id, has, err := lookupID(Tx, "SELECT `pluginID` FROM goku_plugin WHERE `pluginName` = ?;", p.Name)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_plugin SET `chineseName` = ?,`pluginStatus` = ?,`pluginPriority` = ?,`pluginConfig` = ?,`isStop` = ?,`pluginType` = ?,`official` = ?,`pluginDesc` = ?,`version` = ?,`isCheck` = ?,`errorPolicy` = ?,`errorStatus` = ?,`errorBody` = ? WHERE `pluginID` = ?;", p.ChineseName, p.Status, p.Priority, p.Config, p.IsStop, p.Type, p.Official, p.Desc, p.Version, p.IsCheck, p.ErrorPolicy, p.ErrorStatus, p.ErrorBody, id)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_plugin (`pluginName`,`chineseName`,`pluginStatus`,`pluginPriority`,`pluginConfig`,`isStop`,`pluginType`,`official`,`pluginDesc`,`version`,`isCheck`,`errorPolicy`,`errorStatus`,`errorBody`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?);", p.Name, p.ChineseName, p.Status, p.Priority, p.Config, p.IsStop, p.Type, p.Official, p.Desc, p.Version, p.IsCheck, p.ErrorPolicy, p.ErrorStatus, p.ErrorBody)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyBundleProjects(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, p := range b.Projects {
+		projectID, has, err := lookupID(Tx, "SELECT `projectID` FROM goku_gateway_project WHERE `projectName` = ?;", p.Name)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = Tx.Exec("UPDATE goku_gateway_project SET `updateTime` = ? WHERE `projectID` = ?;", now, projectID)
+		} else {
+			var result SQL.Result
+			result, err = Tx.Exec("INSERT INTO goku_gateway_project (`projectName`,`createTime`,`updateTime`) VALUES (?,?,?);", p.Name, now, now)
+			if err == nil {
+				var id int64
+				id, err = result.LastInsertId()
+				projectID = int(id)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		groups := make(map[string]int)
+		for _, path := range p.Groups {
+			if _, err = bundleGroupID(Tx, projectID, path, groups); err != nil {
+				return err
+			}
+		}
+		for _, a := range p.APIs {
+			groupID, err := bundleGroupID(Tx, projectID, a.Group, groups)
+			if err != nil {
+				return err
+			}
+			apiID, has, err := lookupID(Tx, "SELECT `apiID` FROM goku_gateway_api WHERE `projectID` = ? AND `requestURL` = ? AND `requestMethod` = ?;", projectID, a.RequestURL, a.RequestMethod)
+			if err != nil {
+				return err
+			}
+			if has {
+				_, err = Tx.Exec("UPDATE goku_gateway_api SET `groupID` = ?,`apiName` = ?,`protocol` = ?,`balanceName` = ?,`targetURL` = ?,`targetMethod` = ?,`isFollow` = ?,`stripPrefix` = ?,`stripSlash` = ?,`timeout` = ?,`retryCount` = ?,`alertValve` = ?,`apiType` = ?,`responseDataType` = ?,`linkApis` = ?,`staticResponse` = ?,`rewrite` = ?,`updateTime` = ?,`lastUpdateUserID` = ? WHERE `apiID` = ?;", groupID, a.Name, a.Protocol, a.BalanceName, a.TargetURL, a.TargetMethod, a.IsFollow, a.StripPrefix, a.StripSlash, a.Timeout, a.RetryCount, a.AlertValve, a.APIType, a.ResponseDataType, a.LinkAPIs, a.StaticResponse, a.Rewrite, now, userID, apiID)
+			} else {
+				_, err = Tx.Exec("INSERT INTO goku_gateway_api (`projectID`,`groupID`,`apiName`,`requestURL`,`requestMethod`,`protocol`,`balanceName`,`targetURL`,`targetMethod`,`isFollow`,`stripPrefix`,`stripSlash`,`timeout`,`retryCount`,`alertValve`,`apiType`,`responseDataType`,`linkApis`,`staticResponse`,`rewrite`,`createTime`,`updateTime`,`managerID`,`lastUpdateUserID`,`createUserID`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", projectID, groupID, a.Name, a.RequestURL, a.RequestMethod, a.Protocol, a.BalanceName, a.TargetURL, a.TargetMethod, a.IsFollow, a.StripPrefix, a.StripSlash, a.Timeout, a.RetryCount, a.AlertValve, a.APIType, a.ResponseDataType, a.LinkAPIs, a.StaticResponse, a.Rewrite, now, now, userID, userID, userID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bundleGroupID 按分组名称路径获取分组ID，路径上不存在的分组逐级新建
+func bundleGroupID(Tx *SQL.Tx, projectID int, path string, groups map[string]int) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	if groupID, has := groups[path]; has {
+		return groupID, nil
+	}
+	names := strings.Split(path, "/")
+	if len(names) > 5 {
+		return 0, fmt.Errorf("group %s is deeper than 5 levels", path)
+	}
+	parentID, groupPath := 0, ""
+	for i, name := range names {
+		sub := strings.Join(names[:i+1], "/")
+		groupID, has := groups[sub]
+		if !has {
+			var err error
+			groupID, has, err = lookupID(Tx, "SELECT `groupID` FROM goku_gateway_api_group WHERE `projectID` = ? AND `parentGroupID` = ? AND `groupName` = ?;", projectID, parentID, name)
+			if err != nil {
+				return 0, err
+			}
+			if !has {
+				result, err := Tx.Exec("INSERT INTO goku_gateway_api_group (`projectID`,`groupName`,`groupDepth`,`parentGroupID`) VALUES (?,?,?,?);", projectID, name, i+1, parentID)
+				if err != nil {
+					return 0, err
+				}
+				id, err := result.LastInsertId()
+				if err != nil {
+					return 0, err
+				}
+				groupID = int(id)
+				idPath := strconv.Itoa(groupID)
+				if groupPath != "" {
+					idPath = groupPath + "," + idPath
+				}
+				if _, err = Tx.Exec("UPDATE goku_gateway_api_group SET `groupPath` = ? WHERE `groupID` = ?;", idPath, groupID); err != nil {
+					return 0, err
+				}
+			}
+			groups[sub] = groupID
+		}
+		if groupPath == "" {
+			groupPath = strconv.Itoa(groupID)
+		} else {
+			groupPath += "," + strconv.Itoa(groupID)
+		}
+		parentID = groupID
+	}
+	return parentID, nil
+}
+
+func applyBundleStrategies(Tx *SQL.Tx, b *entity.Bundle, now string, userID int) error {
+	for _, s := range b.Strategies {
+		groupID := 0
+		if s.Group != "" {
+			id, has, err := lookupID(Tx, "SELECT `groupID` FROM goku_gateway_strategy_group WHERE `groupName` = ?;", s.Group)
+			if err != nil {
+				return err
+			}
+			if !has {
+				result, err := Tx.Exec("INSERT INTO goku_gateway_strategy_group (`groupName`) VALUES (?);", s.Group)
+				if err != nil {
+					return err
+				}
+				newID, err := result.LastInsertId()
+				if err != nil {
+					return err
+				}
+				id = int(newID)
+			}
+			groupID = id
+		}
+		var count int
+		if err := Tx.QueryRow("SELECT COUNT(*) FROM goku_gateway_strategy WHERE `strategyID` = ?;", s.ID).Scan(&count); err != nil {
+			return err
+		}
+		var err error
+		if count > 0 {
+			_, err = Tx.Exec("UPDATE goku_gateway_strategy SET `strategyName` = ?,`groupID` = ?,`strategyType` = ?,`enableStatus` = ?,`auth` = ?,`authPolicy` = ?,`updateTime` = ? WHERE `strategyID` = ?;", s.Name, groupID, s.Type, s.EnableStatus, s.Auth, authPolicyOrDefault(s.AuthPolicy), now, s.ID)
+		} else {
+			_, err = Tx.Exec("INSERT INTO goku_gateway_strategy (`strategyID`,`strategyName`,`groupID`,`strategyType`,`enableStatus`,`auth`,`authPolicy`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?);", s.ID, s.Name, groupID, s.Type, s.EnableStatus, s.Auth, authPolicyOrDefault(s.AuthPolicy), now, now)
+		}
+		if err != nil {
+			return err
+		}
+		stored, err := storedBundleAuthConfigs(Tx, s.ID)
+		if err != nil {
+			return err
+		}
+		for _, table := range []string{"goku_conn_plugin_strategy", "goku_conn_strategy_api", "goku_conn_plugin_api"} {
+			if _, err = Tx.Exec("DELETE FROM "+table+" WHERE `strategyID` = ?;", s.ID); err != nil {
+				return err
+			}
+		}
+		for _, p := range s.Plugins {
+			config, err := sealBundleAuthConfig(s.ID, p.Name, p.Config, stored[p.Name])
+			if err != nil {
+				return err
+			}
+			_, err = Tx.Exec("INSERT INTO goku_conn_plugin_strategy (`strategyID`,`pluginName`,`pluginConfig`,`pluginStatus`,`createTime`,`updateTime`,`updaterID`) VALUES (?,?,?,?,?,?,?);", s.ID, p.Name, config, p.Status, now, now, userID)
+			if err != nil {
+				return err
+			}
+		}
+		for _, a := range s.APIs {
+			apiID, has, err := lookupID(Tx, "SELECT A.`apiID` FROM goku_gateway_api A INNER JOIN goku_gateway_project P ON A.`projectID` = P.`projectID` WHERE P.`projectName` = ? AND A.`requestURL` = ? AND A.`requestMethod` = ?;", a.Project, a.RequestURL, a.RequestMethod)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return fmt.Errorf("strategy %s: api [%s]%s of project %s does not exist", s.ID, a.RequestMethod, a.RequestURL, a.Project)
+			}
+			_, err = Tx.Exec("INSERT INTO goku_conn_strategy_api (`strategyID`,`apiID`,`target`,`updateTime`) VALUES (?,?,?,?);", s.ID, apiID, a.Target, now)
+			if err != nil {
+				return err
+			}
+			for _, p := range a.Plugins {
+				config, err := sealBundleAuthConfig(s.ID, p.Name, p.Config, stored[p.Name])
+				if err != nil {
+					return err
+				}
+				_, err = Tx.Exec("INSERT INTO goku_conn_plugin_api (`strategyID`,`apiID`,`pluginName`,`pluginConfig`,`pluginStatus`,`createTime`,`updateTime`,`updaterID`) VALUES (?,?,?,?,?,?,?,?);", s.ID, apiID, p.Name, config, p.Status, now, now, userID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// exportBundleAuthConfig 导出鉴权插件的凭证，默认以占位值代替；includeSecrets为true时解密导出，使配置包可以应用到使用其他主密钥的控制台
+func exportBundleAuthConfig(strategyID, name, config string, includeSecrets bool) (string, error) {
+	if includeSecrets {
+		return openBundleAuthConfig(strategyID, name, config)
+	}
+	redacted, err := secret.RedactAuthConfig(name, config)
+	if err != nil {
+		return "", fmt.Errorf("strategy %s: plugin %s: %s", strategyID, name, err.Error())
+	}
+	return redacted, nil
+}
+
+func openBundleAuthConfig(strategyID, name, config string) (string, error) {
+	opened, err := secret.OpenAuthConfig(name, config)
+	if err != nil {
+		return "", fmt.Errorf("strategy %s: plugin %s: %s", strategyID, name, err.Error())
+	}
+	return opened, nil
+}
+
+// storedBundleAuthConfigs 策略当前保存的鉴权插件配置，策略及接口上的同名插件合并为一组凭证
+func storedBundleAuthConfigs(Tx *SQL.Tx, strategyID string) (map[string][]string, error) {
+	rows, err := Tx.Query("SELECT `pluginName`,IFNULL(`pluginConfig`,'') FROM goku_conn_plugin_strategy WHERE `strategyID` = ? UNION ALL SELECT `pluginName`,IFNULL(`pluginConfig`,'') FROM goku_conn_plugin_api WHERE `strategyID` = ?;", strategyID, strategyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stored := make(map[string][]string)
+	for rows.Next() {
+		var name, config string
+		if err = rows.Scan(&name, &config); err != nil {
+			return nil, err
+		}
+		if secret.IsAuthPlugin(name) {
+			stored[name] = append(stored[name], config)
+		}
+	}
+	return stored, rows.Err()
+}
+
+// sealBundleAuthConfig 应用时还原占位值并使用本控制台的主密钥加密凭证，无法解密的密文不予保存
+func sealBundleAuthConfig(strategyID, name, config string, stored []string) (string, error) {
+	restored, err := secret.RestoreAuthConfig(name, config, stored...)
+	if err != nil {
+		return "", fmt.Errorf("strategy %s: plugin %s: %s", strategyID, name, err.Error())
+	}
+	opened, err := openBundleAuthConfig(strategyID, name, restored)
+	if err != nil {
+		return "", err
+	}
+	return secret.SealAuthConfig(name, opened)
+}
+
+func authPolicyOrDefault(policy string) string {
+	if policy == "" {
+		return "any"
+	}
+	return policy
+}
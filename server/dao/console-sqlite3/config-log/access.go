@@ -0,0 +1,39 @@
+package config_log
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+)
+
+//GetAccessOutputs 获取集群的access日志输出
+func (d *ConfigLogDao) GetAccessOutputs(cluster string) ([]*config.AccessLogOutput, error) {
+	var outputs string
+	err := d.db.QueryRow("SELECT `outputs` FROM `goku_config_access_output` WHERE `cluster` = ?;", cluster).Scan(&outputs)
+	if err == sql.ErrNoRows {
+		return make([]*config.AccessLogOutput, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*config.AccessLogOutput, 0)
+	if err = json.Unmarshal([]byte(outputs), &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+//SetAccessOutputs 设置集群的access日志输出，outputs为空时删除
+func (d *ConfigLogDao) SetAccessOutputs(cluster string, outputs []*config.AccessLogOutput, updateTime string) error {
+	if len(outputs) == 0 {
+		_, err := d.db.Exec("DELETE FROM `goku_config_access_output` WHERE `cluster` = ?;", cluster)
+		return err
+	}
+	data, err := json.Marshal(outputs)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec("REPLACE INTO `goku_config_access_output` (`cluster`,`outputs`,`updateTime`) VALUES (?,?,?);", cluster, string(data), updateTime)
+	return err
+}
@@ -10,7 +10,7 @@ import (
 //GetAPIContent 获取接口信息
 func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 	db := d.db
-	sql := "SELECT apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,'') FROM goku_gateway_api"
+	sql := "SELECT goku_gateway_api.apiID,apiName,IFNULL(protocol,'http'),IFNULL(balanceName,''),IFNULL(targetURL,''),CASE WHEN isFollow = 'true' THEN 'FOLLOW' ELSE targetMethod END targetMethod,responseDataType,requestURL,requestMethod,timeout,alertValve,retryCount,IFNULL(linkApis,''),IFNULL(staticResponse,''),IFNULL(goku_gateway_api.rewrite,''),IFNULL(C.config,''),IFNULL(V.config,'') FROM goku_gateway_api LEFT JOIN goku_api_cache C ON goku_gateway_api.apiID = C.apiID LEFT JOIN goku_api_validation V ON goku_gateway_api.apiID = V.apiID"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -20,10 +20,10 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 	defer rows.Close()
 	for rows.Next() {
 		var apiContent config.APIContent
-		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod string
+		var linkApisStr, protocol, balance, targetURL, targetMethod, requestMethod, rewriteStr, cacheStr, validationStr string
 		var retryCount int
 		linkApis := make([]config.APIStepUIConfig, 0)
-		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse)
+		err = rows.Scan(&apiContent.ID, &apiContent.Name, &protocol, &balance, &targetURL, &targetMethod, &apiContent.OutPutEncoder, &apiContent.RequestURL, &requestMethod, &apiContent.TimeOutTotal, &apiContent.AlertThreshold, &retryCount, &linkApisStr, &apiContent.StaticResponse, &rewriteStr, &cacheStr, &validationStr)
 		if err != nil {
 			return nil, err
 		}
@@ -34,8 +34,35 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 			}
 		}
 
+		if cacheStr != "" {
+			cache := new(config.ResponseCacheConfig)
+			if err = json.Unmarshal([]byte(cacheStr), cache); err != nil {
+				return nil, err
+			}
+			if cache.Enable {
+				apiContent.Cache = cache
+			}
+		}
+
+		if validationStr != "" {
+			validation := new(config.ValidationConfig)
+			if err = json.Unmarshal([]byte(validationStr), validation); err != nil {
+				return nil, err
+			}
+			if validation.Enable {
+				apiContent.Validation = validation
+			}
+		}
+
 		apiContent.Methods = strings.Split(requestMethod, ",")
 		if len(linkApis) < 1 {
+			var rewrite *config.RewriteConfig
+			if rewriteStr != "" {
+				rewrite = new(config.RewriteConfig)
+				if err = json.Unmarshal([]byte(rewriteStr), rewrite); err != nil {
+					return nil, err
+				}
+			}
 			apiContent.Steps = append(apiContent.Steps, &config.APIStepConfig{
 				Proto:   protocol,
 				Balance: balance,
@@ -45,6 +72,7 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 				Decode:  apiContent.OutPutEncoder,
 				TimeOut: apiContent.TimeOutTotal,
 				Retry:   retryCount,
+				Rewrite: rewrite,
 			})
 		} else {
 			for _, api := range linkApis {
@@ -69,21 +97,24 @@ func (d *VersionConfigDao)GetAPIContent() ([]*config.APIContent, error) {
 						Target:     rename.Target,
 					})
 				}
+				actions = append(actions, api.Transform...)
 				apiContent.Steps = append(apiContent.Steps, &config.APIStepConfig{
-					Proto:     api.Proto,
-					Balance:   api.Balance,
-					Path:      api.Path,
-					Body:      api.Body,
-					Method:    api.Method,
-					Encode:    api.Encode,
-					Decode:    api.Decode,
-					TimeOut:   api.TimeOut,
-					Retry:     api.Retry,
-					Group:     api.Group,
-					Target:    api.Target,
-					WhiteList: api.WhiteList,
-					BlackList: api.BlackList,
-					Actions:   actions,
+					Proto:      api.Proto,
+					Balance:    api.Balance,
+					Path:       api.Path,
+					Body:       api.Body,
+					Method:     api.Method,
+					Encode:     api.Encode,
+					Decode:     api.Decode,
+					TimeOut:    api.TimeOut,
+					Retry:      api.Retry,
+					Group:      api.Group,
+					Target:     api.Target,
+					WhiteList:  api.WhiteList,
+					BlackList:  api.BlackList,
+					Actions:    actions,
+					Rewrite:    api.Rewrite,
+					GRPCMethod: api.GRPCMethod,
 				})
 			}
 		}
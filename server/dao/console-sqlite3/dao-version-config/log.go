@@ -59,3 +59,25 @@ func (d *VersionConfigDao)GetLogInfo() (*config.LogConfig, *config.AccessLogConf
 	}
 	return logCf, accessCf, nil
 }
+
+//GetAccessLogOutputs 获取各集群的access日志输出
+func (d *VersionConfigDao) GetAccessLogOutputs() (map[string][]*config.AccessLogOutput, error) {
+	rows, err := d.db.Query("SELECT `cluster`,`outputs` FROM goku_config_access_output;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	outputs := make(map[string][]*config.AccessLogOutput)
+	for rows.Next() {
+		var cluster, data string
+		if err = rows.Scan(&cluster, &data); err != nil {
+			return nil, err
+		}
+		list := make([]*config.AccessLogOutput, 0)
+		if err = json.Unmarshal([]byte(data), &list); err != nil {
+			return nil, err
+		}
+		outputs[cluster] = list
+	}
+	return outputs, rows.Err()
+}
@@ -6,10 +6,13 @@ import (
 	"github.com/eolinker/goku-api-gateway/config"
 )
 
+// pluginPolicyFields 插件的执行优先级及错误处理策略
+const pluginPolicyFields = "goku_plugin.pluginPriority,goku_plugin.errorPolicy,goku_plugin.errorStatus,goku_plugin.errorBody"
+
 //GetGlobalPlugin 获取全局插件
 func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error) {
 	db := d.db
-	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType FROM goku_plugin"
+	sql := "SELECT pluginName,isStop,IFNULL(pluginConfig,''),pluginType,pluginPriority,errorPolicy,errorStatus,errorBody FROM goku_plugin WHERE pluginStatus = 1 ORDER BY pluginPriority DESC"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -20,25 +23,16 @@ func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error
 		GlobalPlugins: make([]*config.PluginConfig, 0, 20),
 	}
 	for rows.Next() {
-		var pluginName, pluginConfig string
-		var isStop bool
 		var pluginType int
-		err = rows.Scan(&pluginName, &isStop, &pluginConfig, &pluginType)
+		p := new(config.PluginConfig)
+		err = rows.Scan(&p.Name, &p.IsStop, &p.Config, &pluginType, &p.Priority, &p.ErrorPolicy, &p.ErrorStatus, &p.ErrorBody)
 		if err != nil {
 			return nil, err
 		}
 		if pluginType == 0 {
-			pluginConfigs.GlobalPlugins = append(pluginConfigs.GlobalPlugins, &config.PluginConfig{
-				Name:   pluginName,
-				IsStop: isStop,
-				Config: pluginConfig,
-			})
+			pluginConfigs.GlobalPlugins = append(pluginConfigs.GlobalPlugins, p)
 		} else {
-			pluginConfigs.BeforePlugins = append(pluginConfigs.BeforePlugins, &config.PluginConfig{
-				Name:   pluginName,
-				IsStop: isStop,
-				Config: pluginConfig,
-			})
+			pluginConfigs.BeforePlugins = append(pluginConfigs.BeforePlugins, p)
 		}
 	}
 	return &pluginConfigs, nil
@@ -47,7 +41,7 @@ func (d *VersionConfigDao) GetGlobalPlugin() (*config.GatewayPluginConfig, error
 //GetAPIPlugins 获取接口插件
 func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, error) {
 	db := d.db
-	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName"
+	sql := "SELECT goku_conn_plugin_api.apiID,goku_conn_plugin_api.strategyID,goku_conn_plugin_api.pluginName,goku_conn_plugin_api.pluginConfig,goku_plugin.isStop," + pluginPolicyFields + " FROM goku_conn_plugin_api INNER JOIN goku_plugin ON goku_conn_plugin_api.pluginName = goku_plugin.pluginName ORDER BY goku_plugin.pluginPriority DESC"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, err
@@ -56,9 +50,9 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 	pluginMaps := make(map[string][]*config.PluginConfig)
 	for rows.Next() {
 		var apiID int
-		var isStop bool
-		var pluginName, pluginConfig, strategyID string
-		err = rows.Scan(&apiID, &strategyID, &pluginName, &pluginConfig, &isStop)
+		var strategyID string
+		p := new(config.PluginConfig)
+		err = rows.Scan(&apiID, &strategyID, &p.Name, &p.Config, &p.IsStop, &p.Priority, &p.ErrorPolicy, &p.ErrorStatus, &p.ErrorBody)
 		if err != nil {
 			return nil, err
 		}
@@ -66,11 +60,7 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 		if _, ok := pluginMaps[key]; !ok {
 			pluginMaps[key] = make([]*config.PluginConfig, 0, 20)
 		}
-		pluginMaps[key] = append(pluginMaps[key], &config.PluginConfig{
-			Name:   pluginName,
-			IsStop: isStop,
-			Config: pluginConfig,
-		})
+		pluginMaps[key] = append(pluginMaps[key], p)
 	}
 	return pluginMaps, nil
 
@@ -79,7 +69,7 @@ func (d *VersionConfigDao) GetAPIPlugins() (map[string][]*config.PluginConfig, e
 //GetStrategyPlugins 获取策略插件
 func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConfig, map[string]map[string]string, error) {
 	db := d.db
-	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1"
+	sql := "SELECT goku_conn_plugin_strategy.strategyID,goku_conn_plugin_strategy.pluginName,goku_conn_plugin_strategy.pluginConfig,goku_plugin.isStop," + pluginPolicyFields + " FROM goku_conn_plugin_strategy INNER JOIN goku_plugin ON goku_conn_plugin_strategy.pluginName = goku_plugin.pluginName WHERE goku_plugin.pluginStatus = 1 AND goku_conn_plugin_strategy.pluginStatus = 1 ORDER BY goku_plugin.pluginPriority DESC"
 	rows, err := db.Query(sql)
 	if err != nil {
 		return nil, nil, err
@@ -88,9 +78,9 @@ func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConf
 	pluginMaps := make(map[string][]*config.PluginConfig)
 	authMaps := make(map[string]map[string]string)
 	for rows.Next() {
-		var isStop bool
-		var pluginName, pluginConfig, strategyID string
-		err = rows.Scan(&strategyID, &pluginName, &pluginConfig, &isStop)
+		var strategyID string
+		p := new(config.PluginConfig)
+		err = rows.Scan(&strategyID, &p.Name, &p.Config, &p.IsStop, &p.Priority, &p.ErrorPolicy, &p.ErrorStatus, &p.ErrorBody)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -98,18 +88,14 @@ func (d *VersionConfigDao) GetStrategyPlugins() (map[string][]*config.PluginConf
 		if _, ok := pluginMaps[key]; !ok {
 			pluginMaps[key] = make([]*config.PluginConfig, 0, 20)
 		}
-		if v, ok := autoAuthNames[pluginName]; ok {
+		if v, ok := autoAuthNames[p.Name]; ok {
 			if _, ok := authMaps[key]; !ok {
 				authMaps[key] = make(map[string]string)
 			}
-			authMaps[key][v] = pluginConfig
+			authMaps[key][v] = p.Config
 		}
 
-		pluginMaps[key] = append(pluginMaps[key], &config.PluginConfig{
-			Name:   pluginName,
-			IsStop: isStop,
-			Config: pluginConfig,
-		})
+		pluginMaps[key] = append(pluginMaps[key], p)
 	}
 	return pluginMaps, authMaps, nil
 
@@ -0,0 +1,22 @@
+package dao_version_config
+
+//GetProtoDescriptors 获取protobuf描述文件，key为名称，value为base64编码的FileDescriptorSet
+func (d *VersionConfigDao) GetProtoDescriptors() (map[string]string, error) {
+	db := d.db
+	sql := "SELECT `name`,`content` FROM goku_proto_descriptor;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	descriptors := make(map[string]string)
+	for rows.Next() {
+		var name, content string
+		err = rows.Scan(&name, &content)
+		if err != nil {
+			return nil, err
+		}
+		descriptors[name] = content
+	}
+	return descriptors, nil
+}
@@ -0,0 +1,24 @@
+package dao_version_config
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//GetRateLimits 获取已启用的限流规则
+func (d *VersionConfigDao) GetRateLimits() ([]*config.RateLimitConfig, error) {
+	db := d.db
+	sql := "SELECT `id`,`strategyID`,`apiID`,`algorithm`,`mode`,`limitKey`,`limitCount`,`period`,`burst`,`dailyQuota`,`monthlyQuota` FROM goku_rate_limit WHERE `enable` = 1;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rateLimits := make([]*config.RateLimitConfig, 0)
+	for rows.Next() {
+		var r config.RateLimitConfig
+		err = rows.Scan(&r.ID, &r.StrategyID, &r.APIID, &r.Algorithm, &r.Mode, &r.Key, &r.Limit, &r.Period, &r.Burst, &r.DailyQuota, &r.MonthlyQuota)
+		if err != nil {
+			return nil, err
+		}
+		rateLimits = append(rateLimits, &r)
+	}
+	return rateLimits, nil
+}
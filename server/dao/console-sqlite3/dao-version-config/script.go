@@ -0,0 +1,24 @@
+package dao_version_config
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//GetScripts 获取脚本，key为脚本名称
+func (d *VersionConfigDao) GetScripts() (map[string]*config.ScriptConfig, error) {
+	db := d.db
+	sql := "SELECT `scriptName`,`version`,`language`,`content`,`timeout`,`maxDataSize` FROM goku_script;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	scripts := make(map[string]*config.ScriptConfig)
+	for rows.Next() {
+		var s config.ScriptConfig
+		err = rows.Scan(&s.Name, &s.Version, &s.Language, &s.Content, &s.Timeout, &s.MaxDataSize)
+		if err != nil {
+			return nil, err
+		}
+		scripts[s.Name] = &s
+	}
+	return scripts, nil
+}
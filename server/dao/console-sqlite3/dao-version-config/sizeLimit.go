@@ -0,0 +1,45 @@
+package dao_version_config
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//GetSizeLimits 获取大小限制规则
+func (d *VersionConfigDao) GetSizeLimits() ([]*config.SizeLimitConfig, error) {
+	db := d.db
+	sql := "SELECT `id`,`strategyID`,`apiID`,`maxRequestBody`,`maxResponseBody`,`maxHeader` FROM goku_size_limit;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sizeLimits := make([]*config.SizeLimitConfig, 0)
+	for rows.Next() {
+		var l config.SizeLimitConfig
+		err = rows.Scan(&l.ID, &l.StrategyID, &l.APIID, &l.MaxRequestBody, &l.MaxResponseBody, &l.MaxHeader)
+		if err != nil {
+			return nil, err
+		}
+		sizeLimits = append(sizeLimits, &l)
+	}
+	return sizeLimits, nil
+}
+
+//GetOpenAPIs 获取OpenAPI文档，key为名称
+func (d *VersionConfigDao) GetOpenAPIs() (map[string]string, error) {
+	db := d.db
+	sql := "SELECT `name`,`content` FROM goku_openapi;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	documents := make(map[string]string)
+	for rows.Next() {
+		var name, content string
+		err = rows.Scan(&name, &content)
+		if err != nil {
+			return nil, err
+		}
+		documents[name] = content
+	}
+	return documents, nil
+}
@@ -11,6 +11,7 @@ var autoAuthNames = map[string]string{
 	"goku-apikey_auth": "Apikey",
 	"goku-basic_auth":  "Basic",
 	"goku-jwt_auth":    "Jwt",
+	"goku-hmac_auth":   "Hmac",
 }
 
 //GetAPIsOfStrategy 获取策略内接口数据
@@ -54,7 +55,7 @@ func (d *VersionConfigDao)GetAPIsOfStrategy() (map[string][]*config.APIOfStrateg
 //GetStrategyConfig 获取策略配置
 func (d *VersionConfigDao)GetStrategyConfig() (string, []*config.StrategyConfig, error) {
 	db := d.db
-	sql := "SELECT strategyID,strategyName,enableStatus,strategyType FROM goku_gateway_strategy"
+	sql := "SELECT strategyID,strategyName,enableStatus,strategyType,authPolicy FROM goku_gateway_strategy"
 
 	rows, err := db.Query(sql)
 	if err != nil {
@@ -74,7 +75,7 @@ func (d *VersionConfigDao)GetStrategyConfig() (string, []*config.StrategyConfig,
 	for rows.Next() {
 		var strategyConfig config.StrategyConfig
 		var strategyType int
-		err = rows.Scan(&strategyConfig.ID, &strategyConfig.Name, &strategyConfig.Enable, &strategyType)
+		err = rows.Scan(&strategyConfig.ID, &strategyConfig.Name, &strategyConfig.Enable, &strategyType, &strategyConfig.AuthPolicy)
 		if err != nil {
 			return "", nil, err
 		}
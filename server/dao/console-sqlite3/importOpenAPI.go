@@ -0,0 +1,97 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strconv"
+	"time"
+
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//GetProjectOperations 获取项目下的接口，用于OpenAPI导入对比及导出
+func (d *ImportDao) GetProjectOperations(projectID int) ([]*entity.OpenAPIOperation, error) {
+	db := d.db
+	sql := "SELECT A.`apiID`,A.`apiName`,A.`groupID`,IFNULL(G.`groupName`,''),A.`requestURL`,A.`requestMethod`,IFNULL(A.`targetURL`,''),IFNULL(A.`targetMethod`,''),IFNULL(A.`balanceName`,''),IFNULL(A.`protocol`,'http'),IFNULL(V.`config`,'') FROM goku_gateway_api A LEFT JOIN goku_gateway_api_group G ON A.`groupID` = G.`groupID` LEFT JOIN goku_api_validation V ON A.`apiID` = V.`apiID` WHERE A.`projectID` = ? ORDER BY A.`apiID` ASC;"
+	rows, err := db.Query(sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.OpenAPIOperation, 0)
+	for rows.Next() {
+		var o entity.OpenAPIOperation
+		err = rows.Scan(&o.APIID, &o.APIName, &o.GroupID, &o.GroupName, &o.RequestURL, &o.RequestMethod, &o.TargetURL, &o.TargetMethod, &o.BalanceName, &o.Protocol, &o.Validation)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &o)
+	}
+	return list, nil
+}
+
+//ImportOpenAPI 导入OpenAPI操作，APIID为0时新增接口，否则更新接口；GroupID为0时按GroupName新建一级分组
+func (d *ImportDao) ImportOpenAPI(projectID, userID int, operations []*entity.OpenAPIOperation) error {
+	db := d.db
+	Tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	now := time.Now().Format("2006-01-02 15:04:05")
+	groups := make(map[string]int)
+	for _, o := range operations {
+		if o.GroupID == 0 && o.GroupName != "" {
+			groupID, has := groups[o.GroupName]
+			if !has {
+				groupID, err = d.addOpenAPIGroup(Tx, projectID, o.GroupName)
+				if err != nil {
+					Tx.Rollback()
+					return err
+				}
+				groups[o.GroupName] = groupID
+			}
+			o.GroupID = groupID
+		}
+		if o.APIID != 0 {
+			_, err = Tx.Exec("UPDATE goku_gateway_api SET groupID = ?,apiName = ?,targetURL = ?,targetMethod = ?,balanceName = ?,protocol = ?,updateTime = ?,lastUpdateUserID = ? WHERE apiID = ? AND projectID = ?;", o.GroupID, o.APIName, o.TargetURL, o.TargetMethod, o.BalanceName, o.Protocol, now, userID, o.APIID, projectID)
+			if err != nil {
+				Tx.Rollback()
+				return err
+			}
+			continue
+		}
+		res, err := Tx.Exec("INSERT INTO goku_gateway_api (projectID,groupID,apiName,requestURL,targetURL,requestMethod,targetMethod,isFollow,stripPrefix,timeout,retryCount,createTime,updateTime,protocol,balanceName,stripSlash,responseDataType,managerID,lastUpdateUserID,createUserID) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", projectID, o.GroupID, o.APIName, o.RequestURL, o.TargetURL, o.RequestMethod, o.TargetMethod, "false", "true", 2000, 0, now, now, o.Protocol, o.BalanceName, "true", "origin", userID, userID, userID)
+		if err != nil {
+			Tx.Rollback()
+			return err
+		}
+		apiID, err := res.LastInsertId()
+		if err != nil {
+			Tx.Rollback()
+			return err
+		}
+		o.APIID = int(apiID)
+	}
+	// 更新项目更新时间
+	_, err = Tx.Exec("UPDATE goku_gateway_project SET updateTime = ? WHERE projectID = ?;", now, projectID)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+func (d *ImportDao) addOpenAPIGroup(Tx *SQL.Tx, projectID int, groupName string) (int, error) {
+	result, err := Tx.Exec("INSERT INTO goku_gateway_api_group (projectID,groupName,groupDepth,parentGroupID) VALUES (?,?,?,?);", projectID, groupName, 1, 0)
+	if err != nil {
+		return 0, err
+	}
+	groupID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	_, err = Tx.Exec("UPDATE goku_gateway_api_group SET groupPath = ? WHERE groupID = ?;", strconv.Itoa(int(groupID)), groupID)
+	if err != nil {
+		return 0, err
+	}
+	return int(groupID), nil
+}
@@ -0,0 +1,14 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuAPICacheSQL = `CREATE TABLE IF NOT EXISTS "goku_api_cache" (
+  "apiID" INTEGER NOT NULL PRIMARY KEY,
+  "config" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+func createGokuAPICache(db *SQL.DB) error {
+	_, err := db.Exec(gokuAPICacheSQL)
+	return err
+}
@@ -0,0 +1,15 @@
+package goku320
+
+import (
+	SQL "database/sql"
+)
+
+// addAPIRewrite 接口新增转发路径及query重写配置
+func addAPIRewrite(db *SQL.DB) error {
+	has, err := hasColumn(db, "goku_gateway_api", "rewrite")
+	if err != nil || has {
+		return err
+	}
+	_, err = db.Exec("ALTER TABLE goku_gateway_api ADD COLUMN rewrite TEXT NOT NULL DEFAULT '';")
+	return err
+}
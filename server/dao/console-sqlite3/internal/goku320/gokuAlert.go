@@ -0,0 +1,68 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuConsoleLeaseSQL = `CREATE TABLE IF NOT EXISTS "goku_console_lease" (
+  "name" TEXT NOT NULL PRIMARY KEY,
+  "consoleID" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+var gokuAlertSQL = []string{`CREATE TABLE IF NOT EXISTS "goku_alert_rule" (
+  "ruleID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "name" TEXT NOT NULL,
+  "metric" TEXT NOT NULL,
+  "cluster" TEXT NOT NULL DEFAULT '',
+  "strategyID" TEXT NOT NULL DEFAULT '',
+  "apiID" INTEGER NOT NULL DEFAULT 0,
+  "threshold" REAL NOT NULL DEFAULT 0,
+  "duration" INTEGER NOT NULL DEFAULT 60,
+  "minRequests" INTEGER NOT NULL DEFAULT 0,
+  "channels" TEXT NOT NULL DEFAULT '',
+  "enable" INTEGER NOT NULL DEFAULT 1,
+  "updateTime" TEXT NOT NULL
+);`, `CREATE TABLE IF NOT EXISTS "goku_alert_channel" (
+  "channelID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "name" TEXT NOT NULL,
+  "type" TEXT NOT NULL,
+  "config" TEXT NOT NULL DEFAULT '',
+  "isDefault" INTEGER NOT NULL DEFAULT 0,
+  "enable" INTEGER NOT NULL DEFAULT 1,
+  "updateTime" TEXT NOT NULL
+);`, `CREATE TABLE IF NOT EXISTS "goku_alert_silence" (
+  "silenceID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleID" INTEGER NOT NULL DEFAULT 0,
+  "target" TEXT NOT NULL DEFAULT '',
+  "startTime" INTEGER NOT NULL,
+  "endTime" INTEGER NOT NULL,
+  "comment" TEXT NOT NULL DEFAULT '',
+  "createUserID" INTEGER NOT NULL DEFAULT 0,
+  "createTime" TEXT NOT NULL
+);`, `CREATE TABLE IF NOT EXISTS "goku_alert_history" (
+  "alertID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "ruleID" INTEGER NOT NULL DEFAULT 0,
+  "ruleName" TEXT NOT NULL DEFAULT '',
+  "metric" TEXT NOT NULL,
+  "target" TEXT NOT NULL,
+  "status" TEXT NOT NULL,
+  "value" REAL NOT NULL DEFAULT 0,
+  "threshold" REAL NOT NULL DEFAULT 0,
+  "message" TEXT NOT NULL DEFAULT '',
+  "silenced" INTEGER NOT NULL DEFAULT 0,
+  "fireTime" INTEGER NOT NULL,
+  "resolveTime" INTEGER NOT NULL DEFAULT 0
+);`, `CREATE INDEX IF NOT EXISTS "alertHistoryStatus" ON "goku_alert_history" ("status");`}
+
+func createGokuConsoleLease(db *SQL.DB) error {
+	_, err := db.Exec(gokuConsoleLeaseSQL)
+	return err
+}
+
+func createGokuAlert(db *SQL.DB) error {
+	for _, sql := range gokuAlertSQL {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
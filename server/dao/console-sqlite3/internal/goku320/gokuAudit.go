@@ -0,0 +1,28 @@
+package goku320
+
+import SQL "database/sql"
+
+var gokuAuditLogSQL = []string{`CREATE TABLE IF NOT EXISTS "goku_audit_log" (
+  "logID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "userID" INTEGER NOT NULL DEFAULT 0,
+  "operation" TEXT NOT NULL,
+  "entityType" TEXT NOT NULL DEFAULT '',
+  "entityID" TEXT NOT NULL DEFAULT '',
+  "params" TEXT NOT NULL DEFAULT '',
+  "before" TEXT NOT NULL DEFAULT '',
+  "after" TEXT NOT NULL DEFAULT '',
+  "diff" TEXT NOT NULL DEFAULT '',
+  "statusCode" TEXT NOT NULL DEFAULT '',
+  "versionID" INTEGER NOT NULL DEFAULT 0,
+  "createTime" TEXT NOT NULL
+);`, `CREATE INDEX IF NOT EXISTS "auditLogCreateTime" ON "goku_audit_log" ("createTime");`,
+	`CREATE INDEX IF NOT EXISTS "auditLogEntity" ON "goku_audit_log" ("entityType", "entityID");`}
+
+func createGokuAuditLog(db *SQL.DB) error {
+	for _, sql := range gokuAuditLogSQL {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package goku320
+
+import SQL "database/sql"
+
+var gokuConfigAccessOutputSQL = []string{`CREATE TABLE IF NOT EXISTS "goku_config_access_output" (
+  "cluster" TEXT NOT NULL PRIMARY KEY,
+  "outputs" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`}
+
+func createGokuConfigAccessOutput(db *SQL.DB) error {
+	for _, sql := range gokuConfigAccessOutputSQL {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package goku320
+
+import (
+	SQL "database/sql"
+	"strings"
+)
+
+// nativeAuthPlugins 网关内置实现的鉴权方式，无需插件文件即可使用
+var nativeAuthPlugins = []struct {
+	name        string
+	chineseName string
+	desc        string
+}{
+	{"goku-apikey_auth", "Apikey鉴权", "网关内置的Apikey鉴权"},
+	{"goku-basic_auth", "Basic鉴权", "网关内置的Basic鉴权"},
+	{"goku-jwt_auth", "Jwt鉴权", "网关内置的Jwt鉴权，支持JWKS"},
+	{"goku-hmac_auth", "Hmac鉴权", "网关内置的HTTP签名鉴权"},
+}
+
+// addStrategyAuthPolicy 策略新增多个鉴权方式的校验策略，并登记内置的鉴权插件
+func addStrategyAuthPolicy(db *SQL.DB) error {
+	has, err := hasColumn(db, "goku_gateway_strategy", "authPolicy")
+	if err != nil {
+		return err
+	}
+	if !has {
+		_, err = db.Exec("ALTER TABLE goku_gateway_strategy ADD COLUMN authPolicy TEXT NOT NULL DEFAULT 'any';")
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, p := range nativeAuthPlugins {
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM goku_plugin WHERE pluginName = ?;", p.name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = db.Exec("INSERT INTO goku_plugin (pluginName,chineseName,pluginStatus,pluginPriority,pluginConfig,isStop,pluginType,official,pluginDesc,version,isCheck) VALUES (?,?,1,(SELECT IFNULL(MAX(pluginPriority),0)+1 FROM goku_plugin),'',1,1,'true',?,'1.0',1);", p.name, p.chineseName, p.desc)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasColumn(db *SQL.DB, table, column string) (bool, error) {
+	rows, err := db.Query("PRAGMA table_info(" + table + ");")
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return false, err
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		name := ""
+		for i, c := range columns {
+			if strings.EqualFold(c, "name") {
+				values[i] = &name
+				continue
+			}
+			values[i] = new(interface{})
+		}
+		if err := rows.Scan(values...); err != nil {
+			return false, err
+		}
+		if strings.EqualFold(name, column) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,18 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuOpenAPISQL = `CREATE TABLE IF NOT EXISTS "goku_openapi" (
+  "name" TEXT NOT NULL PRIMARY KEY,
+  "remark" TEXT NOT NULL DEFAULT '',
+  "title" TEXT NOT NULL DEFAULT '',
+  "version" TEXT NOT NULL DEFAULT '',
+  "content" TEXT NOT NULL,
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+func createGokuOpenAPI(db *SQL.DB) error {
+	_, err := db.Exec(gokuOpenAPISQL)
+	return err
+}
@@ -0,0 +1,29 @@
+package goku320
+
+import SQL "database/sql"
+
+// addPluginErrorPolicy 插件新增错误处理策略
+func addPluginErrorPolicy(db *SQL.DB) error {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"errorPolicy", "TEXT NOT NULL DEFAULT ''"},
+		{"errorStatus", "INTEGER NOT NULL DEFAULT 0"},
+		{"errorBody", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, c := range columns {
+		has, err := hasColumn(db, "goku_plugin", c.name)
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+		_, err = db.Exec("ALTER TABLE goku_plugin ADD COLUMN " + c.name + " " + c.definition + ";")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuProtoDescriptorSQL = `CREATE TABLE IF NOT EXISTS "goku_proto_descriptor" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "name" TEXT NOT NULL,
+  "remark" TEXT NOT NULL DEFAULT '',
+  "content" TEXT NOT NULL,
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "protoDescriptorName"
+ON "goku_proto_descriptor" (
+  "name" ASC
+);`
+
+func createGokuProtoDescriptor(db *SQL.DB) error {
+	_, err := db.Exec(gokuProtoDescriptorSQL)
+	if err != nil {
+		return err
+	}
+	return nil
+}
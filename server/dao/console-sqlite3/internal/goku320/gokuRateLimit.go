@@ -0,0 +1,34 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuRateLimitSQL = `CREATE TABLE IF NOT EXISTS "goku_rate_limit" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "strategyID" TEXT NOT NULL DEFAULT '',
+  "apiID" INTEGER NOT NULL DEFAULT 0,
+  "algorithm" TEXT NOT NULL DEFAULT 'token-bucket',
+  "mode" TEXT NOT NULL DEFAULT 'local',
+  "limitKey" TEXT NOT NULL DEFAULT 'ip',
+  "limitCount" INTEGER NOT NULL DEFAULT 0,
+  "period" INTEGER NOT NULL DEFAULT 1,
+  "burst" INTEGER NOT NULL DEFAULT 0,
+  "dailyQuota" INTEGER NOT NULL DEFAULT 0,
+  "monthlyQuota" INTEGER NOT NULL DEFAULT 0,
+  "enable" INTEGER NOT NULL DEFAULT 1,
+  "remark" TEXT NOT NULL DEFAULT '',
+  "updateTime" TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "rateLimitTarget"
+ON "goku_rate_limit" (
+  "strategyID" ASC,
+  "apiID" ASC
+);`
+
+func createGokuRateLimit(db *SQL.DB) error {
+	_, err := db.Exec(gokuRateLimitSQL)
+	if err != nil {
+		return err
+	}
+	return nil
+}
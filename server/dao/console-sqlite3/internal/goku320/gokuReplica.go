@@ -0,0 +1,27 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuNodeSessionSQL = `CREATE TABLE IF NOT EXISTS "goku_node_session" (
+  "nodeKey" TEXT NOT NULL PRIMARY KEY,
+  "consoleID" TEXT NOT NULL,
+  "pluginErrors" TEXT NOT NULL DEFAULT '',
+  "updateTime" TEXT NOT NULL
+);`
+
+const gokuConsoleEventSQL = `CREATE TABLE IF NOT EXISTS "goku_console_event" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "consoleID" TEXT NOT NULL,
+  "eventType" TEXT NOT NULL,
+  "data" TEXT NOT NULL DEFAULT '',
+  "createTime" TEXT NOT NULL
+);`
+
+func createGokuReplica(db *SQL.DB) error {
+	for _, sql := range []string{gokuNodeSessionSQL, gokuConsoleEventSQL} {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
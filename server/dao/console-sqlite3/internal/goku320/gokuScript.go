@@ -0,0 +1,68 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuScriptSQL = `CREATE TABLE IF NOT EXISTS "goku_script" (
+  "scriptID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "scriptName" TEXT NOT NULL,
+  "scriptDesc" TEXT NOT NULL DEFAULT '',
+  "language" TEXT NOT NULL DEFAULT 'javascript',
+  "content" TEXT NOT NULL,
+  "version" INTEGER NOT NULL DEFAULT 1,
+  "timeout" INTEGER NOT NULL DEFAULT 0,
+  "maxDataSize" INTEGER NOT NULL DEFAULT 0,
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "scriptName"
+ON "goku_script" (
+  "scriptName" ASC
+);
+
+CREATE TABLE IF NOT EXISTS "goku_script_history" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "scriptName" TEXT NOT NULL,
+  "version" INTEGER NOT NULL,
+  "content" TEXT NOT NULL,
+  "createTime" TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "scriptHistoryName"
+ON "goku_script_history" (
+  "scriptName" ASC
+);`
+
+// scriptPlugins 脚本插件，分别用于全局、策略及接口，插件配置为 {"scripts":["脚本名称"]}
+var scriptPlugins = []struct {
+	name        string
+	chineseName string
+	pluginType  int
+	status      int
+}{
+	{"goku-global_script", "全局脚本", 0, 0},
+	{"goku-script", "策略脚本", 1, 1},
+	{"goku-api_script", "接口脚本", 2, 1},
+}
+
+func createGokuScript(db *SQL.DB) error {
+	_, err := db.Exec(gokuScriptSQL)
+	if err != nil {
+		return err
+	}
+	for _, p := range scriptPlugins {
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM goku_plugin WHERE pluginName = ?;", p.name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = db.Exec("INSERT INTO goku_plugin (pluginName,chineseName,pluginStatus,pluginPriority,pluginConfig,isStop,pluginType,official,pluginDesc,version,isCheck) VALUES (?,?,?,(SELECT IFNULL(MAX(pluginPriority),0)+1 FROM goku_plugin),'{\"scripts\":[]}',1,?,'true','网关内置的JavaScript脚本插件','1.0',1);", p.name, p.chineseName, p.status, p.pluginType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
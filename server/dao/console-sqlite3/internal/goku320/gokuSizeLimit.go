@@ -0,0 +1,30 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuSizeLimitSQL = `CREATE TABLE IF NOT EXISTS "goku_size_limit" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "strategyID" TEXT NOT NULL DEFAULT '',
+  "apiID" INTEGER NOT NULL DEFAULT 0,
+  "maxRequestBody" INTEGER NOT NULL DEFAULT 0,
+  "maxResponseBody" INTEGER NOT NULL DEFAULT 0,
+  "maxHeader" INTEGER NOT NULL DEFAULT 0,
+  "remark" TEXT NOT NULL DEFAULT '',
+  "updateTime" TEXT NOT NULL
+);`
+
+const gokuAPIValidationSQL = `CREATE TABLE IF NOT EXISTS "goku_api_validation" (
+  "apiID" INTEGER NOT NULL PRIMARY KEY,
+  "config" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`
+
+func createGokuSizeLimit(db *SQL.DB) error {
+	_, err := db.Exec(gokuSizeLimitSQL)
+	return err
+}
+
+func createGokuAPIValidation(db *SQL.DB) error {
+	_, err := db.Exec(gokuAPIValidationSQL)
+	return err
+}
@@ -0,0 +1,29 @@
+package goku320
+
+import SQL "database/sql"
+
+const gokuTrafficStatSQL = `CREATE TABLE IF NOT EXISTS "goku_traffic_stat" (
+  "id" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "cluster" TEXT NOT NULL DEFAULT '',
+  "nodeKey" TEXT NOT NULL DEFAULT '',
+  "reportTime" INTEGER NOT NULL,
+  "period" INTEGER NOT NULL DEFAULT 0,
+  "strategyID" TEXT NOT NULL DEFAULT '',
+  "apiID" INTEGER NOT NULL DEFAULT 0,
+  "requests" INTEGER NOT NULL DEFAULT 0,
+  "status2xx" INTEGER NOT NULL DEFAULT 0,
+  "status3xx" INTEGER NOT NULL DEFAULT 0,
+  "status4xx" INTEGER NOT NULL DEFAULT 0,
+  "status5xx" INTEGER NOT NULL DEFAULT 0,
+  "latencySum" REAL NOT NULL DEFAULT 0,
+  "latencyMax" REAL NOT NULL DEFAULT 0,
+  "latencyBuckets" TEXT NOT NULL DEFAULT '',
+  "upstreamRequests" INTEGER NOT NULL DEFAULT 0,
+  "upstreamErrors" INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS "trafficReportTime" ON "goku_traffic_stat" ("reportTime");`
+
+func createGokuTrafficStat(db *SQL.DB) error {
+	_, err := db.Exec(gokuTrafficStatSQL)
+	return err
+}
@@ -0,0 +1,30 @@
+package goku320
+
+import SQL "database/sql"
+
+var gokuVersionCanarySQL = []string{`CREATE TABLE IF NOT EXISTS "goku_version_canary" (
+  "canaryID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "versionID" INTEGER NOT NULL,
+  "baseVersionID" INTEGER NOT NULL DEFAULT 0,
+  "groupID" INTEGER NOT NULL,
+  "status" TEXT NOT NULL,
+  "autoPromote" INTEGER NOT NULL DEFAULT 0,
+  "observeTime" INTEGER NOT NULL DEFAULT 0,
+  "errorThreshold" REAL NOT NULL DEFAULT 0,
+  "minRequests" INTEGER NOT NULL DEFAULT 0,
+  "userID" INTEGER NOT NULL DEFAULT 0,
+  "reason" TEXT NOT NULL DEFAULT '',
+  "startTime" INTEGER NOT NULL DEFAULT 0,
+  "endTime" INTEGER NOT NULL DEFAULT 0,
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`, `CREATE INDEX IF NOT EXISTS "versionCanaryStatus" ON "goku_version_canary" ("status");`}
+
+func createGokuVersionCanary(db *SQL.DB) error {
+	for _, sql := range gokuVersionCanarySQL {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package goku320
+
+import SQL "database/sql"
+
+var gokuVersionPublishSQL = []string{`CREATE TABLE IF NOT EXISTS "goku_version_publish" (
+  "requestID" INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  "versionID" INTEGER NOT NULL,
+  "status" TEXT NOT NULL,
+  "isRollback" INTEGER NOT NULL DEFAULT 0,
+  "requestUserID" INTEGER NOT NULL DEFAULT 0,
+  "approveUserID" INTEGER NOT NULL DEFAULT 0,
+  "publishTime" INTEGER NOT NULL DEFAULT 0,
+  "remark" TEXT NOT NULL DEFAULT '',
+  "createTime" TEXT NOT NULL,
+  "updateTime" TEXT NOT NULL
+);`, `CREATE INDEX IF NOT EXISTS "versionPublishStatus" ON "goku_version_publish" ("status");`,
+	`CREATE TABLE IF NOT EXISTS "goku_node_config" (
+  "nodeKey" TEXT NOT NULL PRIMARY KEY,
+  "versionID" INTEGER NOT NULL DEFAULT 0,
+  "updateTime" TEXT NOT NULL
+);`}
+
+func createGokuVersionPublish(db *SQL.DB) error {
+	for _, sql := range gokuVersionPublishSQL {
+		if _, err := db.Exec(sql); err != nil {
+			return err
+		}
+	}
+	return nil
+}
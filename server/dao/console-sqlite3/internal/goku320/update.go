@@ -0,0 +1,188 @@
+package goku320
+
+import (
+	"database/sql"
+
+	"github.com/eolinker/goku-api-gateway/common/pdao"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
+)
+
+//Version 版本号
+const Version = "3.2.0"
+
+//DBDriver dbDriver
+const DBDriver = "sqlite3"
+
+//RegisterUpdate RegisterUpdate
+func RegisterUpdate() {
+	pdao.RegisterDBBuilder(DBDriver, new(factory))
+}
+
+type factory struct {
+}
+
+func (f *factory) Build(db *sql.DB) error {
+	return Exec(db)
+}
+
+//Exec 执行3.2.0新增的表
+func Exec(db *sql.DB) error {
+
+	updaterDao := updater.NewUpdaterDaoWidthDB(db)
+
+	if version := updaterDao.GetTableVersion("goku_proto_descriptor"); version != Version {
+		err := createGokuProtoDescriptor(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_proto_descriptor", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_rate_limit"); version != Version {
+		err := createGokuRateLimit(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_rate_limit", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_gateway_strategy"); version != Version {
+		err := addStrategyAuthPolicy(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_gateway_strategy", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_conn_plugin_strategy"); version != Version {
+		err := updaterDao.SealAuthCredentials()
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_conn_plugin_strategy", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_plugin"); version != Version {
+		err := addPluginErrorPolicy(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_plugin", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_script"); version != Version {
+		err := createGokuScript(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_script", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_api_cache"); version != Version {
+		err := createGokuAPICache(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_api_cache", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_size_limit"); version != Version {
+		err := createGokuSizeLimit(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_size_limit", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_api_validation"); version != Version {
+		err := createGokuAPIValidation(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_api_validation", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_openapi"); version != Version {
+		err := createGokuOpenAPI(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_openapi", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_node_session"); version != Version {
+		err := createGokuReplica(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_node_session", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_traffic_stat"); version != Version {
+		err := createGokuTrafficStat(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_traffic_stat", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_console_lease"); version != Version {
+		err := createGokuConsoleLease(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_console_lease", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_alert_rule"); version != Version {
+		err := createGokuAlert(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_alert_rule", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_audit_log"); version != Version {
+		err := createGokuAuditLog(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_audit_log", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_version_publish"); version != Version {
+		err := createGokuVersionPublish(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_version_publish", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_version_canary"); version != Version {
+		err := createGokuVersionCanary(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_version_canary", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_config_access_output"); version != Version {
+		err := createGokuConfigAccessOutput(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_config_access_output", Version)
+	}
+
+	if version := updaterDao.GetTableVersion("goku_gateway_api"); version != Version {
+		err := addAPIRewrite(db)
+		if err != nil {
+			return err
+		}
+		updaterDao.UpdateTableVersion("goku_gateway_api", Version)
+	}
+
+	updaterDao.SetGokuVersion(Version)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//OpenAPIDao OpenAPIDao
+type OpenAPIDao struct {
+	db *SQL.DB
+}
+
+//NewOpenAPIDao new OpenAPIDao
+func NewOpenAPIDao() *OpenAPIDao {
+	return &OpenAPIDao{}
+}
+
+//Create create
+func (d *OpenAPIDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.OpenAPIDao = d
+	return &i, nil
+}
+
+//GetOpenAPIList 获取OpenAPI文档列表
+func (d *OpenAPIDao) GetOpenAPIList() ([]*entity.OpenAPI, error) {
+	db := d.db
+	sql := "SELECT `name`,`remark`,`title`,`version`,`content`,`createTime`,`updateTime` FROM goku_openapi ORDER BY `updateTime` DESC;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.OpenAPI, 0)
+	for rows.Next() {
+		var o entity.OpenAPI
+		err = rows.Scan(&o.Name, &o.Remark, &o.Title, &o.Version, &o.Content, &o.CreateTime, &o.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &o)
+	}
+	return list, nil
+}
+
+//GetOpenAPI 获取OpenAPI文档
+func (d *OpenAPIDao) GetOpenAPI(name string) (*entity.OpenAPI, error) {
+	db := d.db
+	sql := "SELECT `name`,`remark`,`title`,`version`,`content`,`createTime`,`updateTime` FROM goku_openapi WHERE `name` = ?;"
+	var o entity.OpenAPI
+	err := db.QueryRow(sql, name).Scan(&o.Name, &o.Remark, &o.Title, &o.Version, &o.Content, &o.CreateTime, &o.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+//SaveOpenAPI 新增或更新OpenAPI文档
+func (d *OpenAPIDao) SaveOpenAPI(o *entity.OpenAPI) error {
+	db := d.db
+	updated, err := execAffected(db, "UPDATE goku_openapi SET `remark` = ?,`title` = ?,`version` = ?,`content` = ?,`updateTime` = ? WHERE `name` = ?;", o.Remark, o.Title, o.Version, o.Content, o.UpdateTime, o.Name)
+	if err != nil || updated {
+		return err
+	}
+	sql := "INSERT INTO goku_openapi (`name`,`remark`,`title`,`version`,`content`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?);"
+	_, err = db.Exec(sql, o.Name, o.Remark, o.Title, o.Version, o.Content, o.CreateTime, o.UpdateTime)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//BatchDeleteOpenAPI 批量删除OpenAPI文档
+func (d *OpenAPIDao) BatchDeleteOpenAPI(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	db := d.db
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		args = append(args, name)
+	}
+	sql := "DELETE FROM goku_openapi WHERE `name` IN (" + strings.TrimSuffix(strings.Repeat("?,", len(names)), ",") + ");"
+	_, err := db.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
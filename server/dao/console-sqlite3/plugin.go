@@ -33,9 +33,9 @@ func (d *PluginDao) Create(db *SQL.DB) (interface{}, error) {
 //GetPluginInfo 获取插件配置信息
 func (d *PluginDao) GetPluginInfo(pluginName string) (bool, *entity.Plugin, error) {
 	db := d.db
-	sql := `SELECT pluginID,pluginName,pluginStatus,IFNULL(pluginConfig,""),pluginPriority,isStop,IFNULL(pluginDesc,""),IFNULL(version,""),pluginType FROM goku_plugin WHERE pluginName = ?;`
+	sql := `SELECT pluginID,pluginName,pluginStatus,IFNULL(pluginConfig,""),pluginPriority,isStop,IFNULL(pluginDesc,""),IFNULL(version,""),pluginType,errorPolicy,errorStatus,errorBody FROM goku_plugin WHERE pluginName = ?;`
 	plugin := &entity.Plugin{}
-	err := db.QueryRow(sql, pluginName).Scan(&plugin.PluginID, &plugin.PluginName, &plugin.PluginStatus, &plugin.PluginConfig, &plugin.PluginIndex, &plugin.IsStop, &plugin.PluginDesc, &plugin.Version, &plugin.PluginType)
+	err := db.QueryRow(sql, pluginName).Scan(&plugin.PluginID, &plugin.PluginName, &plugin.PluginStatus, &plugin.PluginConfig, &plugin.PluginIndex, &plugin.IsStop, &plugin.PluginDesc, &plugin.Version, &plugin.PluginType, &plugin.ErrorPolicy, &plugin.ErrorStatus, &plugin.ErrorBody)
 	if err != nil {
 		return false, &entity.Plugin{}, err
 	}
@@ -315,3 +315,14 @@ func (d *PluginDao) EditPluginCheckStatus(pluginName string, isCheck int) (bool,
 	}
 	return true, "", nil
 }
+
+//EditPluginErrorPolicy 修改插件错误处理策略
+func (d *PluginDao) EditPluginErrorPolicy(pluginName, errorPolicy string, errorStatus int, errorBody string) error {
+	db := d.db
+	sql := "UPDATE goku_plugin SET errorPolicy = ?,errorStatus = ?,errorBody = ? WHERE pluginName = ?;"
+	_, err := db.Exec(sql, errorPolicy, errorStatus, errorBody, pluginName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//ProtoDescriptorDao ProtoDescriptorDao
+type ProtoDescriptorDao struct {
+	db *SQL.DB
+}
+
+//NewProtoDescriptorDao new ProtoDescriptorDao
+func NewProtoDescriptorDao() *ProtoDescriptorDao {
+	return &ProtoDescriptorDao{}
+}
+
+//Create create
+func (d *ProtoDescriptorDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ProtoDescriptorDao = d
+	return &i, nil
+}
+
+//GetProtoDescriptorList 获取protobuf描述文件列表
+func (d *ProtoDescriptorDao) GetProtoDescriptorList() ([]*entity.ProtoDescriptor, error) {
+	db := d.db
+	sql := "SELECT `name`,`remark`,`content`,`createTime`,`updateTime` FROM goku_proto_descriptor ORDER BY `updateTime` DESC;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.ProtoDescriptor, 0)
+	for rows.Next() {
+		var p entity.ProtoDescriptor
+		err = rows.Scan(&p.Name, &p.Remark, &p.Content, &p.CreateTime, &p.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &p)
+	}
+	return list, nil
+}
+
+//GetProtoDescriptor 获取protobuf描述文件
+func (d *ProtoDescriptorDao) GetProtoDescriptor(name string) (*entity.ProtoDescriptor, error) {
+	db := d.db
+	sql := "SELECT `name`,`remark`,`content`,`createTime`,`updateTime` FROM goku_proto_descriptor WHERE `name` = ?;"
+	var p entity.ProtoDescriptor
+	err := db.QueryRow(sql, name).Scan(&p.Name, &p.Remark, &p.Content, &p.CreateTime, &p.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//SaveProtoDescriptor 新增或更新protobuf描述文件
+func (d *ProtoDescriptorDao) SaveProtoDescriptor(name, remark, content, now string) error {
+	db := d.db
+	updated, err := execAffected(db, "UPDATE goku_proto_descriptor SET `remark` = ?,`content` = ?,`updateTime` = ? WHERE `name` = ?;", remark, content, now, name)
+	if err != nil || updated {
+		return err
+	}
+	sql := "INSERT INTO goku_proto_descriptor (`name`,`remark`,`content`,`createTime`,`updateTime`) VALUES (?,?,?,?,?);"
+	_, err = db.Exec(sql, name, remark, content, now, now)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//BatchDeleteProtoDescriptor 批量删除protobuf描述文件
+func (d *ProtoDescriptorDao) BatchDeleteProtoDescriptor(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	db := d.db
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		args = append(args, name)
+	}
+	sql := "DELETE FROM goku_proto_descriptor WHERE `name` IN (" + strings.TrimSuffix(strings.Repeat("?,", len(names)), ",") + ");"
+	_, err := db.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
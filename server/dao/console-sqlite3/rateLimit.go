@@ -0,0 +1,128 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const rateLimitFields = "A.`id`,A.`strategyID`,IFNULL(S.`strategyName`,''),A.`apiID`,IFNULL(G.`apiName`,''),A.`algorithm`,A.`mode`,A.`limitKey`,A.`limitCount`,A.`period`,A.`burst`,A.`dailyQuota`,A.`monthlyQuota`,A.`enable`,A.`remark`,A.`updateTime`"
+
+const rateLimitJoin = " FROM goku_rate_limit A LEFT JOIN goku_gateway_strategy S ON A.`strategyID` = S.`strategyID` LEFT JOIN goku_gateway_api G ON A.`apiID` = G.`apiID`"
+
+//RateLimitDao RateLimitDao
+type RateLimitDao struct {
+	db *SQL.DB
+}
+
+//NewRateLimitDao new RateLimitDao
+func NewRateLimitDao() *RateLimitDao {
+	return &RateLimitDao{}
+}
+
+//Create create
+func (d *RateLimitDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.RateLimitDao = d
+	return &i, nil
+}
+
+//AddRateLimit 新增限流规则
+func (d *RateLimitDao) AddRateLimit(r *entity.RateLimit) (int, error) {
+	db := d.db
+	sql := "INSERT INTO goku_rate_limit (`strategyID`,`apiID`,`algorithm`,`mode`,`limitKey`,`limitCount`,`period`,`burst`,`dailyQuota`,`monthlyQuota`,`enable`,`remark`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	res, err := db.Exec(sql, r.StrategyID, r.APIID, r.Algorithm, r.Mode, r.Key, r.Limit, r.Period, r.Burst, r.DailyQuota, r.MonthlyQuota, r.Enable, r.Remark, r.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+//EditRateLimit 编辑限流规则
+func (d *RateLimitDao) EditRateLimit(r *entity.RateLimit) error {
+	db := d.db
+	sql := "UPDATE goku_rate_limit SET `strategyID` = ?,`apiID` = ?,`algorithm` = ?,`mode` = ?,`limitKey` = ?,`limitCount` = ?,`period` = ?,`burst` = ?,`dailyQuota` = ?,`monthlyQuota` = ?,`enable` = ?,`remark` = ?,`updateTime` = ? WHERE `id` = ?;"
+	_, err := db.Exec(sql, r.StrategyID, r.APIID, r.Algorithm, r.Mode, r.Key, r.Limit, r.Period, r.Burst, r.DailyQuota, r.MonthlyQuota, r.Enable, r.Remark, r.UpdateTime, r.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//GetRateLimit 获取限流规则
+func (d *RateLimitDao) GetRateLimit(id int) (*entity.RateLimit, error) {
+	db := d.db
+	sql := "SELECT " + rateLimitFields + rateLimitJoin + " WHERE A.`id` = ?;"
+	return scanRateLimit(db.QueryRow(sql, id))
+}
+
+//GetRateLimitList 获取限流规则列表，strategyID为空或apiID为0时不作为筛选条件
+func (d *RateLimitDao) GetRateLimitList(strategyID string, apiID int) ([]*entity.RateLimit, error) {
+	db := d.db
+	rule := make([]string, 0, 2)
+	args := make([]interface{}, 0, 2)
+	if strategyID != "" {
+		rule = append(rule, "A.`strategyID` = ?")
+		args = append(args, strategyID)
+	}
+	if apiID != 0 {
+		rule = append(rule, "A.`apiID` = ?")
+		args = append(args, apiID)
+	}
+	sql := "SELECT " + rateLimitFields + rateLimitJoin
+	if len(rule) > 0 {
+		sql += " WHERE " + strings.Join(rule, " AND ")
+	}
+	sql += " ORDER BY A.`updateTime` DESC;"
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.RateLimit, 0)
+	for rows.Next() {
+		r, err := scanRateLimit(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	return list, nil
+}
+
+//BatchDeleteRateLimit 批量删除限流规则
+func (d *RateLimitDao) BatchDeleteRateLimit(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := d.db
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	sql := "DELETE FROM goku_rate_limit WHERE `id` IN (" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ");"
+	_, err := db.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRateLimit(row rowScanner) (*entity.RateLimit, error) {
+	var r entity.RateLimit
+	err := row.Scan(&r.ID, &r.StrategyID, &r.StrategyName, &r.APIID, &r.APIName, &r.Algorithm, &r.Mode, &r.Key, &r.Limit, &r.Period, &r.Burst, &r.DailyQuota, &r.MonthlyQuota, &r.Enable, &r.Remark, &r.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -13,6 +13,7 @@ import (
 	dao_service "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-service"
 	dao_version_config "github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/dao-version-config"
 	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/internal/goku311"
+	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/internal/goku320"
 	"github.com/eolinker/goku-api-gateway/server/dao/console-sqlite3/updater"
 )
 
@@ -24,27 +25,47 @@ func DoRegister() {
 
 	pdao.RegisterDBBuilder(DBDriver, new(TableBuilder))
 	goku311.RegisterUpdate()
+	goku320.RegisterUpdate()
 
-	pdao.RegisterDao(DBDriver, NewAPIDao(), NewAPIGroupDao(), NewAPIPluginDao(), NewAPIStrategyDao())
-	pdao.RegisterDao(DBDriver, NewAuthDao())
-	pdao.RegisterDao(DBDriver, NewClusterDao())
-	pdao.RegisterDao(DBDriver, NewGatewayDao())
-	pdao.RegisterDao(DBDriver, NewGuestDao())
-	pdao.RegisterDao(DBDriver, NewImportDao())
-	pdao.RegisterDao(DBDriver, NewMonitorModulesDao())
-	pdao.RegisterDao(DBDriver, NewNodeDao(), NewNodeGroupDao())
-	pdao.RegisterDao(DBDriver, NewPluginDao())
-	pdao.RegisterDao(DBDriver, NewProjectDao())
-	pdao.RegisterDao(DBDriver, NewStrategyDao(), NewStrategyGroupDao(), NewStrategyPluginDao())
-	pdao.RegisterDao(DBDriver, NewUserDao())
-	pdao.RegisterDao(DBDriver, NewVersionDao())
+	RegisterDaos(DBDriver)
+}
+
+//RegisterDaos 注册dao实现，其中的SQL同时兼容sqlite3及mysql，供其他驱动复用
+func RegisterDaos(driver string) {
+	pdao.RegisterDao(driver, NewAPIDao(), NewAPIGroupDao(), NewAPIPluginDao(), NewAPIStrategyDao())
+	pdao.RegisterDao(driver, NewAlertDao())
+	pdao.RegisterDao(driver, NewAuditDao())
+	pdao.RegisterDao(driver, NewAuthDao())
+	pdao.RegisterDao(driver, NewClusterDao())
+	pdao.RegisterDao(driver, NewGatewayDao())
+	pdao.RegisterDao(driver, NewGuestDao())
+	pdao.RegisterDao(driver, NewImportDao())
+	pdao.RegisterDao(driver, NewMonitorModulesDao())
+	pdao.RegisterDao(driver, NewNodeDao(), NewNodeGroupDao())
+	pdao.RegisterDao(driver, NewPluginDao())
+	pdao.RegisterDao(driver, NewProjectDao())
+	pdao.RegisterDao(driver, NewProtoDescriptorDao())
+	pdao.RegisterDao(driver, NewReplicaDao())
+	pdao.RegisterDao(driver, NewTrafficDao())
+	pdao.RegisterDao(driver, NewScriptDao())
+	pdao.RegisterDao(driver, NewRateLimitDao())
+	pdao.RegisterDao(driver, NewResponseCacheDao())
+	pdao.RegisterDao(driver, NewSizeLimitDao())
+	pdao.RegisterDao(driver, NewValidationDao())
+	pdao.RegisterDao(driver, NewOpenAPIDao())
+	pdao.RegisterDao(driver, NewBundleDao())
+	pdao.RegisterDao(driver, NewStrategyDao(), NewStrategyGroupDao(), NewStrategyPluginDao())
+	pdao.RegisterDao(driver, NewUserDao())
+	pdao.RegisterDao(driver, NewVersionDao())
+	pdao.RegisterDao(driver, NewVersionCanaryDao())
+	pdao.RegisterDao(driver, NewVersionPublishDao())
 
-	pdao.RegisterDao(DBDriver, config_log.NewConfigLogDao())
-	pdao.RegisterDao(DBDriver, dao_balance.NewBalanceDao())
-	pdao.RegisterDao(DBDriver, dao_balance_update.NewBalanceUpdateDao())
-	pdao.RegisterDao(DBDriver, dao_service.NewServiceDao())
-	pdao.RegisterDao(DBDriver, dao_version_config.NewVersionConfigDao())
-	pdao.RegisterDao(DBDriver, updater.NewUpdaterDao())
+	pdao.RegisterDao(driver, config_log.NewConfigLogDao())
+	pdao.RegisterDao(driver, dao_balance.NewBalanceDao())
+	pdao.RegisterDao(driver, dao_balance_update.NewBalanceUpdateDao())
+	pdao.RegisterDao(driver, dao_service.NewServiceDao())
+	pdao.RegisterDao(driver, dao_version_config.NewVersionConfigDao())
+	pdao.RegisterDao(driver, updater.NewUpdaterDao())
 }
 
 //TableBuilder tableBuilder
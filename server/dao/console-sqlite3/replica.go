@@ -0,0 +1,138 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//ReplicaDao ReplicaDao
+type ReplicaDao struct {
+	db *SQL.DB
+}
+
+//NewReplicaDao new ReplicaDao
+func NewReplicaDao() *ReplicaDao {
+	return &ReplicaDao{}
+}
+
+//Create create
+func (d *ReplicaDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ReplicaDao = d
+	return &i, nil
+}
+
+//LockNodeSession 节点连接到控制台实例时占用会话，会话被其他未过期的实例占用时返回false
+func (d *ReplicaDao) LockNodeSession(nodeKey, consoleID, now, expireTime string) (bool, error) {
+	db := d.db
+	matched, err := execAffected(db, "UPDATE goku_node_session SET `consoleID` = ?,`pluginErrors` = '',`updateTime` = ? WHERE `nodeKey` = ? AND (`consoleID` = ? OR `updateTime` < ?);", consoleID, now, nodeKey, consoleID, expireTime)
+	if err != nil || matched {
+		return matched, err
+	}
+	_, err = db.Exec("INSERT INTO goku_node_session (`nodeKey`,`consoleID`,`pluginErrors`,`updateTime`) VALUES (?,?,'',?);", nodeKey, consoleID, now)
+	if err != nil {
+		// 主键冲突说明会话已被其他实例占用
+		var count int
+		if e := db.QueryRow("SELECT COUNT(*) FROM goku_node_session WHERE `nodeKey` = ?;", nodeKey).Scan(&count); e == nil && count > 0 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+//RefreshNodeSessions 刷新控制台实例持有的会话
+func (d *ReplicaDao) RefreshNodeSessions(consoleID, now string) error {
+	_, err := d.db.Exec("UPDATE goku_node_session SET `updateTime` = ? WHERE `consoleID` = ?;", now, consoleID)
+	return err
+}
+
+//SetNodeSessionPluginErrors 记录节点上报的插件加载错误
+func (d *ReplicaDao) SetNodeSessionPluginErrors(nodeKey, consoleID, pluginErrors string) error {
+	_, err := d.db.Exec("UPDATE goku_node_session SET `pluginErrors` = ? WHERE `nodeKey` = ? AND `consoleID` = ?;", pluginErrors, nodeKey, consoleID)
+	return err
+}
+
+//UnlockNodeSession 释放会话
+func (d *ReplicaDao) UnlockNodeSession(nodeKey, consoleID string) error {
+	_, err := d.db.Exec("DELETE FROM goku_node_session WHERE `nodeKey` = ? AND `consoleID` = ?;", nodeKey, consoleID)
+	return err
+}
+
+//GetNodeSessions 获取未过期的会话
+func (d *ReplicaDao) GetNodeSessions(expireTime string) ([]*entity.NodeSession, error) {
+	rows, err := d.db.Query("SELECT `nodeKey`,`consoleID`,`pluginErrors`,`updateTime` FROM goku_node_session WHERE `updateTime` >= ?;", expireTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := make([]*entity.NodeSession, 0)
+	for rows.Next() {
+		var s entity.NodeSession
+		err = rows.Scan(&s.NodeKey, &s.ConsoleID, &s.PluginErrors, &s.UpdateTime)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, nil
+}
+
+//AddConsoleEvent 新增控制台实例间广播的事件
+func (d *ReplicaDao) AddConsoleEvent(consoleID, eventType, data, now string) error {
+	_, err := d.db.Exec("INSERT INTO goku_console_event (`consoleID`,`eventType`,`data`,`createTime`) VALUES (?,?,?,?);", consoleID, eventType, data, now)
+	return err
+}
+
+//GetConsoleEvents 获取指定ID之后的事件
+func (d *ReplicaDao) GetConsoleEvents(lastID int) ([]*entity.ConsoleEvent, error) {
+	rows, err := d.db.Query("SELECT `id`,`consoleID`,`eventType`,`data`,`createTime` FROM goku_console_event WHERE `id` > ? ORDER BY `id` ASC;", lastID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]*entity.ConsoleEvent, 0)
+	for rows.Next() {
+		var e entity.ConsoleEvent
+		err = rows.Scan(&e.ID, &e.ConsoleID, &e.EventType, &e.Data, &e.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+//GetLastConsoleEventID 获取最新事件ID
+func (d *ReplicaDao) GetLastConsoleEventID() (int, error) {
+	var id int
+	err := d.db.QueryRow("SELECT IFNULL(MAX(`id`),0) FROM goku_console_event;").Scan(&id)
+	return id, err
+}
+
+//DeleteConsoleEvents 删除过期事件
+func (d *ReplicaDao) DeleteConsoleEvents(expireTime string) error {
+	_, err := d.db.Exec("DELETE FROM goku_console_event WHERE `createTime` < ?;", expireTime)
+	return err
+}
+
+//AcquireLease 获取或续期租约，租约被其他未过期的实例持有时返回false
+func (d *ReplicaDao) AcquireLease(name, consoleID, now, expireTime string) (bool, error) {
+	db := d.db
+	matched, err := execAffected(db, "UPDATE goku_console_lease SET `consoleID` = ?,`updateTime` = ? WHERE `name` = ? AND (`consoleID` = ? OR `updateTime` < ?);", consoleID, now, name, consoleID, expireTime)
+	if err != nil || matched {
+		return matched, err
+	}
+	_, err = db.Exec("INSERT INTO goku_console_lease (`name`,`consoleID`,`updateTime`) VALUES (?,?,?);", name, consoleID, now)
+	if err != nil {
+		// 主键冲突说明租约已被其他实例持有
+		var count int
+		if e := db.QueryRow("SELECT COUNT(*) FROM goku_console_lease WHERE `name` = ?;", name).Scan(&count); e == nil && count > 0 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,83 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const responseCacheFields = "A.`apiID`,IFNULL(G.`apiName`,''),A.`config`,A.`updateTime` FROM goku_api_cache A LEFT JOIN goku_gateway_api G ON A.`apiID` = G.`apiID`"
+
+//ResponseCacheDao ResponseCacheDao
+type ResponseCacheDao struct {
+	db *SQL.DB
+}
+
+//NewResponseCacheDao new ResponseCacheDao
+func NewResponseCacheDao() *ResponseCacheDao {
+	return &ResponseCacheDao{}
+}
+
+//Create create
+func (d *ResponseCacheDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ResponseCacheDao = d
+	return &i, nil
+}
+
+//SaveResponseCache 新增或更新接口响应缓存配置
+func (d *ResponseCacheDao) SaveResponseCache(apiID int, cfg, updateTime string) error {
+	db := d.db
+	sql := "REPLACE INTO goku_api_cache (`apiID`,`config`,`updateTime`) VALUES (?,?,?);"
+	_, err := db.Exec(sql, apiID, cfg, updateTime)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//GetResponseCache 获取接口响应缓存配置
+func (d *ResponseCacheDao) GetResponseCache(apiID int) (*entity.ResponseCache, error) {
+	db := d.db
+	sql := "SELECT " + responseCacheFields + " WHERE A.`apiID` = ?;"
+	return scanResponseCache(db.QueryRow(sql, apiID))
+}
+
+//GetResponseCacheList 获取已配置缓存的接口列表
+func (d *ResponseCacheDao) GetResponseCacheList() ([]*entity.ResponseCache, error) {
+	db := d.db
+	sql := "SELECT " + responseCacheFields + " WHERE G.`apiID` IS NOT NULL ORDER BY A.`updateTime` DESC;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.ResponseCache, 0)
+	for rows.Next() {
+		c, err := scanResponseCache(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, nil
+}
+
+func scanResponseCache(row rowScanner) (*entity.ResponseCache, error) {
+	c := &entity.ResponseCache{
+		ResponseCacheConfig: new(config.ResponseCacheConfig),
+	}
+	var cfg string
+	err := row.Scan(&c.APIID, &c.APIName, &cfg, &c.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(cfg), c.ResponseCacheConfig)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,165 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const scriptFields = "`scriptID`,`scriptName`,`scriptDesc`,`language`,`content`,`version`,`timeout`,`maxDataSize`,`createTime`,`updateTime`"
+
+//ScriptDao ScriptDao
+type ScriptDao struct {
+	db *SQL.DB
+}
+
+//NewScriptDao new ScriptDao
+func NewScriptDao() *ScriptDao {
+	return &ScriptDao{}
+}
+
+//Create create
+func (d *ScriptDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ScriptDao = d
+	return &i, nil
+}
+
+//AddScript 新增脚本
+func (d *ScriptDao) AddScript(s *entity.Script) (int, error) {
+	db := d.db
+	Tx, _ := db.Begin()
+	sql := "INSERT INTO goku_script (`scriptName`,`scriptDesc`,`language`,`content`,`version`,`timeout`,`maxDataSize`,`createTime`,`updateTime`) VALUES (?,?,?,?,1,?,?,?,?);"
+	res, err := Tx.Exec(sql, s.ScriptName, s.ScriptDesc, s.Language, s.Content, s.Timeout, s.MaxDataSize, s.CreateTime, s.UpdateTime)
+	if err != nil {
+		Tx.Rollback()
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		Tx.Rollback()
+		return 0, err
+	}
+	_, err = Tx.Exec("INSERT INTO goku_script_history (`scriptName`,`version`,`content`,`createTime`) VALUES (?,1,?,?);", s.ScriptName, s.Content, s.UpdateTime)
+	if err != nil {
+		Tx.Rollback()
+		return 0, err
+	}
+	return int(id), Tx.Commit()
+}
+
+//EditScript 编辑脚本，版本号加一并记录历史版本
+func (d *ScriptDao) EditScript(s *entity.Script) error {
+	db := d.db
+	Tx, _ := db.Begin()
+	var name string
+	var version int
+	err := Tx.QueryRow("SELECT `scriptName`,`version` FROM goku_script WHERE `scriptID` = ?;", s.ScriptID).Scan(&name, &version)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	version++
+	sql := "UPDATE goku_script SET `scriptDesc` = ?,`language` = ?,`content` = ?,`version` = ?,`timeout` = ?,`maxDataSize` = ?,`updateTime` = ? WHERE `scriptID` = ?;"
+	_, err = Tx.Exec(sql, s.ScriptDesc, s.Language, s.Content, version, s.Timeout, s.MaxDataSize, s.UpdateTime, s.ScriptID)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	_, err = Tx.Exec("INSERT INTO goku_script_history (`scriptName`,`version`,`content`,`createTime`) VALUES (?,?,?,?);", name, version, s.Content, s.UpdateTime)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+//GetScript 获取脚本
+func (d *ScriptDao) GetScript(scriptID int) (*entity.Script, error) {
+	db := d.db
+	sql := "SELECT " + scriptFields + " FROM goku_script WHERE `scriptID` = ?;"
+	return scanScript(db.QueryRow(sql, scriptID))
+}
+
+//GetScriptList 获取脚本列表
+func (d *ScriptDao) GetScriptList(keyword string) ([]*entity.Script, error) {
+	db := d.db
+	sql := "SELECT " + scriptFields + " FROM goku_script"
+	args := make([]interface{}, 0, 2)
+	if keyword != "" {
+		sql += " WHERE `scriptName` LIKE ? OR `scriptDesc` LIKE ?"
+		args = append(args, "%"+keyword+"%", "%"+keyword+"%")
+	}
+	sql += " ORDER BY `updateTime` DESC;"
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.Script, 0)
+	for rows.Next() {
+		s, err := scanScript(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+//BatchDeleteScript 批量删除脚本，同时删除历史版本
+func (d *ScriptDao) BatchDeleteScript(scriptIDs []int) error {
+	if len(scriptIDs) == 0 {
+		return nil
+	}
+	db := d.db
+	args := make([]interface{}, 0, len(scriptIDs))
+	for _, id := range scriptIDs {
+		args = append(args, id)
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?,", len(scriptIDs)), ",") + ")"
+	Tx, _ := db.Begin()
+	_, err := Tx.Exec("DELETE FROM goku_script_history WHERE `scriptName` IN (SELECT `scriptName` FROM goku_script WHERE `scriptID` IN "+in+");", args...)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	_, err = Tx.Exec("DELETE FROM goku_script WHERE `scriptID` IN "+in+";", args...)
+	if err != nil {
+		Tx.Rollback()
+		return err
+	}
+	return Tx.Commit()
+}
+
+//GetScriptHistory 获取脚本历史版本
+func (d *ScriptDao) GetScriptHistory(scriptID int) ([]*entity.ScriptHistory, error) {
+	db := d.db
+	sql := "SELECT H.`scriptName`,H.`version`,H.`content`,H.`createTime` FROM goku_script_history H INNER JOIN goku_script S ON H.`scriptName` = S.`scriptName` WHERE S.`scriptID` = ? ORDER BY H.`version` DESC;"
+	rows, err := db.Query(sql, scriptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.ScriptHistory, 0)
+	for rows.Next() {
+		var h entity.ScriptHistory
+		err = rows.Scan(&h.ScriptName, &h.Version, &h.Content, &h.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, &h)
+	}
+	return list, nil
+}
+
+func scanScript(row rowScanner) (*entity.Script, error) {
+	var s entity.Script
+	err := row.Scan(&s.ScriptID, &s.ScriptName, &s.ScriptDesc, &s.Language, &s.Content, &s.Version, &s.Timeout, &s.MaxDataSize, &s.CreateTime, &s.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
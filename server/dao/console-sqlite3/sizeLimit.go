@@ -0,0 +1,124 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const sizeLimitFields = "A.`id`,A.`strategyID`,IFNULL(S.`strategyName`,''),A.`apiID`,IFNULL(G.`apiName`,''),A.`maxRequestBody`,A.`maxResponseBody`,A.`maxHeader`,A.`remark`,A.`updateTime`"
+
+const sizeLimitJoin = " FROM goku_size_limit A LEFT JOIN goku_gateway_strategy S ON A.`strategyID` = S.`strategyID` LEFT JOIN goku_gateway_api G ON A.`apiID` = G.`apiID`"
+
+//SizeLimitDao SizeLimitDao
+type SizeLimitDao struct {
+	db *SQL.DB
+}
+
+//NewSizeLimitDao new SizeLimitDao
+func NewSizeLimitDao() *SizeLimitDao {
+	return &SizeLimitDao{}
+}
+
+//Create create
+func (d *SizeLimitDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.SizeLimitDao = d
+	return &i, nil
+}
+
+//AddSizeLimit 新增大小限制
+func (d *SizeLimitDao) AddSizeLimit(l *entity.SizeLimit) (int, error) {
+	db := d.db
+	sql := "INSERT INTO goku_size_limit (`strategyID`,`apiID`,`maxRequestBody`,`maxResponseBody`,`maxHeader`,`remark`,`updateTime`) VALUES (?,?,?,?,?,?,?);"
+	res, err := db.Exec(sql, l.StrategyID, l.APIID, l.MaxRequestBody, l.MaxResponseBody, l.MaxHeader, l.Remark, l.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+//EditSizeLimit 编辑大小限制
+func (d *SizeLimitDao) EditSizeLimit(l *entity.SizeLimit) error {
+	db := d.db
+	sql := "UPDATE goku_size_limit SET `strategyID` = ?,`apiID` = ?,`maxRequestBody` = ?,`maxResponseBody` = ?,`maxHeader` = ?,`remark` = ?,`updateTime` = ? WHERE `id` = ?;"
+	_, err := db.Exec(sql, l.StrategyID, l.APIID, l.MaxRequestBody, l.MaxResponseBody, l.MaxHeader, l.Remark, l.UpdateTime, l.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//GetSizeLimit 获取大小限制
+func (d *SizeLimitDao) GetSizeLimit(id int) (*entity.SizeLimit, error) {
+	db := d.db
+	sql := "SELECT " + sizeLimitFields + sizeLimitJoin + " WHERE A.`id` = ?;"
+	return scanSizeLimit(db.QueryRow(sql, id))
+}
+
+//GetSizeLimitList 获取大小限制列表，strategyID为空或apiID为0时不作为筛选条件
+func (d *SizeLimitDao) GetSizeLimitList(strategyID string, apiID int) ([]*entity.SizeLimit, error) {
+	db := d.db
+	rule := make([]string, 0, 2)
+	args := make([]interface{}, 0, 2)
+	if strategyID != "" {
+		rule = append(rule, "A.`strategyID` = ?")
+		args = append(args, strategyID)
+	}
+	if apiID != 0 {
+		rule = append(rule, "A.`apiID` = ?")
+		args = append(args, apiID)
+	}
+	sql := "SELECT " + sizeLimitFields + sizeLimitJoin
+	if len(rule) > 0 {
+		sql += " WHERE " + strings.Join(rule, " AND ")
+	}
+	sql += " ORDER BY A.`updateTime` DESC;"
+	rows, err := db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.SizeLimit, 0)
+	for rows.Next() {
+		l, err := scanSizeLimit(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	return list, nil
+}
+
+//BatchDeleteSizeLimit 批量删除大小限制
+func (d *SizeLimitDao) BatchDeleteSizeLimit(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	db := d.db
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	sql := "DELETE FROM goku_size_limit WHERE `id` IN (" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ");"
+	_, err := db.Exec(sql, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanSizeLimit(row rowScanner) (*entity.SizeLimit, error) {
+	var l entity.SizeLimit
+	err := row.Scan(&l.ID, &l.StrategyID, &l.StrategyName, &l.APIID, &l.APIName, &l.MaxRequestBody, &l.MaxResponseBody, &l.MaxHeader, &l.Remark, &l.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
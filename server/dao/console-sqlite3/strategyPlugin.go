@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/eolinker/goku-api-gateway/common/secret"
 	"github.com/eolinker/goku-api-gateway/server/dao"
 )
 
@@ -38,6 +39,10 @@ func (d *StrategyPluginDao) AddPluginToStrategy(pluginName, config, strategyID s
 	if err == nil {
 		return false, "[ERROR]The strategy plugin is already exist", errors.New("[ERROR]The strategy plugin is already exist")
 	}
+	config, err = secret.SealAuthConfig(pluginName, config)
+	if err != nil {
+		return false, "[ERROR]Fail to seal credentials", err
+	}
 	now := time.Now().Format("2006-01-02 15:04:05")
 	Tx, _ := db.Begin()
 	result, err := Tx.Exec("INSERT INTO goku_conn_plugin_strategy (pluginName,pluginConfig,strategyID,createTime,updateTime,pluginStatus) VALUES (?,?,?,?,?,?);", pluginName, config, strategyID, now, now, 1)
@@ -75,6 +80,10 @@ func (d *StrategyPluginDao) EditStrategyPluginConfig(pluginName, config, strateg
 	if err != nil {
 		return false, "[ERROR]The strategy plugin is not exist", errors.New("[ERROR]The strategy plugin is not exist")
 	}
+	config, err = secret.SealAuthConfig(pluginName, config)
+	if err != nil {
+		return false, "[ERROR]Fail to seal credentials", err
+	}
 	Tx, _ := db.Begin()
 	_, err = Tx.Exec("UPDATE goku_conn_plugin_strategy SET updateTag = ?,pluginConfig = ?,updateTime = ? WHERE pluginName = ? AND strategyID = ?;", updateTag, config, now, pluginName, strategyID)
 	if err != nil {
@@ -125,6 +134,9 @@ func (d *StrategyPluginDao) GetStrategyPluginList(strategyID, keyword string, co
 		err = rows.Scan(&connID, &pluginName, &pluginConfig, &createTime, &updateTime, &pluginPriority, &pluginStatus, &pluginDesc)
 		if err != nil {
 		}
+		if c, err := secret.OpenAuthConfig(pluginName, pluginConfig); err == nil {
+			pluginConfig = c
+		}
 		pluginInfo := map[string]interface{}{
 			"connID":         connID,
 			"pluginName":     pluginName,
@@ -152,6 +164,10 @@ func (d *StrategyPluginDao) GetStrategyPluginConfig(strategyID, pluginName strin
 		}
 		return false, "", err
 	}
+	p, err = secret.OpenAuthConfig(pluginName, p)
+	if err != nil {
+		return false, "", err
+	}
 	return true, p, nil
 
 }
@@ -0,0 +1,94 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//TrafficDao TrafficDao
+type TrafficDao struct {
+	db *SQL.DB
+}
+
+//NewTrafficDao new TrafficDao
+func NewTrafficDao() *TrafficDao {
+	return &TrafficDao{}
+}
+
+//Create create
+func (d *TrafficDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.TrafficDao = d
+	return &i, nil
+}
+
+//AddTrafficReport 保存节点上报的流量统计
+func (d *TrafficDao) AddTrafficReport(cluster, nodeKey string, report *config.TrafficReport) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	sql := "INSERT INTO goku_traffic_stat (`cluster`,`nodeKey`,`reportTime`,`period`,`strategyID`,`apiID`,`requests`,`status2xx`,`status3xx`,`status4xx`,`status5xx`,`latencySum`,`latencyMax`,`latencyBuckets`,`upstreamRequests`,`upstreamErrors`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);"
+	stmt, err := tx.Prepare(sql)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, s := range report.Stats {
+		buckets, _ := json.Marshal(s.LatencyBuckets)
+		_, err = stmt.Exec(cluster, nodeKey, report.Time, report.Period, s.StrategyID, s.APIID, s.Requests, s.Status2xx, s.Status3xx, s.Status4xx, s.Status5xx, s.LatencySum, s.LatencyMax, string(buckets), s.UpstreamRequests, s.UpstreamErrors)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+//GetTrafficStats 获取时间范围内的流量统计，cluster、strategyID为空及apiID为0时不过滤
+func (d *TrafficDao) GetTrafficStats(start, end int64, cluster, strategyID string, apiID int) ([]*entity.TrafficStat, error) {
+	conditions := []string{"`reportTime` >= ?", "`reportTime` < ?"}
+	args := []interface{}{start, end}
+	if cluster != "" {
+		conditions = append(conditions, "`cluster` = ?")
+		args = append(args, cluster)
+	}
+	if strategyID != "" {
+		conditions = append(conditions, "`strategyID` = ?")
+		args = append(args, strategyID)
+	}
+	if apiID != 0 {
+		conditions = append(conditions, "`apiID` = ?")
+		args = append(args, apiID)
+	}
+	sql := "SELECT `cluster`,`nodeKey`,`reportTime`,`period`,`strategyID`,`apiID`,`requests`,`status2xx`,`status3xx`,`status4xx`,`status5xx`,`latencySum`,`latencyMax`,`latencyBuckets`,`upstreamRequests`,`upstreamErrors` FROM goku_traffic_stat WHERE " + strings.Join(conditions, " AND ") + " ORDER BY `reportTime` ASC;"
+	rows, err := d.db.Query(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make([]*entity.TrafficStat, 0)
+	for rows.Next() {
+		var s entity.TrafficStat
+		buckets := ""
+		err = rows.Scan(&s.Cluster, &s.NodeKey, &s.ReportTime, &s.Period, &s.StrategyID, &s.APIID, &s.Requests, &s.Status2xx, &s.Status3xx, &s.Status4xx, &s.Status5xx, &s.LatencySum, &s.LatencyMax, &buckets, &s.UpstreamRequests, &s.UpstreamErrors)
+		if err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(buckets), &s.LatencyBuckets)
+		stats = append(stats, &s)
+	}
+	return stats, nil
+}
+
+//DeleteTrafficStats 删除指定时间之前的流量统计
+func (d *TrafficDao) DeleteTrafficStats(before int64) error {
+	_, err := d.db.Exec("DELETE FROM goku_traffic_stat WHERE `reportTime` < ?;", before)
+	return err
+}
@@ -1,43 +1,23 @@
 package updater
 
-import (
-	SQL "database/sql"
-	"fmt"
-)
-
 //IsTableExist 检查table是否存在
-func (d *Dao)IsTableExist(name string) bool {
+func (d *Dao) IsTableExist(name string) bool {
 	db := d.db
-	selectType := ""
-	sql := "SELECT type FROM sqlite_master WHERE `type` = 'table' AND `name` = ?"
-	err := db.QueryRow(sql, name).Scan(&selectType)
+	rows, err := db.Query("SELECT * FROM `" + name + "` WHERE 1 = 0;")
 	if err != nil {
 		return false
 	}
+	rows.Close()
 	return true
 }
 
 //IsColumnExist 检查列是否存在
-func (d *Dao)IsColumnExist(name string, column string) bool {
+func (d *Dao) IsColumnExist(name string, column string) bool {
 	db := d.db
-	sql := fmt.Sprintf("PRAGMA table_info(%s)", name)
-	rows, err := db.Query(sql)
+	rows, err := db.Query("SELECT `" + column + "` FROM `" + name + "` WHERE 1 = 0;")
 	if err != nil {
-		panic(err)
 		return false
 	}
-	defer rows.Close()
-	for rows.Next() {
-		var cID, notNull, pk int
-		var name, columnType string
-		var dfltValue SQL.NullString
-		err = rows.Scan(&cID, &name, &columnType, &notNull, &dfltValue, &pk)
-		if err != nil {
-			return false
-		}
-		if name == column {
-			return true
-		}
-	}
-	return false
+	rows.Close()
+	return true
 }
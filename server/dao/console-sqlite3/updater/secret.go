@@ -0,0 +1,130 @@
+package updater
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/common/secret"
+)
+
+//SealAuthCredentials 对已保存的鉴权凭证进行哈希或加密，包括已生成的版本配置
+func (d *Dao) SealAuthCredentials() error {
+	err := sealStrategyPlugins(d.db)
+	if err != nil {
+		return err
+	}
+	return sealVersionConfigs(d.db)
+}
+
+func sealStrategyPlugins(db *SQL.DB) error {
+	rows, err := db.Query("SELECT connID,pluginName,IFNULL(pluginConfig,'') FROM goku_conn_plugin_strategy;")
+	if err != nil {
+		return err
+	}
+	updates := make(map[int]string)
+	for rows.Next() {
+		var connID int
+		var pluginName, pluginConfig string
+		if err := rows.Scan(&connID, &pluginName, &pluginConfig); err != nil {
+			rows.Close()
+			return err
+		}
+		sealed, err := secret.SealAuthConfig(pluginName, pluginConfig)
+		if err != nil {
+			// 无法解析的配置保持原样
+			continue
+		}
+		if sealed != pluginConfig {
+			updates[connID] = sealed
+		}
+	}
+	rows.Close()
+
+	for connID, c := range updates {
+		_, err = db.Exec("UPDATE goku_conn_plugin_strategy SET pluginConfig = ? WHERE connID = ?;", c, connID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sealVersionConfigs(db *SQL.DB) error {
+	rows, err := db.Query("SELECT versionID,IFNULL(config,'') FROM goku_gateway_version_config;")
+	if err != nil {
+		return err
+	}
+	updates := make(map[int]string)
+	for rows.Next() {
+		var versionID int
+		var c string
+		if err := rows.Scan(&versionID, &c); err != nil {
+			rows.Close()
+			return err
+		}
+		sealed, changed := sealVersionConfig(c)
+		if changed {
+			updates[versionID] = sealed
+		}
+	}
+	rows.Close()
+
+	for versionID, c := range updates {
+		_, err = db.Exec("UPDATE goku_gateway_version_config SET config = ? WHERE versionID = ?;", c, versionID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sealVersionConfig 处理版本配置中策略的鉴权配置及鉴权插件配置
+func sealVersionConfig(c string) (string, bool) {
+	conf := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(c), &conf); err != nil {
+		return c, false
+	}
+	strategies, _ := conf["strategy"].([]interface{})
+	changed := false
+	seal := func(name string, v interface{}) interface{} {
+		s, ok := v.(string)
+		if !ok {
+			return v
+		}
+		sealed, err := secret.SealAuthConfig(name, s)
+		if err != nil || sealed == s {
+			return v
+		}
+		changed = true
+		return sealed
+	}
+	for _, item := range strategies {
+		strategy, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if auth, ok := strategy["auth"].(map[string]interface{}); ok {
+			for name, v := range auth {
+				auth[name] = seal(name, v)
+			}
+		}
+		plugins, _ := strategy["plugins"].([]interface{})
+		for _, p := range plugins {
+			plugin, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, ok := plugin["name"].(string); ok {
+				plugin["config"] = seal(name, plugin["config"])
+			}
+		}
+	}
+	if !changed {
+		return c, false
+	}
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return c, false
+	}
+	return string(data), true
+}
@@ -20,3 +20,16 @@ func getPageSQL(db *SQL.DB,sql string, orderBy, orderType string, page, pageSize
 	args = append(args, (page-1)*pageSize, pageSize)
 	return db.Query(pageSQL, args...)
 }
+
+// execAffected 执行更新语句并返回是否匹配到记录，用于在sqlite3及mysql下通用的新增或更新
+func execAffected(db *SQL.DB, sql string, args ...interface{}) (bool, error) {
+	result, err := db.Exec(sql, args...)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
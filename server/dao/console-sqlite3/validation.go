@@ -0,0 +1,83 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+	"encoding/json"
+
+	"github.com/eolinker/goku-api-gateway/config"
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+const validationFields = "A.`apiID`,IFNULL(G.`apiName`,''),A.`config`,A.`updateTime` FROM goku_api_validation A LEFT JOIN goku_gateway_api G ON A.`apiID` = G.`apiID`"
+
+//ValidationDao ValidationDao
+type ValidationDao struct {
+	db *SQL.DB
+}
+
+//NewValidationDao new ValidationDao
+func NewValidationDao() *ValidationDao {
+	return &ValidationDao{}
+}
+
+//Create create
+func (d *ValidationDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.ValidationDao = d
+	return &i, nil
+}
+
+//SaveValidation 新增或更新接口请求校验配置
+func (d *ValidationDao) SaveValidation(apiID int, cfg, updateTime string) error {
+	db := d.db
+	sql := "REPLACE INTO goku_api_validation (`apiID`,`config`,`updateTime`) VALUES (?,?,?);"
+	_, err := db.Exec(sql, apiID, cfg, updateTime)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//GetValidation 获取接口请求校验配置
+func (d *ValidationDao) GetValidation(apiID int) (*entity.Validation, error) {
+	db := d.db
+	sql := "SELECT " + validationFields + " WHERE A.`apiID` = ?;"
+	return scanValidation(db.QueryRow(sql, apiID))
+}
+
+//GetValidationList 获取已配置请求校验的接口列表
+func (d *ValidationDao) GetValidationList() ([]*entity.Validation, error) {
+	db := d.db
+	sql := "SELECT " + validationFields + " WHERE G.`apiID` IS NOT NULL ORDER BY A.`updateTime` DESC;"
+	rows, err := db.Query(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*entity.Validation, 0)
+	for rows.Next() {
+		v, err := scanValidation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func scanValidation(row rowScanner) (*entity.Validation, error) {
+	v := &entity.Validation{
+		ValidationConfig: new(config.ValidationConfig),
+	}
+	var cfg string
+	err := row.Scan(&v.APIID, &v.APIName, &cfg, &v.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(cfg), v.ValidationConfig)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
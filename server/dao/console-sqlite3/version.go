@@ -152,11 +152,21 @@ func (d *VersionDao) GetPublishVersionID() int {
 
 //GetVersionConfig 获取当前版本配置
 func (d *VersionDao) GetVersionConfig() (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
-	db := d.db
 	sql := "SELECT IFNULL(goku_gateway_version_config.config,'{}'),IFNULL(goku_gateway_version_config.balanceConfig,'{}'),IFNULL(goku_gateway_version_config.discoverConfig,'{}') FROM goku_gateway_version_config INNER JOIN goku_gateway ON goku_gateway.versionID = goku_gateway_version_config.versionID"
+	return d.getVersionConfig(sql)
+}
+
+//GetVersionConfigByID 获取指定版本的配置
+func (d *VersionDao) GetVersionConfigByID(versionID int) (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
+	sql := "SELECT IFNULL(config,'{}'),IFNULL(balanceConfig,'{}'),IFNULL(discoverConfig,'{}') FROM goku_gateway_version_config WHERE versionID = ?"
+	return d.getVersionConfig(sql, versionID)
+}
+
+func (d *VersionDao) getVersionConfig(sql string, args ...interface{}) (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error) {
+	db := d.db
 	var cf, bf, df string
 
-	err := db.QueryRow(sql).Scan(&cf, &bf, &df)
+	err := db.QueryRow(sql, args...).Scan(&cf, &bf, &df)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -0,0 +1,82 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//VersionCanaryDao VersionCanaryDao
+type VersionCanaryDao struct {
+	db *SQL.DB
+}
+
+//NewVersionCanaryDao new VersionCanaryDao
+func NewVersionCanaryDao() *VersionCanaryDao {
+	return &VersionCanaryDao{}
+}
+
+//Create create
+func (d *VersionCanaryDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.VersionCanaryDao = d
+	return &i, nil
+}
+
+const versionCanarySQL = "SELECT C.`canaryID`,C.`versionID`,IFNULL(V.`name`,''),C.`baseVersionID`,C.`groupID`,IFNULL(G.`groupName`,''),C.`status`,C.`autoPromote`,C.`observeTime`,C.`errorThreshold`,C.`minRequests`,C.`userID`,IFNULL(A.`loginCall`,''),C.`reason`,C.`startTime`,C.`endTime`,C.`createTime`,C.`updateTime` FROM goku_version_canary C LEFT JOIN goku_gateway_version_config V ON C.`versionID` = V.`versionID` LEFT JOIN goku_node_group G ON C.`groupID` = G.`groupID` LEFT JOIN goku_admin A ON C.`userID` = A.`userID`"
+
+func scanVersionCanary(row rowScanner) (*entity.VersionCanary, error) {
+	var c entity.VersionCanary
+	var autoPromote int
+	err := row.Scan(&c.CanaryID, &c.VersionID, &c.VersionName, &c.BaseVersionID, &c.GroupID, &c.GroupName, &c.Status, &autoPromote, &c.ObserveTime, &c.ErrorThreshold, &c.MinRequests, &c.UserID, &c.LoginCall, &c.Reason, &c.StartTime, &c.EndTime, &c.CreateTime, &c.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	c.AutoPromote = autoPromote == 1
+	return &c, nil
+}
+
+//AddVersionCanary 新增灰度发布
+func (d *VersionCanaryDao) AddVersionCanary(c *entity.VersionCanary) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_version_canary (`versionID`,`baseVersionID`,`groupID`,`status`,`autoPromote`,`observeTime`,`errorThreshold`,`minRequests`,`userID`,`reason`,`startTime`,`endTime`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?);", c.VersionID, c.BaseVersionID, c.GroupID, c.Status, boolToInt(c.AutoPromote), c.ObserveTime, c.ErrorThreshold, c.MinRequests, c.UserID, c.Reason, c.StartTime, c.EndTime, c.CreateTime, c.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//GetVersionCanary 获取灰度发布
+func (d *VersionCanaryDao) GetVersionCanary(canaryID int) (*entity.VersionCanary, error) {
+	return scanVersionCanary(d.db.QueryRow(versionCanarySQL+" WHERE C.`canaryID` = ?;", canaryID))
+}
+
+//GetVersionCanaryByStatus 获取最近一条指定状态的灰度发布
+func (d *VersionCanaryDao) GetVersionCanaryByStatus(status string) (*entity.VersionCanary, error) {
+	return scanVersionCanary(d.db.QueryRow(versionCanarySQL+" WHERE C.`status` = ? ORDER BY C.`canaryID` DESC LIMIT 1;", status))
+}
+
+//GetVersionCanaryList 分页获取灰度发布记录
+func (d *VersionCanaryDao) GetVersionCanaryList(page, pageSize int) ([]*entity.VersionCanary, int, error) {
+	count := getCountSQL(d.db, versionCanarySQL)
+	rows, err := getPageSQL(d.db, versionCanarySQL, "C.`canaryID`", "DESC", page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	list := make([]*entity.VersionCanary, 0)
+	for rows.Next() {
+		c, err := scanVersionCanary(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, c)
+	}
+	return list, count, nil
+}
+
+//FinishVersionCanary 灰度发布处于fromStatus时结束为toStatus，返回是否更新成功
+func (d *VersionCanaryDao) FinishVersionCanary(canaryID int, fromStatus, toStatus, reason string, endTime int64, now string) (bool, error) {
+	return execAffected(d.db, "UPDATE goku_version_canary SET `status` = ?,`reason` = ?,`endTime` = ?,`updateTime` = ? WHERE `canaryID` = ? AND `status` = ?;", toStatus, reason, endTime, now, canaryID, fromStatus)
+}
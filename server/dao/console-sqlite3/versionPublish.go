@@ -0,0 +1,143 @@
+package console_sqlite3
+
+import (
+	SQL "database/sql"
+
+	"github.com/eolinker/goku-api-gateway/server/dao"
+	entity "github.com/eolinker/goku-api-gateway/server/entity/console-entity"
+)
+
+//VersionPublishDao VersionPublishDao
+type VersionPublishDao struct {
+	db *SQL.DB
+}
+
+//NewVersionPublishDao new VersionPublishDao
+func NewVersionPublishDao() *VersionPublishDao {
+	return &VersionPublishDao{}
+}
+
+//Create create
+func (d *VersionPublishDao) Create(db *SQL.DB) (interface{}, error) {
+	d.db = db
+	var i dao.VersionPublishDao = d
+	return &i, nil
+}
+
+const versionPublishSQL = "SELECT P.`requestID`,P.`versionID`,IFNULL(V.`name`,''),P.`status`,P.`isRollback`,P.`requestUserID`,IFNULL(R.`loginCall`,''),P.`approveUserID`,IFNULL(A.`loginCall`,''),P.`publishTime`,P.`remark`,P.`createTime`,P.`updateTime` FROM goku_version_publish P LEFT JOIN goku_gateway_version_config V ON P.`versionID` = V.`versionID` LEFT JOIN goku_admin R ON P.`requestUserID` = R.`userID` LEFT JOIN goku_admin A ON P.`approveUserID` = A.`userID`"
+
+func scanVersionPublish(row rowScanner) (*entity.VersionPublish, error) {
+	var p entity.VersionPublish
+	var isRollback int
+	err := row.Scan(&p.RequestID, &p.VersionID, &p.VersionName, &p.Status, &isRollback, &p.RequestUserID, &p.RequestUser, &p.ApproveUserID, &p.ApproveUser, &p.PublishTime, &p.Remark, &p.CreateTime, &p.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	p.IsRollback = isRollback == 1
+	return &p, nil
+}
+
+func scanVersionPublishRows(rows *SQL.Rows) ([]*entity.VersionPublish, error) {
+	defer rows.Close()
+	list := make([]*entity.VersionPublish, 0)
+	for rows.Next() {
+		p, err := scanVersionPublish(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+//AddVersionPublish 新增版本发布记录
+func (d *VersionPublishDao) AddVersionPublish(p *entity.VersionPublish) (int, error) {
+	res, err := d.db.Exec("INSERT INTO goku_version_publish (`versionID`,`status`,`isRollback`,`requestUserID`,`approveUserID`,`publishTime`,`remark`,`createTime`,`updateTime`) VALUES (?,?,?,?,?,?,?,?,?);", p.VersionID, p.Status, boolToInt(p.IsRollback), p.RequestUserID, p.ApproveUserID, p.PublishTime, p.Remark, p.CreateTime, p.UpdateTime)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+//GetVersionPublish 获取版本发布记录
+func (d *VersionPublishDao) GetVersionPublish(requestID int) (*entity.VersionPublish, error) {
+	return scanVersionPublish(d.db.QueryRow(versionPublishSQL+" WHERE P.`requestID` = ?;", requestID))
+}
+
+//GetVersionPublishList 分页获取版本发布记录，status为空时不过滤
+func (d *VersionPublishDao) GetVersionPublishList(status string, page, pageSize int) ([]*entity.VersionPublish, int, error) {
+	sql := versionPublishSQL
+	args := make([]interface{}, 0, 1)
+	if status != "" {
+		sql += " WHERE P.`status` = ?"
+		args = append(args, status)
+	}
+	count := getCountSQL(d.db, sql, args...)
+	rows, err := getPageSQL(d.db, sql, "P.`requestID`", "DESC", page, pageSize, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := scanVersionPublishRows(rows)
+	return list, count, err
+}
+
+//GetDueVersionPublishes 获取指定状态且计划发布时间不晚于before的发布记录
+func (d *VersionPublishDao) GetDueVersionPublishes(status string, before int64) ([]*entity.VersionPublish, error) {
+	rows, err := d.db.Query(versionPublishSQL+" WHERE P.`status` = ? AND P.`publishTime` <= ? ORDER BY P.`publishTime` ASC,P.`requestID` ASC;", status, before)
+	if err != nil {
+		return nil, err
+	}
+	return scanVersionPublishRows(rows)
+}
+
+//UpdateVersionPublishStatus 发布记录处于fromStatus时更新为toStatus，返回是否更新成功；approveUserID为0时不修改审批人
+func (d *VersionPublishDao) UpdateVersionPublishStatus(requestID int, fromStatus, toStatus string, approveUserID int, now string) (bool, error) {
+	if approveUserID == 0 {
+		return execAffected(d.db, "UPDATE goku_version_publish SET `status` = ?,`updateTime` = ? WHERE `requestID` = ? AND `status` = ?;", toStatus, now, requestID, fromStatus)
+	}
+	return execAffected(d.db, "UPDATE goku_version_publish SET `status` = ?,`approveUserID` = ?,`updateTime` = ? WHERE `requestID` = ? AND `status` = ?;", toStatus, approveUserID, now, requestID, fromStatus)
+}
+
+//GetLastPublishedVersionID 获取最近一次状态为status且不是excludeVersionID的发布版本
+func (d *VersionPublishDao) GetLastPublishedVersionID(status string, excludeVersionID int) (int, error) {
+	var versionID int
+	err := d.db.QueryRow("SELECT P.`versionID` FROM goku_version_publish P INNER JOIN goku_gateway_version_config V ON P.`versionID` = V.`versionID` WHERE P.`status` = ? AND P.`versionID` <> ? ORDER BY P.`updateTime` DESC,P.`requestID` DESC LIMIT 1;", status, excludeVersionID).Scan(&versionID)
+	return versionID, err
+}
+
+//CheckVersionPublishIsExist 检查版本是否存在指定状态的发布记录
+func (d *VersionPublishDao) CheckVersionPublishIsExist(versionID int, status string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM goku_version_publish WHERE `versionID` = ? AND `status` = ?;", versionID, status).Scan(&count)
+	return count > 0, err
+}
+
+//SetNodeConfigVersion 记录节点已应用的配置版本
+func (d *VersionPublishDao) SetNodeConfigVersion(nodeKey string, versionID int, now string) error {
+	matched, err := execAffected(d.db, "UPDATE goku_node_config SET `versionID` = ?,`updateTime` = ? WHERE `nodeKey` = ?;", versionID, now, nodeKey)
+	if err != nil || matched {
+		return err
+	}
+	_, err = d.db.Exec("INSERT INTO goku_node_config (`nodeKey`,`versionID`,`updateTime`) VALUES (?,?,?);", nodeKey, versionID, now)
+	return err
+}
+
+//GetNodeConfigVersions 获取各节点已应用的配置版本，key为nodeKey
+func (d *VersionPublishDao) GetNodeConfigVersions() (map[string]int, error) {
+	rows, err := d.db.Query("SELECT `nodeKey`,`versionID` FROM goku_node_config;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	versions := make(map[string]int)
+	for rows.Next() {
+		var nodeKey string
+		var versionID int
+		if err = rows.Scan(&nodeKey, &versionID); err != nil {
+			return nil, err
+		}
+		versions[nodeKey] = versionID
+	}
+	return versions, nil
+}
@@ -12,9 +12,9 @@ import (
 //APIDao apiDao
 type APIDao interface {
 	// AddAPI 新增接口
-	AddAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkAPIs, staticResponse, responseDataType, balanceName, protocol string, projectID, groupID, timeout, retryCount, alertValve, managerID, userID, apiType int) (bool, int, error)
+	AddAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkAPIs, staticResponse, rewrite, responseDataType, balanceName, protocol string, projectID, groupID, timeout, retryCount, alertValve, managerID, userID, apiType int) (bool, int, error)
 	// EditAPI 修改接口
-	EditAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkAPIs, staticResponse, responseDataType, balanceName, protocol string, projectID, groupID, timeout, retryCount, alertValve, apiID, managerID, userID int) (bool, error)
+	EditAPI(apiName, alias, requestURL, targetURL, requestMethod, targetMethod, isFollow, linkAPIs, staticResponse, rewrite, responseDataType, balanceName, protocol string, projectID, groupID, timeout, retryCount, alertValve, apiID, managerID, userID int) (bool, error)
 	// GetAPIInfo 获取接口信息
 	GetAPIInfo(apiID int) (bool, *entity.API, error)
 	//GetAPIListByGroupList 通过分组列表获取接口列表
@@ -110,7 +110,7 @@ type AuthDao interface {
 	//GetAuthInfo 获取认证信息
 	GetAuthInfo(strategyID string) (bool, map[string]interface{}, error)
 	//EditAuthInfo 编辑认证信息
-	EditAuthInfo(strategyID, strategyName, basicAuthList, apikeyList, jwtCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error)
+	EditAuthInfo(strategyID, strategyName, authPolicy, basicAuthList, apikeyList, jwtCredentialList, hmacCredentialList, oauth2CredentialList string, delClientIDList []string) (bool, error)
 }
 
 //ClusterDao cluster.go
@@ -142,6 +142,10 @@ type ConfigLogDao interface {
 	Get(name string) (*configLogEntry.LogConfig, error)
 	//Set set
 	Set(ent *configLogEntry.LogConfig) error
+	//GetAccessOutputs 获取集群的access日志输出
+	GetAccessOutputs(cluster string) ([]*config.AccessLogOutput, error)
+	//SetAccessOutputs 设置集群的access日志输出，outputs为空时删除
+	SetAccessOutputs(cluster string, outputs []*config.AccessLogOutput, updateTime string) error
 }
 
 //BalanceUpdateDao dao-balance-update
@@ -188,12 +192,24 @@ type VersionConfigDao interface {
 	GetStrategyConfig() (string, []*config.StrategyConfig, error)
 	//GetLogInfo 获取日志信息
 	GetLogInfo() (*config.LogConfig, *config.AccessLogConfig, error)
+	//GetAccessLogOutputs 获取各集群的access日志输出，key为集群名称
+	GetAccessLogOutputs() (map[string][]*config.AccessLogOutput, error)
 	//GetMonitorModules 获取监控模块信息
 	GetMonitorModules(status int, isAll bool) (map[string]string, error)
 
 	GetRouterRules(enable int) ([]*config.Router, error)
 
 	GetGatewayBasicConfig() (*config.Gateway, error)
+	//GetProtoDescriptors 获取protobuf描述文件
+	GetProtoDescriptors() (map[string]string, error)
+	//GetRateLimits 获取限流规则
+	GetRateLimits() ([]*config.RateLimitConfig, error)
+	//GetScripts 获取脚本
+	GetScripts() (map[string]*config.ScriptConfig, error)
+	//GetSizeLimits 获取大小限制规则
+	GetSizeLimits() ([]*config.SizeLimitConfig, error)
+	//GetOpenAPIs 获取OpenAPI文档
+	GetOpenAPIs() (map[string]string, error)
 }
 
 //GatewayDao gateway.go
@@ -224,6 +240,10 @@ type ImportDao interface {
 	ImportProjectFromAms(userID int, projectInfo entity.AmsProject) (bool, string, error)
 	//ImportAPIFromAms 从ams中导入接口
 	ImportAPIFromAms(projectID, groupID, userID int, apiList []entity.AmsAPIInfo) (bool, string, error)
+	//GetProjectOperations 获取项目下的接口
+	GetProjectOperations(projectID int) ([]*entity.OpenAPIOperation, error)
+	//ImportOpenAPI 导入OpenAPI操作
+	ImportOpenAPI(projectID, userID int, operations []*entity.OpenAPIOperation) error
 }
 
 //MonitorModulesDao monitorModule.go
@@ -236,6 +256,168 @@ type MonitorModulesDao interface {
 	CheckModuleStatus(moduleName string) int
 }
 
+//ProtoDescriptorDao protoDescriptor.go
+type ProtoDescriptorDao interface {
+	//GetProtoDescriptorList 获取protobuf描述文件列表
+	GetProtoDescriptorList() ([]*entity.ProtoDescriptor, error)
+	//GetProtoDescriptor 获取protobuf描述文件
+	GetProtoDescriptor(name string) (*entity.ProtoDescriptor, error)
+	//SaveProtoDescriptor 新增或更新protobuf描述文件
+	SaveProtoDescriptor(name, remark, content, now string) error
+	//BatchDeleteProtoDescriptor 批量删除protobuf描述文件
+	BatchDeleteProtoDescriptor(names []string) error
+}
+
+//ScriptDao script.go
+type ScriptDao interface {
+	//AddScript 新增脚本
+	AddScript(s *entity.Script) (int, error)
+	//EditScript 编辑脚本，版本号加一并记录历史版本
+	EditScript(s *entity.Script) error
+	//GetScript 获取脚本
+	GetScript(scriptID int) (*entity.Script, error)
+	//GetScriptList 获取脚本列表
+	GetScriptList(keyword string) ([]*entity.Script, error)
+	//BatchDeleteScript 批量删除脚本
+	BatchDeleteScript(scriptIDs []int) error
+	//GetScriptHistory 获取脚本历史版本
+	GetScriptHistory(scriptID int) ([]*entity.ScriptHistory, error)
+}
+
+//ResponseCacheDao responseCache.go
+type ResponseCacheDao interface {
+	//SaveResponseCache 新增或更新接口响应缓存配置
+	SaveResponseCache(apiID int, cfg, updateTime string) error
+	//GetResponseCache 获取接口响应缓存配置
+	GetResponseCache(apiID int) (*entity.ResponseCache, error)
+	//GetResponseCacheList 获取已配置缓存的接口列表
+	GetResponseCacheList() ([]*entity.ResponseCache, error)
+}
+
+//RateLimitDao rateLimit.go
+type RateLimitDao interface {
+	//AddRateLimit 新增限流规则
+	AddRateLimit(r *entity.RateLimit) (int, error)
+	//EditRateLimit 编辑限流规则
+	EditRateLimit(r *entity.RateLimit) error
+	//GetRateLimit 获取限流规则
+	GetRateLimit(id int) (*entity.RateLimit, error)
+	//GetRateLimitList 获取限流规则列表
+	GetRateLimitList(strategyID string, apiID int) ([]*entity.RateLimit, error)
+	//BatchDeleteRateLimit 批量删除限流规则
+	BatchDeleteRateLimit(ids []int) error
+}
+
+//SizeLimitDao sizeLimit.go
+type SizeLimitDao interface {
+	//AddSizeLimit 新增大小限制
+	AddSizeLimit(l *entity.SizeLimit) (int, error)
+	//EditSizeLimit 编辑大小限制
+	EditSizeLimit(l *entity.SizeLimit) error
+	//GetSizeLimit 获取大小限制
+	GetSizeLimit(id int) (*entity.SizeLimit, error)
+	//GetSizeLimitList 获取大小限制列表
+	GetSizeLimitList(strategyID string, apiID int) ([]*entity.SizeLimit, error)
+	//BatchDeleteSizeLimit 批量删除大小限制
+	BatchDeleteSizeLimit(ids []int) error
+}
+
+//ValidationDao validation.go
+type ValidationDao interface {
+	//SaveValidation 新增或更新接口请求校验配置
+	SaveValidation(apiID int, cfg, updateTime string) error
+	//GetValidation 获取接口请求校验配置
+	GetValidation(apiID int) (*entity.Validation, error)
+	//GetValidationList 获取已配置请求校验的接口列表
+	GetValidationList() ([]*entity.Validation, error)
+}
+
+//OpenAPIDao openAPI.go
+type OpenAPIDao interface {
+	//GetOpenAPIList 获取OpenAPI文档列表
+	GetOpenAPIList() ([]*entity.OpenAPI, error)
+	//GetOpenAPI 获取OpenAPI文档
+	GetOpenAPI(name string) (*entity.OpenAPI, error)
+	//SaveOpenAPI 新增或更新OpenAPI文档
+	SaveOpenAPI(o *entity.OpenAPI) error
+	//BatchDeleteOpenAPI 批量删除OpenAPI文档
+	BatchDeleteOpenAPI(names []string) error
+}
+
+//ReplicaDao replica.go
+type ReplicaDao interface {
+	//LockNodeSession 节点连接到控制台实例时占用会话，会话被其他未过期的实例占用时返回false
+	LockNodeSession(nodeKey, consoleID, now, expireTime string) (bool, error)
+	//RefreshNodeSessions 刷新控制台实例持有的会话
+	RefreshNodeSessions(consoleID, now string) error
+	//SetNodeSessionPluginErrors 记录节点上报的插件加载错误
+	SetNodeSessionPluginErrors(nodeKey, consoleID, pluginErrors string) error
+	//UnlockNodeSession 释放会话
+	UnlockNodeSession(nodeKey, consoleID string) error
+	//GetNodeSessions 获取未过期的会话
+	GetNodeSessions(expireTime string) ([]*entity.NodeSession, error)
+	//AddConsoleEvent 新增控制台实例间广播的事件
+	AddConsoleEvent(consoleID, eventType, data, now string) error
+	//GetConsoleEvents 获取指定ID之后的事件
+	GetConsoleEvents(lastID int) ([]*entity.ConsoleEvent, error)
+	//GetLastConsoleEventID 获取最新事件ID
+	GetLastConsoleEventID() (int, error)
+	//DeleteConsoleEvents 删除过期事件
+	DeleteConsoleEvents(expireTime string) error
+	//AcquireLease 获取或续期租约，租约被其他未过期的实例持有时返回false
+	AcquireLease(name, consoleID, now, expireTime string) (bool, error)
+}
+
+//TrafficDao traffic.go
+type TrafficDao interface {
+	//AddTrafficReport 保存节点上报的流量统计
+	AddTrafficReport(cluster, nodeKey string, report *config.TrafficReport) error
+	//GetTrafficStats 获取时间范围内的流量统计，cluster、strategyID为空及apiID为0时不过滤
+	GetTrafficStats(start, end int64, cluster, strategyID string, apiID int) ([]*entity.TrafficStat, error)
+	//DeleteTrafficStats 删除指定时间之前的流量统计
+	DeleteTrafficStats(before int64) error
+}
+
+//AlertDao alert.go
+type AlertDao interface {
+	//GetAlertRules 获取告警规则列表
+	GetAlertRules() ([]*entity.AlertRule, error)
+	//GetAlertRule 获取告警规则
+	GetAlertRule(ruleID int) (*entity.AlertRule, error)
+	//AddAlertRule 新增告警规则
+	AddAlertRule(r *entity.AlertRule) (int, error)
+	//EditAlertRule 修改告警规则
+	EditAlertRule(r *entity.AlertRule) error
+	//DeleteAlertRules 批量删除告警规则
+	DeleteAlertRules(ruleIDs []int) error
+	//GetAlertChannels 获取通知渠道列表
+	GetAlertChannels() ([]*entity.AlertChannel, error)
+	//GetAlertChannel 获取通知渠道
+	GetAlertChannel(channelID int) (*entity.AlertChannel, error)
+	//AddAlertChannel 新增通知渠道
+	AddAlertChannel(c *entity.AlertChannel) (int, error)
+	//EditAlertChannel 修改通知渠道
+	EditAlertChannel(c *entity.AlertChannel) error
+	//DeleteAlertChannels 批量删除通知渠道
+	DeleteAlertChannels(channelIDs []int) error
+	//GetAlertSilences 获取结束时间在指定时间之后的静默列表
+	GetAlertSilences(after int64) ([]*entity.AlertSilence, error)
+	//AddAlertSilence 新增静默
+	AddAlertSilence(s *entity.AlertSilence) (int, error)
+	//DeleteAlertSilences 批量删除静默
+	DeleteAlertSilences(silenceIDs []int) error
+	//GetFiringAlerts 获取未恢复的告警
+	GetFiringAlerts() ([]*entity.AlertHistory, error)
+	//AddAlertHistory 新增告警记录
+	AddAlertHistory(h *entity.AlertHistory) (int, error)
+	//ResolveAlert 告警恢复
+	ResolveAlert(alertID int, value float64, resolveTime int64) error
+	//GetAlertHistoryList 分页获取告警记录，ruleID为0及status为空时不过滤
+	GetAlertHistoryList(ruleID int, status string, page, pageSize int) ([]*entity.AlertHistory, int, error)
+	//GetAPIAlertValves 获取设置了告警阈值的接口，key为apiID
+	GetAPIAlertValves() (map[int]int, error)
+}
+
 //NodeDao node.go
 type NodeDao interface {
 	//AddNode 新增节点信息
@@ -314,6 +496,8 @@ type PluginDao interface {
 	BatchStartPlugin(pluginNameList string) (bool, string, error)
 	//EditPluginCheckStatus 更新插件检测状态
 	EditPluginCheckStatus(pluginName string, isCheck int) (bool, string, error)
+	//EditPluginErrorPolicy 修改插件错误处理策略
+	EditPluginErrorPolicy(pluginName, errorPolicy string, errorStatus int, errorBody string) error
 }
 
 //ProjectDao project.go
@@ -455,4 +639,60 @@ type VersionDao interface {
 	GetPublishVersionID() int
 	//GetVersionConfig 获取当前版本配置
 	GetVersionConfig() (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error)
+	//GetVersionConfigByID 获取指定版本的配置
+	GetVersionConfigByID(versionID int) (*config.GokuConfig, map[string]map[string]*config.BalanceConfig, map[string]map[string]*config.DiscoverConfig, error)
+}
+
+//BundleDao bundle.go
+type BundleDao interface {
+	//ExportBundle 导出控制台配置，includeSecrets为false时加密保存的凭证以占位值导出
+	ExportBundle(includeSecrets bool) (*entity.Bundle, error)
+	//ApplyBundle 应用控制台配置
+	ApplyBundle(b *entity.Bundle, userID int) error
+}
+
+//AuditDao audit.go
+type AuditDao interface {
+	//AddAuditLog 新增审计日志
+	AddAuditLog(l *entity.AuditLog) (int, error)
+	//GetAuditLogList 分页获取审计日志，pageSize不大于0时返回全部
+	GetAuditLogList(filter *entity.AuditFilter, page, pageSize int) ([]*entity.AuditLog, int, error)
+	//LinkAuditLogs 将尚未关联版本的成功修改关联到版本
+	LinkAuditLogs(versionID int, excludeEntityType string) error
+}
+
+//VersionPublishDao versionPublish.go
+type VersionPublishDao interface {
+	//AddVersionPublish 新增版本发布记录
+	AddVersionPublish(p *entity.VersionPublish) (int, error)
+	//GetVersionPublish 获取版本发布记录
+	GetVersionPublish(requestID int) (*entity.VersionPublish, error)
+	//GetVersionPublishList 分页获取版本发布记录，status为空时不过滤
+	GetVersionPublishList(status string, page, pageSize int) ([]*entity.VersionPublish, int, error)
+	//GetDueVersionPublishes 获取指定状态且计划发布时间不晚于before的发布记录
+	GetDueVersionPublishes(status string, before int64) ([]*entity.VersionPublish, error)
+	//UpdateVersionPublishStatus 发布记录处于fromStatus时更新为toStatus，返回是否更新成功；approveUserID为0时不修改审批人
+	UpdateVersionPublishStatus(requestID int, fromStatus, toStatus string, approveUserID int, now string) (bool, error)
+	//GetLastPublishedVersionID 获取最近一次状态为status且不是excludeVersionID的发布版本
+	GetLastPublishedVersionID(status string, excludeVersionID int) (int, error)
+	//CheckVersionPublishIsExist 检查版本是否存在指定状态的发布记录
+	CheckVersionPublishIsExist(versionID int, status string) (bool, error)
+	//SetNodeConfigVersion 记录节点已应用的配置版本
+	SetNodeConfigVersion(nodeKey string, versionID int, now string) error
+	//GetNodeConfigVersions 获取各节点已应用的配置版本，key为nodeKey
+	GetNodeConfigVersions() (map[string]int, error)
+}
+
+//VersionCanaryDao versionCanary.go
+type VersionCanaryDao interface {
+	//AddVersionCanary 新增灰度发布
+	AddVersionCanary(c *entity.VersionCanary) (int, error)
+	//GetVersionCanary 获取灰度发布
+	GetVersionCanary(canaryID int) (*entity.VersionCanary, error)
+	//GetVersionCanaryByStatus 获取最近一条指定状态的灰度发布
+	GetVersionCanaryByStatus(status string) (*entity.VersionCanary, error)
+	//GetVersionCanaryList 分页获取灰度发布记录
+	GetVersionCanaryList(page, pageSize int) ([]*entity.VersionCanary, int, error)
+	//FinishVersionCanary 灰度发布处于fromStatus时结束为toStatus，返回是否更新成功
+	FinishVersionCanary(canaryID int, fromStatus, toStatus, reason string, endTime int64, now string) (bool, error)
 }
@@ -0,0 +1,56 @@
+package entity
+
+//AlertRule 告警规则，Cluster、StrategyID为空及APIID为0时不限制统计范围
+type AlertRule struct {
+	RuleID      int     `json:"ruleID"`
+	Name        string  `json:"name"`
+	Metric      string  `json:"metric"`
+	Cluster     string  `json:"cluster"`
+	StrategyID  string  `json:"strategyID"`
+	APIID       int     `json:"apiID"`
+	Threshold   float64 `json:"threshold"`
+	Duration    int     `json:"duration"`
+	MinRequests int     `json:"minRequests"`
+	Channels    []int   `json:"channels"`
+	Enable      bool    `json:"enable"`
+	UpdateTime  string  `json:"updateTime"`
+}
+
+//AlertChannel 告警通知渠道，Config为渠道类型对应的JSON配置
+type AlertChannel struct {
+	ChannelID  int    `json:"channelID"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Config     string `json:"config"`
+	IsDefault  bool   `json:"isDefault"`
+	Enable     bool   `json:"enable"`
+	UpdateTime string `json:"updateTime"`
+}
+
+//AlertSilence 告警静默，RuleID为0时匹配所有规则，Target为空时匹配所有对象
+type AlertSilence struct {
+	SilenceID    int    `json:"silenceID"`
+	RuleID       int    `json:"ruleID"`
+	Target       string `json:"target"`
+	StartTime    int64  `json:"startTime"`
+	EndTime      int64  `json:"endTime"`
+	Comment      string `json:"comment"`
+	CreateUserID int    `json:"createUserID"`
+	CreateTime   string `json:"createTime"`
+}
+
+//AlertHistory 告警记录
+type AlertHistory struct {
+	AlertID     int     `json:"alertID"`
+	RuleID      int     `json:"ruleID"`
+	RuleName    string  `json:"ruleName"`
+	Metric      string  `json:"metric"`
+	Target      string  `json:"target"`
+	Status      string  `json:"status"`
+	Value       float64 `json:"value"`
+	Threshold   float64 `json:"threshold"`
+	Message     string  `json:"message"`
+	Silenced    bool    `json:"silenced"`
+	FireTime    int64   `json:"fireTime"`
+	ResolveTime int64   `json:"resolveTime"`
+}
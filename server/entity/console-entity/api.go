@@ -30,6 +30,7 @@ type API struct {
 	APIType          int                      `json:"apiType"`
 	LinkAPIs         []config.APIStepUIConfig `json:"linkApis"`
 	StaticResponse   string                   `json:"staticResponse"`
+	Rewrite          *config.RewriteConfig    `json:"rewrite,omitempty"`
 	ResponseDataType string                   `json:"responseDataType"`
 	*ManagerInfo
 }
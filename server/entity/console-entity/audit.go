@@ -0,0 +1,28 @@
+package entity
+
+//AuditLog 审计日志，Before、After为实体修改前后的JSON快照，Diff为字段级差异的JSON
+type AuditLog struct {
+	LogID      int    `json:"logID"`
+	UserID     int    `json:"userID"`
+	LoginCall  string `json:"loginCall"`
+	Operation  string `json:"operation"`
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityID"`
+	Params     string `json:"params"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	Diff       string `json:"diff"`
+	StatusCode string `json:"statusCode"`
+	VersionID  int    `json:"versionID"`
+	CreateTime string `json:"createTime"`
+}
+
+//AuditFilter 审计日志查询条件，零值表示不过滤，Start、End为"2006-01-02 15:04:05"格式的时间
+type AuditFilter struct {
+	UserID     int
+	EntityType string
+	EntityID   string
+	VersionID  int
+	Start      string
+	End        string
+}
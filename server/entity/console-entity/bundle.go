@@ -0,0 +1,137 @@
+package entity
+
+//BundleVersion 配置包格式版本
+const BundleVersion = 1
+
+//Bundle 控制台配置包，资源均以名称等自然键标识，便于在不同环境间迁移
+type Bundle struct {
+	Version    int                `json:"version" yaml:"version"`
+	Clusters   []*BundleCluster   `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	Discovery  []*BundleDiscovery `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+	Balances   []*BundleBalance   `json:"balances,omitempty" yaml:"balances,omitempty"`
+	LogConfigs []*BundleLogConfig `json:"logConfigs,omitempty" yaml:"logConfigs,omitempty"`
+	Plugins    []*BundlePlugin    `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Projects   []*BundleProject   `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Strategies []*BundleStrategy  `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+}
+
+//BundleCluster 集群
+type BundleCluster struct {
+	Name  string `json:"name" yaml:"name"`
+	Title string `json:"title" yaml:"title"`
+	Note  string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+//BundleDiscovery 服务发现
+type BundleDiscovery struct {
+	Name               string `json:"name" yaml:"name"`
+	Driver             string `json:"driver" yaml:"driver"`
+	Default            int    `json:"default" yaml:"default"`
+	Desc               string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Config             string `json:"config,omitempty" yaml:"config,omitempty"`
+	ClusterConfig      string `json:"clusterConfig,omitempty" yaml:"clusterConfig,omitempty"`
+	HealthCheck        int    `json:"healthCheck" yaml:"healthCheck"`
+	HealthCheckPath    string `json:"healthCheckPath,omitempty" yaml:"healthCheckPath,omitempty"`
+	HealthCheckPeriod  int    `json:"healthCheckPeriod" yaml:"healthCheckPeriod"`
+	HealthCheckCode    string `json:"healthCheckCode,omitempty" yaml:"healthCheckCode,omitempty"`
+	HealthCheckTimeout int    `json:"healthCheckTimeout" yaml:"healthCheckTimeout"`
+}
+
+//BundleBalance 负载
+type BundleBalance struct {
+	Name          string `json:"name" yaml:"name"`
+	ServiceName   string `json:"serviceName" yaml:"serviceName"`
+	AppName       string `json:"appName,omitempty" yaml:"appName,omitempty"`
+	Static        string `json:"static,omitempty" yaml:"static,omitempty"`
+	StaticCluster string `json:"staticCluster,omitempty" yaml:"staticCluster,omitempty"`
+	Desc          string `json:"desc,omitempty" yaml:"desc,omitempty"`
+}
+
+//BundleLogConfig 日志配置
+type BundleLogConfig struct {
+	Name   string `json:"name" yaml:"name"`
+	Enable int    `json:"enable" yaml:"enable"`
+	Dir    string `json:"dir" yaml:"dir"`
+	File   string `json:"file" yaml:"file"`
+	Level  string `json:"level" yaml:"level"`
+	Period string `json:"period" yaml:"period"`
+	Expire int    `json:"expire" yaml:"expire"`
+	Fields string `json:"fields" yaml:"fields"`
+}
+
+//BundlePlugin 插件
+type BundlePlugin struct {
+	Name        string `json:"name" yaml:"name"`
+	ChineseName string `json:"chineseName,omitempty" yaml:"chineseName,omitempty"`
+	Status      int    `json:"status" yaml:"status"`
+	Priority    int    `json:"priority" yaml:"priority"`
+	Config      string `json:"config,omitempty" yaml:"config,omitempty"`
+	IsStop      int    `json:"isStop" yaml:"isStop"`
+	Type        int    `json:"type" yaml:"type"`
+	Official    string `json:"official" yaml:"official"`
+	Desc        string `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+	IsCheck     int    `json:"isCheck" yaml:"isCheck"`
+	ErrorPolicy string `json:"errorPolicy,omitempty" yaml:"errorPolicy,omitempty"`
+	ErrorStatus int    `json:"errorStatus,omitempty" yaml:"errorStatus,omitempty"`
+	ErrorBody   string `json:"errorBody,omitempty" yaml:"errorBody,omitempty"`
+}
+
+//BundleProject 项目，分组以“/”分隔的分组名称路径表示
+type BundleProject struct {
+	Name   string       `json:"name" yaml:"name"`
+	Groups []string     `json:"groups,omitempty" yaml:"groups,omitempty"`
+	APIs   []*BundleAPI `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+//BundleAPI 接口，在项目内以请求路径及请求方式标识
+type BundleAPI struct {
+	Name             string `json:"name" yaml:"name"`
+	Group            string `json:"group,omitempty" yaml:"group,omitempty"`
+	RequestURL       string `json:"requestURL" yaml:"requestURL"`
+	RequestMethod    string `json:"requestMethod" yaml:"requestMethod"`
+	Protocol         string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	BalanceName      string `json:"balanceName,omitempty" yaml:"balanceName,omitempty"`
+	TargetURL        string `json:"targetURL,omitempty" yaml:"targetURL,omitempty"`
+	TargetMethod     string `json:"targetMethod,omitempty" yaml:"targetMethod,omitempty"`
+	IsFollow         string `json:"isFollow" yaml:"isFollow"`
+	StripPrefix      string `json:"stripPrefix,omitempty" yaml:"stripPrefix,omitempty"`
+	StripSlash       string `json:"stripSlash,omitempty" yaml:"stripSlash,omitempty"`
+	Timeout          int    `json:"timeout" yaml:"timeout"`
+	RetryCount       int    `json:"retryCount" yaml:"retryCount"`
+	AlertValve       int    `json:"alertValve" yaml:"alertValve"`
+	APIType          int    `json:"apiType" yaml:"apiType"`
+	ResponseDataType string `json:"responseDataType,omitempty" yaml:"responseDataType,omitempty"`
+	LinkAPIs         string `json:"linkApis,omitempty" yaml:"linkApis,omitempty"`
+	StaticResponse   string `json:"staticResponse,omitempty" yaml:"staticResponse,omitempty"`
+	Rewrite          string `json:"rewrite,omitempty" yaml:"rewrite,omitempty"`
+}
+
+//BundleStrategy 策略
+type BundleStrategy struct {
+	ID           string                  `json:"id" yaml:"id"`
+	Name         string                  `json:"name" yaml:"name"`
+	Group        string                  `json:"group,omitempty" yaml:"group,omitempty"`
+	Type         int                     `json:"type" yaml:"type"`
+	EnableStatus int                     `json:"enableStatus" yaml:"enableStatus"`
+	Auth         string                  `json:"auth,omitempty" yaml:"auth,omitempty"`
+	AuthPolicy   string                  `json:"authPolicy,omitempty" yaml:"authPolicy,omitempty"`
+	Plugins      []*BundleStrategyPlugin `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	APIs         []*BundleStrategyAPI    `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+//BundleStrategyPlugin 策略或策略接口绑定的插件，鉴权凭证保持加密后的形式
+type BundleStrategyPlugin struct {
+	Name   string `json:"name" yaml:"name"`
+	Status int    `json:"status" yaml:"status"`
+	Config string `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+//BundleStrategyAPI 策略绑定的接口
+type BundleStrategyAPI struct {
+	Project       string                  `json:"project" yaml:"project"`
+	RequestURL    string                  `json:"requestURL" yaml:"requestURL"`
+	RequestMethod string                  `json:"requestMethod" yaml:"requestMethod"`
+	Target        string                  `json:"target,omitempty" yaml:"target,omitempty"`
+	Plugins       []*BundleStrategyPlugin `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
@@ -18,6 +18,8 @@ type Node struct {
 	CreateTime    string `json:"createTime"`
 	UpdateTime    string `json:"updateTime"`
 	UpdatePeriod  int    `json:"updatePeriod,omitempty"`
+	//PluginErrors 节点上报的插件加载错误，key为插件名
+	PluginErrors map[string]string `json:"pluginErrors,omitempty"`
 	*SSHInfo
 }
 
@@ -0,0 +1,27 @@
+package entity
+
+//OpenAPI OpenAPI文档
+type OpenAPI struct {
+	Name       string `json:"name"`
+	Remark     string `json:"remark"`
+	Title      string `json:"title"`
+	Version    string `json:"version"`
+	Content    string `json:"-"`
+	CreateTime string `json:"createTime"`
+	UpdateTime string `json:"updateTime"`
+}
+
+//OpenAPIOperation 与OpenAPI操作对应的接口
+type OpenAPIOperation struct {
+	APIID         int    `json:"apiID"`
+	APIName       string `json:"apiName"`
+	GroupID       int    `json:"groupID"`
+	GroupName     string `json:"groupName"`
+	RequestURL    string `json:"requestURL"`
+	RequestMethod string `json:"requestMethod"`
+	TargetURL     string `json:"targetURL"`
+	TargetMethod  string `json:"targetMethod"`
+	BalanceName   string `json:"balanceName"`
+	Protocol      string `json:"protocol"`
+	Validation    string `json:"-"`
+}
@@ -15,6 +15,10 @@ type Plugin struct {
 	PluginDesc string `json:"pluginDesc"`
 	IsStop     int    `json:"isStop"`
 	IsCheck    int    `json:"isCheck"`
+
+	ErrorPolicy string `json:"errorPolicy"`
+	ErrorStatus int    `json:"errorStatus"`
+	ErrorBody   string `json:"errorBody"`
 }
 
 //PluginList 插件列表
@@ -0,0 +1,10 @@
+package entity
+
+//ProtoDescriptor protobuf描述文件
+type ProtoDescriptor struct {
+	Name       string `json:"name"`
+	Remark     string `json:"remark"`
+	Content    string `json:"-"`
+	CreateTime string `json:"createTime"`
+	UpdateTime string `json:"updateTime"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+//RateLimit 限流规则
+type RateLimit struct {
+	ID           int    `json:"id"`
+	StrategyID   string `json:"strategyID"`
+	StrategyName string `json:"strategyName"`
+	APIID        int    `json:"apiID"`
+	APIName      string `json:"apiName"`
+	Algorithm    string `json:"algorithm"`
+	Mode         string `json:"mode"`
+	Key          string `json:"key"`
+	Limit        int64  `json:"limit"`
+	Period       int    `json:"period"`
+	Burst        int64  `json:"burst"`
+	DailyQuota   int64  `json:"dailyQuota"`
+	MonthlyQuota int64  `json:"monthlyQuota"`
+	Enable       int    `json:"enable"`
+	Remark       string `json:"remark"`
+	UpdateTime   string `json:"updateTime"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+//NodeSession 节点与控制台实例的连接会话
+type NodeSession struct {
+	NodeKey      string `json:"nodeKey"`
+	ConsoleID    string `json:"consoleID"`
+	PluginErrors string `json:"pluginErrors"`
+	UpdateTime   string `json:"updateTime"`
+}
+
+//ConsoleEvent 控制台实例间广播的事件
+type ConsoleEvent struct {
+	ID         int    `json:"id"`
+	ConsoleID  string `json:"consoleID"`
+	EventType  string `json:"eventType"`
+	Data       string `json:"data"`
+	CreateTime string `json:"createTime"`
+}
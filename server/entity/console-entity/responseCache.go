@@ -0,0 +1,11 @@
+package entity
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//ResponseCache 接口响应缓存配置
+type ResponseCache struct {
+	APIID      int    `json:"apiID"`
+	APIName    string `json:"apiName"`
+	UpdateTime string `json:"updateTime"`
+	*config.ResponseCacheConfig
+}
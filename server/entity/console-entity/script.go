@@ -0,0 +1,23 @@
+package entity
+
+//Script 脚本
+type Script struct {
+	ScriptID    int    `json:"scriptID"`
+	ScriptName  string `json:"scriptName"`
+	ScriptDesc  string `json:"scriptDesc"`
+	Language    string `json:"language"`
+	Content     string `json:"content"`
+	Version     int    `json:"version"`
+	Timeout     int    `json:"timeout"`
+	MaxDataSize int    `json:"maxDataSize"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+//ScriptHistory 脚本历史版本
+type ScriptHistory struct {
+	ScriptName string `json:"scriptName"`
+	Version    int    `json:"version"`
+	Content    string `json:"content"`
+	CreateTime string `json:"createTime"`
+}
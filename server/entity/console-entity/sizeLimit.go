@@ -0,0 +1,25 @@
+package entity
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//SizeLimit 请求及响应大小限制
+type SizeLimit struct {
+	ID              int    `json:"id"`
+	StrategyID      string `json:"strategyID"`
+	StrategyName    string `json:"strategyName"`
+	APIID           int    `json:"apiID"`
+	APIName         string `json:"apiName"`
+	MaxRequestBody  int64  `json:"maxRequestBody"`
+	MaxResponseBody int64  `json:"maxResponseBody"`
+	MaxHeader       int    `json:"maxHeader"`
+	Remark          string `json:"remark"`
+	UpdateTime      string `json:"updateTime"`
+}
+
+//Validation 接口请求校验配置
+type Validation struct {
+	APIID      int    `json:"apiID"`
+	APIName    string `json:"apiName"`
+	UpdateTime string `json:"updateTime"`
+	*config.ValidationConfig
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "github.com/eolinker/goku-api-gateway/config"
+
+//TrafficStat 节点上报的流量统计记录
+type TrafficStat struct {
+	Cluster    string `json:"cluster"`
+	NodeKey    string `json:"nodeKey"`
+	ReportTime int64  `json:"reportTime"`
+	Period     int    `json:"period"`
+	config.TrafficStat
+}
@@ -0,0 +1,24 @@
+package entity
+
+//VersionCanary 版本灰度发布，灰度期间指定节点分组使用VersionID，其余节点仍使用BaseVersionID。
+//ErrorThreshold为灰度节点5xx比例（百分比）相对其余节点允许升高的幅度，ObserveTime为观察时长（秒）
+type VersionCanary struct {
+	CanaryID       int     `json:"canaryID"`
+	VersionID      int     `json:"versionID"`
+	VersionName    string  `json:"versionName"`
+	BaseVersionID  int     `json:"baseVersionID"`
+	GroupID        int     `json:"groupID"`
+	GroupName      string  `json:"groupName"`
+	Status         string  `json:"status"`
+	AutoPromote    bool    `json:"autoPromote"`
+	ObserveTime    int     `json:"observeTime"`
+	ErrorThreshold float64 `json:"errorThreshold"`
+	MinRequests    int     `json:"minRequests"`
+	UserID         int     `json:"userID"`
+	LoginCall      string  `json:"loginCall"`
+	Reason         string  `json:"reason"`
+	StartTime      int64   `json:"startTime"`
+	EndTime        int64   `json:"endTime"`
+	CreateTime     string  `json:"createTime"`
+	UpdateTime     string  `json:"updateTime"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+//VersionPublish 版本发布申请，PublishTime为计划发布时间（unix秒），0表示审批通过后立即发布
+type VersionPublish struct {
+	RequestID     int    `json:"requestID"`
+	VersionID     int    `json:"versionID"`
+	VersionName   string `json:"versionName"`
+	Status        string `json:"status"`
+	IsRollback    bool   `json:"isRollback"`
+	RequestUserID int    `json:"requestUserID"`
+	RequestUser   string `json:"requestUser"`
+	ApproveUserID int    `json:"approveUserID"`
+	ApproveUser   string `json:"approveUser"`
+	PublishTime   int64  `json:"publishTime"`
+	Remark        string `json:"remark"`
+	CreateTime    string `json:"createTime"`
+	UpdateTime    string `json:"updateTime"`
+}